@@ -0,0 +1,217 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package extract implements the parts of extracting archives into an
+// [os.Root] that are shared by archive/tar and archive/zip.
+package extract
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Options are the options of an Extractor, as set by the ExtractOptions
+// of the archive packages.
+type Options struct {
+	MaxFileSize  int64
+	MaxTotalSize int64
+	Overwrite    bool
+}
+
+// An Extractor holds the state of an extraction.
+type Extractor struct {
+	root     *os.Root
+	opts     Options
+	errLimit error          // the archive package's ErrLimitExceeded
+	total    int64          // bytes written to files
+	dirs     []extractedDir // directories to update when extraction ends
+	errs     []error
+}
+
+type extractedDir struct {
+	name        string // operating system path
+	archiveName string
+	perm        fs.FileMode
+	modTime     time.Time
+}
+
+// New returns an Extractor into root. errLimitExceeded is the error
+// returned when a size limit in opts is exceeded.
+func New(root *os.Root, opts Options, errLimitExceeded error) *Extractor {
+	return &Extractor{root: root, opts: opts, errLimit: errLimitExceeded}
+}
+
+// EntryError returns the error reported for the entry with the archive
+// name when extracting it fails with err.
+func EntryError(name string, err error) error {
+	return &fs.PathError{Op: "extract", Path: name, Err: err}
+}
+
+// AddError records an error to be returned by Finish.
+func (x *Extractor) AddError(err error) {
+	x.errs = append(x.errs, err)
+}
+
+// Finish sets the modes and modification times of the extracted
+// directories, and returns all the recorded errors joined together.
+func (x *Extractor) Finish() error {
+	// Update the innermost directories first, so that a directory's
+	// mode does not prevent access to those within it.
+	slices.SortStableFunc(x.dirs, func(a, b extractedDir) int {
+		return strings.Compare(b.name, a.name)
+	})
+	for _, d := range x.dirs {
+		err := x.root.Chtimes(d.name, time.Time{}, d.modTime)
+		if err == nil && d.perm&0o700 != 0o700 {
+			err = x.root.Chmod(d.name, d.perm)
+		}
+		if err != nil {
+			x.errs = append(x.errs, EntryError(d.archiveName, err))
+		}
+	}
+	return errors.Join(x.errs...)
+}
+
+// LocalName returns the operating system path for an archive name.
+// It reports false if the name is not local.
+func LocalName(name string) (string, bool) {
+	name = path.Clean(name)
+	if name == "." {
+		return ".", true
+	}
+	local, err := filepath.Localize(name)
+	if err != nil || !filepath.IsLocal(local) {
+		return "", false
+	}
+	return local, true
+}
+
+// LocalLink reports whether a symbolic link with the operating system
+// path name and the archive link target refers to a file within the root.
+//
+// The target is resolved from the directory containing name, which is
+// only where the path suggests if none of the parent directories of
+// name is an existing symbolic link. A link to "." named "x" would
+// otherwise let "x/x/y" be created as "y", where a target of "../.."
+// refers to a file outside of the root, so such links are refused.
+func (x *Extractor) LocalLink(name, target string) bool {
+	target = filepath.FromSlash(target)
+	if target == "" || filepath.IsAbs(target) || filepath.VolumeName(target) != "" || os.IsPathSeparator(target[0]) {
+		return false
+	}
+	if !filepath.IsLocal(filepath.Join(filepath.Dir(name), target)) {
+		return false
+	}
+	for dir := filepath.Dir(name); dir != "."; dir = filepath.Dir(dir) {
+		if info, err := x.root.Lstat(dir); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Mkdir creates the directory name, and any missing parents. The
+// directory is made accessible to its owner until Finish gives it the
+// permission bits perm and the modification time modTime.
+func (x *Extractor) Mkdir(name, archiveName string, perm fs.FileMode, modTime time.Time) error {
+	if err := x.root.MkdirAll(name, perm|0o700); err != nil {
+		return err
+	}
+	// Recheck, in case name is an existing symbolic link.
+	if info, err := x.root.Lstat(name); err != nil || !info.IsDir() {
+		return fs.ErrExist
+	}
+	x.dirs = append(x.dirs, extractedDir{name, archiveName, perm, modTime})
+	return nil
+}
+
+// Symlink creates a symbolic link name to the archive link target,
+// which the caller must have checked with [Extractor.LocalLink].
+func (x *Extractor) Symlink(name, target string) error {
+	if err := x.prepare(name); err != nil {
+		return err
+	}
+	return x.root.Symlink(filepath.FromSlash(target), name)
+}
+
+// Link creates a hard link name to the operating system path target,
+// which the caller must have obtained from LocalName.
+func (x *Extractor) Link(name, target string) error {
+	if err := x.prepare(name); err != nil {
+		return err
+	}
+	return x.root.Link(target, name)
+}
+
+// WriteFile creates the regular file name with the contents of r,
+// enforcing the size limits, and the given permission bits and
+// modification time. If it fails, the file is removed.
+func (x *Extractor) WriteFile(name string, r io.Reader, perm fs.FileMode, modTime time.Time) error {
+	if err := x.prepare(name); err != nil {
+		return err
+	}
+	f, err := x.root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	err = x.copy(f, r)
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		x.root.Remove(name)
+		return err
+	}
+	return x.root.Chtimes(name, time.Time{}, modTime)
+}
+
+// prepare creates the parent directories of name, and removes
+// any existing file with that name if the options permit it.
+func (x *Extractor) prepare(name string) error {
+	if dir := filepath.Dir(name); dir != "." {
+		if err := x.root.MkdirAll(dir, 0o777); err != nil {
+			return err
+		}
+	}
+	info, err := x.root.Lstat(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !x.opts.Overwrite || info.IsDir() {
+		return fs.ErrExist
+	}
+	return x.root.Remove(name)
+}
+
+// copy copies r to w, enforcing the size limits.
+func (x *Extractor) copy(w io.Writer, r io.Reader) error {
+	limit := int64(-1)
+	if x.opts.MaxFileSize > 0 {
+		limit = x.opts.MaxFileSize
+	}
+	if x.opts.MaxTotalSize > 0 && (limit < 0 || x.opts.MaxTotalSize-x.total < limit) {
+		limit = x.opts.MaxTotalSize - x.total
+	}
+	if limit < 0 {
+		n, err := io.Copy(w, r)
+		x.total += n
+		return err
+	}
+	n, err := io.Copy(w, io.LimitReader(r, limit+1))
+	x.total += n
+	if err == nil && n > limit {
+		err = x.errLimit
+	}
+	return err
+}
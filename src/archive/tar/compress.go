@@ -0,0 +1,75 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tar
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"compress/zstd"
+	"errors"
+	"io"
+)
+
+// Magic numbers that identify compressed streams.
+var (
+	magicGzip  = []byte{0x1f, 0x8b}
+	magicBzip2 = []byte("BZh")
+	magicZstd  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	magicXz    = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+
+	// A bzip2 stream continues with a block size digit, and the
+	// magic number of its first block or of the end of the stream.
+	bzip2BlockMagic = []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}
+	bzip2EOSMagic   = []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90}
+)
+
+// magicLen is the number of bytes needed to detect a compressed stream.
+const magicLen = 10
+
+// isBzip2 reports whether magic is the start of a bzip2 stream.
+// The "BZh" signature alone could be the start of a file name
+// in an uncompressed archive.
+func isBzip2(magic []byte) bool {
+	if len(magic) < magicLen || !bytes.HasPrefix(magic, magicBzip2) || magic[3] < '1' || magic[3] > '9' {
+		return false
+	}
+	m := magic[4:magicLen]
+	return bytes.Equal(m, bzip2BlockMagic) || bytes.Equal(m, bzip2EOSMagic)
+}
+
+// NewCompressedReader creates a new [Reader] reading from r,
+// which may hold a compressed tar archive.
+//
+// The compression format is detected from the start of the stream.
+// Archives compressed with gzip, bzip2, or Zstandard are decompressed
+// transparently, and other data is read as an uncompressed archive.
+// The error returned is non-nil if the compressed stream is invalid
+// or uses an unsupported format, such as xz.
+//
+// NewCompressedReader may read more data than necessary from r.
+func NewCompressedReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	// A short stream can't be compressed; let Next report any error.
+	magic, _ := br.Peek(magicLen)
+
+	var dr io.Reader = br
+	switch {
+	case bytes.HasPrefix(magic, magicGzip):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		dr = zr
+	case isBzip2(magic):
+		dr = bzip2.NewReader(br)
+	case bytes.HasPrefix(magic, magicZstd):
+		dr = zstd.NewReader(br)
+	case bytes.HasPrefix(magic, magicXz):
+		return nil, errors.New("archive/tar: xz compression is not supported")
+	}
+	return NewReader(dr), nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tar
+
+import (
+	"archive/internal/extract"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// ErrLimitExceeded is returned by [Reader.ExtractTo] when the archive
+// exceeds one of the limits in its [ExtractOptions].
+var ErrLimitExceeded = errors.New("archive/tar: extraction limit exceeded")
+
+var errUnsupportedType = errors.New("archive/tar: unsupported file type")
+
+// ExtractOptions are options for [Reader.ExtractTo].
+// A zero ExtractOptions places no limits on the archive.
+type ExtractOptions struct {
+	// MaxEntries is the maximum number of entries in the archive.
+	// If MaxEntries is zero, there is no limit.
+	MaxEntries int
+
+	// MaxFileSize is the maximum size in bytes of each extracted file.
+	// If MaxFileSize is zero, there is no limit.
+	MaxFileSize int64
+
+	// MaxTotalSize is the maximum total size in bytes of the extracted files.
+	// If MaxTotalSize is zero, there is no limit.
+	MaxTotalSize int64
+
+	// Overwrite permits extracted files to replace existing files.
+	// Existing directories are always reused.
+	// If Overwrite is false, an entry naming an existing file
+	// other than a directory is not extracted.
+	Overwrite bool
+}
+
+// ExtractTo extracts the remaining entries of the archive into root.
+//
+// Directories, regular files, symbolic links, and hard links are
+// extracted, with the permission bits recorded in the archive (before
+// umask) and their modification times. Parent directories are created
+// as needed. Directories are made accessible to their owner while
+// extraction is in progress, and are given their recorded modes and
+// times when it ends. Set-user-ID, set-group-ID, and sticky bits,
+// owners, and access times are not restored.
+//
+// Entries are extracted only within root. Entries whose names are not
+// local (as defined by [filepath.IsLocal] after removing any trailing
+// slash and cleaning), symbolic and hard links whose targets refer
+// to files outside of root, and symbolic links in a directory reached
+// through another symbolic link, are not extracted, and report an error
+// wrapping [ErrInsecurePath]. Entries of other types, such as device
+// files and named pipes, are not extracted either. PAX global headers,
+// such as those written by git archive, and other metadata entries
+// are skipped, and do not count towards opts.MaxEntries.
+//
+// An error extracting an entry does not stop the extraction of later
+// entries. Such errors are reported as [*fs.PathError] values with the
+// Op "extract" and the entry's name, and ExtractTo returns all of them
+// joined with [errors.Join]. An error reading the archive, or exceeding
+// a limit in opts, stops the extraction and is returned along with the
+// errors reported for earlier entries. If opts is nil, no limits apply.
+func (tr *Reader) ExtractTo(root *os.Root, opts *ExtractOptions) error {
+	var o ExtractOptions
+	if opts != nil {
+		o = *opts
+	}
+	x := extract.New(root, extract.Options{
+		MaxFileSize:  o.MaxFileSize,
+		MaxTotalSize: o.MaxTotalSize,
+		Overwrite:    o.Overwrite,
+	}, ErrLimitExceeded)
+	for n := 0; ; {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != ErrInsecurePath {
+			x.AddError(err)
+			break
+		}
+		if isMetadata(hdr.Typeflag) {
+			continue
+		}
+		if n++; o.MaxEntries > 0 && n > o.MaxEntries {
+			x.AddError(extract.EntryError(hdr.Name, ErrLimitExceeded))
+			break
+		}
+		if err := extractEntry(x, hdr, o.MaxFileSize, tr); err != nil {
+			err = extract.EntryError(hdr.Name, err)
+			x.AddError(err)
+			if errors.Is(err, ErrLimitExceeded) {
+				break
+			}
+		}
+	}
+	return x.Finish()
+}
+
+// isMetadata reports whether entries of type flag describe other
+// entries, or the archive, rather than files. Reader.Next consumes
+// most of them, but returns global headers.
+func isMetadata(flag byte) bool {
+	switch flag {
+	case TypeXGlobalHeader, TypeXHeader, TypeGNULongName, TypeGNULongLink,
+		'V': // GNU volume header
+		return true
+	}
+	return false
+}
+
+// extractEntry extracts the entry described by hdr, whose contents are
+// read from r.
+func extractEntry(x *extract.Extractor, hdr *Header, maxFileSize int64, r io.Reader) error {
+	name, ok := extract.LocalName(hdr.Name)
+	if !ok {
+		return ErrInsecurePath
+	}
+	perm := hdr.FileInfo().Mode().Perm()
+	if hdr.Typeflag == TypeDir {
+		return x.Mkdir(name, hdr.Name, perm, hdr.ModTime)
+	}
+	if name == "." {
+		return fs.ErrExist
+	}
+
+	switch hdr.Typeflag {
+	case TypeReg, TypeGNUSparse:
+		if maxFileSize > 0 && hdr.Size > maxFileSize {
+			return ErrLimitExceeded
+		}
+		return x.WriteFile(name, r, perm, hdr.ModTime)
+	case TypeSymlink:
+		if !x.LocalLink(name, hdr.Linkname) {
+			return ErrInsecurePath
+		}
+		return x.Symlink(name, hdr.Linkname)
+	case TypeLink:
+		target, ok := extract.LocalName(hdr.Linkname)
+		if !ok {
+			return ErrInsecurePath
+		}
+		return x.Link(name, target)
+	}
+	return errUnsupportedType
+}
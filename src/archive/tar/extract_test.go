@@ -0,0 +1,240 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tar
+
+import (
+	"bytes"
+	"errors"
+	"internal/testenv"
+	"io/fs"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// makeTar returns a tar archive containing the given headers.
+// The contents of regular files are their names.
+func makeTar(t *testing.T, hdrs []*Header) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := NewWriter(&buf)
+	for _, hdr := range hdrs {
+		if hdr.Typeflag == TypeReg && hdr.Size == 0 {
+			hdr.Size = int64(len(hdr.Name))
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag == TypeReg {
+			if _, err := tw.Write([]byte(strings.Repeat(hdr.Name, int(hdr.Size))[:hdr.Size])); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func openTestRoot(t *testing.T) *os.Root {
+	t.Helper()
+	root, err := os.OpenRoot(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { root.Close() })
+	return root
+}
+
+func TestExtractTo(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	buf := makeTar(t, []*Header{
+		{Name: "./dir/", Typeflag: TypeDir, Mode: 0o750, ModTime: mtime},
+		{Name: "./dir/file", Typeflag: TypeReg, Mode: 0o640, ModTime: mtime},
+		{Name: "dir/link", Typeflag: TypeSymlink, Linkname: "file", ModTime: mtime},
+		{Name: "hard", Typeflag: TypeLink, Linkname: "dir/file", ModTime: mtime},
+		{Name: "implicit/sub/file", Typeflag: TypeReg, Mode: 0o600, ModTime: mtime},
+	})
+	root := openTestRoot(t)
+	if err := NewReader(buf).ExtractTo(root, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"dir/file", "dir/link", "hard"} {
+		if b, err := root.ReadFile(name); err != nil || string(b) != "./dir/file" {
+			t.Errorf("ReadFile(%q) = %q, %v; want %q", name, b, err, "./dir/file")
+		}
+	}
+	if b, err := root.ReadFile("implicit/sub/file"); err != nil || string(b) != "implicit/sub/file" {
+		t.Errorf("ReadFile(implicit/sub/file) = %q, %v", b, err)
+	}
+	if target, err := root.Readlink("dir/link"); err != nil || target != "file" {
+		t.Errorf("Readlink(dir/link) = %q, %v; want %q", target, err, "file")
+	}
+	for _, tt := range []struct {
+		name string
+		mode fs.FileMode
+	}{
+		{"dir", fs.ModeDir | 0o750},
+		{"dir/file", 0o640},
+		{"implicit/sub/file", 0o600},
+	} {
+		info, err := root.Stat(tt.name)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if runtime.GOOS != "windows" && runtime.GOOS != "plan9" && info.Mode() != tt.mode {
+			t.Errorf("Stat(%q).Mode() = %v, want %v", tt.name, info.Mode(), tt.mode)
+		}
+		if !info.ModTime().Equal(mtime) {
+			t.Errorf("Stat(%q).ModTime() = %v, want %v", tt.name, info.ModTime(), mtime)
+		}
+	}
+}
+
+func TestExtractToGlobalHeader(t *testing.T) {
+	// git archive writes a global header with the commit ID.
+	buf := makeTar(t, []*Header{
+		{Name: "pax_global_header", Typeflag: TypeXGlobalHeader, PAXRecords: map[string]string{"comment": "0123456789abcdef"}},
+		{Name: "file", Typeflag: TypeReg, Mode: 0o644},
+	})
+	root := openTestRoot(t)
+	if err := NewReader(buf).ExtractTo(root, &ExtractOptions{MaxEntries: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := root.ReadFile("file"); err != nil || string(b) != "file" {
+		t.Errorf("ReadFile(file) = %q, %v; want %q", b, err, "file")
+	}
+	if _, err := root.Lstat("pax_global_header"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Lstat(pax_global_header) = %v, want ErrNotExist", err)
+	}
+}
+
+func TestExtractToInsecure(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+	buf := makeTar(t, []*Header{
+		{Name: "../escape", Typeflag: TypeReg},
+		{Name: "/abs", Typeflag: TypeReg},
+		{Name: "a/../../escape", Typeflag: TypeReg},
+		{Name: "uplink", Typeflag: TypeSymlink, Linkname: "../outside"},
+		{Name: "dir/uplink", Typeflag: TypeSymlink, Linkname: "../../outside"},
+		{Name: "abslink", Typeflag: TypeSymlink, Linkname: "/etc/passwd"},
+		{Name: "hardlink", Typeflag: TypeLink, Linkname: "../outside"},
+		{Name: "fifo", Typeflag: TypeFifo},
+		{Name: "dir/ok", Typeflag: TypeReg},
+		{Name: "dir/okup", Typeflag: TypeSymlink, Linkname: "../dir/ok"},
+	})
+	root := openTestRoot(t)
+	err := NewReader(buf).ExtractTo(root, nil)
+	if err == nil {
+		t.Fatal("ExtractTo succeeded, want errors")
+	}
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+	want := []string{"../escape", "/abs", "a/../../escape", "uplink", "dir/uplink", "abslink", "hardlink", "fifo"}
+	if len(errs) != len(want) {
+		t.Fatalf("ExtractTo returned %d errors, want %d:\n%v", len(errs), len(want), err)
+	}
+	for i, err := range errs {
+		pe, ok := errors.AsType[*fs.PathError](err)
+		if !ok || pe.Op != "extract" || pe.Path != want[i] {
+			t.Errorf("error %d = %v, want PathError for %q", i, err, want[i])
+			continue
+		}
+		if want[i] != "fifo" && !errors.Is(err, ErrInsecurePath) {
+			t.Errorf("error %d = %v, want ErrInsecurePath", i, err)
+		}
+	}
+	if b, err := root.ReadFile("dir/okup"); err != nil || string(b) != "dir/ok" {
+		t.Errorf("ReadFile(dir/okup) = %q, %v; want %q", b, err, "dir/ok")
+	}
+	for _, name := range []string{"uplink", "abslink", "hardlink", "fifo"} {
+		if _, err := root.Lstat(name); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Lstat(%q) = %v, want ErrNotExist", name, err)
+		}
+	}
+}
+
+func TestExtractToSymlinkParent(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+	// x/x/y would be created as y, and its target refer to the
+	// parent of the root.
+	buf := makeTar(t, []*Header{
+		{Name: "x", Typeflag: TypeSymlink, Linkname: "."},
+		{Name: "x/x/y", Typeflag: TypeSymlink, Linkname: "../.."},
+	})
+	root := openTestRoot(t)
+	err := NewReader(buf).ExtractTo(root, nil)
+	if !errors.Is(err, ErrInsecurePath) {
+		t.Errorf("ExtractTo = %v, want ErrInsecurePath", err)
+	}
+	if _, err := root.Lstat("y"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Lstat(y) = %v, want ErrNotExist", err)
+	}
+}
+
+func TestExtractToLimits(t *testing.T) {
+	hdrs := []*Header{
+		{Name: "a", Typeflag: TypeReg, Size: 10},
+		{Name: "b", Typeflag: TypeReg, Size: 10},
+		{Name: "c", Typeflag: TypeReg, Size: 10},
+	}
+	for _, tt := range []struct {
+		opts    ExtractOptions
+		created []string
+		failed  string
+	}{
+		{ExtractOptions{}, []string{"a", "b", "c"}, ""},
+		{ExtractOptions{MaxEntries: 2}, []string{"a", "b"}, "c"},
+		{ExtractOptions{MaxFileSize: 9}, nil, "a"},
+		{ExtractOptions{MaxFileSize: 10}, []string{"a", "b", "c"}, ""},
+		{ExtractOptions{MaxTotalSize: 25}, []string{"a", "b"}, "c"},
+	} {
+		root := openTestRoot(t)
+		err := NewReader(makeTar(t, hdrs)).ExtractTo(root, &tt.opts)
+		if tt.failed == "" {
+			if err != nil {
+				t.Errorf("ExtractTo(%+v) = %v", tt.opts, err)
+			}
+		} else if pe, ok := errors.AsType[*fs.PathError](err); !ok || pe.Path != tt.failed || !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("ExtractTo(%+v) = %v, want ErrLimitExceeded for %q", tt.opts, err, tt.failed)
+		}
+		entries, err := fs.ReadDir(root.FS(), ".")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		if strings.Join(names, ",") != strings.Join(tt.created, ",") {
+			t.Errorf("ExtractTo(%+v) created %q, want %q", tt.opts, names, tt.created)
+		}
+	}
+}
+
+func TestExtractToOverwrite(t *testing.T) {
+	root := openTestRoot(t)
+	if err := root.WriteFile("a", []byte("old"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	hdrs := []*Header{{Name: "a", Typeflag: TypeReg}}
+	if err := NewReader(makeTar(t, hdrs)).ExtractTo(root, nil); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("ExtractTo over existing file = %v, want ErrExist", err)
+	}
+	if b, _ := root.ReadFile("a"); string(b) != "old" {
+		t.Errorf("after failed ExtractTo, file contains %q, want %q", b, "old")
+	}
+	if err := NewReader(makeTar(t, hdrs)).ExtractTo(root, &ExtractOptions{Overwrite: true}); err != nil {
+		t.Errorf("ExtractTo with Overwrite = %v", err)
+	}
+	if b, _ := root.ReadFile("a"); string(b) != "a" {
+		t.Errorf("after ExtractTo with Overwrite, file contains %q, want %q", b, "a")
+	}
+}
@@ -7,6 +7,8 @@ package tar
 import (
 	"bytes"
 	"compress/bzip2"
+	"compress/gzip"
+	"compress/zstd"
 	"errors"
 	"fmt"
 	"hash/crc32"
@@ -1702,3 +1704,98 @@ func TestDisableInsecurePathCheck(t *testing.T) {
 		t.Fatalf("tr.Next with tarinsecurepath=1: got name %q, want %q", h.Name, name)
 	}
 }
+
+func TestNewCompressedReader(t *testing.T) {
+	raw, err := os.ReadFile("testdata/gnu.tar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bz, err := os.ReadFile("testdata/gnu.tar.bz2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gz, zs bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	gw.Write(raw)
+	gw.Close()
+	zw := zstd.NewWriter(&zs)
+	zw.Write(raw)
+	zw.Close()
+
+	// readAll returns the names and contents of the entries in tr.
+	readAll := func(tr *Reader) (string, error) {
+		var sb strings.Builder
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return sb.String(), nil
+			}
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(hdr.Name + ":")
+			if _, err := io.Copy(&sb, tr); err != nil {
+				return "", err
+			}
+		}
+	}
+	want, err := readAll(NewReader(bytes.NewReader(raw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		data []byte
+	}{
+		{"uncompressed", raw},
+		{"gzip", gz.Bytes()},
+		{"bzip2", bz},
+		{"zstd", zs.Bytes()},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			tr, err := NewCompressedReader(bytes.NewReader(test.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := readAll(tr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+	}
+
+	xz := []byte("\xfd7zXZ\x00\x00\x04")
+	if _, err := NewCompressedReader(bytes.NewReader(xz)); err == nil {
+		t.Error("xz stream accepted")
+	}
+	if _, err := NewCompressedReader(bytes.NewReader(magicGzip)); err == nil {
+		t.Error("truncated gzip stream accepted")
+	}
+	tr, err := NewCompressedReader(bytes.NewReader(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tr.Next(); err != io.EOF {
+		t.Errorf("Next on empty stream = %v, want io.EOF", err)
+	}
+
+	// An uncompressed archive whose first name starts like a bzip2 stream.
+	var plain bytes.Buffer
+	tw := NewWriter(&plain)
+	if err := tw.WriteHeader(&Header{Name: "BZhello", Mode: 0o644, Size: 2}); err != nil {
+		t.Fatal(err)
+	}
+	tw.Write([]byte("hi"))
+	tw.Close()
+	tr, err = NewCompressedReader(&plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := readAll(tr); err != nil || got != "BZhello:hi" {
+		t.Errorf("uncompressed archive starting with BZh: got %q, %v; want %q", got, err, "BZhello:hi")
+	}
+}
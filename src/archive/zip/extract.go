@@ -0,0 +1,137 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zip
+
+import (
+	"archive/internal/extract"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// ErrLimitExceeded is returned by [Reader.ExtractTo] when the archive
+// exceeds one of the limits in its [ExtractOptions].
+var ErrLimitExceeded = errors.New("zip: extraction limit exceeded")
+
+var errUnsupportedType = errors.New("zip: unsupported file type")
+
+// maxSymlinkSize is the maximum length of the target of
+// a symbolic link extracted by ExtractTo.
+const maxSymlinkSize = 4096
+
+// ExtractOptions are options for [Reader.ExtractTo].
+// A zero ExtractOptions places no limits on the archive.
+type ExtractOptions struct {
+	// MaxEntries is the maximum number of entries in the archive.
+	// If MaxEntries is zero, there is no limit.
+	MaxEntries int
+
+	// MaxFileSize is the maximum uncompressed size in bytes
+	// of each extracted file.
+	// If MaxFileSize is zero, there is no limit.
+	MaxFileSize int64
+
+	// MaxTotalSize is the maximum total uncompressed size in bytes
+	// of the extracted files.
+	// If MaxTotalSize is zero, there is no limit.
+	MaxTotalSize int64
+
+	// Overwrite permits extracted files to replace existing files.
+	// Existing directories are always reused.
+	// If Overwrite is false, an entry naming an existing file
+	// other than a directory is not extracted.
+	Overwrite bool
+}
+
+// ExtractTo extracts the files in the archive into root.
+//
+// Directories, regular files, and symbolic links are extracted, with
+// the permission bits recorded in the archive (before umask) and their
+// modification times. Parent directories are created as needed.
+// Directories are made accessible to their owner while extraction is
+// in progress, and are given their recorded modes and times when it ends.
+//
+// Files are extracted only within root. Files whose names are not
+// local (as defined by [filepath.IsLocal] after removing any trailing
+// slash and cleaning), symbolic links whose targets refer to files
+// outside of root, and symbolic links in a directory reached through
+// another symbolic link, are not extracted, and report an error wrapping
+// [ErrInsecurePath]. Files of other types are not extracted either.
+//
+// The limits in opts apply to the uncompressed sizes recorded in the
+// archive, and to the number of bytes actually decompressed, so that
+// an archive cannot exceed them by misreporting its sizes.
+//
+// An error extracting a file does not stop the extraction of later
+// files. Such errors are reported as [*fs.PathError] values with the
+// Op "extract" and the file's name, and ExtractTo returns all of them
+// joined with [errors.Join]. Exceeding a limit in opts stops the
+// extraction, and is returned along with the errors reported for
+// earlier files. If opts is nil, no limits apply.
+func (r *Reader) ExtractTo(root *os.Root, opts *ExtractOptions) error {
+	var o ExtractOptions
+	if opts != nil {
+		o = *opts
+	}
+	x := extract.New(root, extract.Options{
+		MaxFileSize:  o.MaxFileSize,
+		MaxTotalSize: o.MaxTotalSize,
+		Overwrite:    o.Overwrite,
+	}, ErrLimitExceeded)
+	for i, f := range r.File {
+		if o.MaxEntries > 0 && i >= o.MaxEntries {
+			x.AddError(extract.EntryError(f.Name, ErrLimitExceeded))
+			break
+		}
+		if err := extractFile(x, f, o.MaxFileSize); err != nil {
+			err = extract.EntryError(f.Name, err)
+			x.AddError(err)
+			if errors.Is(err, ErrLimitExceeded) {
+				break
+			}
+		}
+	}
+	return x.Finish()
+}
+
+// extractFile extracts the file f.
+func extractFile(x *extract.Extractor, f *File, maxFileSize int64) error {
+	name, ok := extract.LocalName(f.Name)
+	if !ok {
+		return ErrInsecurePath
+	}
+	mode := f.Mode()
+	if strings.HasSuffix(f.Name, "/") || mode.IsDir() {
+		return x.Mkdir(name, f.Name, mode.Perm(), f.Modified)
+	}
+	if name == "." {
+		return fs.ErrExist
+	}
+	if mode.Type() != 0 && mode.Type() != fs.ModeSymlink {
+		return errUnsupportedType
+	}
+	if maxFileSize > 0 && f.UncompressedSize64 > uint64(maxFileSize) {
+		return ErrLimitExceeded
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if mode.Type() == fs.ModeSymlink {
+		target, err := io.ReadAll(io.LimitReader(rc, maxSymlinkSize+1))
+		if err != nil {
+			return err
+		}
+		if len(target) > maxSymlinkSize || !x.LocalLink(name, string(target)) {
+			return ErrInsecurePath
+		}
+		return x.Symlink(name, string(target))
+	}
+	return x.WriteFile(name, rc, mode.Perm(), f.Modified)
+}
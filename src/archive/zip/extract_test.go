@@ -0,0 +1,237 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zip
+
+import (
+	"bytes"
+	"errors"
+	"internal/testenv"
+	"io/fs"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// makeZip returns a reader for a zip archive containing the given files.
+// The contents of a regular file are its name, repeated to size bytes
+// if size is not zero; the contents of a symbolic link are its target.
+func makeZip(t *testing.T, files []extractTestFile) *Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := NewWriter(&buf)
+	for _, f := range files {
+		h := &FileHeader{Name: f.name, Method: Deflate, Modified: f.modTime}
+		h.SetMode(f.mode)
+		w, err := zw.CreateHeader(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data := f.name
+		if f.size > 0 {
+			data = strings.Repeat(f.name, f.size)[:f.size]
+		}
+		if f.mode.Type() == fs.ModeSymlink {
+			data = f.target
+		}
+		if !f.mode.IsDir() {
+			if _, err := w.Write([]byte(data)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil && err != ErrInsecurePath {
+		t.Fatal(err)
+	}
+	return r
+}
+
+type extractTestFile struct {
+	name    string
+	mode    fs.FileMode
+	target  string
+	size    int
+	modTime time.Time
+}
+
+func openTestRoot(t *testing.T) *os.Root {
+	t.Helper()
+	root, err := os.OpenRoot(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { root.Close() })
+	return root
+}
+
+func TestExtractTo(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+	mtime := time.Date(2020, 1, 2, 3, 4, 6, 0, time.UTC)
+	r := makeZip(t, []extractTestFile{
+		{name: "dir/", mode: fs.ModeDir | 0o750, modTime: mtime},
+		{name: "dir/file", mode: 0o640, modTime: mtime},
+		{name: "dir/link", mode: fs.ModeSymlink | 0o777, target: "file", modTime: mtime},
+		{name: "implicit/sub/file", mode: 0o600, modTime: mtime},
+	})
+	root := openTestRoot(t)
+	if err := r.ExtractTo(root, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"dir/file", "dir/link"} {
+		if b, err := root.ReadFile(name); err != nil || string(b) != "dir/file" {
+			t.Errorf("ReadFile(%q) = %q, %v; want %q", name, b, err, "dir/file")
+		}
+	}
+	if target, err := root.Readlink("dir/link"); err != nil || target != "file" {
+		t.Errorf("Readlink(dir/link) = %q, %v; want %q", target, err, "file")
+	}
+	for _, tt := range []struct {
+		name string
+		mode fs.FileMode
+	}{
+		{"dir", fs.ModeDir | 0o750},
+		{"dir/file", 0o640},
+		{"implicit/sub/file", 0o600},
+	} {
+		info, err := root.Stat(tt.name)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if runtime.GOOS != "windows" && runtime.GOOS != "plan9" && info.Mode() != tt.mode {
+			t.Errorf("Stat(%q).Mode() = %v, want %v", tt.name, info.Mode(), tt.mode)
+		}
+		if !info.ModTime().Equal(mtime) {
+			t.Errorf("Stat(%q).ModTime() = %v, want %v", tt.name, info.ModTime(), mtime)
+		}
+	}
+}
+
+func TestExtractToInsecure(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+	r := makeZip(t, []extractTestFile{
+		{name: "../escape", mode: 0o666},
+		{name: "/abs", mode: 0o666},
+		{name: "a/../../escape", mode: 0o666},
+		{name: "uplink", mode: fs.ModeSymlink | 0o777, target: "../outside"},
+		{name: "dir/uplink", mode: fs.ModeSymlink | 0o777, target: "../../outside"},
+		{name: "abslink", mode: fs.ModeSymlink | 0o777, target: "/etc/passwd"},
+		{name: "fifo", mode: fs.ModeNamedPipe | 0o666},
+		{name: "dir/ok", mode: 0o666},
+		{name: "dir/okup", mode: fs.ModeSymlink | 0o777, target: "../dir/ok"},
+	})
+	root := openTestRoot(t)
+	err := r.ExtractTo(root, nil)
+	if err == nil {
+		t.Fatal("ExtractTo succeeded, want errors")
+	}
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+	want := []string{"../escape", "/abs", "a/../../escape", "uplink", "dir/uplink", "abslink", "fifo"}
+	if len(errs) != len(want) {
+		t.Fatalf("ExtractTo returned %d errors, want %d:\n%v", len(errs), len(want), err)
+	}
+	for i, err := range errs {
+		pe, ok := errors.AsType[*fs.PathError](err)
+		if !ok || pe.Op != "extract" || pe.Path != want[i] {
+			t.Errorf("error %d = %v, want PathError for %q", i, err, want[i])
+			continue
+		}
+		if want[i] != "fifo" && !errors.Is(err, ErrInsecurePath) {
+			t.Errorf("error %d = %v, want ErrInsecurePath", i, err)
+		}
+	}
+	if b, err := root.ReadFile("dir/okup"); err != nil || string(b) != "dir/ok" {
+		t.Errorf("ReadFile(dir/okup) = %q, %v; want %q", b, err, "dir/ok")
+	}
+	for _, name := range []string{"uplink", "abslink", "fifo"} {
+		if _, err := root.Lstat(name); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Lstat(%q) = %v, want ErrNotExist", name, err)
+		}
+	}
+}
+
+func TestExtractToSymlinkParent(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+	// x/x/y would be created as y, and its target refer to the
+	// parent of the root.
+	r := makeZip(t, []extractTestFile{
+		{name: "x", mode: fs.ModeSymlink | 0o777, target: "."},
+		{name: "x/x/y", mode: fs.ModeSymlink | 0o777, target: "../.."},
+	})
+	root := openTestRoot(t)
+	err := r.ExtractTo(root, nil)
+	if !errors.Is(err, ErrInsecurePath) {
+		t.Errorf("ExtractTo = %v, want ErrInsecurePath", err)
+	}
+	if _, err := root.Lstat("y"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Lstat(y) = %v, want ErrNotExist", err)
+	}
+}
+
+func TestExtractToLimits(t *testing.T) {
+	files := []extractTestFile{
+		{name: "a", mode: 0o666, size: 10},
+		{name: "b", mode: 0o666, size: 10},
+		{name: "c", mode: 0o666, size: 10},
+	}
+	for _, tt := range []struct {
+		opts    ExtractOptions
+		created []string
+		failed  string
+	}{
+		{ExtractOptions{}, []string{"a", "b", "c"}, ""},
+		{ExtractOptions{MaxEntries: 2}, []string{"a", "b"}, "c"},
+		{ExtractOptions{MaxFileSize: 9}, nil, "a"},
+		{ExtractOptions{MaxFileSize: 10}, []string{"a", "b", "c"}, ""},
+		{ExtractOptions{MaxTotalSize: 25}, []string{"a", "b"}, "c"},
+	} {
+		root := openTestRoot(t)
+		err := makeZip(t, files).ExtractTo(root, &tt.opts)
+		if tt.failed == "" {
+			if err != nil {
+				t.Errorf("ExtractTo(%+v) = %v", tt.opts, err)
+			}
+		} else if pe, ok := errors.AsType[*fs.PathError](err); !ok || pe.Path != tt.failed || !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("ExtractTo(%+v) = %v, want ErrLimitExceeded for %q", tt.opts, err, tt.failed)
+		}
+		entries, err := fs.ReadDir(root.FS(), ".")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		if strings.Join(names, ",") != strings.Join(tt.created, ",") {
+			t.Errorf("ExtractTo(%+v) created %q, want %q", tt.opts, names, tt.created)
+		}
+	}
+}
+
+func TestExtractToOverwrite(t *testing.T) {
+	root := openTestRoot(t)
+	if err := root.WriteFile("a", []byte("old"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	files := []extractTestFile{{name: "a", mode: 0o666}}
+	if err := makeZip(t, files).ExtractTo(root, nil); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("ExtractTo over existing file = %v, want ErrExist", err)
+	}
+	if b, _ := root.ReadFile("a"); string(b) != "old" {
+		t.Errorf("after failed ExtractTo, file contains %q, want %q", b, "old")
+	}
+	if err := makeZip(t, files).ExtractTo(root, &ExtractOptions{Overwrite: true}); err != nil {
+		t.Errorf("ExtractTo with Overwrite = %v", err)
+	}
+	if b, _ := root.ReadFile("a"); string(b) != "a" {
+		t.Errorf("after ExtractTo with Overwrite, file contains %q, want %q", b, "a")
+	}
+}
@@ -6,6 +6,7 @@ package zip
 
 import (
 	"compress/flate"
+	"compress/zstd"
 	"errors"
 	"io"
 	"sync"
@@ -101,11 +102,57 @@ func (r *pooledFlateReader) Close() error {
 	return err
 }
 
+var zstdReaderPool sync.Pool
+
+func newZstdReader(r io.Reader) io.ReadCloser {
+	zr, ok := zstdReaderPool.Get().(*zstd.Reader)
+	if ok {
+		zr.Reset(r)
+	} else {
+		zr = zstd.NewReader(r)
+	}
+	return &pooledZstdReader{zr: zr}
+}
+
+type pooledZstdReader struct {
+	mu sync.Mutex // guards Close and Read
+	zr *zstd.Reader
+}
+
+func (r *pooledZstdReader) Read(p []byte) (n int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.zr == nil {
+		return 0, errors.New("Read after Close")
+	}
+	return r.zr.Read(p)
+}
+
+func (r *pooledZstdReader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var err error
+	if r.zr != nil {
+		err = r.zr.Close()
+		zstdReaderPool.Put(r.zr)
+		r.zr = nil
+	}
+	return err
+}
+
 var (
 	compressors   sync.Map // map[uint16]Compressor
 	decompressors sync.Map // map[uint16]Decompressor
 )
 
+// defaultDecompressors are built in decompressors that are consulted
+// after those in decompressors. They were added after programs could
+// register their own implementations for the same methods, so
+// RegisterDecompressor may replace them rather than panicking.
+var defaultDecompressors = map[uint16]Decompressor{
+	Zstd: newZstdReader,
+}
+
 func init() {
 	compressors.Store(Store, Compressor(func(w io.Writer) (io.WriteCloser, error) { return &nopCloser{w}, nil }))
 	compressors.Store(Deflate, Compressor(func(w io.Writer) (io.WriteCloser, error) { return newFlateWriter(w), nil }))
@@ -116,6 +163,8 @@ func init() {
 
 // RegisterDecompressor allows custom decompressors for a specified method ID.
 // The common methods [Store] and [Deflate] are built in.
+// The [Zstd] method is also built in, but may be replaced by
+// registering a different decompressor for it.
 func RegisterDecompressor(method uint16, dcomp Decompressor) {
 	if _, dup := decompressors.LoadOrStore(method, dcomp); dup {
 		panic("decompressor already registered")
@@ -141,7 +190,7 @@ func compressor(method uint16) Compressor {
 func decompressor(method uint16) Decompressor {
 	di, ok := decompressors.Load(method)
 	if !ok {
-		return nil
+		return defaultDecompressors[method]
 	}
 	return di.(Decompressor)
 }
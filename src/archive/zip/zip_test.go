@@ -9,6 +9,7 @@ package zip
 import (
 	"bytes"
 	"cmp"
+	"compress/zstd"
 	"errors"
 	"fmt"
 	"hash"
@@ -817,3 +818,64 @@ func (zeros) Read(p []byte) (int, error) {
 	clear(p)
 	return len(p), nil
 }
+
+func TestZstd(t *testing.T) {
+	content := bytes.Repeat([]byte("zstd compressed zip content\n"), 1000)
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.RegisterCompressor(Zstd, func(out io.Writer) (io.WriteCloser, error) {
+		return zstd.NewWriter(out), nil
+	})
+	fw, err := w.CreateHeader(&FileHeader{Name: "file.txt", Method: Zstd})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fw.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := r.File[0]
+	if f.Method != Zstd {
+		t.Fatalf("Method = %d, want %d", f.Method, Zstd)
+	}
+	if f.CompressedSize64 >= f.UncompressedSize64/10 {
+		t.Errorf("compressed %d bytes to %d", f.UncompressedSize64, f.CompressedSize64)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Error("content mismatch")
+	}
+}
+
+func TestRegisterDecompressorZstd(t *testing.T) {
+	// Programs that registered their own zstd decompressor
+	// before it was built in must continue to work.
+	defer decompressors.Delete(Zstd)
+	called := false
+	RegisterDecompressor(Zstd, func(r io.Reader) io.ReadCloser {
+		called = true
+		return io.NopCloser(r)
+	})
+	decompressor(Zstd)(strings.NewReader(""))
+	if !called {
+		t.Error("registered decompressor not used")
+	}
+}
@@ -37,6 +37,7 @@ var passFlagToTest = map[string]bool{
 	"skip":                 true,
 	"timeout":              true,
 	"trace":                true,
+	"update-golden":        true,
 	"v":                    true,
 }
 
@@ -347,6 +347,11 @@ control the execution of any test:
 	    If d is 0, the timeout is disabled.
 	    The default is 10 minutes (10m).
 
+	-update-golden
+	    Write the output passed to t.Golden to the golden files in
+	    testdata, instead of comparing it with their contents.
+	    See 'go doc testing.T.Golden'.
+
 	-v
 	    Verbose output: log all tests as they are run. Also print all
 	    text from Log and Logf calls even if the test succeeds.
@@ -0,0 +1,78 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package zstd implements reading and writing of Zstandard format
+// compressed data, as specified in RFC 8878.
+//
+// A Zstandard stream is a sequence of frames. A [Reader] returns the
+// concatenation of the uncompressed data of each frame, and a [Writer]
+// writes a single frame.
+//
+// Both sides support dictionaries, which improve the compression of
+// small inputs that resemble the dictionary. A dictionary may be
+// either a formatted dictionary, such as one produced by
+// "zstd --train", or arbitrary content.
+package zstd
+
+import (
+	"internal/zstd"
+	"io"
+)
+
+// A Reader is an [io.Reader] that can be read to retrieve
+// uncompressed data from a Zstandard stream.
+//
+// Frames that include a checksum of their uncompressed data are
+// verified when the end of the frame is reached. Clients should treat
+// data returned by [Reader.Read] as tentative until they receive the
+// [io.EOF] marking the end of the data.
+type Reader struct {
+	z zstd.Reader
+}
+
+// NewReader creates a new [Reader] reading the given reader.
+//
+// It is the caller's responsibility to call [Reader.Close] when done.
+func NewReader(r io.Reader) *Reader {
+	z := new(Reader)
+	z.z.Reset(r)
+	return z
+}
+
+// NewReaderDict is like [NewReader] but decompresses using the
+// dictionary dict. The dictionary is used for frames that were
+// compressed with it, and for frames that do not name a dictionary.
+// A frame that names a different dictionary is reported as an error.
+//
+// The error returned will be nil if the dictionary is valid.
+func NewReaderDict(r io.Reader, dict []byte) (*Reader, error) {
+	d, err := zstd.ParseDict(dict)
+	if err != nil {
+		return nil, err
+	}
+	z := NewReader(r)
+	z.z.SetDict(d)
+	return z, nil
+}
+
+// Reset discards the [Reader] z's state and makes it equivalent to the
+// result of its original state from [NewReader] or [NewReaderDict],
+// but reading from r instead. This permits reusing a [Reader] rather
+// than allocating a new one.
+func (z *Reader) Reset(r io.Reader) {
+	z.z.Reset(r)
+}
+
+// Read implements [io.Reader], reading uncompressed bytes from its
+// underlying reader.
+func (z *Reader) Read(p []byte) (int, error) {
+	return z.z.Read(p)
+}
+
+// Close closes the [Reader]. It does not close the underlying reader.
+// In order for the checksums to be verified, the reader must be
+// fully consumed until the [io.EOF].
+func (z *Reader) Close() error {
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"fmt"
+	"internal/zstd"
+	"io"
+)
+
+// These constants select the compression level.
+// Higher levels compress better, and more slowly.
+const (
+	NoCompression      = 0
+	BestSpeed          = 1
+	BestCompression    = zstd.MaxLevel
+	DefaultCompression = -1
+)
+
+// defaultLevel is the level used for [DefaultCompression].
+const defaultLevel = 3
+
+// A Writer is an [io.WriteCloser].
+// Writes to a Writer are compressed and written to w.
+type Writer struct {
+	// Checksum controls whether a checksum of the uncompressed data
+	// is written at the end of the stream, to be verified by the
+	// reader. It is true for a new Writer. Callers that wish to
+	// change it must do so before the first call to Write, Flush,
+	// or Close.
+	Checksum bool
+
+	z *zstd.Writer
+}
+
+// NewWriter returns a new [Writer].
+// Writes to the returned writer are compressed and written to w.
+//
+// It is the caller's responsibility to call Close on the [Writer] when done.
+// Writes may be buffered and not flushed until Close.
+func NewWriter(w io.Writer) *Writer {
+	z, _ := NewWriterLevel(w, DefaultCompression)
+	return z
+}
+
+// NewWriterLevel is like [NewWriter] but specifies the compression level
+// instead of assuming [DefaultCompression].
+//
+// The compression level can be [DefaultCompression], [NoCompression],
+// or any integer value between [BestSpeed] and [BestCompression] inclusive.
+// The error returned will be nil if the level is valid.
+func NewWriterLevel(w io.Writer, level int) (*Writer, error) {
+	return newWriter(w, level, nil)
+}
+
+// NewWriterDict is like [NewWriterLevel] but compresses using the
+// dictionary dict. The compressed data can only be decompressed by a
+// [Reader] created by [NewReaderDict] with the same dictionary.
+//
+// The error returned will be nil if the level and dictionary are valid.
+func NewWriterDict(w io.Writer, level int, dict []byte) (*Writer, error) {
+	d, err := zstd.ParseDict(dict)
+	if err != nil {
+		return nil, err
+	}
+	return newWriter(w, level, d)
+}
+
+func newWriter(w io.Writer, level int, dict *zstd.Dict) (*Writer, error) {
+	if level == DefaultCompression {
+		level = defaultLevel
+	}
+	if level < NoCompression || level > BestCompression {
+		return nil, fmt.Errorf("zstd: invalid compression level: %d", level)
+	}
+	return &Writer{
+		Checksum: true,
+		z:        zstd.NewWriter(w, level, dict),
+	}, nil
+}
+
+// Reset discards the [Writer] z's state and makes it equivalent to the
+// result of its original state from [NewWriter], [NewWriterLevel], or
+// [NewWriterDict], but writing to w instead. This permits reusing a
+// [Writer] rather than allocating a new one.
+func (z *Writer) Reset(w io.Writer) {
+	z.Checksum = true
+	z.z.Reset(w)
+}
+
+// Write writes a compressed form of p to the underlying [io.Writer]. The
+// compressed bytes are not necessarily flushed until the [Writer] is closed.
+func (z *Writer) Write(p []byte) (int, error) {
+	z.z.SetChecksum(z.Checksum)
+	return z.z.Write(p)
+}
+
+// Flush flushes any pending compressed data to the underlying writer.
+//
+// It is useful mainly in compressed network protocols, to ensure that
+// a remote reader has enough data to reconstruct a packet. Flush does
+// not return until the data has been written. If the underlying
+// writer returns an error, Flush returns that error.
+func (z *Writer) Flush() error {
+	z.z.SetChecksum(z.Checksum)
+	return z.z.Flush()
+}
+
+// Close closes the [Writer] by flushing any unwritten data to the
+// underlying [io.Writer] and writing the end of the frame.
+// It does not close the underlying [io.Writer].
+func (z *Writer) Close() error {
+	z.z.SetChecksum(z.Checksum)
+	return z.z.Close()
+}
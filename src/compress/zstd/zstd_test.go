@@ -0,0 +1,165 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func readTestData(t *testing.T) []byte {
+	data, err := os.ReadFile("../testdata/e.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestRoundTrip(t *testing.T) {
+	data := readTestData(t)
+	levels := []int{DefaultCompression, NoCompression, BestSpeed, 5, BestCompression}
+	for _, level := range levels {
+		var buf bytes.Buffer
+		w, err := NewWriterLevel(&buf, level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		r := NewReader(&buf)
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("level %d: %v", level, err)
+		}
+		if err := r.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("level %d: round trip mismatch", level)
+		}
+	}
+}
+
+func TestInvalidLevel(t *testing.T) {
+	for _, level := range []int{-2, BestCompression + 1} {
+		if _, err := NewWriterLevel(io.Discard, level); err == nil {
+			t.Errorf("NewWriterLevel(%d) succeeded", level)
+		}
+	}
+}
+
+func TestConcatenatedFrames(t *testing.T) {
+	var buf bytes.Buffer
+	for _, s := range []string{"hello, ", "", "world"} {
+		w := NewWriter(&buf)
+		io.WriteString(w, s)
+		w.Close()
+	}
+	got, err := io.ReadAll(NewReader(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hello, world" {
+		t.Errorf("got %q, want %q", got, "hello, world")
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	data := []byte(strings.Repeat("checksum test data. ", 100))
+	for _, checksum := range []bool{false, true} {
+		var buf bytes.Buffer
+		w := NewWriter(&buf)
+		w.Checksum = checksum
+		w.Write(data)
+		w.Close()
+
+		// Corrupt the last byte, which is part of the checksum
+		// if there is one.
+		compressed := buf.Bytes()
+		compressed[len(compressed)-1] ^= 0xff
+
+		_, err := io.ReadAll(NewReader(bytes.NewReader(compressed)))
+		if checksum && err == nil {
+			t.Error("corrupted checksum not detected")
+		}
+	}
+}
+
+func TestDict(t *testing.T) {
+	data := readTestData(t)
+	dict := data[:4096]
+	sample := data[1000:2000]
+
+	var buf bytes.Buffer
+	w, err := NewWriterDict(&buf, BestCompression, dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(sample)
+	w.Close()
+	if buf.Len() > len(sample)/10 {
+		t.Errorf("compressed %d bytes to %d using dictionary", len(sample), buf.Len())
+	}
+
+	r, err := NewReaderDict(bytes.NewReader(buf.Bytes()), dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, sample) {
+		t.Error("round trip mismatch")
+	}
+
+	// Without the dictionary the references are out of range.
+	if _, err := io.ReadAll(NewReader(bytes.NewReader(buf.Bytes()))); err == nil {
+		t.Error("reading without dictionary succeeded")
+	}
+}
+
+func TestReset(t *testing.T) {
+	data := readTestData(t)
+	var buf1, buf2 bytes.Buffer
+	w := NewWriter(&buf1)
+	w.Checksum = false
+	w.Write(data)
+	w.Close()
+	w.Reset(&buf2)
+	w.Write(data)
+	w.Close()
+	if buf2.Len() != buf1.Len()+4 {
+		t.Errorf("got %d bytes after Reset, want %d with checksum", buf2.Len(), buf1.Len()+4)
+	}
+
+	r := NewReader(&buf1)
+	for _, buf := range []*bytes.Buffer{&buf1, &buf2} {
+		r.Reset(buf)
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Error("round trip mismatch")
+		}
+	}
+}
+
+func TestWriteAfterClose(t *testing.T) {
+	w := NewWriter(io.Discard)
+	w.Close()
+	if _, err := w.Write([]byte("x")); err == nil {
+		t.Error("Write after Close succeeded")
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package chacha20poly1305 implements the ChaCha20-Poly1305 AEAD and its
+// extended nonce variant XChaCha20-Poly1305, as specified in RFC 8439 and
+// draft-irtf-cfrg-xchacha-03.
+//
+// ChaCha20-Poly1305 is not an approved FIPS 140-3 algorithm, and the functions
+// in this package return an error if [crypto/fips140.Enforced] is true.
+package chacha20poly1305
+
+import (
+	"crypto/cipher"
+	"crypto/internal/fips140/alias"
+	"crypto/internal/rand"
+	"errors"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	// KeySize is the size of the key used by this AEAD, in bytes.
+	KeySize = 32
+
+	// NonceSize is the size of the nonce used with the standard variant of this
+	// AEAD, in bytes.
+	//
+	// Note that this is too short to be safely generated at random if the same
+	// key is reused more than 2³² times.
+	NonceSize = 12
+
+	// NonceSizeX is the size of the nonce used with the XChaCha20-Poly1305
+	// variant of this AEAD, in bytes.
+	NonceSizeX = 24
+
+	// Overhead is the size of the Poly1305 authentication tag, and the
+	// difference between a ciphertext length and its plaintext.
+	Overhead = 16
+)
+
+// New returns a ChaCha20-Poly1305 AEAD that uses the given 256-bit key.
+func New(key []byte) (cipher.AEAD, error) {
+	return chacha20poly1305.New(key)
+}
+
+// NewX returns a XChaCha20-Poly1305 AEAD that uses the given 256-bit key.
+//
+// XChaCha20-Poly1305 is a ChaCha20-Poly1305 variant that takes a longer nonce,
+// suitable to be generated randomly without risk of collisions. It should be
+// preferred when nonce uniqueness cannot be trivially ensured, or whenever
+// nonces are randomly generated. See also [NewXWithRandomNonce].
+func NewX(key []byte) (cipher.AEAD, error) {
+	return chacha20poly1305.NewX(key)
+}
+
+// NewXWithRandomNonce returns a XChaCha20-Poly1305 AEAD that uses the given
+// 256-bit key, with randomly-generated nonces.
+//
+// It generates a random 192-bit nonce, which is prepended to the ciphertext by
+// Seal, and is extracted from the ciphertext by Open. The NonceSize of the AEAD
+// is zero, while the Overhead is 40 bytes (the combination of nonce size and
+// tag size).
+//
+// Unlike [crypto/cipher.NewGCMWithRandomNonce], the nonce is long enough that
+// there is no practical limit on the number of messages encrypted with a key.
+func NewXWithRandomNonce(key []byte) (cipher.AEAD, error) {
+	x, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+	return xWithRandomNonce{x}, nil
+}
+
+type xWithRandomNonce struct {
+	aead cipher.AEAD
+}
+
+func (xWithRandomNonce) NonceSize() int {
+	return 0
+}
+
+func (xWithRandomNonce) Overhead() int {
+	return NonceSizeX + Overhead
+}
+
+func (x xWithRandomNonce) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != 0 {
+		panic("crypto/chacha20poly1305: non-empty nonce passed to XChaCha20-Poly1305 with random nonces")
+	}
+
+	ret, out := sliceForAppend(dst, NonceSizeX+len(plaintext)+Overhead)
+	if alias.InexactOverlap(out, plaintext) {
+		panic("crypto/chacha20poly1305: invalid buffer overlap of output and input")
+	}
+	if alias.AnyOverlap(out, additionalData) {
+		panic("crypto/chacha20poly1305: invalid buffer overlap of output and additional data")
+	}
+	nonce = out[:NonceSizeX]
+	ciphertext := out[NonceSizeX:]
+
+	// The AEAD interface allows using plaintext[:0] as dst, in which case the
+	// plaintext needs to be moved past the nonce before encrypting in place.
+	// See the equivalent discussion in crypto/cipher.gcmWithRandomNonce.
+	if alias.AnyOverlap(out, plaintext) {
+		copy(ciphertext, plaintext)
+		plaintext = ciphertext[:len(plaintext)]
+	}
+
+	rand.Reader.Read(nonce)
+	x.aead.Seal(ciphertext[:0], nonce, plaintext, additionalData)
+	return ret
+}
+
+var errOpen = errors.New("chacha20poly1305: message authentication failed")
+
+func (x xWithRandomNonce) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != 0 {
+		panic("crypto/chacha20poly1305: non-empty nonce passed to XChaCha20-Poly1305 with random nonces")
+	}
+	if len(ciphertext) < NonceSizeX+Overhead {
+		return nil, errOpen
+	}
+
+	ret, out := sliceForAppend(dst, len(ciphertext)-NonceSizeX-Overhead)
+	if alias.InexactOverlap(out, ciphertext) {
+		panic("crypto/chacha20poly1305: invalid buffer overlap of output and input")
+	}
+	if alias.AnyOverlap(out, additionalData) {
+		panic("crypto/chacha20poly1305: invalid buffer overlap of output and additional data")
+	}
+	nonce = ciphertext[:NonceSizeX]
+	ciphertext = ciphertext[NonceSizeX:]
+
+	// If there is any overlap at this point, it's because out starts at the
+	// beginning of the original ciphertext, so it has enough capacity to hold
+	// the sealed message moved over the nonce for in-place decryption.
+	if alias.AnyOverlap(out, ciphertext) {
+		nonce = append([]byte(nil), nonce...)
+		copy(out[:len(ciphertext)], ciphertext)
+		ciphertext = out[:len(ciphertext)]
+	}
+
+	if _, err := x.aead.Open(out[:0], nonce, ciphertext, additionalData); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// sliceForAppend takes a slice and a requested number of bytes. It returns a
+// slice with the contents of the given slice followed by that many bytes and a
+// second slice that aliases into it and contains only the extra bytes. If the
+// original slice has sufficient capacity then no allocation is performed.
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package chacha20poly1305_test
+
+import (
+	"bytes"
+	"crypto/chacha20poly1305"
+	"crypto/cipher"
+	"crypto/internal/cryptotest"
+	"encoding/hex"
+	"testing"
+)
+
+func fromHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+var testKey = fromHex("808182838485868788898a8b8c8d8e8f909192939495969798999a9b9c9d9e9f")
+
+func TestAEAD(t *testing.T) {
+	t.Run("ChaCha20Poly1305", func(t *testing.T) {
+		cryptotest.TestAEAD(t, func() (cipher.AEAD, error) { return chacha20poly1305.New(testKey) })
+	})
+	t.Run("XChaCha20Poly1305", func(t *testing.T) {
+		cryptotest.TestAEAD(t, func() (cipher.AEAD, error) { return chacha20poly1305.NewX(testKey) })
+	})
+	t.Run("XChaCha20Poly1305WithRandomNonce", func(t *testing.T) {
+		cryptotest.TestAEAD(t, func() (cipher.AEAD, error) { return chacha20poly1305.NewXWithRandomNonce(testKey) })
+	})
+}
+
+const (
+	testPlaintext = "Ladies and Gentlemen of the class of '99: If I could offer you only one tip for the future, sunscreen would be it."
+	testAD        = "50515253c0c1c2c3c4c5c6c7"
+)
+
+func TestVectors(t *testing.T) {
+	tests := []struct {
+		name  string
+		new   func([]byte) (cipher.AEAD, error)
+		nonce string
+		out   string
+	}{
+		{
+			// RFC 8439, Section 2.8.2.
+			"ChaCha20Poly1305", chacha20poly1305.New,
+			"070000004041424344454647",
+			"d31a8d34648e60db7b86afbc53ef7ec2a4aded51296e08fea9e2b5a736ee62d63dbea45e8ca9671282fafb69da92728b1a71de0a9e060b2905d6a5b67ecd3b3692ddbd7f2d778b8c9803aee328091b58fab324e4fad675945585808b4831d7bc3ff4def08e4b7a9de576d26586cec64b6116" +
+				"1ae10b594f09e26a7e902ecbd0600691",
+		},
+		{
+			// draft-irtf-cfrg-xchacha-03, Appendix A.3.1.
+			"XChaCha20Poly1305", chacha20poly1305.NewX,
+			"404142434445464748494a4b4c4d4e4f5051525354555657",
+			"bd6d179d3e83d43b9576579493c0e939572a1700252bfaccbed2902c21396cbb731c7f1b0b4aa6440bf3a82f4eda7e39ae64c6708c54c216cb96b72e1213b4522f8c9ba40db5d945b11b69b982c1bb9e3f3fac2bc369488f76b2383565d3fff921f9664c97637da9768812f615c68b13b52e" +
+				"c0875924c1c7987947deafd8780acf49",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aead, err := tt.new(testKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			nonce, ad, want := fromHex(tt.nonce), fromHex(testAD), fromHex(tt.out)
+			got := aead.Seal(nil, nonce, []byte(testPlaintext), ad)
+			if !bytes.Equal(got, want) {
+				t.Errorf("Seal = %x, want %x", got, want)
+			}
+			pt, err := aead.Open(nil, nonce, want, ad)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(pt) != testPlaintext {
+				t.Errorf("Open = %q, want %q", pt, testPlaintext)
+			}
+		})
+	}
+}
+
+func TestRandomNonce(t *testing.T) {
+	aead, err := chacha20poly1305.NewXWithRandomNonce(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, err := chacha20poly1305.NewX(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := aead.Seal(nil, nil, []byte(testPlaintext), nil)
+	b := aead.Seal(nil, nil, []byte(testPlaintext), nil)
+	if bytes.Equal(a[:chacha20poly1305.NonceSizeX], b[:chacha20poly1305.NonceSizeX]) {
+		t.Errorf("nonce was reused")
+	}
+	if len(a) != len(testPlaintext)+aead.Overhead() {
+		t.Errorf("ciphertext length = %d, want %d", len(a), len(testPlaintext)+aead.Overhead())
+	}
+
+	// The output must be a nonce followed by a regular XChaCha20-Poly1305
+	// ciphertext under that nonce.
+	pt, err := x.Open(nil, a[:chacha20poly1305.NonceSizeX], a[chacha20poly1305.NonceSizeX:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(pt) != testPlaintext {
+		t.Errorf("Open = %q, want %q", pt, testPlaintext)
+	}
+
+	if _, err := aead.Open(nil, nil, a[:chacha20poly1305.NonceSizeX+chacha20poly1305.Overhead-1], nil); err == nil {
+		t.Errorf("Open accepted a truncated ciphertext")
+	}
+}
+
+func TestBadKey(t *testing.T) {
+	for _, f := range []func([]byte) (cipher.AEAD, error){
+		chacha20poly1305.New, chacha20poly1305.NewX, chacha20poly1305.NewXWithRandomNonce,
+	} {
+		if _, err := f(testKey[:16]); err == nil {
+			t.Errorf("accepted a 128-bit key")
+		}
+	}
+}
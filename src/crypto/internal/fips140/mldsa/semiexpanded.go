@@ -19,7 +19,8 @@ import (
 // For a hot second, it looked like we could have all agreed to only use seeds,
 // but unfortunately OpenSSL and BouncyCastle lobbied hard against that during
 // the WGLC of the LAMPS IETF working group. Also, ACVP tests provide and expect
-// semi-expanded keys, so we implement them here for testing purposes.
+// semi-expanded keys, so we implement them here for testing purposes, and to
+// check the expanded keys which accompany seeds in PKCS #8.
 
 func semiExpandedPrivKeySize(p parameters) int {
 	k, l := p.k, p.l
@@ -116,6 +117,12 @@ func TestingOnlyNewPrivateKeyFromSemiExpanded(sk []byte) (*PrivateKey, error) {
 }
 
 func TestingOnlyPrivateKeySemiExpandedBytes(priv *PrivateKey) []byte {
+	return priv.SemiExpandedBytes()
+}
+
+// SemiExpandedBytes returns the semi-expanded encoding of priv, which
+// is checked against the seed when parsing PKCS #8 keys which carry both.
+func (priv *PrivateKey) SemiExpandedBytes() []byte {
 	k, l, η := priv.pub.p.k, priv.pub.p.l, priv.pub.p.η
 	sk := make([]byte, 0, semiExpandedPrivKeySize(priv.pub.p))
 	sk = append(sk, priv.pub.raw[:32]...) // ρ
@@ -0,0 +1,22 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package mldsaexpand provides the semi-expanded encoding of
+// [crypto/mldsa] private keys, which crypto/x509 needs to check
+// PKCS #8 keys, without adding it to the crypto/mldsa API.
+package mldsaexpand
+
+import (
+	fipsmldsa "crypto/internal/fips140/mldsa"
+	"crypto/mldsa"
+	_ "unsafe"
+)
+
+//go:linkname mldsaUnwrap
+func mldsaUnwrap(*mldsa.PrivateKey) *fipsmldsa.PrivateKey
+
+// PrivateKeyBytes returns the FIPS 204 semi-expanded encoding of priv.
+func PrivateKeyBytes(priv *mldsa.PrivateKey) []byte {
+	return mldsaUnwrap(priv).SemiExpandedBytes()
+}
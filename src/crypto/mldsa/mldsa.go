@@ -0,0 +1,238 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package mldsa implements the quantum-resistant digital signature algorithm
+// ML-DSA (formerly known as Dilithium), as specified in [NIST FIPS 204].
+//
+// Keys are generated and represented by a 32-byte seed, and signatures are
+// produced in the "pure" hedged mode: the message is signed directly, and
+// not pre-hashed by the caller. Most applications should use ML-DSA-65,
+// returned by [MLDSA65].
+//
+// [NIST FIPS 204]: https://doi.org/10.6028/NIST.FIPS.204
+package mldsa
+
+import (
+	"crypto"
+	"crypto/internal/fips140/mldsa"
+	"errors"
+	"io"
+	_ "unsafe"
+)
+
+const (
+	// SeedSize is the size of a seed used to generate a private key.
+	SeedSize = 32
+
+	// PublicKeySize44 is the size of an ML-DSA-44 public key.
+	PublicKeySize44 = 1312
+
+	// SignatureSize44 is the size of an ML-DSA-44 signature.
+	SignatureSize44 = 2420
+
+	// PublicKeySize65 is the size of an ML-DSA-65 public key.
+	PublicKeySize65 = 1952
+
+	// SignatureSize65 is the size of an ML-DSA-65 signature.
+	SignatureSize65 = 3309
+
+	// PublicKeySize87 is the size of an ML-DSA-87 public key.
+	PublicKeySize87 = 2592
+
+	// SignatureSize87 is the size of an ML-DSA-87 signature.
+	SignatureSize87 = 4627
+)
+
+// Parameters is an ML-DSA parameter set.
+type Parameters struct {
+	name          string
+	publicKeySize int
+	signatureSize int
+	generateKey   func() *mldsa.PrivateKey
+	newPrivateKey func([]byte) (*mldsa.PrivateKey, error)
+	newPublicKey  func([]byte) (*mldsa.PublicKey, error)
+}
+
+var (
+	params44 = &Parameters{"ML-DSA-44", PublicKeySize44, SignatureSize44,
+		mldsa.GenerateKey44, mldsa.NewPrivateKey44, mldsa.NewPublicKey44}
+	params65 = &Parameters{"ML-DSA-65", PublicKeySize65, SignatureSize65,
+		mldsa.GenerateKey65, mldsa.NewPrivateKey65, mldsa.NewPublicKey65}
+	params87 = &Parameters{"ML-DSA-87", PublicKeySize87, SignatureSize87,
+		mldsa.GenerateKey87, mldsa.NewPrivateKey87, mldsa.NewPublicKey87}
+)
+
+// MLDSA44 returns the ML-DSA-44 parameter set, for security category 2.
+func MLDSA44() *Parameters { return params44 }
+
+// MLDSA65 returns the ML-DSA-65 parameter set, for security category 3.
+func MLDSA65() *Parameters { return params65 }
+
+// MLDSA87 returns the ML-DSA-87 parameter set, for security category 5.
+func MLDSA87() *Parameters { return params87 }
+
+// String returns the name of the parameter set, such as "ML-DSA-65".
+func (p *Parameters) String() string { return p.name }
+
+// PublicKeySize returns the size of a public key, in bytes.
+func (p *Parameters) PublicKeySize() int { return p.publicKeySize }
+
+// SignatureSize returns the size of a signature, in bytes.
+func (p *Parameters) SignatureSize() int { return p.signatureSize }
+
+// PrivateKey is an ML-DSA private key. It implements [crypto.Signer]
+// and [crypto.MessageSigner].
+type PrivateKey struct {
+	params *Parameters
+	key    *mldsa.PrivateKey
+}
+
+// GenerateKey generates a new private key with the given parameters,
+// drawing random bytes from a secure source. The private key must be
+// kept secret.
+func GenerateKey(params *Parameters) (*PrivateKey, error) {
+	return &PrivateKey{params, params.generateKey()}, nil
+}
+
+// NewPrivateKey expands a private key with the given parameters from
+// a 32-byte seed. The seed must be uniformly random.
+func NewPrivateKey(params *Parameters, seed []byte) (*PrivateKey, error) {
+	key, err := params.newPrivateKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{params, key}, nil
+}
+
+//go:linkname mldsaexpand_mldsaUnwrap crypto/internal/mldsaexpand.mldsaUnwrap
+func mldsaexpand_mldsaUnwrap(priv *PrivateKey) *mldsa.PrivateKey {
+	return priv.key
+}
+
+// Parameters returns the parameter set of the key.
+func (priv *PrivateKey) Parameters() *Parameters {
+	return priv.params
+}
+
+// Bytes returns the private key as a 32-byte seed.
+//
+// The private key must be kept secret.
+func (priv *PrivateKey) Bytes() []byte {
+	return priv.key.Bytes()
+}
+
+// PublicKey returns the public key corresponding to priv.
+func (priv *PrivateKey) PublicKey() *PublicKey {
+	return &PublicKey{priv.params, priv.key.PublicKey()}
+}
+
+// Public returns the public key corresponding to priv, as a [*PublicKey].
+func (priv *PrivateKey) Public() crypto.PublicKey {
+	return priv.PublicKey()
+}
+
+// Equal reports whether priv and x have the same value.
+func (priv *PrivateKey) Equal(x crypto.PrivateKey) bool {
+	xx, ok := x.(*PrivateKey)
+	if !ok {
+		return false
+	}
+	return priv.key.Equal(xx.key)
+}
+
+// Options can be used with [PrivateKey.Sign] or [Verify]
+// to select an ML-DSA context string.
+type Options struct {
+	// Context, if not empty, selects a context string which separates
+	// the signatures of different protocols. It can be at most 255 bytes.
+	Context string
+}
+
+// HashFunc returns zero, to indicate that the message is not pre-hashed.
+func (o *Options) HashFunc() crypto.Hash { return crypto.Hash(0) }
+
+// Sign signs message with priv.
+//
+// The message is signed directly, so opts.HashFunc() must return zero.
+// A value of type [*Options] can be used as opts, or crypto.Hash(0) for
+// an empty context string.
+//
+// The signature is randomized with bytes from a secure source; rand is
+// ignored.
+func (priv *PrivateKey) Sign(rand io.Reader, message []byte, opts crypto.SignerOpts) (signature []byte, err error) {
+	context, err := contextFromOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return mldsa.Sign(priv.key, message, context)
+}
+
+// SignMessage is the same as [PrivateKey.Sign].
+//
+// It implements [crypto.MessageSigner].
+func (priv *PrivateKey) SignMessage(rand io.Reader, message []byte, opts crypto.SignerOpts) (signature []byte, err error) {
+	return priv.Sign(rand, message, opts)
+}
+
+func contextFromOptions(opts crypto.SignerOpts) (string, error) {
+	if opts != nil && opts.HashFunc() != crypto.Hash(0) {
+		return "", errors.New("mldsa: cannot sign pre-hashed messages")
+	}
+	if opts, ok := opts.(*Options); ok && opts != nil {
+		return opts.Context, nil
+	}
+	return "", nil
+}
+
+// PublicKey is an ML-DSA public key.
+type PublicKey struct {
+	params *Parameters
+	key    *mldsa.PublicKey
+}
+
+// NewPublicKey parses a public key with the given parameters from its
+// encoded form. If the public key is not valid, NewPublicKey returns
+// an error.
+func NewPublicKey(params *Parameters, key []byte) (*PublicKey, error) {
+	k, err := params.newPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return &PublicKey{params, k}, nil
+}
+
+// Parameters returns the parameter set of the key.
+func (pub *PublicKey) Parameters() *Parameters {
+	return pub.params
+}
+
+// Bytes returns the encoded public key.
+func (pub *PublicKey) Bytes() []byte {
+	return pub.key.Bytes()
+}
+
+// Equal reports whether pub and x have the same value.
+func (pub *PublicKey) Equal(x crypto.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	return pub.key.Equal(xx.key)
+}
+
+// Verify reports whether sig is a valid signature of message by pub,
+// returning nil if it is. The context string is taken from opts,
+// which may be nil for an empty context.
+func Verify(pub *PublicKey, message, sig []byte, opts *Options) error {
+	var context string
+	if opts != nil {
+		context = opts.Context
+	}
+	return mldsa.Verify(pub.key, message, sig, context)
+}
+
+var (
+	_ crypto.Signer        = (*PrivateKey)(nil)
+	_ crypto.MessageSigner = (*PrivateKey)(nil)
+)
@@ -0,0 +1,98 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mldsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for _, params := range []*Parameters{MLDSA44(), MLDSA65(), MLDSA87()} {
+		t.Run(params.String(), func(t *testing.T) {
+			priv, err := GenerateKey(params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if priv.Parameters() != params {
+				t.Errorf("Parameters() = %v, want %v", priv.Parameters(), params)
+			}
+			pub := priv.PublicKey()
+			if len(pub.Bytes()) != params.PublicKeySize() {
+				t.Errorf("public key length = %d, want %d", len(pub.Bytes()), params.PublicKeySize())
+			}
+
+			msg := []byte("hello")
+			sig, err := priv.Sign(rand.Reader, msg, crypto.Hash(0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(sig) != params.SignatureSize() {
+				t.Errorf("signature length = %d, want %d", len(sig), params.SignatureSize())
+			}
+			if err := Verify(pub, msg, sig, nil); err != nil {
+				t.Errorf("Verify: %v", err)
+			}
+			if err := Verify(pub, []byte("other"), sig, nil); err == nil {
+				t.Errorf("Verify succeeded for a different message")
+			}
+			if err := Verify(pub, msg, sig, &Options{Context: "ctx"}); err == nil {
+				t.Errorf("Verify succeeded with a different context")
+			}
+
+			sig, err = crypto.SignMessage(priv, rand.Reader, msg, &Options{Context: "ctx"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := Verify(pub, msg, sig, &Options{Context: "ctx"}); err != nil {
+				t.Errorf("Verify with context: %v", err)
+			}
+			if _, err := priv.Sign(rand.Reader, msg, crypto.SHA256); err == nil {
+				t.Errorf("Sign with pre-hashed message succeeded")
+			}
+
+			// Keys can be reconstructed from their encodings.
+			priv2, err := NewPrivateKey(params, priv.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !priv.Equal(priv2) || !priv2.PublicKey().Equal(pub) {
+				t.Errorf("private key from seed is not equal to the original")
+			}
+			pub2, err := NewPublicKey(params, pub.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !pub.Equal(pub2) || !bytes.Equal(pub2.Bytes(), pub.Bytes()) {
+				t.Errorf("parsed public key is not equal to the original")
+			}
+			if err := Verify(pub2, msg, sig, &Options{Context: "ctx"}); err != nil {
+				t.Errorf("Verify with parsed public key: %v", err)
+			}
+		})
+	}
+}
+
+func TestInvalidKeys(t *testing.T) {
+	if _, err := NewPrivateKey(MLDSA65(), make([]byte, SeedSize-1)); err == nil {
+		t.Errorf("NewPrivateKey with short seed succeeded")
+	}
+	priv, err := GenerateKey(MLDSA44())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPublicKey(MLDSA65(), priv.PublicKey().Bytes()); err == nil {
+		t.Errorf("NewPublicKey with key of the wrong parameter set succeeded")
+	}
+	other, err := NewPrivateKey(MLDSA65(), priv.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if priv.Equal(other) {
+		t.Errorf("keys with different parameter sets are equal")
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package password
+
+import (
+	"crypto/internal/fips140only"
+	"crypto/internal/rand"
+	"crypto/subtle"
+	"errors"
+	"math"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2id is a [Hasher] that uses Argon2id, as specified in RFC 9106.
+//
+// Hashes are encoded as PHC strings of the form
+//
+//	$argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>
+//
+// with a 16-byte random salt.
+//
+// Any zero field is replaced by its default value. The defaults are the
+// second recommended option of RFC 9106, Section 4: 64 MiB of memory, three
+// passes and four lanes, with a 32-byte key. Parameters that require more than
+// 4 GiB of memory are rejected, by both Hash and [Verify].
+type Argon2id struct {
+	// Memory is the amount of memory to use, in KiB.
+	Memory uint32
+
+	// Time is the number of passes over the memory.
+	Time uint32
+
+	// Threads is the degree of parallelism, which is also the number of
+	// lanes the memory is divided into.
+	Threads uint8
+
+	// KeyLength is the length of the derived key, in bytes.
+	KeyLength uint32
+}
+
+const (
+	defaultArgon2Memory    = 64 * 1024
+	defaultArgon2Time      = 3
+	defaultArgon2Threads   = 4
+	defaultArgon2KeyLength = 32
+
+	argon2SaltLength = 16
+
+	// maxArgon2Memory is the most memory, in KiB, that parameters accepted
+	// by Hash and Verify can require, so that hashes from untrusted sources
+	// can't exhaust memory.
+	maxArgon2Memory = 4 * 1024 * 1024
+)
+
+func (a *Argon2id) params() (memory, time uint32, threads uint8, keyLength uint32) {
+	memory, time, threads, keyLength = a.Memory, a.Time, a.Threads, a.KeyLength
+	if memory == 0 {
+		memory = defaultArgon2Memory
+	}
+	if time == 0 {
+		time = defaultArgon2Time
+	}
+	if threads == 0 {
+		threads = defaultArgon2Threads
+	}
+	if keyLength == 0 {
+		keyLength = defaultArgon2KeyLength
+	}
+	return
+}
+
+// Hash implements [Hasher].
+func (a *Argon2id) Hash(password string) (string, error) {
+	if fips140only.Enforced() {
+		return "", errFIPS
+	}
+	memory, time, threads, keyLength := a.params()
+	if memory < 8*uint32(threads) {
+		return "", errors.New("crypto/password: Argon2id memory must be at least 8 KiB per thread")
+	}
+	if memory > maxArgon2Memory {
+		return "", errors.New("crypto/password: Argon2id memory must be at most 4 GiB")
+	}
+	salt := make([]byte, argon2SaltLength)
+	rand.Reader.Read(salt)
+	h := &phcHash{
+		id:      "argon2id",
+		version: argon2.Version,
+		params: []phcParam{
+			{"m", uint64(memory)},
+			{"t", uint64(time)},
+			{"p", uint64(threads)},
+		},
+		salt: salt,
+		hash: argon2.IDKey([]byte(password), salt, time, memory, threads, keyLength),
+	}
+	return h.String(), nil
+}
+
+// NeedsRehash implements [Hasher].
+func (a *Argon2id) NeedsRehash(hash string) bool {
+	h, err := parseArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	memory, time, threads, keyLength := a.params()
+	return h.params[0].value != uint64(memory) ||
+		h.params[1].value != uint64(time) ||
+		h.params[2].value != uint64(threads) ||
+		len(h.hash) != int(keyLength) ||
+		len(h.salt) < argon2SaltLength
+}
+
+func parseArgon2id(hash string) (*phcHash, error) {
+	h, err := parsePHC(hash, "argon2id", true, "m", "t", "p")
+	if err != nil {
+		return nil, err
+	}
+	if h.version != argon2.Version {
+		return nil, errors.New("crypto/password: unsupported Argon2id version")
+	}
+	memory, time, threads := h.params[0].value, h.params[1].value, h.params[2].value
+	if time == 0 || time > math.MaxUint32 || threads == 0 || threads > 255 ||
+		memory < 8*threads || memory > maxArgon2Memory {
+		return nil, errMalformed
+	}
+	if len(h.salt) < 8 || len(h.hash) < 4 {
+		return nil, errMalformed
+	}
+	return h, nil
+}
+
+func verifyArgon2id(password, hash string) error {
+	h, err := parseArgon2id(hash)
+	if err != nil {
+		return err
+	}
+	key := argon2.IDKey([]byte(password), h.salt, uint32(h.params[1].value),
+		uint32(h.params[0].value), uint8(h.params[2].value), uint32(len(h.hash)))
+	if subtle.ConstantTimeCompare(key, h.hash) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
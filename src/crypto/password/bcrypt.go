@@ -0,0 +1,84 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package password
+
+import (
+	"crypto/internal/fips140only"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Bcrypt is a [Hasher] that uses bcrypt.
+//
+// Hashes are encoded in the traditional format
+//
+//	$2a$<cost>$<salt and key>
+//
+// which is not a PHC string. bcrypt only uses the first 72 bytes of a
+// password, so Hash returns an error for longer passwords.
+//
+// Bcrypt is provided for compatibility with existing password databases.
+// New applications should prefer [Argon2id].
+type Bcrypt struct {
+	// Cost is the base-2 logarithm of the number of key expansion rounds,
+	// between 4 and 31. If zero, the default of 10 is used.
+	Cost int
+}
+
+func (b *Bcrypt) cost() int {
+	if b.Cost == 0 {
+		return bcrypt.DefaultCost
+	}
+	return b.Cost
+}
+
+// Hash implements [Hasher].
+func (b *Bcrypt) Hash(password string) (string, error) {
+	if fips140only.Enforced() {
+		return "", errFIPS
+	}
+	cost := b.cost()
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return "", errors.New("crypto/password: bcrypt cost must be between 4 and 31")
+	}
+	if len(password) > 72 {
+		return "", errors.New("crypto/password: bcrypt password length exceeds 72 bytes")
+	}
+	h, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return "", err
+	}
+	return string(h), nil
+}
+
+// NeedsRehash implements [Hasher].
+func (b *Bcrypt) NeedsRehash(hash string) bool {
+	if !isBcrypt(hash) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != b.cost()
+}
+
+func isBcrypt(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") ||
+		strings.HasPrefix(hash, "$2b$") ||
+		strings.HasPrefix(hash, "$2y$")
+}
+
+func verifyBcrypt(password, hash string) error {
+	// bcrypt.CompareHashAndPassword compares the keys in constant time.
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+		return ErrMismatch
+	default:
+		return errMalformed
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package password implements password hashing with Argon2id, scrypt and
+// bcrypt.
+//
+// Hashes are encoded as strings that carry the algorithm, its parameters and
+// a random salt alongside the derived key, so that they can be stored as is
+// and later checked with [Verify]. Argon2id and scrypt hashes use the PHC
+// string format, while bcrypt hashes use the traditional $2a$ format.
+//
+// [Hash] uses Argon2id with parameters suitable for interactive logins. As
+// recommended parameters change over time, applications should call
+// [NeedsRehash] after a successful [Verify], and if it reports true, replace
+// the stored hash with a new one.
+//
+// None of these algorithms is approved for use in FIPS 140-3 mode, so hashing
+// and verifying return an error if [crypto/fips140.Enforced] is true.
+package password
+
+import (
+	"crypto/internal/fips140only"
+	"errors"
+	"strings"
+)
+
+// A Hasher hashes passwords using a specific algorithm and set of parameters.
+//
+// The Hasher implementations in this package are [*Argon2id], [*Scrypt] and
+// [*Bcrypt].
+type Hasher interface {
+	// Hash returns the encoded hash of password, using a new random salt.
+	Hash(password string) (string, error)
+
+	// NeedsRehash reports whether hash was produced with a different
+	// algorithm or different parameters than those of the Hasher, or can't
+	// be parsed at all.
+	NeedsRehash(hash string) bool
+}
+
+// ErrMismatch is returned by [Verify] when the password does not match the
+// hash.
+var ErrMismatch = errors.New("crypto/password: password does not match hash")
+
+var (
+	errMalformed   = errors.New("crypto/password: malformed hash")
+	errUnsupported = errors.New("crypto/password: unsupported hash algorithm")
+	errFIPS        = errors.New("crypto/password: use of password hashing algorithms is not allowed in FIPS 140-only mode")
+)
+
+// defaultHasher is the Hasher used by [Hash] and [NeedsRehash].
+var defaultHasher = &Argon2id{}
+
+// Hash returns the encoded Argon2id hash of password, with the default
+// parameters documented on [Argon2id].
+func Hash(password string) (string, error) {
+	return defaultHasher.Hash(password)
+}
+
+// NeedsRehash reports whether hash was not produced by [Hash] with the
+// current default algorithm and parameters.
+func NeedsRehash(hash string) bool {
+	return defaultHasher.NeedsRehash(hash)
+}
+
+// Verify checks whether password matches hash, which must have been produced
+// by any of the Hasher implementations in this package. It returns nil on a
+// match, [ErrMismatch] if the password does not match, or a different error
+// if the hash is malformed or uses an unsupported algorithm.
+//
+// The derived keys are compared in constant time.
+func Verify(password, hash string) error {
+	if fips140only.Enforced() {
+		return errFIPS
+	}
+	switch {
+	case strings.HasPrefix(hash, "$argon2id$"):
+		return verifyArgon2id(password, hash)
+	case strings.HasPrefix(hash, "$scrypt$"):
+		return verifyScrypt(password, hash)
+	case isBcrypt(hash):
+		return verifyBcrypt(password, hash)
+	case strings.HasPrefix(hash, "$"):
+		return errUnsupported
+	default:
+		return errMalformed
+	}
+}
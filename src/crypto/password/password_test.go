@@ -0,0 +1,219 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package password_test
+
+import (
+	"crypto/password"
+	"strings"
+	"testing"
+)
+
+func TestVectors(t *testing.T) {
+	tests := []struct {
+		name, password, hash string
+	}{
+		{
+			// From the reference implementation, with t=1, m=64, p=1.
+			"Argon2id", "password",
+			"$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHQ$ZVrRXqxlLcWfcXCnMyv0m4Rpvh/bnCi7",
+		},
+		{
+			// RFC 7914, Section 12.
+			"scrypt", "pleaseletmein",
+			"$scrypt$ln=14,r=8,p=1$U29kaXVtQ2hsb3JpZGU$cCO9yzr9c0hGHAbNgf046/2o+7qQT44+qbVD9lRdofLVQylVYT8Pz2LUlwUkKpr55h6F3A1lHkDfzwF7RVdYhw",
+		},
+		{
+			"bcrypt", "allmine",
+			"$2a$10$XajjQvNhvvRt5GSeFk1xFeyqRrsxkhBkUiQeg0dt.wU1qD4aFDcga",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := password.Verify(tt.password, tt.hash); err != nil {
+				t.Errorf("Verify(%q) = %v", tt.password, err)
+			}
+			if err := password.Verify(tt.password+"x", tt.hash); err != password.ErrMismatch {
+				t.Errorf("Verify(wrong password) = %v, want ErrMismatch", err)
+			}
+		})
+	}
+}
+
+func TestHashers(t *testing.T) {
+	tests := []struct {
+		name   string
+		h      password.Hasher
+		prefix string
+	}{
+		{"Argon2id", &password.Argon2id{Memory: 64, Time: 1, Threads: 1}, "$argon2id$v=19$m=64,t=1,p=1$"},
+		{"Argon2idKeyLength", &password.Argon2id{Memory: 256, Time: 2, Threads: 2, KeyLength: 16}, "$argon2id$v=19$m=256,t=2,p=2$"},
+		{"scrypt", &password.Scrypt{LogN: 10, R: 8, P: 1}, "$scrypt$ln=10,r=8,p=1$"},
+		{"scryptKeyLength", &password.Scrypt{LogN: 4, R: 1, P: 2, KeyLength: 64}, "$scrypt$ln=4,r=1,p=2$"},
+		{"bcrypt", &password.Bcrypt{Cost: 4}, "$2a$04$"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := tt.h.Hash("correct horse battery staple")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(hash, tt.prefix) {
+				t.Errorf("Hash = %q, want prefix %q", hash, tt.prefix)
+			}
+			if err := password.Verify("correct horse battery staple", hash); err != nil {
+				t.Errorf("Verify = %v", err)
+			}
+			if err := password.Verify("correct horse battery stapler", hash); err != password.ErrMismatch {
+				t.Errorf("Verify(wrong password) = %v, want ErrMismatch", err)
+			}
+			if tt.h.NeedsRehash(hash) {
+				t.Errorf("NeedsRehash(%q) = true for the same parameters", hash)
+			}
+
+			again, err := tt.h.Hash("correct horse battery staple")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again == hash {
+				t.Errorf("Hash returned the same hash twice, salt is not random")
+			}
+		})
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	argon, err := (&password.Argon2id{Memory: 64, Time: 1, Threads: 1}).Hash("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scrypt, err := (&password.Scrypt{LogN: 4, R: 1, P: 1}).Hash("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcrypt, err := (&password.Bcrypt{Cost: 4}).Hash("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		h    password.Hasher
+		hash string
+		want bool
+	}{
+		{&password.Argon2id{Memory: 64, Time: 1, Threads: 1}, argon, false},
+		{&password.Argon2id{Memory: 128, Time: 1, Threads: 1}, argon, true},
+		{&password.Argon2id{Memory: 64, Time: 2, Threads: 1}, argon, true},
+		{&password.Argon2id{Memory: 64, Time: 1, Threads: 2}, argon, true},
+		{&password.Argon2id{Memory: 64, Time: 1, Threads: 1, KeyLength: 16}, argon, true},
+		{&password.Argon2id{}, argon, true},
+		{&password.Argon2id{}, scrypt, true},
+		{&password.Argon2id{}, bcrypt, true},
+		{&password.Scrypt{LogN: 4, R: 1, P: 1}, scrypt, false},
+		{&password.Scrypt{LogN: 5, R: 1, P: 1}, scrypt, true},
+		{&password.Scrypt{}, scrypt, true},
+		{&password.Scrypt{}, argon, true},
+		{&password.Bcrypt{Cost: 4}, bcrypt, false},
+		{&password.Bcrypt{}, bcrypt, true},
+		{&password.Bcrypt{}, argon, true},
+		{&password.Argon2id{}, "", true},
+		{&password.Bcrypt{}, "$2a$", true},
+	}
+	for _, tt := range tests {
+		if got := tt.h.NeedsRehash(tt.hash); got != tt.want {
+			t.Errorf("%#v.NeedsRehash(%q) = %v, want %v", tt.h, tt.hash, got, tt.want)
+		}
+	}
+
+	if !password.NeedsRehash(argon) {
+		t.Errorf("NeedsRehash(%q) = false, want true", argon)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	if testing.Short() {
+		t.Skip("default parameters are slow")
+	}
+	hash, err := password.Hash("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=65536,t=3,p=4$") {
+		t.Errorf("Hash = %q, want default Argon2id parameters", hash)
+	}
+	if err := password.Verify("password", hash); err != nil {
+		t.Errorf("Verify = %v", err)
+	}
+	if password.NeedsRehash(hash) {
+		t.Errorf("NeedsRehash(%q) = true", hash)
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	const salt, key = "c29tZXNhbHQ", "ZVrRXqxlLcWfcXCnMyv0m4Rpvh/bnCi7"
+	for _, hash := range []string{
+		"",
+		"password",
+		"$md5$rounds=1000$" + salt + "$" + key,
+		"$argon2id$",
+		"$argon2id$m=64,t=1,p=1$" + salt + "$" + key,
+		"$argon2id$v=16$m=64,t=1,p=1$" + salt + "$" + key,
+		"$argon2id$v=19$m=64,t=1$" + salt + "$" + key,
+		"$argon2id$v=19$t=1,m=64,p=1$" + salt + "$" + key,
+		"$argon2id$v=19$m=64,t=1,p=1,x=1$" + salt + "$" + key,
+		"$argon2id$v=19$m=064,t=1,p=1$" + salt + "$" + key,
+		"$argon2id$v=19$m=64,t=0,p=1$" + salt + "$" + key,
+		"$argon2id$v=19$m=64,t=1,p=0$" + salt + "$" + key,
+		"$argon2id$v=19$m=64,t=1,p=256$" + salt + "$" + key,
+		"$argon2id$v=19$m=7,t=1,p=1$" + salt + "$" + key,
+		"$argon2id$v=19$m=64,t=1,p=1$" + salt,
+		"$argon2id$v=19$m=64,t=1,p=1$" + salt + "$" + key + "$",
+		"$argon2id$v=19$m=64,t=1,p=1$" + salt + "=$" + key,
+		"$argon2id$v=19$m=64,t=1,p=1$$" + key,
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA$" + key,
+		"$scrypt$ln=0,r=8,p=1$" + salt + "$" + key,
+		"$scrypt$ln=63,r=8,p=1$" + salt + "$" + key,
+		"$scrypt$ln=4,r=0,p=1$" + salt + "$" + key,
+		"$scrypt$ln=4,r=1073741824,p=1$" + salt + "$" + key,
+		"$scrypt$v=1$ln=4,r=8,p=1$" + salt + "$" + key,
+		// Parameters requiring more than 4 GiB of memory.
+		"$scrypt$ln=40,r=8,p=1$c2FsdHNhbHRzYWx0$aGFzaGhhc2hoYXNo",
+		"$scrypt$ln=23,r=8,p=1$" + salt + "$" + key,
+		"$argon2id$v=19$m=4294967295,t=1,p=1$" + salt + "$" + key,
+		"$argon2id$v=19$m=4294967360,t=1,p=1$" + salt + "$" + key,
+		"$argon2id$v=19$m=64,t=4294967296,p=1$" + salt + "$" + key,
+		"$2a$10$XajjQvNhvvRt5GSe",
+		"$2a$99$XajjQvNhvvRt5GSeFk1xFeyqRrsxkhBkUiQeg0dt.wU1qD4aFDcga",
+	} {
+		err := password.Verify("password", hash)
+		if err == nil || err == password.ErrMismatch {
+			t.Errorf("Verify(%q) = %v, want a parsing error", hash, err)
+		}
+	}
+}
+
+func TestHashMemoryLimit(t *testing.T) {
+	for _, h := range []password.Hasher{
+		&password.Scrypt{LogN: 40},
+		&password.Scrypt{LogN: 23, R: 8},
+		&password.Argon2id{Memory: 1<<32 - 1},
+	} {
+		if _, err := h.Hash("password"); err == nil {
+			t.Errorf("%#v: Hash succeeded, want an error", h)
+		}
+	}
+}
+
+func TestBcryptLongPassword(t *testing.T) {
+	b := &password.Bcrypt{Cost: 4}
+	if _, err := b.Hash(strings.Repeat("a", 72)); err != nil {
+		t.Errorf("Hash(72 bytes) = %v", err)
+	}
+	if _, err := b.Hash(strings.Repeat("a", 73)); err == nil {
+		t.Errorf("Hash(73 bytes) succeeded")
+	}
+	if _, err := (&password.Bcrypt{Cost: 3}).Hash("password"); err == nil {
+		t.Errorf("Hash with cost 3 succeeded")
+	}
+}
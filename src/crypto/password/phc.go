@@ -0,0 +1,125 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package password
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+// phcEncoding is the base64 variant used by the PHC string format: the
+// standard alphabet, without padding.
+var phcEncoding = base64.RawStdEncoding.Strict()
+
+// phcHash is a hash in the PHC string format,
+//
+//	$<id>[$v=<version>]$<param>=<value>(,<param>=<value>)*$<salt>$<hash>
+//
+// as specified at https://github.com/P-H-C/phc-string-format. Only numeric
+// parameters are supported, and the salt and hash are mandatory.
+type phcHash struct {
+	id      string
+	version uint32 // zero if absent
+	params  []phcParam
+	salt    []byte
+	hash    []byte
+}
+
+type phcParam struct {
+	name  string
+	value uint64
+}
+
+func (h *phcHash) String() string {
+	var b strings.Builder
+	b.WriteString("$")
+	b.WriteString(h.id)
+	if h.version != 0 {
+		b.WriteString("$v=")
+		b.WriteString(strconv.FormatUint(uint64(h.version), 10))
+	}
+	for i, p := range h.params {
+		if i == 0 {
+			b.WriteString("$")
+		} else {
+			b.WriteString(",")
+		}
+		b.WriteString(p.name)
+		b.WriteString("=")
+		b.WriteString(strconv.FormatUint(p.value, 10))
+	}
+	b.WriteString("$")
+	b.WriteString(phcEncoding.EncodeToString(h.salt))
+	b.WriteString("$")
+	b.WriteString(phcEncoding.EncodeToString(h.hash))
+	return b.String()
+}
+
+// parsePHC parses s as a PHC string with the given id and, in order, exactly
+// the given parameter names, each with a decimal value that fits in 32 bits.
+// If hasVersion is true, the version field is mandatory, otherwise it must be
+// absent.
+func parsePHC(s, id string, hasVersion bool, names ...string) (*phcHash, error) {
+	rest, ok := strings.CutPrefix(s, "$"+id+"$")
+	if !ok {
+		return nil, errUnsupported
+	}
+	fields := strings.Split(rest, "$")
+	h := &phcHash{id: id}
+
+	if hasVersion {
+		if len(fields) == 0 {
+			return nil, errMalformed
+		}
+		v, ok := strings.CutPrefix(fields[0], "v=")
+		if !ok {
+			return nil, errMalformed
+		}
+		n, err := parseDecimal(v)
+		if err != nil || n == 0 {
+			return nil, errMalformed
+		}
+		h.version = uint32(n)
+		fields = fields[1:]
+	}
+
+	if len(fields) != 3 {
+		return nil, errMalformed
+	}
+	params := strings.Split(fields[0], ",")
+	if len(params) != len(names) {
+		return nil, errMalformed
+	}
+	for i, p := range params {
+		name, value, ok := strings.Cut(p, "=")
+		if !ok || name != names[i] {
+			return nil, errMalformed
+		}
+		n, err := parseDecimal(value)
+		if err != nil {
+			return nil, errMalformed
+		}
+		h.params = append(h.params, phcParam{name, n})
+	}
+
+	var err error
+	if h.salt, err = phcEncoding.DecodeString(fields[1]); err != nil || len(h.salt) == 0 {
+		return nil, errMalformed
+	}
+	if h.hash, err = phcEncoding.DecodeString(fields[2]); err != nil || len(h.hash) == 0 {
+		return nil, errMalformed
+	}
+	return h, nil
+}
+
+// parseDecimal parses a canonical decimal number that fits in 32 bits, as
+// required by the PHC string format.
+func parseDecimal(s string) (uint64, error) {
+	if len(s) > 1 && s[0] == '0' {
+		return 0, errMalformed
+	}
+	return strconv.ParseUint(s, 10, 32)
+}
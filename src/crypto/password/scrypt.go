@@ -0,0 +1,153 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package password
+
+import (
+	"crypto/internal/fips140only"
+	"crypto/internal/rand"
+	"crypto/subtle"
+	"errors"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Scrypt is a [Hasher] that uses scrypt, as specified in RFC 7914.
+//
+// Hashes are encoded as PHC strings of the form
+//
+//	$scrypt$ln=<log2 N>,r=<r>,p=<p>$<salt>$<key>
+//
+// with a 16-byte random salt.
+//
+// Any zero field is replaced by its default value. The defaults are N = 2¹⁷,
+// r = 8 and p = 1, which use 128 MiB of memory, with a 32-byte key.
+// Parameters that require more than 4 GiB of memory are rejected, by both Hash
+// and [Verify].
+type Scrypt struct {
+	// LogN is the base-2 logarithm of the CPU/memory cost parameter N.
+	LogN uint8
+
+	// R is the block size parameter.
+	R int
+
+	// P is the parallelization parameter.
+	P int
+
+	// KeyLength is the length of the derived key, in bytes.
+	KeyLength int
+}
+
+const (
+	defaultScryptLogN      = 17
+	defaultScryptR         = 8
+	defaultScryptP         = 1
+	defaultScryptKeyLength = 32
+
+	scryptSaltLength = 16
+
+	// maxScryptMemory is the most memory, in bytes, that parameters accepted
+	// by Hash and Verify can require, so that hashes from untrusted sources
+	// can't exhaust memory.
+	maxScryptMemory = 4 << 30
+)
+
+// scryptParamsValid reports whether scrypt can run with the given parameters
+// within maxScryptMemory, which it uses 128 * r * (N + p) bytes of.
+func scryptParamsValid(logN, r, p uint64) bool {
+	if logN == 0 || logN > 32 || r == 0 || p == 0 || r*p >= 1<<30 {
+		return false
+	}
+	return r <= maxScryptMemory/(128*(1<<logN+p))
+}
+
+func (s *Scrypt) params() (logN uint8, r, p, keyLength int) {
+	logN, r, p, keyLength = s.LogN, s.R, s.P, s.KeyLength
+	if logN == 0 {
+		logN = defaultScryptLogN
+	}
+	if r == 0 {
+		r = defaultScryptR
+	}
+	if p == 0 {
+		p = defaultScryptP
+	}
+	if keyLength == 0 {
+		keyLength = defaultScryptKeyLength
+	}
+	return
+}
+
+// Hash implements [Hasher].
+func (s *Scrypt) Hash(password string) (string, error) {
+	if fips140only.Enforced() {
+		return "", errFIPS
+	}
+	logN, r, p, keyLength := s.params()
+	if !scryptParamsValid(uint64(logN), uint64(r), uint64(p)) {
+		return "", errors.New("crypto/password: invalid scrypt parameters or more than 4 GiB of memory required")
+	}
+	salt := make([]byte, scryptSaltLength)
+	rand.Reader.Read(salt)
+	key, err := scrypt.Key([]byte(password), salt, 1<<logN, r, p, keyLength)
+	if err != nil {
+		return "", err
+	}
+	h := &phcHash{
+		id: "scrypt",
+		params: []phcParam{
+			{"ln", uint64(logN)},
+			{"r", uint64(r)},
+			{"p", uint64(p)},
+		},
+		salt: salt,
+		hash: key,
+	}
+	return h.String(), nil
+}
+
+// NeedsRehash implements [Hasher].
+func (s *Scrypt) NeedsRehash(hash string) bool {
+	h, err := parseScrypt(hash)
+	if err != nil {
+		return true
+	}
+	logN, r, p, keyLength := s.params()
+	return h.params[0].value != uint64(logN) ||
+		h.params[1].value != uint64(r) ||
+		h.params[2].value != uint64(p) ||
+		len(h.hash) != keyLength ||
+		len(h.salt) < scryptSaltLength
+}
+
+func parseScrypt(hash string) (*phcHash, error) {
+	h, err := parsePHC(hash, "scrypt", false, "ln", "r", "p")
+	if err != nil {
+		return nil, err
+	}
+	logN, r, p := h.params[0].value, h.params[1].value, h.params[2].value
+	if !scryptParamsValid(logN, r, p) {
+		return nil, errMalformed
+	}
+	if len(h.salt) < 8 || len(h.hash) < 4 {
+		return nil, errMalformed
+	}
+	return h, nil
+}
+
+func verifyScrypt(password, hash string) error {
+	h, err := parseScrypt(hash)
+	if err != nil {
+		return err
+	}
+	key, err := scrypt.Key([]byte(password), h.salt, 1<<h.params[0].value,
+		int(h.params[1].value), int(h.params[2].value), len(h.hash))
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(key, h.hash) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
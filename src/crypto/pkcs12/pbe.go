@@ -0,0 +1,404 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pkcs12
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/hmac"
+	"crypto/internal/rand"
+	"crypto/pbkdf2"
+	"crypto/pkcs12/internal/rc2"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"hash"
+	"unicode/utf16"
+)
+
+// pfxPassword holds a password in the encodings used by the different
+// password-based schemes.
+type pfxPassword struct {
+	// raw is the password as is, used by PBES2.
+	raw string
+	// bmp is the password as a NUL-terminated BMPString, used by the
+	// PKCS #12 key derivation function.
+	bmp []byte
+}
+
+func newPassword(password string) (*pfxPassword, error) {
+	bmp := make([]byte, 0, 2*len(password)+2)
+	for _, r := range password {
+		if r >= 0x10000 || utf16.IsSurrogate(r) {
+			return nil, errors.New("crypto/pkcs12: password contains characters outside of the Basic Multilingual Plane")
+		}
+		bmp = append(bmp, byte(r>>8), byte(r))
+	}
+	return &pfxPassword{raw: password, bmp: append(bmp, 0, 0)}, nil
+}
+
+// pkcs12KDF implements the key derivation function of RFC 7292, Appendix B.2,
+// returning size bytes of key material for the given purpose id.
+func pkcs12KDF(h func() hash.Hash, password, salt []byte, iterations int, id byte, size int) []byte {
+	hh := h()
+	u, v := hh.Size(), hh.BlockSize()
+
+	// I is the concatenation of the salt and the password, each repeated to
+	// a multiple of v bytes.
+	fill := func(b []byte) []byte {
+		if len(b) == 0 {
+			return nil
+		}
+		n := v * ((len(b) + v - 1) / v)
+		return bytes.Repeat(b, (n+len(b)-1)/len(b))[:n]
+	}
+	I := append(fill(salt), fill(password)...)
+	D := bytes.Repeat([]byte{id}, v)
+	B := make([]byte, v)
+
+	out := make([]byte, 0, size+u)
+	for {
+		hh.Reset()
+		hh.Write(D)
+		hh.Write(I)
+		A := hh.Sum(nil)
+		for range iterations - 1 {
+			hh.Reset()
+			hh.Write(A)
+			A = hh.Sum(A[:0])
+		}
+		out = append(out, A...)
+		if len(out) >= size {
+			return out[:size]
+		}
+
+		// Set each v-byte block of I to (I_j + B + 1) mod 2^(8v), where B is
+		// A repeated to v bytes.
+		for i := range B {
+			B[i] = A[i%u]
+		}
+		for j := 0; j < len(I); j += v {
+			carry := uint16(1)
+			for k := v - 1; k >= 0; k-- {
+				sum := uint16(I[j+k]) + uint16(B[k]) + carry
+				I[j+k] = byte(sum)
+				carry = sum >> 8
+			}
+		}
+	}
+}
+
+const (
+	pkcs12KDFKey = 1
+	pkcs12KDFIV  = 2
+	pkcs12KDFMAC = 3
+)
+
+type macData struct {
+	Mac        digestInfo
+	MacSalt    []byte
+	Iterations int `asn1:"optional,default:1"`
+}
+
+type digestInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Digest    []byte
+}
+
+var macHashes = []struct {
+	oid  asn1.ObjectIdentifier
+	hash crypto.Hash
+	new  func() hash.Hash
+}{
+	{asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}, crypto.SHA1, sha1.New},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}, crypto.SHA256, sha256.New},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}, crypto.SHA384, sha512.New384},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}, crypto.SHA512, sha512.New},
+}
+
+// verifyMAC checks the HMAC of message, keyed with the PKCS #12 key
+// derivation function, and returns ErrIncorrectPassword if it doesn't match.
+func verifyMAC(md *macData, message, password []byte) error {
+	for _, h := range macHashes {
+		if !md.Mac.Algorithm.Algorithm.Equal(h.oid) {
+			continue
+		}
+		key := pkcs12KDF(h.new, password, md.MacSalt, md.Iterations, pkcs12KDFMAC, h.hash.Size())
+		mac := hmac.New(h.new, key)
+		mac.Write(message)
+		if !hmac.Equal(mac.Sum(nil), md.Mac.Digest) {
+			return ErrIncorrectPassword
+		}
+		return nil
+	}
+	return errors.New("crypto/pkcs12: unsupported MAC algorithm " + md.Mac.Algorithm.Algorithm.String())
+}
+
+// computeMAC returns the macData for message, using hash h.
+func computeMAC(h crypto.Hash, message, password []byte, iterations int) (*macData, error) {
+	for _, mh := range macHashes {
+		if mh.hash != h {
+			continue
+		}
+		salt := randomSalt()
+		key := pkcs12KDF(mh.new, password, salt, iterations, pkcs12KDFMAC, h.Size())
+		mac := hmac.New(mh.new, key)
+		mac.Write(message)
+		return &macData{
+			Mac: digestInfo{
+				Algorithm: pkix.AlgorithmIdentifier{Algorithm: mh.oid, Parameters: asn1.NullRawValue},
+				Digest:    mac.Sum(nil),
+			},
+			MacSalt:    salt,
+			Iterations: iterations,
+		}, nil
+	}
+	return nil, errors.New("crypto/pkcs12: unsupported MAC hash")
+}
+
+var (
+	oidPBEWithSHAAnd128BitRC2CBC     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 5}
+	oidPBEWithSHAAnd40BitRC2CBC      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 6}
+	oidPBEWithSHAAnd3KeyTripleDESCBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 3}
+	oidPBEWithSHAAnd2KeyTripleDESCBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 4}
+
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA224 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 8}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+
+	oidAES128CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+// pbeParams are the parameters of the PKCS #12 password-based encryption
+// schemes, from RFC 7292, Appendix C.
+type pbeParams struct {
+	Salt       []byte
+	Iterations int
+}
+
+// pbes2Params are the parameters of PBES2, from RFC 8018, Appendix A.4.
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params are the parameters of PBKDF2, from RFC 8018, Appendix A.2.
+type pbkdf2Params struct {
+	Salt       []byte
+	Iterations int
+	KeyLength  int                      `asn1:"optional"`
+	PRF        pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// pbeAlgorithm is a password-based encryption scheme used by Encode.
+type pbeAlgorithm int
+
+const (
+	pbes2AES256 pbeAlgorithm = iota
+	pbeSHA1TripleDES
+	pbeSHA1RC2
+)
+
+// decrypt decrypts ciphertext with the password-based encryption scheme alg.
+func decrypt(alg pkix.AlgorithmIdentifier, ciphertext []byte, pw *pfxPassword) ([]byte, error) {
+	var block cipher.Block
+	var iv []byte
+	var err error
+	switch {
+	case alg.Algorithm.Equal(oidPBES2):
+		block, iv, err = pbes2Cipher(alg.Parameters.FullBytes, pw.raw)
+	default:
+		block, iv, err = pkcs12Cipher(alg, pw.bmp)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bs := block.BlockSize()
+	if len(ciphertext) == 0 || len(ciphertext)%bs != 0 {
+		return nil, errors.New("crypto/pkcs12: invalid encrypted data length")
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	// Remove the RFC 8018, Section 6.1.1 padding. A wrong padding most
+	// likely means that the content was encrypted with a different password
+	// than the one used for the MAC.
+	n := int(plaintext[len(plaintext)-1])
+	if n == 0 || n > bs || !bytes.Equal(plaintext[len(plaintext)-n:], bytes.Repeat([]byte{byte(n)}, n)) {
+		return nil, ErrIncorrectPassword
+	}
+	return plaintext[:len(plaintext)-n], nil
+}
+
+// pkcs12Cipher returns the block cipher and IV of one of the PKCS #12
+// password-based encryption schemes.
+func pkcs12Cipher(alg pkix.AlgorithmIdentifier, password []byte) (cipher.Block, []byte, error) {
+	var keyLen int
+	var newCipher func(key []byte) (cipher.Block, error)
+	switch {
+	case alg.Algorithm.Equal(oidPBEWithSHAAnd3KeyTripleDESCBC):
+		keyLen, newCipher = 24, des.NewTripleDESCipher
+	case alg.Algorithm.Equal(oidPBEWithSHAAnd2KeyTripleDESCBC):
+		keyLen = 16
+		newCipher = func(key []byte) (cipher.Block, error) {
+			return des.NewTripleDESCipher(append(key, key[:8]...))
+		}
+	case alg.Algorithm.Equal(oidPBEWithSHAAnd128BitRC2CBC):
+		keyLen = 16
+		newCipher = func(key []byte) (cipher.Block, error) { return rc2.New(key, 128) }
+	case alg.Algorithm.Equal(oidPBEWithSHAAnd40BitRC2CBC):
+		keyLen = 5
+		newCipher = func(key []byte) (cipher.Block, error) { return rc2.New(key, 40) }
+	default:
+		return nil, nil, errors.New("crypto/pkcs12: unsupported encryption algorithm " + alg.Algorithm.String())
+	}
+
+	var params pbeParams
+	if err := unmarshal(alg.Parameters.FullBytes, &params); err != nil {
+		return nil, nil, errors.New("crypto/pkcs12: malformed encryption parameters: " + err.Error())
+	}
+	key := pkcs12KDF(sha1.New, password, params.Salt, params.Iterations, pkcs12KDFKey, keyLen)
+	iv := pkcs12KDF(sha1.New, password, params.Salt, params.Iterations, pkcs12KDFIV, 8)
+	block, err := newCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return block, iv, nil
+}
+
+// pbes2Cipher returns the block cipher and IV for the DER-encoded PBES2
+// parameters. Only PBKDF2 with AES-CBC is supported.
+func pbes2Cipher(der []byte, password string) (cipher.Block, []byte, error) {
+	var params pbes2Params
+	if err := unmarshal(der, &params); err != nil {
+		return nil, nil, errors.New("crypto/pkcs12: malformed PBES2 parameters: " + err.Error())
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, nil, errors.New("crypto/pkcs12: unsupported PBES2 key derivation function " + params.KeyDerivationFunc.Algorithm.String())
+	}
+	var kdf pbkdf2Params
+	if err := unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		return nil, nil, errors.New("crypto/pkcs12: malformed PBKDF2 parameters: " + err.Error())
+	}
+
+	var keyLen int
+	switch scheme := params.EncryptionScheme.Algorithm; {
+	case scheme.Equal(oidAES128CBC):
+		keyLen = 16
+	case scheme.Equal(oidAES192CBC):
+		keyLen = 24
+	case scheme.Equal(oidAES256CBC):
+		keyLen = 32
+	default:
+		return nil, nil, errors.New("crypto/pkcs12: unsupported PBES2 encryption scheme " + scheme.String())
+	}
+	if kdf.KeyLength != 0 && kdf.KeyLength != keyLen {
+		return nil, nil, errors.New("crypto/pkcs12: invalid PBKDF2 key length")
+	}
+	var iv []byte
+	if err := unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil || len(iv) != aes.BlockSize {
+		return nil, nil, errors.New("crypto/pkcs12: invalid AES-CBC IV")
+	}
+
+	var key []byte
+	var err error
+	switch prf := kdf.PRF.Algorithm; {
+	case prf == nil, prf.Equal(oidHMACWithSHA1):
+		key, err = pbkdf2.Key(sha1.New, password, kdf.Salt, kdf.Iterations, keyLen)
+	case prf.Equal(oidHMACWithSHA224):
+		key, err = pbkdf2.Key(sha256.New224, password, kdf.Salt, kdf.Iterations, keyLen)
+	case prf.Equal(oidHMACWithSHA256):
+		key, err = pbkdf2.Key(sha256.New, password, kdf.Salt, kdf.Iterations, keyLen)
+	case prf.Equal(oidHMACWithSHA384):
+		key, err = pbkdf2.Key(sha512.New384, password, kdf.Salt, kdf.Iterations, keyLen)
+	case prf.Equal(oidHMACWithSHA512):
+		key, err = pbkdf2.Key(sha512.New, password, kdf.Salt, kdf.Iterations, keyLen)
+	default:
+		return nil, nil, errors.New("crypto/pkcs12: unsupported PBKDF2 PRF " + prf.String())
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return block, iv, nil
+}
+
+// encrypt encrypts plaintext with alg, using a random salt, and returns the
+// algorithm identifier with its parameters and the ciphertext.
+func encrypt(alg pbeAlgorithm, plaintext []byte, pw *pfxPassword, iterations int) (pkix.AlgorithmIdentifier, []byte, error) {
+	var id pkix.AlgorithmIdentifier
+	var params any
+	salt := randomSalt()
+	switch alg {
+	case pbes2AES256:
+		iv := make([]byte, aes.BlockSize)
+		rand.Reader.Read(iv)
+		ivParams, err := asn1.Marshal(iv)
+		if err != nil {
+			return id, nil, err
+		}
+		kdfParams, err := asn1.Marshal(pbkdf2Params{
+			Salt:       salt,
+			Iterations: iterations,
+			PRF:        pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+		})
+		if err != nil {
+			return id, nil, err
+		}
+		id.Algorithm = oidPBES2
+		params = pbes2Params{
+			KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+			EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParams}},
+		}
+	case pbeSHA1TripleDES:
+		id.Algorithm = oidPBEWithSHAAnd3KeyTripleDESCBC
+		params = pbeParams{Salt: salt, Iterations: iterations}
+	case pbeSHA1RC2:
+		id.Algorithm = oidPBEWithSHAAnd40BitRC2CBC
+		params = pbeParams{Salt: salt, Iterations: iterations}
+	default:
+		panic("crypto/pkcs12: unknown encryption algorithm")
+	}
+	der, err := asn1.Marshal(params)
+	if err != nil {
+		return id, nil, err
+	}
+	id.Parameters = asn1.RawValue{FullBytes: der}
+
+	// Encrypt through the same code path as decrypt, so that the parameters
+	// are guaranteed to be interpreted the same way.
+	var block cipher.Block
+	var iv []byte
+	if alg == pbes2AES256 {
+		block, iv, err = pbes2Cipher(der, pw.raw)
+	} else {
+		block, iv, err = pkcs12Cipher(id, pw.bmp)
+	}
+	if err != nil {
+		return id, nil, err
+	}
+	bs := block.BlockSize()
+	n := bs - len(plaintext)%bs
+	ciphertext := append(bytes.Clone(plaintext), bytes.Repeat([]byte{byte(n)}, n)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+	return id, ciphertext, nil
+}
@@ -0,0 +1,475 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package pkcs12 implements decoding and encoding of PKCS #12 (PFX) files, as
+// defined in RFC 7292, which bundle a private key with its certificate chain.
+//
+// Only password-protected files containing a single private key are
+// supported. Files may use the modern PBES2 encryption scheme with AES, as
+// produced by OpenSSL 3 by default, or the legacy password-based encryption
+// schemes with 3DES and RC2 still required by some older software.
+//
+// PKCS #12 relies on a key derivation function that is not approved for use
+// in FIPS 140-3 mode, so [Decode] and [Encode] return an error if
+// [crypto/fips140.Enforced] is true.
+package pkcs12
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/internal/fips140only"
+	"crypto/internal/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+)
+
+// ErrIncorrectPassword is returned by [Decode] when the PFX data can't be
+// authenticated or decrypted with the password.
+var ErrIncorrectPassword = errors.New("crypto/pkcs12: incorrect password")
+
+var errFIPS = errors.New("crypto/pkcs12: use of PKCS #12 is not allowed in FIPS 140-only mode")
+
+var (
+	oidDataContentType          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidEncryptedDataContentType = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 6}
+
+	oidKeyBag              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 1}
+	oidPKCS8ShroudedKeyBag = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+
+	oidCertTypeX509Certificate = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+	oidLocalKeyID              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 21}
+)
+
+// pfxPDU is the PFX structure of RFC 7292, Section 4.
+type pfxPDU struct {
+	Version  int
+	AuthSafe contentInfo
+	MacData  macData `asn1:"optional"`
+}
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"tag:0,explicit,optional"`
+}
+
+type encryptedData struct {
+	Version              int
+	EncryptedContentInfo encryptedContentInfo
+}
+
+type encryptedContentInfo struct {
+	ContentType                asn1.ObjectIdentifier
+	ContentEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedContent           []byte `asn1:"tag:0,optional"`
+}
+
+type safeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue     `asn1:"tag:0,explicit"`
+	Attributes []pkcs12Attribute `asn1:"set,optional"`
+}
+
+type pkcs12Attribute struct {
+	ID    asn1.ObjectIdentifier
+	Value asn1.RawValue `asn1:"set"`
+}
+
+type certBag struct {
+	ID   asn1.ObjectIdentifier
+	Data []byte `asn1:"tag:0,explicit"`
+}
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// unmarshal parses the DER-encoded ASN.1 in into out, rejecting trailing data.
+func unmarshal(in []byte, out any) error {
+	rest, err := asn1.Unmarshal(in, out)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 0 {
+		return errors.New("trailing data")
+	}
+	return nil
+}
+
+// Decode extracts a private key, the certificate matching it, and any other
+// certificates from pfxData, which must be a DER-encoded PKCS #12 file
+// protected by password.
+//
+// The private key is returned as by [x509.ParsePKCS8PrivateKey]. The
+// certificate is the one the file associates with the key, or otherwise the
+// one with the key's public key. The remaining certificates are returned as
+// caCerts, in the order they appear in the file.
+//
+// Decode returns [ErrIncorrectPassword] if the password is wrong, and an
+// error if the file doesn't contain exactly one private key, or no
+// certificate matches it. Bags of other types, such as CRLs and secrets, are
+// ignored.
+func Decode(pfxData []byte, password string) (privateKey any, certificate *x509.Certificate, caCerts []*x509.Certificate, err error) {
+	if fips140only.Enforced() {
+		return nil, nil, nil, errFIPS
+	}
+
+	bags, pw, err := safeContents(pfxData, password)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var keyID []byte
+	var certs []*x509.Certificate
+	var certIDs [][]byte
+	for _, bag := range bags {
+		switch {
+		case bag.ID.Equal(oidKeyBag), bag.ID.Equal(oidPKCS8ShroudedKeyBag):
+			if privateKey != nil {
+				return nil, nil, nil, errors.New("crypto/pkcs12: found more than one private key")
+			}
+			der := bag.Value.Bytes
+			if bag.ID.Equal(oidPKCS8ShroudedKeyBag) {
+				var info encryptedPrivateKeyInfo
+				if err := unmarshal(der, &info); err != nil {
+					return nil, nil, nil, errors.New("crypto/pkcs12: malformed shrouded key bag: " + err.Error())
+				}
+				if der, err = decrypt(info.Algorithm, info.EncryptedData, pw); err != nil {
+					return nil, nil, nil, err
+				}
+			}
+			if privateKey, err = x509.ParsePKCS8PrivateKey(der); err != nil {
+				return nil, nil, nil, errors.New("crypto/pkcs12: invalid private key: " + err.Error())
+			}
+			keyID = localKeyID(bag.Attributes)
+
+		case bag.ID.Equal(oidCertBag):
+			var cb certBag
+			if err := unmarshal(bag.Value.Bytes, &cb); err != nil {
+				return nil, nil, nil, errors.New("crypto/pkcs12: malformed certificate bag: " + err.Error())
+			}
+			if !cb.ID.Equal(oidCertTypeX509Certificate) {
+				continue
+			}
+			cert, err := x509.ParseCertificate(cb.Data)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			certs = append(certs, cert)
+			certIDs = append(certIDs, localKeyID(bag.Attributes))
+		}
+	}
+	if privateKey == nil {
+		return nil, nil, nil, errors.New("crypto/pkcs12: no private key found")
+	}
+
+	leaf := -1
+	if keyID != nil {
+		for i, id := range certIDs {
+			if bytes.Equal(id, keyID) {
+				leaf = i
+				break
+			}
+		}
+	}
+	if leaf == -1 {
+		for i, cert := range certs {
+			if keyMatches(privateKey, cert) {
+				leaf = i
+				break
+			}
+		}
+	}
+	if leaf == -1 {
+		return nil, nil, nil, errors.New("crypto/pkcs12: no certificate matches the private key")
+	}
+	certificate = certs[leaf]
+	for i, cert := range certs {
+		if i != leaf {
+			caCerts = append(caCerts, cert)
+		}
+	}
+	return privateKey, certificate, caCerts, nil
+}
+
+// safeContents verifies the integrity of pfxData and returns the decrypted
+// bags it contains, as well as the encoding of password that passed the
+// integrity check.
+func safeContents(pfxData []byte, password string) ([]safeBag, *pfxPassword, error) {
+	var pfx pfxPDU
+	if err := unmarshal(pfxData, &pfx); err != nil {
+		return nil, nil, errors.New("crypto/pkcs12: malformed PFX: " + err.Error())
+	}
+	if pfx.Version != 3 {
+		return nil, nil, errors.New("crypto/pkcs12: unsupported PFX version")
+	}
+	if !pfx.AuthSafe.ContentType.Equal(oidDataContentType) {
+		return nil, nil, errors.New("crypto/pkcs12: only password integrity mode is supported")
+	}
+	var authSafe []byte
+	if err := unmarshal(pfx.AuthSafe.Content.Bytes, &authSafe); err != nil {
+		return nil, nil, errors.New("crypto/pkcs12: malformed PFX: " + err.Error())
+	}
+	if pfx.MacData.Mac.Algorithm.Algorithm == nil {
+		return nil, nil, errors.New("crypto/pkcs12: PFX has no MAC")
+	}
+
+	pw, err := newPassword(password)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := verifyMAC(&pfx.MacData, authSafe, pw.bmp); err != nil {
+		// An empty password is encoded by some implementations as two zero
+		// bytes, and by others as an empty string.
+		if err != ErrIncorrectPassword || password != "" {
+			return nil, nil, err
+		}
+		pw.bmp = nil
+		if err := verifyMAC(&pfx.MacData, authSafe, pw.bmp); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var infos []contentInfo
+	if err := unmarshal(authSafe, &infos); err != nil {
+		return nil, nil, errors.New("crypto/pkcs12: malformed authenticated safe: " + err.Error())
+	}
+	var bags []safeBag
+	for _, info := range infos {
+		var data []byte
+		switch {
+		case info.ContentType.Equal(oidDataContentType):
+			if err := unmarshal(info.Content.Bytes, &data); err != nil {
+				return nil, nil, errors.New("crypto/pkcs12: malformed authenticated safe: " + err.Error())
+			}
+		case info.ContentType.Equal(oidEncryptedDataContentType):
+			var ed encryptedData
+			if err := unmarshal(info.Content.Bytes, &ed); err != nil {
+				return nil, nil, errors.New("crypto/pkcs12: malformed encrypted data: " + err.Error())
+			}
+			if data, err = decrypt(ed.EncryptedContentInfo.ContentEncryptionAlgorithm, ed.EncryptedContentInfo.EncryptedContent, pw); err != nil {
+				return nil, nil, err
+			}
+		default:
+			return nil, nil, errors.New("crypto/pkcs12: unsupported content type " + info.ContentType.String())
+		}
+		var contents []safeBag
+		if err := unmarshal(data, &contents); err != nil {
+			return nil, nil, errors.New("crypto/pkcs12: malformed safe contents: " + err.Error())
+		}
+		bags = append(bags, contents...)
+	}
+	return bags, pw, nil
+}
+
+// localKeyID returns the value of the localKeyId attribute, or nil.
+func localKeyID(attrs []pkcs12Attribute) []byte {
+	for _, attr := range attrs {
+		if !attr.ID.Equal(oidLocalKeyID) {
+			continue
+		}
+		var id []byte
+		if unmarshal(attr.Value.Bytes, &id) != nil {
+			return nil
+		}
+		return id
+	}
+	return nil
+}
+
+// A Profile is a set of algorithms used by [Encode] to protect a PFX file.
+type Profile int
+
+const (
+	// Modern encrypts the private key and certificates with PBES2, using
+	// PBKDF2 with HMAC-SHA-256 and AES-256-CBC, and protects the file's
+	// integrity with HMAC-SHA-256. It matches the default of OpenSSL 3, and
+	// is supported by most current software.
+	Modern Profile = iota
+
+	// Legacy encrypts the private key with pbeWithSHAAnd3-KeyTripleDES-CBC and
+	// the certificates with pbeWithSHAAnd40BitRC2-CBC, and protects the
+	// file's integrity with HMAC-SHA-1. It matches the -legacy option of
+	// OpenSSL, and should only be used for compatibility with software that
+	// doesn't support Modern, as the algorithms are weak.
+	Legacy
+)
+
+// EncodeOptions configures [Encode].
+type EncodeOptions struct {
+	// Profile selects the encryption and integrity algorithms.
+	Profile Profile
+
+	// Iterations is the iteration count of the key derivation functions.
+	// If zero, 2048 is used, matching OpenSSL.
+	Iterations int
+}
+
+const defaultIterations = 2048
+
+// Encode returns a DER-encoded PKCS #12 file containing privateKey,
+// certificate and caCerts, protected by password. If opts is nil, the
+// default options are used.
+//
+// privateKey must be supported by [x509.MarshalPKCS8PrivateKey] and match
+// the public key of certificate, and password must only contain characters
+// from the Unicode Basic Multilingual Plane.
+func Encode(privateKey any, certificate *x509.Certificate, caCerts []*x509.Certificate, password string, opts *EncodeOptions) ([]byte, error) {
+	if fips140only.Enforced() {
+		return nil, errFIPS
+	}
+	if opts == nil {
+		opts = &EncodeOptions{}
+	}
+	if opts.Profile != Modern && opts.Profile != Legacy {
+		return nil, errors.New("crypto/pkcs12: unknown profile")
+	}
+	iterations := opts.Iterations
+	if iterations == 0 {
+		iterations = defaultIterations
+	}
+	if iterations < 0 {
+		return nil, errors.New("crypto/pkcs12: invalid iteration count")
+	}
+	if certificate == nil {
+		return nil, errors.New("crypto/pkcs12: missing certificate")
+	}
+	if !keyMatches(privateKey, certificate) {
+		return nil, errors.New("crypto/pkcs12: private key does not match the certificate")
+	}
+	pw, err := newPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// As OpenSSL does, use the SHA-1 hash of the certificate to link it to
+	// the private key.
+	id := sha1.Sum(certificate.Raw)
+	idAttr, err := localKeyIDAttribute(id[:])
+	if err != nil {
+		return nil, err
+	}
+
+	certBags := make([]safeBag, 0, 1+len(caCerts))
+	for i, cert := range append([]*x509.Certificate{certificate}, caCerts...) {
+		bag, err := asn1.Marshal(certBag{ID: oidCertTypeX509Certificate, Data: cert.Raw})
+		if err != nil {
+			return nil, err
+		}
+		sb := safeBag{ID: oidCertBag, Value: explicit(bag)}
+		if i == 0 {
+			sb.Attributes = []pkcs12Attribute{idAttr}
+		}
+		certBags = append(certBags, sb)
+	}
+	certContents, err := asn1.Marshal(certBags)
+	if err != nil {
+		return nil, err
+	}
+
+	certAlg, keyAlg, macHash := pbes2AES256, pbes2AES256, crypto.SHA256
+	if opts.Profile == Legacy {
+		certAlg, keyAlg, macHash = pbeSHA1RC2, pbeSHA1TripleDES, crypto.SHA1
+	}
+
+	alg, ciphertext, err := encrypt(certAlg, certContents, pw, iterations)
+	if err != nil {
+		return nil, err
+	}
+	certSafe, err := asn1.Marshal(encryptedData{
+		Version: 0,
+		EncryptedContentInfo: encryptedContentInfo{
+			ContentType:                oidDataContentType,
+			ContentEncryptionAlgorithm: alg,
+			EncryptedContent:           ciphertext,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	alg, ciphertext, err = encrypt(keyAlg, keyDER, pw, iterations)
+	if err != nil {
+		return nil, err
+	}
+	shrouded, err := asn1.Marshal(encryptedPrivateKeyInfo{Algorithm: alg, EncryptedData: ciphertext})
+	if err != nil {
+		return nil, err
+	}
+	keyContents, err := asn1.Marshal([]safeBag{{
+		ID:         oidPKCS8ShroudedKeyBag,
+		Value:      explicit(shrouded),
+		Attributes: []pkcs12Attribute{idAttr},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	keySafe, err := asn1.Marshal(keyContents)
+	if err != nil {
+		return nil, err
+	}
+
+	authSafe, err := asn1.Marshal([]contentInfo{
+		{ContentType: oidEncryptedDataContentType, Content: explicit(certSafe)},
+		{ContentType: oidDataContentType, Content: explicit(keySafe)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	mac, err := computeMAC(macHash, authSafe, pw.bmp, iterations)
+	if err != nil {
+		return nil, err
+	}
+	authSafeData, err := asn1.Marshal(authSafe)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(pfxPDU{
+		Version:  3,
+		AuthSafe: contentInfo{ContentType: oidDataContentType, Content: explicit(authSafeData)},
+		MacData:  *mac,
+	})
+}
+
+// keyMatches reports whether the public key of privateKey is the one in cert.
+func keyMatches(privateKey any, cert *x509.Certificate) bool {
+	priv, ok := privateKey.(interface{ Public() crypto.PublicKey })
+	if !ok {
+		return false
+	}
+	pub, ok := priv.Public().(interface{ Equal(crypto.PublicKey) bool })
+	return ok && pub.Equal(cert.PublicKey)
+}
+
+// explicit wraps DER-encoded ASN.1 in an [0] EXPLICIT tag.
+func explicit(der []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: der}
+}
+
+func localKeyIDAttribute(id []byte) (pkcs12Attribute, error) {
+	value, err := asn1.Marshal(id)
+	if err != nil {
+		return pkcs12Attribute{}, err
+	}
+	return pkcs12Attribute{
+		ID:    oidLocalKeyID,
+		Value: asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: value},
+	}, nil
+}
+
+// randomSalt returns a new random salt for key derivation.
+func randomSalt() []byte {
+	salt := make([]byte, 16)
+	rand.Reader.Read(salt)
+	return salt
+}
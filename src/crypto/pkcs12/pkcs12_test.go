@@ -0,0 +1,256 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pkcs12
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// The files in testdata were generated with OpenSSL 3.0, from an RSA leaf
+// certificate issued by an ECDSA CA, with
+//
+//	openssl pkcs12 -export [-legacy] -inkey leaf.key -in leaf.pem [-certfile ca.pem] -passout pass:[password]
+func TestDecodeOpenSSL(t *testing.T) {
+	tests := []struct {
+		file     string
+		password string
+		caCerts  int
+	}{
+		{"modern.p12", "password", 1},
+		{"legacy.p12", "password", 1},
+		{"empty.p12", "", 1},
+		{"legacy-empty.p12", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			key, cert, caCerts, err := Decode(data, tt.password)
+			if err != nil {
+				t.Fatal(err)
+			}
+			priv, ok := key.(*rsa.PrivateKey)
+			if !ok {
+				t.Fatalf("got private key of type %T, want *rsa.PrivateKey", key)
+			}
+			if err := priv.Validate(); err != nil {
+				t.Errorf("invalid private key: %v", err)
+			}
+			if !priv.PublicKey.Equal(cert.PublicKey) {
+				t.Errorf("private key doesn't match the certificate")
+			}
+			if cert.Subject.CommonName != "pkcs12.example" {
+				t.Errorf("certificate CommonName = %q, want %q", cert.Subject.CommonName, "pkcs12.example")
+			}
+			if len(caCerts) != tt.caCerts {
+				t.Fatalf("got %d CA certificates, want %d", len(caCerts), tt.caCerts)
+			}
+			if len(caCerts) > 0 {
+				if caCerts[0].Subject.CommonName != "PKCS12 Test CA" {
+					t.Errorf("CA certificate CommonName = %q, want %q", caCerts[0].Subject.CommonName, "PKCS12 Test CA")
+				}
+				if err := cert.CheckSignatureFrom(caCerts[0]); err != nil {
+					t.Errorf("certificate is not signed by the CA: %v", err)
+				}
+			}
+
+			if _, _, _, err := Decode(data, "wrong"); err != ErrIncorrectPassword {
+				t.Errorf("Decode with the wrong password = %v, want ErrIncorrectPassword", err)
+			}
+		})
+	}
+}
+
+func newTestCertificate(t *testing.T, key crypto.Signer, parent *x509.Certificate, parentKey crypto.Signer, name string) *x509.Certificate {
+	t.Helper()
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestEncode(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca := newTestCertificate(t, caKey, nil, nil, "CA")
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, profile := range []Profile{Modern, Legacy} {
+		for _, key := range []crypto.Signer{rsaKey, ecdsaKey, ed25519Key} {
+			for _, password := range []string{"password", "", "pässwörd ☃"} {
+				leaf := newTestCertificate(t, key, ca, caKey, "leaf")
+				data, err := Encode(key, leaf, []*x509.Certificate{ca}, password, &EncodeOptions{Profile: profile, Iterations: 100})
+				if err != nil {
+					t.Fatal(err)
+				}
+				gotKey, gotLeaf, gotCAs, err := Decode(data, password)
+				if err != nil {
+					t.Fatalf("profile %v, %T, password %q: %v", profile, key, password, err)
+				}
+				if !key.(interface{ Equal(crypto.PrivateKey) bool }).Equal(gotKey) {
+					t.Errorf("profile %v, %T: private key doesn't round-trip", profile, key)
+				}
+				if !gotLeaf.Equal(leaf) {
+					t.Errorf("profile %v, %T: certificate doesn't round-trip", profile, key)
+				}
+				if len(gotCAs) != 1 || !gotCAs[0].Equal(ca) {
+					t.Errorf("profile %v, %T: CA certificates don't round-trip", profile, key)
+				}
+				if _, _, _, err := Decode(data, password+"x"); err != ErrIncorrectPassword {
+					t.Errorf("Decode with the wrong password = %v, want ErrIncorrectPassword", err)
+				}
+			}
+		}
+	}
+}
+
+func TestEncodeDefaults(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := newTestCertificate(t, key, nil, nil, "self-signed")
+	data, err := Encode(key, cert, nil, "password", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pfx pfxPDU
+	if err := unmarshal(data, &pfx); err != nil {
+		t.Fatal(err)
+	}
+	if pfx.MacData.Iterations != defaultIterations {
+		t.Errorf("MAC iterations = %d, want %d", pfx.MacData.Iterations, defaultIterations)
+	}
+	if !pfx.MacData.Mac.Algorithm.Algorithm.Equal(macHashes[1].oid) {
+		t.Errorf("MAC algorithm = %v, want SHA-256", pfx.MacData.Mac.Algorithm.Algorithm)
+	}
+
+	bags, _, err := safeContents(data, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The key and the certificate are linked by a localKeyId attribute.
+	id := sha1.Sum(cert.Raw)
+	for _, bag := range bags {
+		if !bytes.Equal(localKeyID(bag.Attributes), id[:]) {
+			t.Errorf("bag %v has localKeyId %x, want %x", bag.ID, localKeyID(bag.Attributes), id)
+		}
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := newTestCertificate(t, key, nil, nil, "self-signed")
+
+	if _, err := Encode(otherKey, cert, nil, "password", nil); err == nil {
+		t.Errorf("Encode accepted a private key not matching the certificate")
+	}
+	if _, err := Encode(key, nil, nil, "password", nil); err == nil {
+		t.Errorf("Encode accepted a nil certificate")
+	}
+	if _, err := Encode(key, cert, nil, "😀", nil); err == nil {
+		t.Errorf("Encode accepted a password outside of the BMP")
+	}
+	if _, err := Encode(key, cert, nil, "password", &EncodeOptions{Profile: 42}); err == nil {
+		t.Errorf("Encode accepted an unknown profile")
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := newTestCertificate(t, key, nil, nil, "self-signed")
+	data, err := Encode(key, cert, nil, "password", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, _, err := Decode(append(data, 0), "password"); err == nil {
+		t.Errorf("Decode accepted trailing data")
+	}
+	if _, _, _, err := Decode(data[:len(data)-1], "password"); err == nil {
+		t.Errorf("Decode accepted truncated data")
+	}
+
+	// Flip a bit in the authenticated safe, which is covered by the MAC.
+	tampered := bytes.Clone(data)
+	tampered[len(tampered)/2] ^= 1
+	if _, _, _, err := Decode(tampered, "password"); err == nil {
+		t.Errorf("Decode accepted tampered data")
+	}
+}
+
+func TestPKCS12KDF(t *testing.T) {
+	pw, err := newPassword("sesame")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := pkcs12KDF(sha1.New, pw.bmp, []byte("\xff\xff\xff\xff\xff\xff\xff\xff"), 2048, pkcs12KDFKey, 24)
+	if want := []byte("\x7c\xd9\xfd\x3e\x2b\x3b\xe7\x69\x1a\x44\xe3\xbe\xf0\xf9\xea\x0f\xb9\xb8\x97\xd4\xe3\x25\xd9\xd1"); !bytes.Equal(key, want) {
+		t.Errorf("got %x, want %x", key, want)
+	}
+
+	// This input makes I_j have a leading zero byte during the derivation.
+	key = pkcs12KDF(sha1.New, []byte{0, 0}, []byte("\xf3\x7e\x05\xb5\x18\x32\x4b\x4b"), 2048, pkcs12KDFKey, 24)
+	if want := []byte("\x00\xf7\x59\xff\x47\xd1\x4d\xd0\x36\x65\xd5\x94\x3c\xb3\xc4\xa3\x9a\x25\x55\xc0\x2a\xed\x66\xe1"); !bytes.Equal(key, want) {
+		t.Errorf("got %x, want %x", key, want)
+	}
+}
@@ -6,6 +6,7 @@ package tls
 
 import (
 	"crypto"
+	"crypto/mldsa"
 	"crypto/tls/internal/fips140tls"
 	"internal/testenv"
 	"strconv"
@@ -30,6 +31,11 @@ func TestSignatureSelection(t *testing.T) {
 		Certificate: [][]byte{testEd25519Certificate},
 		PrivateKey:  testEd25519PrivateKey,
 	}
+	mldsaKey, err := mldsa.NewPrivateKey(mldsa.MLDSA65(), make([]byte, mldsa.SeedSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mldsaCert := &Certificate{PrivateKey: mldsaKey}
 
 	tests := []struct {
 		cert        *Certificate
@@ -54,6 +60,7 @@ func TestSignatureSelection(t *testing.T) {
 		{ecdsaCert, []SignatureScheme{ECDSAWithP256AndSHA256}, VersionTLS13, "", ECDSAWithP256AndSHA256, signatureECDSA, crypto.SHA256},
 		{ed25519Cert, []SignatureScheme{Ed25519}, VersionTLS12, "", Ed25519, signatureEd25519, directSigning},
 		{ed25519Cert, []SignatureScheme{Ed25519}, VersionTLS13, "", Ed25519, signatureEd25519, directSigning},
+		{mldsaCert, []SignatureScheme{Ed25519, MLDSA44, MLDSA65}, VersionTLS13, "", MLDSA65, signatureMLDSA, directSigning},
 
 		// TLS 1.2 without signature_algorithms extension
 		{rsaCert, nil, VersionTLS12, "tlssha1=1", PKCS1WithSHA1, signaturePKCS1v15, crypto.SHA1},
@@ -122,6 +129,9 @@ func TestSignatureSelection(t *testing.T) {
 		{ed25519Cert, nil, VersionTLS13},
 		// Wrong curve, which TLS 1.3 checks
 		{ecdsaCert, []SignatureScheme{ECDSAWithP384AndSHA384}, VersionTLS13},
+		// Wrong ML-DSA parameter set, and ML-DSA is only supported in TLS 1.3.
+		{mldsaCert, []SignatureScheme{MLDSA44, MLDSA87}, VersionTLS13},
+		{mldsaCert, []SignatureScheme{MLDSA65}, VersionTLS12},
 		// TLS 1.3 does not support PKCS1v1.5 or SHA-1.
 		{rsaCert, []SignatureScheme{PKCS1WithSHA256}, VersionTLS13},
 		{pkcs1Cert, []SignatureScheme{PSSWithSHA256, PKCS1WithSHA256}, VersionTLS13},
@@ -15,6 +15,7 @@ import (
 
 var tlsmlkem = godebug.New("tlsmlkem")
 var tlssecpmlkem = godebug.New("tlssecpmlkem")
+var tlsmldsa = godebug.New("tlsmldsa")
 
 // defaultCurvePreferences is the default set of supported key exchanges, as
 // well as the preference order.
@@ -39,6 +40,26 @@ func defaultCurvePreferences() []CurveID {
 // CertificateRequest. The two fields are merged to match with TLS 1.3.
 // Note that in TLS 1.2, the ECDSA algorithms are not constrained to P-256, etc.
 func defaultSupportedSignatureAlgorithms() []SignatureScheme {
+	// tlsmldsa=1 enables the ML-DSA algorithms, which are only used in TLS 1.3.
+	if tlsmldsa.Value() == "1" {
+		return []SignatureScheme{
+			PSSWithSHA256,
+			ECDSAWithP256AndSHA256,
+			Ed25519,
+			PSSWithSHA384,
+			PSSWithSHA512,
+			PKCS1WithSHA256,
+			PKCS1WithSHA384,
+			PKCS1WithSHA512,
+			ECDSAWithP384AndSHA384,
+			ECDSAWithP521AndSHA512,
+			MLDSA44,
+			MLDSA65,
+			MLDSA87,
+			PKCS1WithSHA1,
+			ECDSAWithSHA1,
+		}
+	}
 	return []SignatureScheme{
 		PSSWithSHA256,
 		ECDSAWithP256AndSHA256,
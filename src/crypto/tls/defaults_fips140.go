@@ -10,6 +10,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/elliptic"
+	"crypto/mldsa"
 	"crypto/rsa"
 	"crypto/x509"
 )
@@ -49,6 +50,9 @@ var (
 		PKCS1WithSHA512,
 		ECDSAWithP384AndSHA384,
 		ECDSAWithP521AndSHA512,
+		MLDSA44,
+		MLDSA65,
+		MLDSA87,
 	}
 	allowedCipherSuitesFIPS = []uint16{
 		TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
@@ -70,7 +74,7 @@ func isCertificateAllowedFIPS(c *x509.Certificate) bool {
 		return k.N.BitLen() >= 2048
 	case *ecdsa.PublicKey:
 		return k.Curve == elliptic.P256() || k.Curve == elliptic.P384() || k.Curve == elliptic.P521()
-	case ed25519.PublicKey:
+	case ed25519.PublicKey, *mldsa.PublicKey:
 		return true
 	default:
 		return false
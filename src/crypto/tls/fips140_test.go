@@ -164,7 +164,10 @@ func isFIPSSignatureScheme(alg SignatureScheme) bool {
 		ECDSAWithP521AndSHA512,
 		PSSWithSHA256,
 		PSSWithSHA384,
-		PSSWithSHA512:
+		PSSWithSHA512,
+		MLDSA44,
+		MLDSA65,
+		MLDSA87:
 		return true
 	case Ed25519:
 		// Only for the native module.
@@ -279,6 +282,8 @@ func TestFIPSServerSignatureAndHash(t *testing.T) {
 
 			sigType, _, _ := typeAndHashFromSignatureScheme(sigHash)
 			switch sigType {
+			case signatureMLDSA:
+				t.Skip("ML-DSA is only supported in TLS 1.3")
 			case signaturePKCS1v15, signatureRSAPSS:
 				serverConfig.CipherSuites = []uint16{TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}
 				serverConfig.Certificates[0].Certificate = [][]byte{testRSAPSS2048Certificate}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"crypto/mldsa"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/tls/internal/fips140tls"
@@ -20,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"internal/byteorder"
+	"internal/testenv"
 	"io"
 	"math/big"
 	"net"
@@ -2927,3 +2929,77 @@ func TestECHTLS12Server(t *testing.T) {
 		t.Fatalf("unexpected handshake error: got %q, want %q", err, expectedErr)
 	}
 }
+
+func TestHandshakeMLDSA(t *testing.T) {
+	newCert := func(t *testing.T, params *mldsa.Parameters) (Certificate, *x509.CertPool) {
+		k, err := mldsa.GenerateKey(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "test"},
+			DNSNames:     []string{"example.golang"},
+			NotBefore:    testConfig.Time().Add(-time.Hour),
+			NotAfter:     testConfig.Time().Add(time.Hour),
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, k.PublicKey(), k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pool := x509.NewCertPool()
+		pool.AddCert(cert)
+		return Certificate{Certificate: [][]byte{der}, PrivateKey: k, Leaf: cert}, pool
+	}
+	configs := func(t *testing.T, params *mldsa.Parameters) (clientConfig, serverConfig *Config) {
+		serverCert, serverPool := newCert(t, params)
+		clientCert, clientPool := newCert(t, params)
+		clientConfig, serverConfig = testConfig.Clone(), testConfig.Clone()
+		serverConfig.Certificates = []Certificate{serverCert}
+		serverConfig.ClientAuth = RequireAndVerifyClientCert
+		serverConfig.ClientCAs = clientPool
+		clientConfig.Certificates = []Certificate{clientCert}
+		clientConfig.RootCAs = serverPool
+		clientConfig.ServerName = "example.golang"
+		clientConfig.InsecureSkipVerify = false
+		return clientConfig, serverConfig
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		// Without tlsmldsa=1, the ML-DSA algorithms are not advertised.
+		clientConfig, serverConfig := configs(t, mldsa.MLDSA65())
+		if _, _, err := testHandshake(t, clientConfig, serverConfig); err == nil {
+			t.Fatal("handshake succeeded without tlsmldsa=1")
+		}
+	})
+
+	testenv.SetGODEBUG(t, "tlsmldsa=1")
+	for _, params := range []*mldsa.Parameters{mldsa.MLDSA44(), mldsa.MLDSA65(), mldsa.MLDSA87()} {
+		t.Run(params.String(), func(t *testing.T) {
+			clientConfig, serverConfig := configs(t, params)
+			serverState, clientState, err := testHandshake(t, clientConfig, serverConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if clientState.Version != VersionTLS13 {
+				t.Errorf("negotiated version %x, want TLS 1.3", clientState.Version)
+			}
+			if len(clientState.VerifiedChains) != 1 || len(serverState.VerifiedChains) != 1 {
+				t.Errorf("peer certificates were not verified")
+			}
+		})
+	}
+
+	t.Run("TLS12", func(t *testing.T) {
+		clientConfig, serverConfig := configs(t, mldsa.MLDSA65())
+		serverConfig.MaxVersion = VersionTLS12
+		if _, _, err := testHandshake(t, clientConfig, serverConfig); err == nil {
+			t.Fatal("TLS 1.2 handshake with ML-DSA certificates succeeded")
+		}
+	})
+}
@@ -340,6 +340,10 @@ func (ka *ecdheKeyAgreement) processServerKeyExchange(config *Config, clientHell
 		if err != nil {
 			return err
 		}
+		if sigType == signatureMLDSA {
+			// ML-DSA is only defined for TLS 1.3.
+			return errors.New("tls: certificate used with invalid signature algorithm")
+		}
 		if sigHash == crypto.SHA1 {
 			tlssha1.Value() // ensure godebug is initialized
 			tlssha1.IncNonDefault()
@@ -0,0 +1,236 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tls
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"sync"
+	"time"
+)
+
+// An OCSPStapler keeps the OCSP response stapled to a server certificate up
+// to date.
+//
+// It fetches OCSP responses from the responders listed in the leaf
+// certificate, and serves the certificate with the most recent valid response
+// as its [Certificate.OCSPStaple]. If a response expires before a new one can
+// be obtained, the certificate is served without a staple. Responses that
+// don't specify a NextUpdate time expire seven days after their ThisUpdate
+// time, as they would be rejected by [x509.RevocationOptions] checks.
+//
+// An OCSPStapler is safe for concurrent use.
+type OCSPStapler struct {
+	fetcher      x509.RevocationFetcher
+	base         Certificate
+	leaf, issuer *x509.Certificate
+
+	mu          sync.Mutex
+	cert        *Certificate       // base with the current staple
+	resp        *x509.OCSPResponse // parsed current staple, or nil
+	lastAttempt time.Time
+	failures    int
+}
+
+const (
+	ocspMinRetry = time.Minute
+	ocspMaxRetry = time.Hour
+
+	// ocspRefreshWithoutNextUpdate is how often responses that don't
+	// specify a NextUpdate time are refreshed.
+	ocspRefreshWithoutNextUpdate = time.Hour
+
+	// ocspMaxAgeWithoutNextUpdate is how long after ThisUpdate responses that
+	// don't specify a NextUpdate time are served. It matches the age beyond
+	// which crypto/x509 rejects such responses.
+	ocspMaxAgeWithoutNextUpdate = 7 * 24 * time.Hour
+)
+
+// ocspExpiry returns the time after which resp must no longer be served.
+func ocspExpiry(resp *x509.OCSPResponse) time.Time {
+	if resp.NextUpdate.IsZero() {
+		return resp.ThisUpdate.Add(ocspMaxAgeWithoutNextUpdate)
+	}
+	return resp.NextUpdate
+}
+
+// NewOCSPStapler returns an OCSPStapler for cert, which fetches responses
+// using fetcher. cert.Certificate must contain the certificate of the issuer
+// of the leaf as its second element.
+//
+// If cert.OCSPStaple is set and is a valid, current response, it is served
+// until it needs refreshing. Otherwise, the certificate is served without a
+// staple until [OCSPStapler.Refresh] succeeds.
+//
+// The OCSPStapler doesn't fetch responses on its own, see [OCSPStapler.Run].
+func NewOCSPStapler(cert *Certificate, fetcher x509.RevocationFetcher) (*OCSPStapler, error) {
+	if fetcher == nil {
+		return nil, errors.New("tls: nil OCSP fetcher")
+	}
+	if len(cert.Certificate) < 2 {
+		return nil, errors.New("tls: OCSP stapling requires the issuer certificate in the chain")
+	}
+	leaf, err := cert.leaf()
+	if err != nil {
+		return nil, err
+	}
+	if len(leaf.OCSPServer) == 0 {
+		return nil, errors.New("tls: certificate doesn't specify an OCSP responder")
+	}
+	issuer, err := x509.ParseCertificate(cert.Certificate[1])
+	if err != nil {
+		return nil, err
+	}
+
+	s := &OCSPStapler{
+		fetcher: fetcher,
+		base:    *cert,
+		leaf:    leaf,
+		issuer:  issuer,
+	}
+	s.base.Leaf = leaf
+	s.base.OCSPStaple = nil
+	s.cert = &s.base
+	if cert.OCSPStaple != nil {
+		if resp, err := s.checkResponse(cert.OCSPStaple, time.Now()); err == nil {
+			s.setResponse(cert.OCSPStaple, resp)
+		}
+	}
+	return s, nil
+}
+
+// Certificate returns the certificate with the current OCSP staple, if any.
+// The returned Certificate must not be modified.
+func (s *OCSPStapler) Certificate() *Certificate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.resp != nil && time.Now().After(ocspExpiry(s.resp)) {
+		// Serving an expired response would be worse than serving none.
+		s.resp = nil
+		s.cert = &s.base
+	}
+	return s.cert
+}
+
+// GetCertificate returns [OCSPStapler.Certificate]. It can be used as
+// [Config.GetCertificate].
+func (s *OCSPStapler) GetCertificate(*ClientHelloInfo) (*Certificate, error) {
+	return s.Certificate(), nil
+}
+
+// Refresh fetches a new OCSP response and, if it is valid and more recent
+// than the current one, starts serving it. If no valid response can be
+// obtained, Refresh returns an error and the current staple, if any, remains
+// in use until it expires.
+func (s *OCSPStapler) Refresh() error {
+	req, err := x509.CreateOCSPRequest(s.leaf, s.issuer, 0)
+	if err != nil {
+		return err
+	}
+
+	var der []byte
+	var resp *x509.OCSPResponse
+	for _, server := range s.leaf.OCSPServer {
+		der, err = s.fetcher.FetchOCSP(server, req)
+		if err == nil {
+			resp, err = s.checkResponse(der, time.Now())
+		}
+		if err == nil {
+			break
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastAttempt = time.Now()
+	if err != nil {
+		s.failures++
+		return err
+	}
+	s.failures = 0
+	if s.resp == nil || resp.ThisUpdate.After(s.resp.ThisUpdate) {
+		s.setResponse(der, resp)
+	}
+	return nil
+}
+
+// checkResponse parses and verifies a DER-encoded OCSP response for the leaf.
+func (s *OCSPStapler) checkResponse(der []byte, now time.Time) (*x509.OCSPResponse, error) {
+	resp, err := x509.ParseOCSPResponse(der, s.leaf)
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.CheckSignatureFrom(s.issuer); err != nil {
+		return nil, err
+	}
+	if now.Before(resp.ThisUpdate) || now.After(ocspExpiry(resp)) {
+		return nil, errors.New("tls: OCSP response is expired or not yet valid")
+	}
+	if resp.Status == x509.OCSPUnknown {
+		return nil, errors.New("tls: OCSP responder doesn't know about the certificate")
+	}
+	return resp, nil
+}
+
+func (s *OCSPStapler) setResponse(der []byte, resp *x509.OCSPResponse) {
+	cert := s.base
+	cert.OCSPStaple = der
+	s.cert = &cert
+	s.resp = resp
+}
+
+// nextRefresh returns when Run should next call Refresh. The zero time means
+// immediately.
+func (s *OCSPStapler) nextRefresh() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Refresh halfway through the validity period of the current response,
+	// so that there's ample time to retry if the responder is unavailable.
+	var next time.Time
+	if s.resp != nil {
+		if s.resp.NextUpdate.IsZero() {
+			next = s.resp.ThisUpdate.Add(ocspRefreshWithoutNextUpdate)
+		} else {
+			next = s.resp.ThisUpdate.Add(s.resp.NextUpdate.Sub(s.resp.ThisUpdate) / 2)
+		}
+	}
+	// Don't retry sooner than ocspMinRetry after the last attempt, even if it
+	// succeeded: the responder may keep serving a response that is past its
+	// refresh point. After failures, back off exponentially.
+	if !s.lastAttempt.IsZero() {
+		backoff := ocspMinRetry
+		if s.failures > 6 {
+			backoff = ocspMaxRetry
+		} else if s.failures > 0 {
+			backoff = min(ocspMinRetry<<(s.failures-1), ocspMaxRetry)
+		}
+		if retry := s.lastAttempt.Add(backoff); retry.After(next) {
+			next = retry
+		}
+	}
+	return next
+}
+
+// Run refreshes the OCSP staple in the background until ctx is done, at which
+// point it returns ctx.Err().
+//
+// It fetches a new response immediately if there is no current one, and
+// otherwise halfway through the validity period of the current one, but no
+// more often than once a minute. Failed attempts are retried with exponential
+// backoff, between one minute and one hour.
+func (s *OCSPStapler) Run(ctx context.Context) error {
+	for {
+		timer := time.NewTimer(time.Until(s.nextRefresh()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		s.Refresh()
+	}
+}
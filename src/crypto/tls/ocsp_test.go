@@ -0,0 +1,345 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tls
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testOCSPResponder struct {
+	issuer *x509.Certificate
+	key    crypto.Signer
+
+	mu       sync.Mutex
+	status   x509.OCSPStatus
+	validity time.Duration
+	fail     bool
+	age      time.Duration // how long ago responses were produced
+	calls    int
+}
+
+func (r *testOCSPResponder) FetchOCSP(server string, request []byte) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	if r.fail {
+		return nil, errors.New("responder unavailable")
+	}
+	req, err := x509.ParseOCSPRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.OCSPResponse{
+		Status:       r.status,
+		SerialNumber: req.SerialNumber,
+		ThisUpdate:   now.Add(-r.age - time.Second),
+		RevokedAt:    now.Add(-time.Hour),
+	}
+	if r.validity != 0 {
+		template.NextUpdate = now.Add(-r.age + r.validity)
+	}
+	return x509.CreateOCSPResponse(rand.Reader, template, r.issuer, r.issuer, r.key)
+}
+
+func (r *testOCSPResponder) FetchCRL(url string) ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+func newOCSPStaplerTest(t *testing.T) (*Certificate, *x509.CertPool, *testOCSPResponder) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "OCSP Stapling Test CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		DNSNames:     []string{"example.golang"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		OCSPServer:   []string{"http://ocsp.example.golang"},
+	}, ca, leafKey.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	cert := &Certificate{
+		Certificate: [][]byte{leafDER, caDER},
+		PrivateKey:  leafKey,
+	}
+	responder := &testOCSPResponder{issuer: ca, key: caKey, status: x509.OCSPGood, validity: time.Hour}
+	return cert, roots, responder
+}
+
+func TestOCSPStapler(t *testing.T) {
+	cert, roots, responder := newOCSPStaplerTest(t)
+
+	if _, err := NewOCSPStapler(&Certificate{Certificate: cert.Certificate[:1]}, responder); err == nil {
+		t.Errorf("NewOCSPStapler accepted a chain without the issuer")
+	}
+
+	s, err := NewOCSPStapler(cert, responder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Certificate().OCSPStaple != nil {
+		t.Errorf("unexpected staple before Refresh")
+	}
+	if err := s.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	staple := s.Certificate().OCSPStaple
+	if staple == nil {
+		t.Fatal("no staple after Refresh")
+	}
+	if cert.OCSPStaple != nil {
+		t.Errorf("the original Certificate was modified")
+	}
+
+	// The staple is served, and can be used for revocation checking.
+	serverConfig := testConfig.Clone()
+	serverConfig.Certificates = nil
+	serverConfig.GetCertificate = s.GetCertificate
+	clientConfig := testConfig.Clone()
+	clientConfig.InsecureSkipVerify = false
+	clientConfig.RootCAs = roots
+	clientConfig.ServerName = "example.golang"
+	clientConfig.Time = time.Now
+	clientConfig.VerifyConnection = func(cs ConnectionState) error {
+		_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: x509.NewCertPool(),
+			Revocation: &x509.RevocationOptions{
+				Mode:       x509.RevocationHardFail,
+				OCSPStaple: cs.OCSPResponse,
+			},
+		})
+		return err
+	}
+	for _, v := range []uint16{VersionTLS12, VersionTLS13} {
+		clientConfig.MaxVersion = v
+		_, cs, err := testHandshake(t, clientConfig, serverConfig)
+		if err != nil {
+			t.Fatalf("%s: %v", VersionName(v), err)
+		}
+		if !bytes.Equal(cs.OCSPResponse, staple) {
+			t.Errorf("%s: client got a different OCSP response", VersionName(v))
+		}
+	}
+
+	// A failed refresh keeps the current staple.
+	responder.mu.Lock()
+	responder.fail = true
+	responder.mu.Unlock()
+	if err := s.Refresh(); err == nil {
+		t.Errorf("Refresh succeeded with a failing responder")
+	}
+	if !bytes.Equal(s.Certificate().OCSPStaple, staple) {
+		t.Errorf("staple changed after a failed Refresh")
+	}
+
+	// Responses with unknown status are rejected.
+	responder.mu.Lock()
+	responder.fail = false
+	responder.status = x509.OCSPUnknown
+	responder.mu.Unlock()
+	if err := s.Refresh(); err == nil {
+		t.Errorf("Refresh accepted an unknown status")
+	}
+}
+
+func TestOCSPStaplerInitialStaple(t *testing.T) {
+	cert, _, responder := newOCSPStaplerTest(t)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := x509.CreateOCSPRequest(leaf, responder.issuer, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert.OCSPStaple, err = responder.FetchOCSP("", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewOCSPStapler(cert, responder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(s.Certificate().OCSPStaple, cert.OCSPStaple) {
+		t.Errorf("a valid initial staple was not used")
+	}
+
+	cert.OCSPStaple = []byte("garbage")
+	s, err = NewOCSPStapler(cert, responder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Certificate().OCSPStaple != nil {
+		t.Errorf("an invalid initial staple was used")
+	}
+}
+
+func TestOCSPStaplerExpiry(t *testing.T) {
+	cert, _, responder := newOCSPStaplerTest(t)
+	s, err := NewOCSPStapler(cert, responder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Certificate().OCSPStaple == nil {
+		t.Fatal("no staple after Refresh")
+	}
+	s.mu.Lock()
+	s.resp.NextUpdate = time.Now().Add(-time.Second)
+	s.mu.Unlock()
+	if s.Certificate().OCSPStaple != nil {
+		t.Errorf("an expired staple is still served")
+	}
+}
+
+func TestOCSPStaplerNoNextUpdate(t *testing.T) {
+	cert, _, responder := newOCSPStaplerTest(t)
+	responder.validity = 0
+	responder.age = 8 * 24 * time.Hour
+	s, err := NewOCSPStapler(cert, responder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Refresh(); err == nil {
+		t.Errorf("Refresh accepted a response without NextUpdate produced eight days ago")
+	}
+
+	responder.age = 0
+	if err := s.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Certificate().OCSPStaple == nil {
+		t.Fatal("no staple after Refresh")
+	}
+	s.mu.Lock()
+	s.resp.ThisUpdate = time.Now().Add(-8 * 24 * time.Hour)
+	s.mu.Unlock()
+	if s.Certificate().OCSPStaple != nil {
+		t.Errorf("a staple without NextUpdate produced eight days ago is still served")
+	}
+}
+
+func TestOCSPStaplerRun(t *testing.T) {
+	cert, _, responder := newOCSPStaplerTest(t)
+	s, err := NewOCSPStapler(cert, responder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+	for s.Certificate().OCSPStaple == nil {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run = %v, want context.Canceled", err)
+	}
+
+	// The next refresh is halfway through the response validity period.
+	next := time.Until(s.nextRefresh())
+	if next < 20*time.Minute || next > 40*time.Minute {
+		t.Errorf("next refresh in %v, want about 30m", next)
+	}
+
+	// Failures are retried with backoff.
+	responder.mu.Lock()
+	responder.fail = true
+	responder.mu.Unlock()
+	s.mu.Lock()
+	s.resp = nil
+	s.mu.Unlock()
+	for i, want := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute} {
+		s.Refresh()
+		if next := time.Until(s.nextRefresh()); next > want || next < want-10*time.Second {
+			t.Errorf("after %d failures, next refresh in %v, want %v", i+1, next, want)
+		}
+	}
+	for range 10 {
+		s.Refresh()
+	}
+	if next := time.Until(s.nextRefresh()); next > time.Hour || next < time.Hour-10*time.Second {
+		t.Errorf("after many failures, next refresh in %v, want 1h", next)
+	}
+}
+
+func TestOCSPStaplerRunStaleResponder(t *testing.T) {
+	cert, _, responder := newOCSPStaplerTest(t)
+	// The responder keeps serving a response that is past its refresh point,
+	// but still valid.
+	responder.validity = 7 * 24 * time.Hour
+	responder.age = 4 * 24 * time.Hour
+	s, err := NewOCSPStapler(cert, responder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+	<-done
+
+	if s.Certificate().OCSPStaple == nil {
+		t.Errorf("no staple after Run")
+	}
+	responder.mu.Lock()
+	defer responder.mu.Unlock()
+	if responder.calls != 1 {
+		t.Errorf("got %d fetches, want 1", responder.calls)
+	}
+	if next := time.Until(s.nextRefresh()); next < ocspMinRetry-10*time.Second {
+		t.Errorf("next refresh in %v, want at least %v", next, ocspMinRetry)
+	}
+}
@@ -0,0 +1,660 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x509
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/subtle"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"io"
+	"math/big"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// OCSPStatus is the status of a certificate in an [OCSPResponse], as
+// specified in RFC 6960, Section 2.2.
+type OCSPStatus int
+
+const (
+	// OCSPGood indicates that the certificate is not revoked.
+	OCSPGood OCSPStatus = iota
+	// OCSPRevoked indicates that the certificate has been revoked.
+	OCSPRevoked
+	// OCSPUnknown indicates that the responder doesn't know about the
+	// certificate.
+	OCSPUnknown
+)
+
+func (s OCSPStatus) String() string {
+	switch s {
+	case OCSPGood:
+		return "good"
+	case OCSPRevoked:
+		return "revoked"
+	case OCSPUnknown:
+		return "unknown"
+	default:
+		return "OCSPStatus(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// OCSPResponseStatus is the processing status of an OCSP request, as
+// specified in RFC 6960, Section 4.2.1.
+type OCSPResponseStatus int
+
+const (
+	OCSPSuccessful       OCSPResponseStatus = 0
+	OCSPMalformedRequest OCSPResponseStatus = 1
+	OCSPInternalError    OCSPResponseStatus = 2
+	OCSPTryLater         OCSPResponseStatus = 3
+	// Status code four is not used.
+	OCSPSigRequired  OCSPResponseStatus = 5
+	OCSPUnauthorized OCSPResponseStatus = 6
+)
+
+func (s OCSPResponseStatus) String() string {
+	switch s {
+	case OCSPSuccessful:
+		return "successful"
+	case OCSPMalformedRequest:
+		return "malformed request"
+	case OCSPInternalError:
+		return "internal error"
+	case OCSPTryLater:
+		return "try later"
+	case OCSPSigRequired:
+		return "signature required"
+	case OCSPUnauthorized:
+		return "unauthorized"
+	default:
+		return "OCSPResponseStatus(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// OCSPResponseError is returned by [ParseOCSPResponse] when the responder
+// returned an error status instead of a signed response.
+type OCSPResponseError struct {
+	Status OCSPResponseStatus
+}
+
+func (e OCSPResponseError) Error() string {
+	return "x509: OCSP responder returned error status: " + e.Status.String()
+}
+
+// OCSPRequest represents a request for the status of a single certificate, as
+// specified in RFC 6960, Section 4.1.
+type OCSPRequest struct {
+	// Raw contains the complete ASN.1 DER content of the request. It is set
+	// when parsing a request.
+	Raw []byte
+
+	// IssuerHash is the hash function used to compute IssuerNameHash and
+	// IssuerKeyHash. It is one of crypto.SHA1, crypto.SHA256, crypto.SHA384
+	// or crypto.SHA512.
+	IssuerHash crypto.Hash
+	// IssuerNameHash is the hash of the DER-encoded issuer name.
+	IssuerNameHash []byte
+	// IssuerKeyHash is the hash of the issuer's public key, excluding the
+	// algorithm identifier and the BIT STRING tag, length and unused bits.
+	IssuerKeyHash []byte
+	// SerialNumber is the serial number of the certificate.
+	SerialNumber *big.Int
+}
+
+// OCSPResponse represents a signed OCSP response for a single certificate, as
+// specified in RFC 6960, Section 4.2.
+type OCSPResponse struct {
+	// Raw contains the complete ASN.1 DER content of the OCSP response.
+	Raw []byte
+	// RawTBSResponseData contains just the signed tbsResponseData portion of
+	// the ASN.1 DER.
+	RawTBSResponseData []byte
+
+	Signature []byte
+	// SignatureAlgorithm is used to determine the signature algorithm to be
+	// used when signing the response. If 0 the default algorithm for the
+	// signing key will be used.
+	SignatureAlgorithm SignatureAlgorithm
+
+	// RawResponderName contains the DER-encoded name of the responder, if the
+	// response identifies its responder by name. It is set when parsing a
+	// response.
+	RawResponderName []byte
+	// ResponderKeyHash contains the SHA-1 hash of the responder's public key,
+	// if the response identifies its responder by key. It is set when parsing
+	// a response.
+	ResponderKeyHash []byte
+	// Certificates are the certificates included in the response, usually a
+	// delegated responder certificate issued by the certificate's issuer. It
+	// is set when parsing a response.
+	Certificates []*Certificate
+
+	// ProducedAt is the time at which the response was signed. When creating
+	// a response, the zero value is replaced with the current time.
+	ProducedAt time.Time
+
+	// Status is the status of the certificate.
+	Status OCSPStatus
+	// SerialNumber is the serial number of the certificate.
+	SerialNumber *big.Int
+
+	// IssuerHash is the hash function used to compute IssuerNameHash and
+	// IssuerKeyHash. When creating a response, the zero value means
+	// crypto.SHA1, which is nearly universally used in OCSP.
+	IssuerHash crypto.Hash
+	// IssuerNameHash and IssuerKeyHash identify the issuer of the
+	// certificate, as in [OCSPRequest]. They are set when parsing a response,
+	// and are computed from the issuer certificate when creating a response.
+	IssuerNameHash []byte
+	IssuerKeyHash  []byte
+
+	// ThisUpdate is the time at which the status is known to be correct.
+	ThisUpdate time.Time
+	// NextUpdate is the time at or before which newer information will be
+	// available. If zero, newer information is always available.
+	NextUpdate time.Time
+
+	// RevokedAt is the time at which the certificate was revoked, if Status
+	// is OCSPRevoked.
+	RevokedAt time.Time
+	// RevocationReason is the reason for revocation, using the integer enum
+	// values specified in RFC 5280 Section 5.3.1, if Status is OCSPRevoked.
+	RevocationReason int
+
+	// Extensions contains the raw singleExtensions of the response. When
+	// creating a response, the Extensions field is ignored, see
+	// ExtraExtensions.
+	Extensions []pkix.Extension
+	// ExtraExtensions contains extensions to be copied, raw, into the
+	// singleExtensions of a created response.
+	ExtraExtensions []pkix.Extension
+}
+
+// These structures reflect the ASN.1 structure of OCSP requests and responses,
+// as specified in RFC 6960, Sections 4.1.1 and 4.2.1.
+
+type ocspCertID struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	NameHash      []byte
+	IssuerKeyHash []byte
+	SerialNumber  *big.Int
+}
+
+type ocspRequest struct {
+	TBSRequest ocspTBSRequest
+}
+
+type ocspTBSRequest struct {
+	Version       int           `asn1:"explicit,tag:0,default:0,optional"`
+	RequestorName asn1.RawValue `asn1:"explicit,tag:1,optional"`
+	RequestList   []ocspSingleRequest
+}
+
+type ocspSingleRequest struct {
+	Cert ocspCertID
+}
+
+type ocspResponseASN1 struct {
+	Status   asn1.Enumerated
+	Response ocspResponseBytes `asn1:"explicit,tag:0,optional"`
+}
+
+type ocspResponseBytes struct {
+	ResponseType asn1.ObjectIdentifier
+	Response     []byte
+}
+
+type ocspBasicResponse struct {
+	TBSResponseData    ocspResponseData
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          asn1.BitString
+	Certificates       []asn1.RawValue `asn1:"explicit,tag:0,optional"`
+}
+
+type ocspResponseData struct {
+	Raw            asn1.RawContent
+	Version        int `asn1:"optional,default:0,explicit,tag:0"`
+	RawResponderID asn1.RawValue
+	ProducedAt     time.Time `asn1:"generalized"`
+	Responses      []ocspSingleResponse
+	Extensions     []pkix.Extension `asn1:"explicit,tag:1,optional"`
+}
+
+type ocspSingleResponse struct {
+	CertID           ocspCertID
+	Good             asn1.Flag        `asn1:"tag:0,optional"`
+	Revoked          ocspRevokedInfo  `asn1:"tag:1,optional"`
+	Unknown          asn1.Flag        `asn1:"tag:2,optional"`
+	ThisUpdate       time.Time        `asn1:"generalized"`
+	NextUpdate       time.Time        `asn1:"generalized,explicit,tag:0,optional"`
+	SingleExtensions []pkix.Extension `asn1:"explicit,tag:1,optional"`
+}
+
+type ocspRevokedInfo struct {
+	RevocationTime time.Time       `asn1:"generalized"`
+	Reason         asn1.Enumerated `asn1:"explicit,tag:0,optional"`
+}
+
+var oidOCSPBasicResponse = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 1}
+
+var ocspHashOIDs = []struct {
+	hash crypto.Hash
+	oid  asn1.ObjectIdentifier
+}{
+	{crypto.SHA1, asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}},
+	{crypto.SHA256, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}},
+	{crypto.SHA384, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}},
+	{crypto.SHA512, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}},
+}
+
+func ocspHashFromOID(oid asn1.ObjectIdentifier) crypto.Hash {
+	for _, h := range ocspHashOIDs {
+		if h.oid.Equal(oid) {
+			return h.hash
+		}
+	}
+	return 0
+}
+
+func ocspOIDFromHash(hash crypto.Hash) asn1.ObjectIdentifier {
+	for _, h := range ocspHashOIDs {
+		if h.hash == hash {
+			return h.oid
+		}
+	}
+	return nil
+}
+
+// ocspIssuerHashes returns the hashes of the issuer's name and public key
+// used to identify it in OCSP requests and responses.
+func ocspIssuerHashes(issuer *Certificate, hash crypto.Hash) (nameHash, keyHash []byte, err error) {
+	if ocspOIDFromHash(hash) == nil {
+		return nil, nil, errors.New("x509: unsupported OCSP issuer hash algorithm")
+	}
+	var spki publicKeyInfo
+	if rest, err := asn1.Unmarshal(issuer.RawSubjectPublicKeyInfo, &spki); err != nil {
+		return nil, nil, err
+	} else if len(rest) != 0 {
+		return nil, nil, errors.New("x509: trailing data after issuer public key")
+	}
+	h := hash.New()
+	h.Write(issuer.RawSubject)
+	nameHash = h.Sum(nil)
+	h.Reset()
+	h.Write(spki.PublicKey.RightAlign())
+	keyHash = h.Sum(nil)
+	return nameHash, keyHash, nil
+}
+
+// CreateOCSPRequest returns a DER-encoded OCSP request for the status of
+// cert, which must have been issued by issuer. hash is used to identify the
+// issuer and, if zero, defaults to crypto.SHA1, which is nearly universally
+// supported by OCSP responders.
+func CreateOCSPRequest(cert, issuer *Certificate, hash crypto.Hash) ([]byte, error) {
+	if cert == nil || issuer == nil {
+		return nil, errors.New("x509: certificate and issuer can not be nil")
+	}
+	if hash == 0 {
+		hash = crypto.SHA1
+	}
+	nameHash, keyHash, err := ocspIssuerHashes(issuer, hash)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(ocspRequest{
+		TBSRequest: ocspTBSRequest{
+			RequestList: []ocspSingleRequest{{
+				Cert: ocspCertID{
+					HashAlgorithm: pkix.AlgorithmIdentifier{
+						Algorithm:  ocspOIDFromHash(hash),
+						Parameters: asn1.NullRawValue,
+					},
+					NameHash:      nameHash,
+					IssuerKeyHash: keyHash,
+					SerialNumber:  cert.SerialNumber,
+				},
+			}},
+		},
+	})
+}
+
+// ParseOCSPRequest parses a single OCSP request from the given ASN.1 DER data.
+// Only unsigned requests for the status of a single certificate are
+// supported.
+func ParseOCSPRequest(der []byte) (*OCSPRequest, error) {
+	var req ocspRequest
+	rest, err := asn1.Unmarshal(der, &req)
+	if err != nil {
+		return nil, errors.New("x509: malformed OCSP request")
+	}
+	if len(rest) != 0 {
+		// This also rejects the optionalSignature field, which follows
+		// tbsRequest in the OCSPRequest SEQUENCE.
+		return nil, errors.New("x509: trailing data or unsupported signature in OCSP request")
+	}
+	if len(req.TBSRequest.RequestList) != 1 {
+		return nil, errors.New("x509: OCSP request must be for a single certificate")
+	}
+	certID := req.TBSRequest.RequestList[0].Cert
+	hash := ocspHashFromOID(certID.HashAlgorithm.Algorithm)
+	if hash == 0 {
+		return nil, errors.New("x509: unsupported OCSP issuer hash algorithm")
+	}
+	return &OCSPRequest{
+		Raw:            der,
+		IssuerHash:     hash,
+		IssuerNameHash: certID.NameHash,
+		IssuerKeyHash:  certID.IssuerKeyHash,
+		SerialNumber:   certID.SerialNumber,
+	}, nil
+}
+
+// ParseOCSPResponse parses a signed OCSP response from the given ASN.1 DER
+// data. If the responder returned an error status instead, the returned error
+// is an [OCSPResponseError].
+//
+// If cert is not nil, the returned [OCSPResponse] is the one about cert, among
+// the possibly multiple ones in the response. Otherwise, the response must be
+// about a single certificate.
+//
+// The signature on the response is not verified, see
+// [OCSPResponse.CheckSignatureFrom].
+func ParseOCSPResponse(der []byte, cert *Certificate) (*OCSPResponse, error) {
+	var outer ocspResponseASN1
+	rest, err := asn1.Unmarshal(der, &outer)
+	if err != nil {
+		return nil, errors.New("x509: malformed OCSP response")
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("x509: trailing data in OCSP response")
+	}
+	if status := OCSPResponseStatus(outer.Status); status != OCSPSuccessful {
+		return nil, OCSPResponseError{status}
+	}
+	if !outer.Response.ResponseType.Equal(oidOCSPBasicResponse) {
+		return nil, errors.New("x509: unsupported OCSP response type")
+	}
+
+	var basic ocspBasicResponse
+	rest, err = asn1.Unmarshal(outer.Response.Response, &basic)
+	if err != nil {
+		return nil, errors.New("x509: malformed OCSP basic response")
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("x509: trailing data in OCSP basic response")
+	}
+	if basic.TBSResponseData.Version != 0 {
+		return nil, errors.New("x509: unsupported OCSP response version")
+	}
+	for _, ext := range basic.TBSResponseData.Extensions {
+		if ext.Critical {
+			return nil, errors.New("x509: unsupported critical extension in OCSP response")
+		}
+	}
+
+	responses := basic.TBSResponseData.Responses
+	var single *ocspSingleResponse
+	switch {
+	case len(responses) == 0:
+		return nil, errors.New("x509: OCSP response contains no certificate status")
+	case cert == nil && len(responses) != 1:
+		return nil, errors.New("x509: OCSP response contains multiple certificate statuses")
+	case cert == nil:
+		single = &responses[0]
+	default:
+		for i := range responses {
+			if responses[i].CertID.SerialNumber != nil && responses[i].CertID.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+				single = &responses[i]
+				break
+			}
+		}
+		if single == nil {
+			return nil, errors.New("x509: OCSP response contains no status for the certificate")
+		}
+	}
+	for _, ext := range single.SingleExtensions {
+		if ext.Critical {
+			return nil, errors.New("x509: unsupported critical extension in OCSP response")
+		}
+	}
+
+	resp := &OCSPResponse{
+		Raw:                der,
+		RawTBSResponseData: basic.TBSResponseData.Raw,
+		Signature:          basic.Signature.RightAlign(),
+		SignatureAlgorithm: getSignatureAlgorithmFromAI(basic.SignatureAlgorithm),
+		ProducedAt:         basic.TBSResponseData.ProducedAt,
+		SerialNumber:       single.CertID.SerialNumber,
+		IssuerHash:         ocspHashFromOID(single.CertID.HashAlgorithm.Algorithm),
+		IssuerNameHash:     single.CertID.NameHash,
+		IssuerKeyHash:      single.CertID.IssuerKeyHash,
+		ThisUpdate:         single.ThisUpdate,
+		NextUpdate:         single.NextUpdate,
+		Extensions:         single.SingleExtensions,
+	}
+	if resp.IssuerHash == 0 {
+		return nil, errors.New("x509: unsupported OCSP issuer hash algorithm")
+	}
+
+	responderID := basic.TBSResponseData.RawResponderID
+	switch {
+	case responderID.Class == asn1.ClassContextSpecific && responderID.Tag == 1:
+		var name asn1.RawValue
+		if rest, err := asn1.Unmarshal(responderID.Bytes, &name); err != nil || len(rest) != 0 || name.Tag != asn1.TagSequence {
+			return nil, errors.New("x509: malformed OCSP responder name")
+		}
+		resp.RawResponderName = responderID.Bytes
+	case responderID.Class == asn1.ClassContextSpecific && responderID.Tag == 2:
+		if rest, err := asn1.Unmarshal(responderID.Bytes, &resp.ResponderKeyHash); err != nil || len(rest) != 0 {
+			return nil, errors.New("x509: malformed OCSP responder key hash")
+		}
+	default:
+		return nil, errors.New("x509: malformed OCSP responder ID")
+	}
+
+	for _, raw := range basic.Certificates {
+		c, err := ParseCertificate(raw.FullBytes)
+		if err != nil {
+			return nil, err
+		}
+		resp.Certificates = append(resp.Certificates, c)
+	}
+
+	switch {
+	case bool(single.Good):
+		resp.Status = OCSPGood
+	case bool(single.Unknown):
+		resp.Status = OCSPUnknown
+	default:
+		resp.Status = OCSPRevoked
+		resp.RevokedAt = single.Revoked.RevocationTime
+		resp.RevocationReason = int(single.Revoked.Reason)
+	}
+
+	return resp, nil
+}
+
+// CheckSignatureFrom verifies that the signature on resp is a valid signature
+// from issuer, either directly or through a delegated responder certificate
+// included in the response, issued by issuer and with the OCSPSigning
+// extended key usage, as specified in RFC 6960, Section 4.2.2.2.
+//
+// It also checks that resp identifies issuer as the issuer of the
+// certificate. It doesn't check the validity period of the delegated
+// responder certificate, nor of the response itself.
+func (resp *OCSPResponse) CheckSignatureFrom(issuer *Certificate) error {
+	_, err := resp.checkSignatureFrom(issuer)
+	return err
+}
+
+// checkSignatureFrom is like CheckSignatureFrom but also returns the
+// certificate that signed the response.
+func (resp *OCSPResponse) checkSignatureFrom(issuer *Certificate) (*Certificate, error) {
+	if ocspOIDFromHash(resp.IssuerHash) == nil {
+		return nil, errors.New("x509: unsupported OCSP issuer hash algorithm")
+	}
+	nameHash, keyHash, err := ocspIssuerHashes(issuer, resp.IssuerHash)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(nameHash, resp.IssuerNameHash) != 1 ||
+		subtle.ConstantTimeCompare(keyHash, resp.IssuerKeyHash) != 1 {
+		return nil, errors.New("x509: OCSP response is for a different issuer")
+	}
+
+	if resp.signedBy(issuer) {
+		return issuer, checkSignature(resp.SignatureAlgorithm, resp.RawTBSResponseData, resp.Signature, issuer.PublicKey, true)
+	}
+
+	for _, responder := range resp.Certificates {
+		if !resp.signedBy(responder) {
+			continue
+		}
+		if !slices.Contains(responder.ExtKeyUsage, ExtKeyUsageOCSPSigning) {
+			return nil, errors.New("x509: OCSP responder certificate is not authorized for OCSP signing")
+		}
+		if err := responder.CheckSignatureFrom(issuer); err != nil {
+			return nil, errors.New("x509: OCSP responder certificate is not issued by the issuer: " + err.Error())
+		}
+		return responder, checkSignature(resp.SignatureAlgorithm, resp.RawTBSResponseData, resp.Signature, responder.PublicKey, true)
+	}
+
+	return nil, errors.New("x509: OCSP response is not signed by the issuer or a delegated responder")
+}
+
+// signedBy reports whether the responder ID of resp identifies c.
+func (resp *OCSPResponse) signedBy(c *Certificate) bool {
+	if resp.RawResponderName != nil {
+		return bytes.Equal(resp.RawResponderName, c.RawSubject)
+	}
+	_, keyHash, err := ocspIssuerHashes(c, crypto.SHA1)
+	return err == nil && bytes.Equal(resp.ResponderKeyHash, keyHash)
+}
+
+// CreateOCSPResponse creates a new signed OCSP response, according to RFC
+// 6960, based on template.
+//
+// The following members of template are used: ExtraExtensions, IssuerHash,
+// NextUpdate, ProducedAt, RevocationReason, RevokedAt, SerialNumber,
+// SignatureAlgorithm, Status and ThisUpdate.
+//
+// The response is signed by priv, which must be associated with the public key
+// of responder. If responder is not issuer, it must be a delegated responder
+// certificate issued by issuer with the OCSPSigning extended key usage, and it
+// is included in the response. The responder is identified by name.
+func CreateOCSPResponse(rand io.Reader, template *OCSPResponse, issuer, responder *Certificate, priv crypto.Signer) ([]byte, error) {
+	if template == nil {
+		return nil, errors.New("x509: template can not be nil")
+	}
+	if issuer == nil || responder == nil {
+		return nil, errors.New("x509: issuer and responder can not be nil")
+	}
+	if template.SerialNumber == nil {
+		return nil, errors.New("x509: template contains nil SerialNumber field")
+	}
+	if !template.NextUpdate.IsZero() && template.NextUpdate.Before(template.ThisUpdate) {
+		return nil, errors.New("x509: template.ThisUpdate is after template.NextUpdate")
+	}
+
+	hash := template.IssuerHash
+	if hash == 0 {
+		hash = crypto.SHA1
+	}
+	nameHash, keyHash, err := ocspIssuerHashes(issuer, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if pub, ok := priv.Public().(interface{ Equal(crypto.PublicKey) bool }); ok && !pub.Equal(responder.PublicKey) {
+		return nil, errors.New("x509: provided PrivateKey doesn't match responder's PublicKey")
+	}
+
+	signatureAlgorithm, algorithmIdentifier, err := signingParamsForKey(priv, template.SignatureAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	single := ocspSingleResponse{
+		CertID: ocspCertID{
+			HashAlgorithm: pkix.AlgorithmIdentifier{
+				Algorithm:  ocspOIDFromHash(hash),
+				Parameters: asn1.NullRawValue,
+			},
+			NameHash:      nameHash,
+			IssuerKeyHash: keyHash,
+			SerialNumber:  template.SerialNumber,
+		},
+		ThisUpdate:       template.ThisUpdate.UTC(),
+		NextUpdate:       template.NextUpdate.UTC(),
+		SingleExtensions: template.ExtraExtensions,
+	}
+	switch template.Status {
+	case OCSPGood:
+		single.Good = true
+	case OCSPUnknown:
+		single.Unknown = true
+	case OCSPRevoked:
+		if template.RevokedAt.IsZero() {
+			return nil, errors.New("x509: template contains zero RevokedAt field")
+		}
+		single.Revoked = ocspRevokedInfo{
+			RevocationTime: template.RevokedAt.UTC(),
+			Reason:         asn1.Enumerated(template.RevocationReason),
+		}
+	default:
+		return nil, errors.New("x509: template contains invalid Status field")
+	}
+
+	producedAt := template.ProducedAt
+	if producedAt.IsZero() {
+		producedAt = time.Now()
+	}
+	tbs := ocspResponseData{
+		RawResponderID: asn1.RawValue{
+			Class:      asn1.ClassContextSpecific,
+			Tag:        1,
+			IsCompound: true,
+			Bytes:      responder.RawSubject,
+		},
+		ProducedAt: producedAt.UTC().Truncate(time.Second),
+		Responses:  []ocspSingleResponse{single},
+	}
+	tbsContents, err := asn1.Marshal(tbs)
+	if err != nil {
+		return nil, err
+	}
+	tbs.Raw = tbsContents
+
+	signature, err := signTBS(tbsContents, priv, signatureAlgorithm, rand)
+	if err != nil {
+		return nil, err
+	}
+
+	basic := ocspBasicResponse{
+		TBSResponseData:    tbs,
+		SignatureAlgorithm: algorithmIdentifier,
+		Signature:          asn1.BitString{Bytes: signature, BitLength: len(signature) * 8},
+	}
+	if responder != issuer && !bytes.Equal(responder.Raw, issuer.Raw) {
+		basic.Certificates = []asn1.RawValue{{FullBytes: responder.Raw}}
+	}
+	basicContents, err := asn1.Marshal(basic)
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(ocspResponseASN1{
+		Status: asn1.Enumerated(OCSPSuccessful),
+		Response: ocspResponseBytes{
+			ResponseType: oidOCSPBasicResponse,
+			Response:     basicContents,
+		},
+	})
+}
@@ -0,0 +1,244 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x509
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// ocspTestPKI is a root CA, a leaf certificate it issued, and a delegated
+// OCSP responder certificate.
+type ocspTestPKI struct {
+	root, leaf, responder          *Certificate
+	rootKey, leafKey, responderKey crypto.Signer
+}
+
+func newOCSPTestPKI(t *testing.T) *ocspTestPKI {
+	t.Helper()
+	now := time.Now()
+	create := func(template, parent *Certificate, key, parentKey crypto.Signer) *Certificate {
+		t.Helper()
+		der, err := CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, err := ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cert
+	}
+	newKey := func() crypto.Signer {
+		t.Helper()
+		k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+
+	pki := &ocspTestPKI{rootKey: newKey(), leafKey: newKey(), responderKey: newKey()}
+	rootTemplate := &Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "OCSP Test Root"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		KeyUsage:              KeyUsageCertSign | KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	pki.root = create(rootTemplate, rootTemplate, pki.rootKey, pki.rootKey)
+	pki.leaf = create(&Certificate{
+		SerialNumber:          big.NewInt(42),
+		Subject:               pkix.Name{CommonName: "leaf"},
+		DNSNames:              []string{"example.com"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		KeyUsage:              KeyUsageDigitalSignature,
+		ExtKeyUsage:           []ExtKeyUsage{ExtKeyUsageServerAuth},
+		OCSPServer:            []string{"http://ocsp.example.com"},
+		CRLDistributionPoints: []string{"http://crl.example.com/root.crl"},
+	}, pki.root, pki.leafKey, pki.rootKey)
+	pki.responder = create(&Certificate{
+		SerialNumber: big.NewInt(43),
+		Subject:      pkix.Name{CommonName: "OCSP Responder"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(24 * time.Hour),
+		KeyUsage:     KeyUsageDigitalSignature,
+		ExtKeyUsage:  []ExtKeyUsage{ExtKeyUsageOCSPSigning},
+	}, pki.root, pki.responderKey, pki.rootKey)
+	return pki
+}
+
+// ocspResponse returns a response about pki.leaf signed by responder.
+func (pki *ocspTestPKI) ocspResponse(t *testing.T, status OCSPStatus, responder *Certificate, key crypto.Signer, thisUpdate, nextUpdate time.Time) []byte {
+	t.Helper()
+	template := &OCSPResponse{
+		Status:       status,
+		SerialNumber: pki.leaf.SerialNumber,
+		ThisUpdate:   thisUpdate,
+		NextUpdate:   nextUpdate,
+	}
+	if status == OCSPRevoked {
+		template.RevokedAt = thisUpdate.Add(-time.Minute)
+		template.RevocationReason = 1 // keyCompromise
+	}
+	der, err := CreateOCSPResponse(rand.Reader, template, pki.root, responder, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func TestOCSPRequest(t *testing.T) {
+	pki := newOCSPTestPKI(t)
+	for _, hash := range []crypto.Hash{0, crypto.SHA1, crypto.SHA256, crypto.SHA384, crypto.SHA512} {
+		der, err := CreateOCSPRequest(pki.leaf, pki.root, hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := ParseOCSPRequest(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := hash
+		if want == 0 {
+			want = crypto.SHA1
+		}
+		if req.IssuerHash != want {
+			t.Errorf("IssuerHash = %v, want %v", req.IssuerHash, want)
+		}
+		if req.SerialNumber.Cmp(pki.leaf.SerialNumber) != 0 {
+			t.Errorf("SerialNumber = %v, want %v", req.SerialNumber, pki.leaf.SerialNumber)
+		}
+		nameHash, keyHash, err := ocspIssuerHashes(pki.root, want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(req.IssuerNameHash, nameHash) || !bytes.Equal(req.IssuerKeyHash, keyHash) {
+			t.Errorf("issuer hashes don't match")
+		}
+		if !bytes.Equal(req.Raw, der) {
+			t.Errorf("Raw doesn't match the input")
+		}
+	}
+
+	if _, err := CreateOCSPRequest(pki.leaf, pki.root, crypto.MD5); err == nil {
+		t.Errorf("CreateOCSPRequest accepted MD5")
+	}
+
+	nameHash := sha1.Sum(pki.root.RawSubject)
+	der, _ := CreateOCSPRequest(pki.leaf, pki.root, crypto.SHA1)
+	req, _ := ParseOCSPRequest(der)
+	if !bytes.Equal(req.IssuerNameHash, nameHash[:]) {
+		t.Errorf("IssuerNameHash = %x, want %x", req.IssuerNameHash, nameHash)
+	}
+
+	if _, err := ParseOCSPRequest(append(der, 0)); err == nil {
+		t.Errorf("ParseOCSPRequest accepted trailing data")
+	}
+}
+
+func TestOCSPResponse(t *testing.T) {
+	pki := newOCSPTestPKI(t)
+	now := time.Now().Truncate(time.Second)
+
+	for _, status := range []OCSPStatus{OCSPGood, OCSPRevoked, OCSPUnknown} {
+		for _, delegated := range []bool{false, true} {
+			responder, key := pki.root, pki.rootKey
+			if delegated {
+				responder, key = pki.responder, pki.responderKey
+			}
+			der := pki.ocspResponse(t, status, responder, key, now, now.Add(time.Hour))
+			resp, err := ParseOCSPResponse(der, nil)
+			if err != nil {
+				t.Fatalf("%v/%v: %v", status, delegated, err)
+			}
+			if resp.Status != status {
+				t.Errorf("Status = %v, want %v", resp.Status, status)
+			}
+			if resp.SerialNumber.Cmp(pki.leaf.SerialNumber) != 0 {
+				t.Errorf("SerialNumber = %v, want %v", resp.SerialNumber, pki.leaf.SerialNumber)
+			}
+			if !resp.ThisUpdate.Equal(now) || !resp.NextUpdate.Equal(now.Add(time.Hour)) {
+				t.Errorf("ThisUpdate, NextUpdate = %v, %v, want %v, %v", resp.ThisUpdate, resp.NextUpdate, now, now.Add(time.Hour))
+			}
+			if status == OCSPRevoked && (resp.RevocationReason != 1 || !resp.RevokedAt.Equal(now.Add(-time.Minute))) {
+				t.Errorf("RevocationReason, RevokedAt = %v, %v", resp.RevocationReason, resp.RevokedAt)
+			}
+			if !bytes.Equal(resp.RawResponderName, responder.RawSubject) {
+				t.Errorf("RawResponderName doesn't match the responder")
+			}
+			if delegated != (len(resp.Certificates) == 1) {
+				t.Errorf("got %d certificates, delegated = %v", len(resp.Certificates), delegated)
+			}
+			if err := resp.CheckSignatureFrom(pki.root); err != nil {
+				t.Errorf("%v/%v: CheckSignatureFrom: %v", status, delegated, err)
+			}
+			if err := resp.CheckSignatureFrom(pki.responder); err == nil {
+				t.Errorf("%v/%v: CheckSignatureFrom accepted the wrong issuer", status, delegated)
+			}
+			if _, err := ParseOCSPResponse(der, pki.leaf); err != nil {
+				t.Errorf("ParseOCSPResponse for leaf: %v", err)
+			}
+			if _, err := ParseOCSPResponse(der, pki.root); err == nil {
+				t.Errorf("ParseOCSPResponse accepted a response for a different certificate")
+			}
+		}
+	}
+}
+
+func TestOCSPResponseUnauthorizedResponder(t *testing.T) {
+	pki := newOCSPTestPKI(t)
+	now := time.Now()
+
+	// The leaf is issued by the root but lacks the OCSPSigning EKU.
+	der := pki.ocspResponse(t, OCSPGood, pki.leaf, pki.leafKey, now, now.Add(time.Hour))
+	resp, err := ParseOCSPResponse(der, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := resp.CheckSignatureFrom(pki.root); err == nil {
+		t.Errorf("CheckSignatureFrom accepted a responder without the OCSPSigning EKU")
+	}
+
+	// Tamper with the signed data.
+	der = pki.ocspResponse(t, OCSPGood, pki.root, pki.rootKey, now, now.Add(time.Hour))
+	resp, err = ParseOCSPResponse(der, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Signature[len(resp.Signature)-1] ^= 1
+	if err := resp.CheckSignatureFrom(pki.root); err == nil {
+		t.Errorf("CheckSignatureFrom accepted an invalid signature")
+	}
+
+	if _, err := CreateOCSPResponse(rand.Reader, &OCSPResponse{SerialNumber: big.NewInt(1)}, pki.root, pki.responder, pki.rootKey); err == nil {
+		t.Errorf("CreateOCSPResponse accepted a key not matching the responder")
+	}
+}
+
+func TestOCSPResponseError(t *testing.T) {
+	// A tryLater response, with no responseBytes.
+	_, err := ParseOCSPResponse([]byte{0x30, 0x03, 0x0a, 0x01, 0x03}, nil)
+	var respErr OCSPResponseError
+	if !errors.As(err, &respErr) || respErr.Status != OCSPTryLater {
+		t.Errorf("ParseOCSPResponse = %v, want an OCSPResponseError with OCSPTryLater", err)
+	}
+
+	if _, err := ParseOCSPResponse([]byte{0x30, 0x03, 0x0a, 0x01}, nil); err == nil {
+		t.Errorf("ParseOCSPResponse accepted a truncated response")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"crypto/ecdh"
 	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/mldsa"
 	"crypto/rsa"
 	"crypto/x509/pkix"
 	"encoding/asn1"
@@ -310,6 +311,14 @@ func parsePublicKey(keyData *publicKeyInfo) (any, error) {
 			return nil, errors.New("x509: X25519 key encoded with illegal parameters")
 		}
 		return ecdh.X25519().NewPublicKey(data)
+	case mldsaParametersFromOID(oid) != nil:
+		// RFC 9881, Section 2
+		// > The contents of the parameters component for each algorithm
+		// > MUST be absent.
+		if len(params.FullBytes) != 0 {
+			return nil, errors.New("x509: ML-DSA key encoded with illegal parameters")
+		}
+		return mldsa.NewPublicKey(mldsaParametersFromOID(oid), data)
 	case oid.Equal(oidPublicKeyDSA):
 		der := cryptobyte.String(data)
 		y := new(big.Int)
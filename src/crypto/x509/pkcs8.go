@@ -8,11 +8,17 @@ import (
 	"crypto/ecdh"
 	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/internal/mldsaexpand"
+	"crypto/mldsa"
 	"crypto/rsa"
+	"crypto/subtle"
 	"crypto/x509/pkix"
 	"encoding/asn1"
 	"errors"
 	"fmt"
+
+	"golang.org/x/crypto/cryptobyte"
+	cryptobyte_asn1 "golang.org/x/crypto/cryptobyte/asn1"
 )
 
 // pkcs8 reflects an ASN.1, PKCS #8 PrivateKey. See
@@ -28,8 +34,12 @@ type pkcs8 struct {
 // ParsePKCS8PrivateKey parses an unencrypted private key in PKCS #8, ASN.1 DER form.
 //
 // It returns a *[rsa.PrivateKey], an *[ecdsa.PrivateKey], an [ed25519.PrivateKey] (not
-// a pointer), or an *[ecdh.PrivateKey] (for X25519). More types might be supported
-// in the future.
+// a pointer), an *[ecdh.PrivateKey] (for X25519), or an *[mldsa.PrivateKey]. More
+// types might be supported in the future.
+//
+// ML-DSA private keys are supported in the seed and both forms of RFC 9881.
+// A key in the both form is expanded from its seed, and rejected if its
+// expanded key does not match.
 //
 // This kind of key is commonly encoded in PEM blocks of type "PRIVATE KEY".
 //
@@ -89,6 +99,48 @@ func ParsePKCS8PrivateKey(der []byte) (key any, err error) {
 		}
 		return ecdh.X25519().NewPrivateKey(curvePrivateKey)
 
+	case mldsaParametersFromOID(privKey.Algo.Algorithm) != nil:
+		if l := len(privKey.Algo.Parameters.FullBytes); l != 0 {
+			return nil, errors.New("x509: invalid ML-DSA private key parameters")
+		}
+		// RFC 9881, Section 6
+		//
+		//	ML-DSA-PrivateKey ::= CHOICE {
+		//	  seed [0] OCTET STRING (SIZE (32)),
+		//	  expandedKey OCTET STRING,
+		//	  both SEQUENCE { seed OCTET STRING, expandedKey OCTET STRING } }
+		params := mldsaParametersFromOID(privKey.Algo.Algorithm)
+		input := cryptobyte.String(privKey.PrivateKey)
+		var seed, expandedKey cryptobyte.String
+		switch {
+		case input.PeekASN1Tag(cryptobyte_asn1.Tag(0).ContextSpecific()):
+			if !input.ReadASN1(&seed, cryptobyte_asn1.Tag(0).ContextSpecific()) || !input.Empty() {
+				return nil, errors.New("x509: invalid ML-DSA private key")
+			}
+		case input.PeekASN1Tag(cryptobyte_asn1.SEQUENCE):
+			var both cryptobyte.String
+			if !input.ReadASN1(&both, cryptobyte_asn1.SEQUENCE) || !input.Empty() ||
+				!both.ReadASN1(&seed, cryptobyte_asn1.OCTET_STRING) ||
+				!both.ReadASN1(&expandedKey, cryptobyte_asn1.OCTET_STRING) || !both.Empty() {
+				return nil, errors.New("x509: invalid ML-DSA private key")
+			}
+		default:
+			return nil, errors.New("x509: invalid ML-DSA private key: the expandedKey form is not supported")
+		}
+		if l := len(seed); l != mldsa.SeedSize {
+			return nil, fmt.Errorf("x509: invalid ML-DSA private key length: %d", l)
+		}
+		key, err := mldsa.NewPrivateKey(params, seed)
+		if err != nil {
+			return nil, err
+		}
+		if expandedKey != nil {
+			if subtle.ConstantTimeCompare(mldsaexpand.PrivateKeyBytes(key), expandedKey) != 1 {
+				return nil, errors.New("x509: invalid ML-DSA private key: expanded key does not match seed")
+			}
+		}
+		return key, nil
+
 	default:
 		return nil, fmt.Errorf("x509: PKCS#8 wrapping contained private key with unknown algorithm: %v", privKey.Algo.Algorithm)
 	}
@@ -97,7 +149,8 @@ func ParsePKCS8PrivateKey(der []byte) (key any, err error) {
 // MarshalPKCS8PrivateKey converts a private key to PKCS #8, ASN.1 DER form.
 //
 // The following key types are currently supported: *[rsa.PrivateKey],
-// *[ecdsa.PrivateKey], [ed25519.PrivateKey] (not a pointer), and *[ecdh.PrivateKey].
+// *[ecdsa.PrivateKey], [ed25519.PrivateKey] (not a pointer), *[ecdh.PrivateKey],
+// and *[mldsa.PrivateKey]. ML-DSA keys are encoded in the seed form of RFC 9881.
 // Unsupported key types result in an error.
 //
 // This kind of key is commonly encoded in PEM blocks of type "PRIVATE KEY".
@@ -147,6 +200,16 @@ func MarshalPKCS8PrivateKey(key any) ([]byte, error) {
 		}
 		privKey.PrivateKey = curvePrivateKey
 
+	case *mldsa.PrivateKey:
+		privKey.Algo = pkix.AlgorithmIdentifier{
+			Algorithm: oidFromMLDSAParameters(k.Parameters()),
+		}
+		var b cryptobyte.Builder
+		b.AddASN1(cryptobyte_asn1.Tag(0).ContextSpecific(), func(b *cryptobyte.Builder) {
+			b.AddBytes(k.Bytes())
+		})
+		privKey.PrivateKey = b.BytesOrPanic()
+
 	case *ecdh.PrivateKey:
 		if k.Curve() == ecdh.X25519() {
 			privKey.Algo = pkix.AlgorithmIdentifier{
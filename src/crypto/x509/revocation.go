@@ -0,0 +1,290 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x509
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/asn1"
+	"errors"
+	"slices"
+	"time"
+)
+
+// RevocationMode controls how [Certificate.Verify] handles certificates whose
+// revocation status can't be determined.
+type RevocationMode int
+
+const (
+	// RevocationSoftFail rejects chains containing a certificate that is
+	// known to be revoked, but accepts certificates for which no valid
+	// revocation information is available.
+	RevocationSoftFail RevocationMode = iota
+
+	// RevocationHardFail additionally rejects chains containing a
+	// certificate for which no valid revocation information is available.
+	RevocationHardFail
+)
+
+// RevocationFetcher retrieves revocation information from the network. It is
+// used by [Certificate.Verify] when [RevocationOptions.Fetcher] is set.
+//
+// Implementations should apply their own timeouts, since Verify blocks on
+// them. They may cache responses, as long as they are not used past their
+// NextUpdate time.
+type RevocationFetcher interface {
+	// FetchOCSP sends the DER-encoded OCSP request to the responder at the
+	// given URL, taken from [Certificate.OCSPServer], and returns the
+	// DER-encoded response. See RFC 6960, Appendix A.
+	FetchOCSP(server string, request []byte) ([]byte, error)
+
+	// FetchCRL returns the DER-encoded CRL at the given URL, taken from
+	// [Certificate.CRLDistributionPoints].
+	FetchCRL(url string) ([]byte, error)
+}
+
+// RevocationOptions configures revocation checking in [Certificate.Verify].
+//
+// The revocation status of each certificate in a chain, other than the root,
+// is determined by consulting, in order, the stapled OCSP response (for the
+// leaf only), the provided CRLs, and then the certificate's OCSP responders
+// and CRL distribution points through the Fetcher, until one of them provides
+// a valid answer.
+//
+// OCSP responses must be signed by the certificate's issuer or by a delegated
+// responder, and CRLs must be signed by the certificate's issuer. Both must be
+// current at [VerifyOptions.CurrentTime]: not before their ThisUpdate time and
+// not after their NextUpdate time or, if they don't have one, not more than
+// seven days after their ThisUpdate time. Indirect and delta CRLs are not
+// supported, and partitioned CRLs are only used to find revoked certificates.
+type RevocationOptions struct {
+	// Mode controls whether certificates with unknown revocation status are
+	// accepted.
+	Mode RevocationMode
+
+	// OCSPStaple is an optional DER-encoded OCSP response for the leaf
+	// certificate, such as one stapled to a TLS handshake.
+	OCSPStaple []byte
+
+	// CRLs is an optional set of revocation lists to consult.
+	CRLs []*RevocationList
+
+	// Fetcher, if not nil, is used to retrieve OCSP responses and CRLs for
+	// certificates whose status is not established by OCSPStaple and CRLs.
+	Fetcher RevocationFetcher
+}
+
+// maxRevocationInfoAge is how long after ThisUpdate OCSP responses and CRLs
+// without a NextUpdate time are accepted, so that old responses can't be
+// replayed indefinitely.
+const maxRevocationInfoAge = 7 * 24 * time.Hour
+
+// expired reports whether revocation information with the given ThisUpdate
+// and NextUpdate times is no longer current at now.
+func expired(now, thisUpdate, nextUpdate time.Time) bool {
+	if nextUpdate.IsZero() {
+		nextUpdate = thisUpdate.Add(maxRevocationInfoAge)
+	}
+	return now.After(nextUpdate)
+}
+
+var oidExtensionIssuingDistributionPoint = asn1.ObjectIdentifier{2, 5, 29, 28}
+var oidExtensionDeltaCRLIndicator = asn1.ObjectIdentifier{2, 5, 29, 27}
+
+// filterRevokedChains removes chains that fail revocation checking according
+// to opts.Revocation. If no chains are left, it returns the error that caused
+// the first one to be rejected.
+func filterRevokedChains(chains [][]*Certificate, opts *VerifyOptions) ([][]*Certificate, error) {
+	if opts.Revocation == nil || len(chains) == 0 {
+		return chains, nil
+	}
+	rc := &revocationChecker{
+		opts:    opts.Revocation,
+		now:     opts.CurrentTime,
+		results: make(map[[2]*Certificate]error),
+		crls:    make(map[string]*RevocationList),
+	}
+	if rc.now.IsZero() {
+		rc.now = time.Now()
+	}
+	var firstErr error
+	chains = slices.DeleteFunc(chains, func(chain []*Certificate) bool {
+		err := rc.checkChain(chain)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return err != nil
+	})
+	if len(chains) == 0 {
+		return nil, firstErr
+	}
+	return chains, nil
+}
+
+type revocationChecker struct {
+	opts *RevocationOptions
+	now  time.Time
+
+	// results caches the outcome for each certificate and issuer pair, as
+	// they are shared by many candidate chains.
+	results map[[2]*Certificate]error
+	// crls caches fetched and parsed CRLs by URL. Failures are cached as nil.
+	crls map[string]*RevocationList
+}
+
+func (rc *revocationChecker) checkChain(chain []*Certificate) error {
+	for i := 0; i < len(chain)-1; i++ {
+		key := [2]*Certificate{chain[i], chain[i+1]}
+		err, ok := rc.results[key]
+		if !ok {
+			err = rc.check(chain[i], chain[i+1], i == 0)
+			rc.results[key] = err
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check returns nil if cert is known not to be revoked, or if its status is
+// unknown and the mode is RevocationSoftFail, and a CertificateInvalidError
+// otherwise.
+func (rc *revocationChecker) check(cert, issuer *Certificate, leaf bool) error {
+	// unknownDetail records why the last source of information was not
+	// usable, for the RevocationStatusUnknown error.
+	unknownDetail := "no revocation information available"
+
+	if leaf && rc.opts.OCSPStaple != nil {
+		known, err := rc.checkOCSP(cert, issuer, rc.opts.OCSPStaple)
+		if known {
+			return err
+		}
+		unknownDetail = "stapled OCSP response: " + err.Error()
+	}
+
+	for _, crl := range rc.opts.CRLs {
+		if known, err := rc.checkCRL(cert, issuer, crl); known {
+			return err
+		}
+	}
+
+	if f := rc.opts.Fetcher; f != nil {
+		if len(cert.OCSPServer) > 0 {
+			req, err := CreateOCSPRequest(cert, issuer, crypto.SHA1)
+			if err != nil {
+				unknownDetail = "OCSP request: " + err.Error()
+			} else {
+				for _, server := range cert.OCSPServer {
+					der, err := f.FetchOCSP(server, req)
+					if err == nil {
+						var known bool
+						if known, err = rc.checkOCSP(cert, issuer, der); known {
+							return err
+						}
+					}
+					unknownDetail = "OCSP response from " + server + ": " + err.Error()
+				}
+			}
+		}
+
+		for _, url := range cert.CRLDistributionPoints {
+			crl, ok := rc.crls[url]
+			if !ok {
+				der, err := f.FetchCRL(url)
+				if err == nil {
+					crl, err = ParseRevocationList(der)
+				}
+				if err != nil {
+					unknownDetail = "CRL from " + url + ": " + err.Error()
+				}
+				rc.crls[url] = crl
+			}
+			if crl == nil {
+				continue
+			}
+			if known, err := rc.checkCRL(cert, issuer, crl); known {
+				return err
+			}
+		}
+	}
+
+	if rc.opts.Mode == RevocationHardFail {
+		return CertificateInvalidError{cert, RevocationStatusUnknown, unknownDetail}
+	}
+	return nil
+}
+
+var (
+	errOCSPNotYetValid = errors.New("x509: OCSP response is not yet valid")
+	errOCSPExpired     = errors.New("x509: OCSP response has expired")
+	errOCSPUnknown     = errors.New("x509: OCSP responder doesn't know about the certificate")
+)
+
+// checkOCSP reports whether the DER-encoded OCSP response establishes the
+// revocation status of cert. If known is false, err explains why not.
+func (rc *revocationChecker) checkOCSP(cert, issuer *Certificate, der []byte) (known bool, err error) {
+	resp, err := ParseOCSPResponse(der, cert)
+	if err != nil {
+		return false, err
+	}
+	signer, err := resp.checkSignatureFrom(issuer)
+	if err != nil {
+		return false, err
+	}
+	if signer != issuer && (rc.now.Before(signer.NotBefore) || rc.now.After(signer.NotAfter)) {
+		return false, errors.New("x509: OCSP responder certificate has expired or is not yet valid")
+	}
+	if rc.now.Before(resp.ThisUpdate) {
+		return false, errOCSPNotYetValid
+	}
+	if expired(rc.now, resp.ThisUpdate, resp.NextUpdate) {
+		return false, errOCSPExpired
+	}
+
+	switch resp.Status {
+	case OCSPGood:
+		return true, nil
+	case OCSPRevoked:
+		return true, CertificateInvalidError{cert, CertificateRevoked,
+			"OCSP response reports revocation at " + resp.RevokedAt.Format(time.RFC3339)}
+	default:
+		return false, errOCSPUnknown
+	}
+}
+
+// checkCRL reports whether crl establishes the revocation status of cert, in
+// which case it returns a CertificateInvalidError if cert is revoked.
+func (rc *revocationChecker) checkCRL(cert, issuer *Certificate, crl *RevocationList) (known bool, err error) {
+	if !bytes.Equal(crl.RawIssuer, issuer.RawSubject) {
+		return false, nil
+	}
+	if rc.now.Before(crl.ThisUpdate) || expired(rc.now, crl.ThisUpdate, crl.NextUpdate) {
+		return false, nil
+	}
+	if crl.CheckSignatureFrom(issuer) != nil {
+		return false, nil
+	}
+
+	revoked := false
+	for _, entry := range crl.RevokedCertificateEntries {
+		if entry.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+			revoked = true
+			break
+		}
+	}
+	if revoked {
+		return true, CertificateInvalidError{cert, CertificateRevoked, "listed in CRL issued at " + crl.ThisUpdate.Format(time.RFC3339)}
+	}
+
+	// A partitioned or delta CRL only covers some certificates, so not
+	// being listed in it doesn't mean the certificate is not revoked.
+	for _, ext := range crl.Extensions {
+		if ext.Id.Equal(oidExtensionIssuingDistributionPoint) || ext.Id.Equal(oidExtensionDeltaCRLIndicator) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
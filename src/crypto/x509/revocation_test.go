@@ -0,0 +1,187 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x509
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+type testRevocationFetcher struct {
+	ocsp, crl           []byte
+	ocspCalls, crlCalls int
+}
+
+func (f *testRevocationFetcher) FetchOCSP(server string, request []byte) ([]byte, error) {
+	f.ocspCalls++
+	if _, err := ParseOCSPRequest(request); err != nil {
+		return nil, err
+	}
+	if f.ocsp == nil {
+		return nil, errors.New("no OCSP response")
+	}
+	return f.ocsp, nil
+}
+
+func (f *testRevocationFetcher) FetchCRL(url string) ([]byte, error) {
+	f.crlCalls++
+	if f.crl == nil {
+		return nil, errors.New("no CRL")
+	}
+	return f.crl, nil
+}
+
+func (pki *ocspTestPKI) crl(t *testing.T, revoked bool, thisUpdate, nextUpdate time.Time) []byte {
+	t.Helper()
+	template := &RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: thisUpdate,
+		NextUpdate: nextUpdate,
+	}
+	if revoked {
+		template.RevokedCertificateEntries = []RevocationListEntry{
+			{SerialNumber: pki.leaf.SerialNumber, RevocationTime: thisUpdate.Add(-time.Minute)},
+		}
+	}
+	// The root needs a SubjectKeyId to issue CRLs, which CreateCertificate
+	// generated for it.
+	der, err := CreateRevocationList(rand.Reader, template, pki.root, pki.rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func TestVerifyRevocation(t *testing.T) {
+	pki := newOCSPTestPKI(t)
+	now := time.Now()
+	roots := NewCertPool()
+	roots.AddCert(pki.root)
+
+	good := pki.ocspResponse(t, OCSPGood, pki.responder, pki.responderKey, now.Add(-time.Minute), now.Add(time.Hour))
+	revoked := pki.ocspResponse(t, OCSPRevoked, pki.root, pki.rootKey, now.Add(-time.Minute), now.Add(time.Hour))
+	unknown := pki.ocspResponse(t, OCSPUnknown, pki.root, pki.rootKey, now.Add(-time.Minute), now.Add(time.Hour))
+	expired := pki.ocspResponse(t, OCSPRevoked, pki.root, pki.rootKey, now.Add(-2*time.Hour), now.Add(-time.Hour))
+	noNextUpdate := pki.ocspResponse(t, OCSPGood, pki.root, pki.rootKey, now.Add(-time.Hour), time.Time{})
+	staleNoNextUpdate := pki.ocspResponse(t, OCSPGood, pki.root, pki.rootKey, now.Add(-8*24*time.Hour), time.Time{})
+	forged := pki.ocspResponse(t, OCSPGood, pki.leaf, pki.leafKey, now.Add(-time.Minute), now.Add(time.Hour))
+
+	parseCRL := func(der []byte) *RevocationList {
+		crl, err := ParseRevocationList(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return crl
+	}
+	revokedCRL := pki.crl(t, true, now.Add(-time.Minute), now.Add(time.Hour))
+	cleanCRL := pki.crl(t, false, now.Add(-time.Minute), now.Add(time.Hour))
+	staleCRL := pki.crl(t, true, now.Add(-2*time.Hour), now.Add(-time.Hour))
+
+	tests := []struct {
+		name    string
+		opts    RevocationOptions
+		wantErr InvalidReason
+		ok      bool
+	}{
+		{"NoInformation/SoftFail", RevocationOptions{}, 0, true},
+		{"NoInformation/HardFail", RevocationOptions{Mode: RevocationHardFail}, RevocationStatusUnknown, false},
+		{"Staple/Good", RevocationOptions{Mode: RevocationHardFail, OCSPStaple: good}, 0, true},
+		{"Staple/Revoked", RevocationOptions{OCSPStaple: revoked}, CertificateRevoked, false},
+		{"Staple/Unknown", RevocationOptions{Mode: RevocationHardFail, OCSPStaple: unknown}, RevocationStatusUnknown, false},
+		{"Staple/Expired", RevocationOptions{OCSPStaple: expired}, 0, true},
+		{"Staple/NoNextUpdate", RevocationOptions{Mode: RevocationHardFail, OCSPStaple: noNextUpdate}, 0, true},
+		{"Staple/StaleNoNextUpdate", RevocationOptions{Mode: RevocationHardFail, OCSPStaple: staleNoNextUpdate}, RevocationStatusUnknown, false},
+		{"Staple/Forged", RevocationOptions{Mode: RevocationHardFail, OCSPStaple: forged}, RevocationStatusUnknown, false},
+		{"Staple/Garbage", RevocationOptions{OCSPStaple: []byte("garbage")}, 0, true},
+		{"CRL/Revoked", RevocationOptions{CRLs: []*RevocationList{parseCRL(revokedCRL)}}, CertificateRevoked, false},
+		{"CRL/Clean", RevocationOptions{Mode: RevocationHardFail, CRLs: []*RevocationList{parseCRL(cleanCRL)}}, 0, true},
+		{"CRL/Stale", RevocationOptions{Mode: RevocationHardFail, CRLs: []*RevocationList{parseCRL(staleCRL)}}, RevocationStatusUnknown, false},
+		{"StapleBeforeCRL", RevocationOptions{OCSPStaple: good, CRLs: []*RevocationList{parseCRL(revokedCRL)}}, 0, true},
+		{"Fetcher/OCSPGood", RevocationOptions{Mode: RevocationHardFail, Fetcher: &testRevocationFetcher{ocsp: good}}, 0, true},
+		{"Fetcher/OCSPRevoked", RevocationOptions{Fetcher: &testRevocationFetcher{ocsp: revoked}}, CertificateRevoked, false},
+		{"Fetcher/CRLRevoked", RevocationOptions{Fetcher: &testRevocationFetcher{crl: revokedCRL}}, CertificateRevoked, false},
+		{"Fetcher/OCSPUnknownCRLClean", RevocationOptions{Mode: RevocationHardFail, Fetcher: &testRevocationFetcher{ocsp: unknown, crl: cleanCRL}}, 0, true},
+		{"Fetcher/Failing", RevocationOptions{Mode: RevocationHardFail, Fetcher: &testRevocationFetcher{}}, RevocationStatusUnknown, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			chains, err := pki.leaf.Verify(VerifyOptions{
+				Roots:      roots,
+				DNSName:    "example.com",
+				Revocation: &opts,
+			})
+			if tt.ok {
+				if err != nil {
+					t.Fatalf("Verify: %v", err)
+				}
+				if len(chains) != 1 {
+					t.Errorf("got %d chains, want 1", len(chains))
+				}
+				return
+			}
+			var invalidErr CertificateInvalidError
+			if !errors.As(err, &invalidErr) || invalidErr.Reason != tt.wantErr {
+				t.Fatalf("Verify = %v, want CertificateInvalidError with reason %v", err, tt.wantErr)
+			}
+			if invalidErr.Cert != pki.leaf {
+				t.Errorf("error is about %v, want the leaf", invalidErr.Cert.Subject)
+			}
+		})
+	}
+}
+
+func TestVerifyRevocationCurrentTime(t *testing.T) {
+	pki := newOCSPTestPKI(t)
+	now := time.Now()
+	roots := NewCertPool()
+	roots.AddCert(pki.root)
+
+	// A response that was current an hour ago is accepted at that time.
+	resp := pki.ocspResponse(t, OCSPRevoked, pki.root, pki.rootKey, now.Add(-2*time.Hour), now.Add(-30*time.Minute))
+	_, err := pki.leaf.Verify(VerifyOptions{
+		Roots:       roots,
+		CurrentTime: now.Add(-time.Hour),
+		Revocation:  &RevocationOptions{OCSPStaple: resp},
+	})
+	var invalidErr CertificateInvalidError
+	if !errors.As(err, &invalidErr) || invalidErr.Reason != CertificateRevoked {
+		t.Errorf("Verify = %v, want CertificateRevoked", err)
+	}
+}
+
+func TestVerifyRevocationFetcherCalls(t *testing.T) {
+	pki := newOCSPTestPKI(t)
+	now := time.Now()
+	roots := NewCertPool()
+	roots.AddCert(pki.root)
+
+	f := &testRevocationFetcher{crl: pki.crl(t, false, now.Add(-time.Minute), now.Add(time.Hour))}
+	if _, err := pki.leaf.Verify(VerifyOptions{
+		Roots:      roots,
+		Revocation: &RevocationOptions{Mode: RevocationHardFail, Fetcher: f},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if f.ocspCalls != 1 || f.crlCalls != 1 {
+		t.Errorf("got %d OCSP and %d CRL fetches, want 1 and 1", f.ocspCalls, f.crlCalls)
+	}
+
+	// A valid staple makes fetching unnecessary.
+	f = &testRevocationFetcher{}
+	staple := pki.ocspResponse(t, OCSPGood, pki.root, pki.rootKey, now.Add(-time.Minute), now.Add(time.Hour))
+	if _, err := pki.leaf.Verify(VerifyOptions{
+		Roots:      roots,
+		Revocation: &RevocationOptions{Mode: RevocationHardFail, OCSPStaple: staple, Fetcher: f},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if f.ocspCalls != 0 || f.crlCalls != 0 {
+		t.Errorf("got %d OCSP and %d CRL fetches, want none", f.ocspCalls, f.crlCalls)
+	}
+}
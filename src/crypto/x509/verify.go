@@ -59,6 +59,13 @@ const (
 	CANotAuthorizedForExtKeyUsage
 	// NoValidChains results when there are no valid chains to return.
 	NoValidChains
+	// CertificateRevoked results when revocation checking is enabled and a
+	// certificate in the chain has been revoked.
+	CertificateRevoked
+	// RevocationStatusUnknown results when revocation checking is enabled
+	// with RevocationHardFail and the revocation status of a certificate in
+	// the chain can't be determined.
+	RevocationStatusUnknown
 )
 
 // CertificateInvalidError results when an odd error occurs. Users of this
@@ -95,6 +102,10 @@ func (e CertificateInvalidError) Error() string {
 			s = fmt.Sprintf("%s: %s", s, e.Detail)
 		}
 		return s
+	case CertificateRevoked:
+		return "x509: certificate has been revoked: " + e.Detail
+	case RevocationStatusUnknown:
+		return "x509: certificate revocation status is unknown: " + e.Detail
 	}
 	return "x509: unknown error"
 }
@@ -223,6 +234,10 @@ type VerifyOptions struct {
 	// field implies any valid policy is acceptable.
 	CertificatePolicies []OID
 
+	// Revocation, if not nil, enables revocation checking of the certificates
+	// in each candidate chain, other than the root. See [RevocationOptions].
+	Revocation *RevocationOptions
+
 	// The following policy fields are unexported, because we do not expect
 	// users to actually need to use them, but are useful for testing the
 	// policy validation code.
@@ -538,7 +553,10 @@ func (c *Certificate) isValid(certType int, currentChain []*Certificate, opts *V
 //
 // Certificates other than c in the returned chains should not be modified.
 //
-// WARNING: this function doesn't do any revocation checking.
+// Revocation checking is only performed if opts.Revocation is set. Chains
+// that fail it are discarded, and if none are left, the returned error is a
+// [CertificateInvalidError] with reason [CertificateRevoked] or
+// [RevocationStatusUnknown].
 func (c *Certificate) Verify(opts VerifyOptions) ([][]*Certificate, error) {
 	// Platform-specific verification needs the ASN.1 contents so
 	// this makes the behavior consistent across platforms.
@@ -561,14 +579,21 @@ func (c *Certificate) Verify(opts VerifyOptions) ([][]*Certificate, error) {
 		// i.e. if SetFallbackRoots was called with x509usefallbackroots=1.
 		systemPool := systemRootsPool()
 		if opts.Roots == nil && (systemPool == nil || systemPool.systemPool) {
-			return c.systemVerify(&opts)
+			platformChains, err := c.systemVerify(&opts)
+			if err != nil {
+				return nil, err
+			}
+			return filterRevokedChains(platformChains, &opts)
 		}
 		if opts.Roots != nil && opts.Roots.systemPool {
 			platformChains, err := c.systemVerify(&opts)
 			// If the platform verifier succeeded, or there are no additional
 			// roots, return the platform verifier result. Otherwise, continue
 			// with the Go verifier.
-			if err == nil || opts.Roots.len() == 0 {
+			if err == nil {
+				return filterRevokedChains(platformChains, &opts)
+			}
+			if opts.Roots.len() == 0 {
 				return platformChains, err
 			}
 		}
@@ -657,7 +682,7 @@ func (c *Certificate) Verify(opts VerifyOptions) ([][]*Certificate, error) {
 		return nil, err
 	}
 
-	return candidateChains, nil
+	return filterRevokedChains(candidateChains, &opts)
 }
 
 func appendToFreshChain(chain []*Certificate, cert *Certificate) []*Certificate {
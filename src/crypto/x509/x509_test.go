@@ -12,6 +12,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/elliptic"
+	"crypto/mldsa"
 	"crypto/rand"
 	"crypto/rsa"
 	_ "crypto/sha256"
@@ -152,6 +153,24 @@ func TestParsePKIXPublicKey(t *testing.T) {
 			t.Errorf("Value returned from ParsePKIXPublicKey was not an X25519 public key")
 		}
 	})
+	t.Run("ML-DSA", func(t *testing.T) {
+		priv, err := mldsa.GenerateKey(mldsa.MLDSA87())
+		if err != nil {
+			t.Fatal(err)
+		}
+		der, err := MarshalPKIXPublicKey(priv.PublicKey())
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := ParsePKIXPublicKey(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k, ok := pub.(*mldsa.PublicKey)
+		if !ok || k.Parameters() != mldsa.MLDSA87() || !k.Equal(priv.PublicKey()) {
+			t.Errorf("Value returned from ParsePKIXPublicKey was not the ML-DSA-87 public key")
+		}
+	})
 }
 
 var pemPublicKey = `-----BEGIN PUBLIC KEY-----
@@ -692,6 +711,11 @@ func TestCreateSelfSignedCertificate(t *testing.T) {
 		t.Fatalf("Failed to generate Ed25519 key: %s", err)
 	}
 
+	mldsaPriv, err := mldsa.GenerateKey(mldsa.MLDSA65())
+	if err != nil {
+		t.Fatalf("Failed to generate ML-DSA key: %s", err)
+	}
+
 	tests := []struct {
 		name      string
 		pub, priv any
@@ -706,6 +730,8 @@ func TestCreateSelfSignedCertificate(t *testing.T) {
 		{"ECDSA/RSAPSS", &ecdsaPriv.PublicKey, testPrivateKey, false, SHA256WithRSAPSS},
 		{"RSAPSS/ECDSA", &testPrivateKey.PublicKey, ecdsaPriv, false, ECDSAWithSHA384},
 		{"Ed25519", ed25519Pub, ed25519Priv, true, PureEd25519},
+		{"ML-DSA", mldsaPriv.PublicKey(), mldsaPriv, true, MLDSA65},
+		{"ECDSA/ML-DSA", &ecdsaPriv.PublicKey, mldsaPriv, false, MLDSA65},
 	}
 
 	testExtKeyUsage := []ExtKeyUsage{ExtKeyUsageClientAuth, ExtKeyUsageServerAuth}
@@ -1492,6 +1518,11 @@ func TestCreateCertificateRequest(t *testing.T) {
 		t.Fatalf("Failed to generate Ed25519 key: %s", err)
 	}
 
+	mldsaPriv, err := mldsa.GenerateKey(mldsa.MLDSA44())
+	if err != nil {
+		t.Fatalf("Failed to generate ML-DSA key: %s", err)
+	}
+
 	tests := []struct {
 		name    string
 		priv    any
@@ -1503,6 +1534,7 @@ func TestCreateCertificateRequest(t *testing.T) {
 		{"ECDSA-384", ecdsa384Priv, ECDSAWithSHA256},
 		{"ECDSA-521", ecdsa521Priv, ECDSAWithSHA256},
 		{"Ed25519", ed25519Priv, PureEd25519},
+		{"ML-DSA-44", mldsaPriv, MLDSA44},
 	}
 
 	for _, test := range tests {
@@ -4269,3 +4301,38 @@ func TestEKUOIDS(t *testing.T) {
 		}
 	}
 }
+
+func TestMLDSAParameterSetMismatch(t *testing.T) {
+	priv, err := mldsa.GenerateKey(mldsa.MLDSA65())
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &Certificate{
+		SerialNumber:       big.NewInt(1),
+		NotBefore:          time.Unix(1000, 0),
+		NotAfter:           time.Unix(100000, 0),
+		SignatureAlgorithm: MLDSA44,
+	}
+	if _, err := CreateCertificate(rand.Reader, template, template, priv.PublicKey(), priv); err == nil {
+		t.Errorf("CreateCertificate with ML-DSA-44 signature algorithm and ML-DSA-65 key succeeded")
+	}
+
+	template.SignatureAlgorithm = 0
+	der, err := CreateCertificate(rand.Reader, template, template, priv.PublicKey(), priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.SignatureAlgorithm != MLDSA65 || cert.PublicKeyAlgorithm != MLDSA {
+		t.Errorf("got signature algorithm %v and public key algorithm %v, want ML-DSA-65 and ML-DSA", cert.SignatureAlgorithm, cert.PublicKeyAlgorithm)
+	}
+	if err := cert.CheckSignature(MLDSA44, cert.RawTBSCertificate, cert.Signature); err == nil {
+		t.Errorf("CheckSignature with ML-DSA-44 and an ML-DSA-65 key succeeded")
+	}
+	if err := cert.CheckSignature(MLDSA65, cert.RawTBSCertificate, cert.Signature); err != nil {
+		t.Errorf("CheckSignature: %v", err)
+	}
+}
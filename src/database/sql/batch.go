@@ -0,0 +1,259 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// ExecBatch executes query once for each element of args, without
+// returning any rows. Each element of args holds the placeholder
+// parameters for one execution.
+//
+// If the driver implements [driver.BatchExecer], the executions are
+// sent to the database together. Otherwise, ExecBatch prepares query
+// and executes the statement once for each element of args, stopping
+// at the first error.
+//
+// The returned [Result] reports the total number of rows affected
+// by all of the executions, and the id of the last row inserted.
+// ExecBatch does not run the executions in a transaction; use
+// [Tx.ExecBatch] if they must all succeed or fail together.
+func (db *DB) ExecBatch(ctx context.Context, query string, args [][]any) (Result, error) {
+	var res Result
+	var err error
+
+	err = db.retry(func(strategy connReuseStrategy) error {
+		res, err = db.execBatch(ctx, query, args, strategy)
+		return err
+	})
+
+	return res, err
+}
+
+func (db *DB) execBatch(ctx context.Context, query string, args [][]any, strategy connReuseStrategy) (Result, error) {
+	dc, err := db.conn(ctx, strategy)
+	if err != nil {
+		return nil, err
+	}
+	return db.execBatchDC(ctx, dc, dc.releaseConn, query, args)
+}
+
+// ExecBatch executes query once for each element of args.
+// See [DB.ExecBatch] for details.
+func (c *Conn) ExecBatch(ctx context.Context, query string, args [][]any) (Result, error) {
+	dc, release, err := c.grabConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.db.execBatchDC(ctx, dc, release, query, args)
+}
+
+// ExecBatch executes query once for each element of args
+// within the transaction. See [DB.ExecBatch] for details.
+func (tx *Tx) ExecBatch(ctx context.Context, query string, args [][]any) (Result, error) {
+	dc, release, err := tx.grabConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.db.execBatchDC(ctx, dc, release, query, args)
+}
+
+func (db *DB) execBatchDC(ctx context.Context, dc *driverConn, release func(error), query string, args [][]any) (res Result, err error) {
+	// Once a statement has been executed, retrying the batch on
+	// another connection would execute it twice, so ErrBadConn
+	// is no longer reported to the caller.
+	executed := 0
+	defer func() {
+		release(err)
+		if executed > 0 && errors.Is(err, driver.ErrBadConn) {
+			err = fmt.Errorf("sql: batch failed after %d executions: %v", executed, err)
+		}
+	}()
+	if len(args) == 0 {
+		return &batchResult{idErr: errNoBatchInsert}, nil
+	}
+
+	if be, ok := dc.ci.(driver.BatchExecer); ok {
+		var resi driver.Result
+		withLock(dc, func() {
+			nvargs := make([][]driver.NamedValue, len(args))
+			for i, a := range args {
+				nvargs[i], err = driverArgsConnLocked(dc.ci, nil, a)
+				if err != nil {
+					return
+				}
+			}
+			resi, err = be.ExecBatch(ctx, query, nvargs)
+		})
+		if err != driver.ErrSkip {
+			if err != nil {
+				return nil, err
+			}
+			return driverResult{dc, resi}, nil
+		}
+	}
+
+	var si driver.Stmt
+	withLock(dc, func() {
+		si, err = ctxDriverPrepare(ctx, dc.ci, query)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ds := &driverStmt{Locker: dc, si: si}
+	defer ds.Close()
+
+	br := new(batchResult)
+	for _, a := range args {
+		r, err := resultFromStatement(ctx, dc.ci, ds, a...)
+		if err != nil {
+			return nil, err
+		}
+		executed++
+		br.add(r)
+	}
+	return br, nil
+}
+
+var errNoBatchInsert = errors.New("sql: batch inserted no rows")
+
+// batchResult is the Result of a batch executed one statement at a time.
+type batchResult struct {
+	rows    int64
+	rowsErr error
+	id      int64
+	idErr   error
+}
+
+// add records the result of the next statement in the batch.
+func (br *batchResult) add(r Result) {
+	if br.rowsErr == nil {
+		n, err := r.RowsAffected()
+		br.rows += n
+		br.rowsErr = err
+	}
+	br.id, br.idErr = r.LastInsertId()
+}
+
+func (br *batchResult) LastInsertId() (int64, error) { return br.id, br.idErr }
+func (br *batchResult) RowsAffected() (int64, error) { return br.rows, br.rowsErr }
+
+// Copy describes the destination of [Conn.CopyFrom].
+type Copy struct {
+	// Table and Columns name the table and columns the
+	// rows are copied into.
+	Table   string
+	Columns []string
+
+	// Insert is a statement which inserts a single row, with
+	// one placeholder parameter for each of the Columns, in the
+	// syntax of the driver. It is used when the driver does not
+	// support bulk copies. If Insert is empty, CopyFrom fails
+	// for such drivers.
+	Insert string
+}
+
+// CopyFrom inserts rows into the table described by cp,
+// and returns the number of rows inserted.
+// Each row holds a value for each of cp.Columns.
+//
+// If the driver implements [driver.Copier], the rows are sent using
+// the database's bulk-loading protocol. Otherwise, CopyFrom prepares
+// cp.Insert and executes it once for each row.
+//
+// If rows yields an error, CopyFrom stops and returns that error.
+// Whether the rows already copied remain in the table depends on
+// the driver; use a transaction on the connection if they must not.
+func (c *Conn) CopyFrom(ctx context.Context, cp Copy, rows iter.Seq2[[]any, error]) (n int64, err error) {
+	dc, release, err := c.grabConn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		release(err)
+	}()
+
+	if copier, ok := dc.ci.(driver.Copier); ok {
+		next, stop := iter.Pull2(rows)
+		src := &copySource{ci: dc.ci, next: next}
+		withLock(dc, func() {
+			n, err = copier.CopyFrom(ctx, cp.Table, cp.Columns, src)
+		})
+		stop()
+		if err != driver.ErrSkip || src.started {
+			if src.err != nil {
+				// Report the error from rows, rather than the
+				// driver's error describing the failed copy.
+				err = src.err
+			}
+			return n, err
+		}
+	}
+
+	if cp.Insert == "" {
+		return 0, fmt.Errorf("sql: driver does not support CopyFrom and Copy.Insert is empty")
+	}
+	var si driver.Stmt
+	withLock(dc, func() {
+		si, err = ctxDriverPrepare(ctx, dc.ci, cp.Insert)
+	})
+	if err != nil {
+		return 0, err
+	}
+	ds := &driverStmt{Locker: dc, si: si}
+	defer ds.Close()
+
+	for row, rerr := range rows {
+		if rerr != nil {
+			return n, rerr
+		}
+		if _, err := resultFromStatement(ctx, dc.ci, ds, row...); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// copySource is the driver.CopySource used by Conn.CopyFrom.
+// Its Next method is called with the driverConn's lock held.
+type copySource struct {
+	ci      driver.Conn
+	next    func() ([]any, error, bool)
+	started bool
+	err     error // error yielded by the rows
+}
+
+func (s *copySource) Next(dest []driver.Value) error {
+	s.started = true
+	if s.err != nil {
+		return s.err
+	}
+	row, err, ok := s.next()
+	if !ok {
+		return io.EOF
+	}
+	if err != nil {
+		s.err = err
+		return err
+	}
+	nv, err := driverArgsConnLocked(s.ci, nil, row)
+	if err != nil {
+		return err
+	}
+	if len(nv) != len(dest) {
+		return fmt.Errorf("sql: CopyFrom row has %d values, want %d", len(nv), len(dest))
+	}
+	for i := range nv {
+		dest[i] = nv[i].Value
+	}
+	return nil
+}
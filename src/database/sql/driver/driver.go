@@ -19,6 +19,10 @@
 // If named parameters or context are supported, the driver's [Conn] should implement:
 // [ExecerContext], [QueryerContext], [ConnPrepareContext], and [ConnBeginTx].
 //
+// If the database supports sending several executions of a statement at once,
+// or a bulk-loading protocol, the driver's [Conn] should implement [BatchExecer]
+// or [Copier].
+//
 // To support custom data types, implement [NamedValueChecker]. [NamedValueChecker]
 // also allows queries to accept per-query options as a parameter by returning
 // [ErrRemoveArgument] from CheckNamedValue.
@@ -200,6 +204,48 @@ type ExecerContext interface {
 	ExecContext(ctx context.Context, query string, args []NamedValue) (Result, error)
 }
 
+// BatchExecer is an optional interface that may be implemented by a [Conn].
+//
+// ExecBatch executes query once for each element of args, typically
+// in a single round trip to the database. The returned [Result]
+// describes all of the executions: RowsAffected reports the total
+// number of rows affected, and LastInsertId the id of the last
+// row inserted.
+//
+// If a [Conn] does not implement BatchExecer, or ExecBatch returns
+// [ErrSkip], [database/sql.DB.ExecBatch] prepares query and executes
+// the statement once for each element of args.
+//
+// ExecBatch must honor the context timeout and return when the context is canceled.
+type BatchExecer interface {
+	ExecBatch(ctx context.Context, query string, args [][]NamedValue) (Result, error)
+}
+
+// Copier is an optional interface that may be implemented by a [Conn].
+//
+// CopyFrom inserts the rows read from src into the named columns of
+// table, using the database's bulk-loading protocol, and returns the
+// number of rows inserted.
+//
+// If a [Conn] does not implement Copier, or CopyFrom returns [ErrSkip]
+// before reading from src, [database/sql.Conn.CopyFrom] inserts
+// the rows one at a time with a prepared statement.
+//
+// CopyFrom must honor the context timeout and return when the context is canceled.
+type Copier interface {
+	CopyFrom(ctx context.Context, table string, columns []string, src CopySource) (int64, error)
+}
+
+// CopySource is the source of the rows read by [Copier.CopyFrom].
+type CopySource interface {
+	// Next is called to populate the next row of data into
+	// the provided slice, which has one element per column.
+	// The values are of the types described for [Value].
+	//
+	// Next should return io.EOF when there are no more rows.
+	Next(dest []Value) error
+}
+
 // Queryer is an optional interface that may be implemented by a [Conn].
 //
 // If a [Conn] implements neither [QueryerContext] nor [Queryer],
@@ -56,13 +56,15 @@ type fakeDriver struct {
 type fakeConnector struct {
 	name string
 
-	waiter func(context.Context)
-	closed bool
+	waiter  func(context.Context)
+	noBatch bool
+	closed  bool
 }
 
 func (c *fakeConnector) Connect(context.Context) (driver.Conn, error) {
 	conn, err := fdriver.Open(c.name)
 	conn.(*fakeConn).waiter = c.waiter
+	conn.(*fakeConn).noBatch = c.noBatch
 	return conn, err
 }
 
@@ -144,6 +146,8 @@ type fakeConn struct {
 	stmtsMade   int
 	stmtsClosed int
 	numPrepare  int
+	numBatch    int
+	numCopy     int
 
 	// bad connection tests; see isBad()
 	bad       bool
@@ -158,6 +162,10 @@ type fakeConn struct {
 	// The waiter is called before each query. May be used in place of the "WAIT"
 	// directive.
 	waiter func(context.Context)
+
+	// noBatch causes ExecBatch and CopyFrom to return driver.ErrSkip,
+	// as if the conn did not implement them.
+	noBatch bool
 }
 
 func (c *fakeConn) touchMem() {
@@ -473,6 +481,96 @@ func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.
 	return nil, driver.ErrSkip
 }
 
+var (
+	_ driver.BatchExecer = (*fakeConn)(nil)
+	_ driver.Copier      = (*fakeConn)(nil)
+)
+
+// ExecBatch supports only INSERT, which it executes for each args.
+func (c *fakeConn) ExecBatch(ctx context.Context, query string, args [][]driver.NamedValue) (driver.Result, error) {
+	if c.noBatch {
+		return nil, driver.ErrSkip
+	}
+	c.incrStat(&c.numBatch)
+	if c.stickyBad {
+		return nil, fakeError{Message: "ExecBatch: Sticky Bad", Wrapped: driver.ErrBadConn}
+	}
+	if c.isDirtyAndMark() {
+		return nil, errFakeConnSessionDirty
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(query, "INSERT|") {
+		return nil, fmt.Errorf("fakedb: ExecBatch of unsupported query %q", query)
+	}
+	si, err := c.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer si.Close()
+	stmt := si.(*fakeStmt)
+	for _, a := range args {
+		if len(a) != stmt.placeholders {
+			return nil, fmt.Errorf("fakedb: ExecBatch got %d args, want %d", len(a), stmt.placeholders)
+		}
+		if err := checkSubsetTypes(c.db.allowAny, a); err != nil {
+			return nil, err
+		}
+	}
+	for _, a := range args {
+		if _, err := stmt.execInsert(a, true); err != nil {
+			return nil, err
+		}
+	}
+	return driver.RowsAffected(len(args)), nil
+}
+
+func (c *fakeConn) CopyFrom(ctx context.Context, table string, columns []string, src driver.CopySource) (int64, error) {
+	if c.noBatch {
+		return 0, driver.ErrSkip
+	}
+	c.incrStat(&c.numCopy)
+	if c.isDirtyAndMark() {
+		return 0, errFakeConnSessionDirty
+	}
+	c.db.mu.Lock()
+	t, ok := c.db.table(table)
+	c.db.mu.Unlock()
+	if !ok {
+		return 0, fmt.Errorf("fakedb: table %q doesn't exist", table)
+	}
+	colidx := make([]int, len(columns))
+	for i, name := range columns {
+		colidx[i] = t.columnIndex(name)
+		if colidx[i] == -1 {
+			return 0, fmt.Errorf("fakedb: column %q doesn't exist", name)
+		}
+	}
+	var n int64
+	dest := make([]driver.Value, len(columns))
+	for {
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+		err := src.Next(dest)
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		cols := make([]any, len(t.colname))
+		for i, v := range dest {
+			cols[colidx[i]] = v
+		}
+		t.mu.Lock()
+		t.rows = append(t.rows, &row{cols: cols})
+		t.mu.Unlock()
+		n++
+	}
+}
+
 func (c *fakeConn) Query(query string, args []driver.Value) (driver.Rows, error) {
 	// Ensure that ExecContext is called if available.
 	panic("QueryContext was not called.")
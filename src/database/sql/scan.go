@@ -0,0 +1,267 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ScanStruct copies the columns in the current row into the fields of
+// the struct pointed to by dest.
+//
+// Each column is stored in the field with the same name. A field's
+// name defaults to the Go field name, matched case-insensitively, and
+// may be set with a "db" struct tag, which is matched exactly:
+//
+//	// Field is stored from the column named "created_at".
+//	Field time.Time `db:"created_at"`
+//
+//	// Field is ignored.
+//	Field string `db:"-"`
+//
+// Only exported fields are used. The fields of an embedded struct are
+// treated as if they were fields of the outer struct, with fields at a
+// shallower depth hiding those of the same name in embedded structs.
+//
+// It is an error for a column to have no matching field.
+// Fields without a matching column are left unchanged.
+//
+// Columns are converted into fields as described for [Rows.Scan],
+// including the restrictions on fields of type [RawBytes].
+func (rs *Rows) ScanStruct(dest any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("sql: ScanStruct requires a non-nil pointer to a struct, not %T", dest)
+	}
+	cols, err := rs.Columns()
+	if err != nil {
+		return err
+	}
+	fields := cachedStructFields(v.Elem().Type())
+	args := make([]any, len(cols))
+	for i, col := range cols {
+		f, err := fields.lookup(col)
+		if err != nil {
+			return err
+		}
+		fv, err := fieldByIndexAlloc(v.Elem(), f.index)
+		if err != nil {
+			return fmt.Errorf("sql: ScanStruct column %q: %w", col, err)
+		}
+		args[i] = fv.Addr().Interface()
+	}
+	return rs.Scan(args...)
+}
+
+// Queryer is implemented by [*DB], [*Conn], and [*Tx].
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*Rows, error)
+}
+
+// Query executes a query on q and returns an iterator over the rows of
+// its result, each scanned into a value of type T.
+//
+// If T is a struct type, or a pointer to a struct type, each row is
+// scanned with [Rows.ScanStruct]. Otherwise, or if T or *T implements
+// [Scanner], or T is [time.Time] or *time.Time, the query must return a
+// single column, which is scanned with [Rows.Scan].
+//
+// If the query, a scan, or the iteration fails, the iterator yields
+// the error, with the zero value of T, and stops.
+// The [Rows] are closed when the iteration ends, including when the
+// loop over the iterator exits early.
+//
+// Values of T must not retain [RawBytes] beyond the loop iteration
+// which yielded them.
+func Query[T any](ctx context.Context, q Queryer, query string, args ...any) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+
+		t := reflect.TypeFor[T]()
+		isStruct := scansAsStruct(t)
+		isPtr := isStruct && t.Kind() == reflect.Pointer
+		for rows.Next() {
+			var v T
+			if isPtr {
+				reflect.ValueOf(&v).Elem().Set(reflect.New(t.Elem()))
+			}
+			switch {
+			case isPtr:
+				err = rows.ScanStruct(any(v))
+			case isStruct:
+				err = rows.ScanStruct(&v)
+			default:
+				err = rows.Scan(&v)
+			}
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// scansAsStruct reports whether Query scans values of type t, a struct
+// or a pointer to one, with ScanStruct rather than as a single column.
+func scansAsStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == timeType {
+		return false
+	}
+	return !isScanner(t)
+}
+
+// A structField is a struct field which may receive a column.
+type structField struct {
+	index []int
+	depth int
+}
+
+// structFields describes the fields of a struct type used by ScanStruct.
+// A nil entry in either map marks a name which matches several fields
+// at the same depth.
+type structFields struct {
+	exact map[string]*structField // by tag name, or field name
+	fold  map[string]*structField // untagged fields, by lower-case name
+}
+
+var structFieldCache sync.Map // map[reflect.Type]*structFields
+
+func cachedStructFields(t reflect.Type) *structFields {
+	if f, ok := structFieldCache.Load(t); ok {
+		return f.(*structFields)
+	}
+	f, _ := structFieldCache.LoadOrStore(t, typeStructFields(t))
+	return f.(*structFields)
+}
+
+// lookup returns the field which receives the column named col.
+func (fs *structFields) lookup(col string) (*structField, error) {
+	f, ok := fs.exact[col]
+	if !ok {
+		f, ok = fs.fold[strings.ToLower(col)]
+	}
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("sql: no struct field for column %q", col)
+	case f == nil:
+		return nil, fmt.Errorf("sql: column %q matches several struct fields", col)
+	}
+	return f, nil
+}
+
+// typeStructFields returns the fields of struct type t,
+// visiting embedded structs breadth first.
+func typeStructFields(t reflect.Type) *structFields {
+	fs := &structFields{
+		exact: make(map[string]*structField),
+		fold:  make(map[string]*structField),
+	}
+	add := func(m map[string]*structField, key string, f *structField) {
+		old, ok := m[key]
+		switch {
+		case !ok:
+			m[key] = f
+		case old == nil || old.depth < f.depth:
+			// Ambiguous or hidden at a shallower depth.
+		default:
+			m[key] = nil
+		}
+	}
+
+	type queued struct {
+		typ   reflect.Type
+		index []int
+	}
+	next := []queued{{typ: t}}
+	visited := make(map[reflect.Type]bool)
+	for depth := 0; len(next) > 0; depth++ {
+		current := next
+		next = nil
+		for _, q := range current {
+			if visited[q.typ] {
+				continue
+			}
+			visited[q.typ] = true
+			for i := range q.typ.NumField() {
+				sf := q.typ.Field(i)
+				tag := sf.Tag.Get("db")
+				if tag == "-" {
+					continue
+				}
+				index := append(append([]int(nil), q.index...), i)
+				ft := sf.Type
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous && tag == "" && ft.Kind() == reflect.Struct && !isScanner(ft) {
+					next = append(next, queued{typ: ft, index: index})
+					continue
+				}
+				if !sf.IsExported() {
+					continue
+				}
+				f := &structField{index: index, depth: depth}
+				if tag != "" {
+					add(fs.exact, tag, f)
+				} else {
+					add(fs.exact, sf.Name, f)
+					add(fs.fold, strings.ToLower(sf.Name), f)
+				}
+			}
+		}
+	}
+	return fs
+}
+
+var scannerType = reflect.TypeFor[Scanner]()
+
+// isScanner reports whether a pointer to t implements Scanner,
+// in which case an embedded t receives a column rather than
+// providing fields.
+func isScanner(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(scannerType)
+}
+
+// fieldByIndexAlloc returns the field of v with the given index sequence,
+// allocating nil embedded struct pointers.
+func fieldByIndexAlloc(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, errors.New("cannot set embedded pointer to unexported struct " + v.Type().Elem().String())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
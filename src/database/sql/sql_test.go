@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"internal/race"
 	"internal/testenv"
+	"iter"
 	"math/rand"
 	"reflect"
 	"runtime"
@@ -5410,3 +5411,367 @@ func TestNullTypeScanNil(t *testing.T) {
 		})
 	}
 }
+
+func TestRowsScanStruct(t *testing.T) {
+	synctest.Test(t, testRowsScanStruct)
+}
+func testRowsScanStruct(t *testing.T) {
+	db := newTestDB(t, "people")
+
+	type base struct {
+		Name string
+		Age  int // hidden by person.Years
+	}
+	type person struct {
+		base
+		Years   int64      `db:"age"`
+		Dead    NullBool   `db:"dead"`
+		Ignored string     `db:"-"`
+		BDate   *time.Time // matched case-insensitively
+	}
+	rows, err := db.Query("SELECT|people|name,age,dead,bdate|")
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	defer rows.Close()
+	var got []person
+	for rows.Next() {
+		var p person
+		if err := rows.ScanStruct(&p); err != nil {
+			t.Fatalf("ScanStruct: %v", err)
+		}
+		got = append(got, p)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d rows, want 3", len(got))
+	}
+	for i, name := range []string{"Alice", "Bob", "Chris"} {
+		if got[i].Name != name || got[i].Years != int64(i+1) || got[i].Age != 0 {
+			t.Errorf("row %d = %+v, want Name %q, Years %d", i, got[i], name, i+1)
+		}
+	}
+	if got[0].BDate != nil {
+		t.Errorf("row 0 BDate = %v, want nil", got[0].BDate)
+	}
+	if got[2].BDate == nil || !got[2].BDate.Equal(chrisBirthday) {
+		t.Errorf("row 2 BDate = %v, want %v", got[2].BDate, chrisBirthday)
+	}
+}
+
+func TestRowsScanStructErrors(t *testing.T) {
+	synctest.Test(t, testRowsScanStructErrors)
+}
+func testRowsScanStructErrors(t *testing.T) {
+	db := newTestDB(t, "people")
+
+	type ambiguous struct {
+		Name string
+		Age  int
+		AGE  int
+	}
+	type a struct{ Age int }
+	type b struct{ Age int }
+	type embedded struct {
+		a
+		b
+		Name string
+	}
+	tests := []struct {
+		dest any
+		want string
+	}{
+		{dest: struct{ Name string }{}, want: "non-nil pointer to a struct"},
+		{dest: &struct{ Name string }{}, want: `no struct field for column "age"`},
+		{dest: &struct {
+			Name string
+			Age  []int
+		}{}, want: `Scan error on column index 1, name "age"`},
+		{dest: &embedded{}, want: `column "age" matches several struct fields`},
+		{dest: &ambiguous{}, want: `column "age" matches several struct fields`},
+	}
+	for _, tt := range tests {
+		rows, err := db.Query("SELECT|people|name,age|")
+		if err != nil {
+			t.Fatalf("Query: %v", err)
+		}
+		if !rows.Next() {
+			t.Fatalf("Next: %v", rows.Err())
+		}
+		err = rows.ScanStruct(tt.dest)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ScanStruct(%T) = %v, want error containing %q", tt.dest, err, tt.want)
+		}
+		rows.Close()
+	}
+}
+
+func TestQueryIter(t *testing.T) {
+	synctest.Test(t, testQueryIter)
+}
+func testQueryIter(t *testing.T) {
+	db := newTestDB(t, "people")
+	ctx := context.Background()
+
+	type person struct {
+		Name string `db:"name"`
+		Age  int    `db:"age"`
+	}
+	var got []person
+	for p, err := range Query[person](ctx, db, "SELECT|people|name,age|") {
+		if err != nil {
+			t.Fatalf("Query: %v", err)
+		}
+		got = append(got, p)
+	}
+	want := []person{{"Alice", 1}, {"Bob", 2}, {"Chris", 3}}
+	if !slices.Equal(got, want) {
+		t.Errorf("Query[person] = %v, want %v", got, want)
+	}
+	waitForFree(t, db, 1)
+
+	// Pointers to structs, and breaking out of the loop early.
+	var names []string
+	for p, err := range Query[*person](ctx, db, "SELECT|people|name,age|") {
+		if err != nil {
+			t.Fatalf("Query: %v", err)
+		}
+		names = append(names, p.Name)
+		break
+	}
+	if !slices.Equal(names, []string{"Alice"}) {
+		t.Errorf("Query[*person] = %v, want [Alice]", names)
+	}
+	waitForFree(t, db, 1)
+
+	// Single columns.
+	var ages []int
+	for age, err := range Query[int](ctx, db, "SELECT|people|age|") {
+		if err != nil {
+			t.Fatalf("Query: %v", err)
+		}
+		ages = append(ages, age)
+	}
+	if !slices.Equal(ages, []int{1, 2, 3}) {
+		t.Errorf("Query[int] = %v, want [1 2 3]", ages)
+	}
+
+	// Errors are yielded once.
+	n := 0
+	for _, err := range Query[int](ctx, db, "SELECT|people|name,age|") {
+		if err == nil {
+			t.Errorf("Query[int] of two columns yielded no error")
+		}
+		n++
+	}
+	if n != 1 {
+		t.Errorf("Query[int] of two columns yielded %d times, want 1", n)
+	}
+	for _, err := range Query[int](ctx, db, "SELECT|nosuchtable|age|") {
+		if err == nil {
+			t.Errorf("Query of missing table yielded no error")
+		}
+	}
+	waitForFree(t, db, 1)
+
+	// Structs implementing Scanner, and time.Time, are single columns.
+	var bdates []time.Time
+	for bdate, err := range Query[time.Time](ctx, db, "SELECT|people|bdate|name=?", "Chris") {
+		if err != nil {
+			t.Fatalf("Query[time.Time]: %v", err)
+		}
+		bdates = append(bdates, bdate)
+	}
+	if len(bdates) != 1 || !bdates[0].Equal(chrisBirthday) {
+		t.Errorf("Query[time.Time] = %v, want [%v]", bdates, chrisBirthday)
+	}
+	for bdate, err := range Query[*time.Time](ctx, db, "SELECT|people|bdate|name=?", "Chris") {
+		if err != nil {
+			t.Fatalf("Query[*time.Time]: %v", err)
+		}
+		if bdate == nil || !bdate.Equal(chrisBirthday) {
+			t.Errorf("Query[*time.Time] = %v, want %v", bdate, chrisBirthday)
+		}
+	}
+	var nullTimes []NullTime
+	for bdate, err := range Query[NullTime](ctx, db, "SELECT|people|bdate|") {
+		if err != nil {
+			t.Fatalf("Query[NullTime]: %v", err)
+		}
+		nullTimes = append(nullTimes, bdate)
+	}
+	wantNullTimes := []NullTime{{}, {}, {Time: chrisBirthday, Valid: true}}
+	if !slices.EqualFunc(nullTimes, wantNullTimes, func(a, b NullTime) bool { return a.Valid == b.Valid && a.Time.Equal(b.Time) }) {
+		t.Errorf("Query[NullTime] = %v, want %v", nullTimes, wantNullTimes)
+	}
+	var nullAges []Null[int64]
+	for age, err := range Query[Null[int64]](ctx, db, "SELECT|people|age|") {
+		if err != nil {
+			t.Fatalf("Query[Null[int64]]: %v", err)
+		}
+		nullAges = append(nullAges, age)
+	}
+	if want := []Null[int64]{{1, true}, {2, true}, {3, true}}; !slices.Equal(nullAges, want) {
+		t.Errorf("Query[Null[int64]] = %v, want %v", nullAges, want)
+	}
+	waitForFree(t, db, 1)
+}
+
+func TestExecBatch(t *testing.T) {
+	synctest.Test(t, testExecBatch)
+}
+func testExecBatch(t *testing.T) {
+	ctx := context.Background()
+	for _, noBatch := range []bool{false, true} {
+		db := newTestDBConnector(t, &fakeConnector{noBatch: noBatch}, "")
+		exec(t, db, "CREATE|people|name=string,age=int32")
+		const insert = "INSERT|people|name=?,age=?"
+
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fc := conn.dc.ci.(*fakeConn)
+		fc.skipDirtySession = true
+		res, err := conn.ExecBatch(ctx, insert, [][]any{{"Alice", 1}, {"Bob", 2}, {"Chris", 3}})
+		if err != nil {
+			t.Fatalf("noBatch=%v: Conn.ExecBatch: %v", noBatch, err)
+		}
+		if n, err := res.RowsAffected(); n != 3 || err != nil {
+			t.Errorf("noBatch=%v: RowsAffected = %d, %v; want 3, nil", noBatch, n, err)
+		}
+		wantBatch := 1
+		if noBatch {
+			wantBatch = 0
+		}
+		if fc.numBatch != wantBatch {
+			t.Errorf("noBatch=%v: driver ExecBatch called %d times, want %d", noBatch, fc.numBatch, wantBatch)
+		}
+		if _, err := conn.ExecBatch(ctx, insert, [][]any{{"Dave"}}); err == nil {
+			t.Errorf("noBatch=%v: ExecBatch with missing argument succeeded", noBatch)
+		}
+		res, err = conn.ExecBatch(ctx, insert, nil)
+		if err != nil {
+			t.Fatalf("noBatch=%v: ExecBatch with no args: %v", noBatch, err)
+		}
+		if n, err := res.RowsAffected(); n != 0 || err != nil {
+			t.Errorf("noBatch=%v: RowsAffected of empty batch = %d, %v; want 0, nil", noBatch, n, err)
+		}
+		conn.Close()
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.ExecBatch(ctx, insert, [][]any{{"Eve", 5}}); err != nil {
+			t.Fatalf("noBatch=%v: Tx.ExecBatch: %v", noBatch, err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for name, err := range Query[string](ctx, db, "SELECT|people|name|") {
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, name)
+		}
+		if want := []string{"Alice", "Bob", "Chris", "Eve"}; !slices.Equal(got, want) {
+			t.Errorf("noBatch=%v: names = %q, want %q", noBatch, got, want)
+		}
+	}
+
+	db := newTestDB(t, "")
+	exec(t, db, "CREATE|people|name=string,age=int32")
+	res, err := db.ExecBatch(ctx, "INSERT|people|name=?,age=?", [][]any{{"Alice", 1}, {"Bob", 2}})
+	if err != nil {
+		t.Fatalf("DB.ExecBatch: %v", err)
+	}
+	if n, err := res.RowsAffected(); n != 2 || err != nil {
+		t.Errorf("RowsAffected = %d, %v; want 2, nil", n, err)
+	}
+	waitForFree(t, db, 1)
+}
+
+func TestConnCopyFrom(t *testing.T) {
+	synctest.Test(t, testConnCopyFrom)
+}
+func testConnCopyFrom(t *testing.T) {
+	ctx := context.Background()
+	cp := Copy{
+		Table:   "people",
+		Columns: []string{"name", "age"},
+		Insert:  "INSERT|people|name=?,age=?",
+	}
+	people := func(names ...string) iter.Seq2[[]any, error] {
+		return func(yield func([]any, error) bool) {
+			for i, name := range names {
+				if !yield([]any{name, i + 1}, nil) {
+					return
+				}
+			}
+		}
+	}
+	errStop := errors.New("stop")
+	for _, noBatch := range []bool{false, true} {
+		db := newTestDBConnector(t, &fakeConnector{noBatch: noBatch}, "")
+		exec(t, db, "CREATE|people|name=string,age=int32")
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fc := conn.dc.ci.(*fakeConn)
+		fc.skipDirtySession = true
+
+		n, err := conn.CopyFrom(ctx, cp, people("Alice", "Bob", "Chris"))
+		if n != 3 || err != nil {
+			t.Fatalf("noBatch=%v: CopyFrom = %d, %v; want 3, nil", noBatch, n, err)
+		}
+		wantCopy := 1
+		if noBatch {
+			wantCopy = 0
+		}
+		if fc.numCopy != wantCopy {
+			t.Errorf("noBatch=%v: driver CopyFrom called %d times, want %d", noBatch, fc.numCopy, wantCopy)
+		}
+
+		failing := func(yield func([]any, error) bool) {
+			for row := range people("Dave") {
+				if !yield(row, nil) {
+					return
+				}
+			}
+			yield(nil, errStop)
+		}
+		n, err = conn.CopyFrom(ctx, cp, failing)
+		if n != 1 || !errors.Is(err, errStop) {
+			t.Errorf("noBatch=%v: CopyFrom of failing rows = %d, %v; want 1, %v", noBatch, n, err, errStop)
+		}
+
+		_, err = conn.CopyFrom(ctx, Copy{Table: cp.Table, Columns: cp.Columns}, people("Eve"))
+		if noBatch && err == nil {
+			t.Errorf("noBatch=%v: CopyFrom without Insert succeeded", noBatch)
+		} else if !noBatch && err != nil {
+			t.Errorf("noBatch=%v: CopyFrom without Insert: %v", noBatch, err)
+		}
+		conn.Close()
+
+		var got []string
+		for name, err := range Query[string](ctx, db, "SELECT|people|name|") {
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, name)
+		}
+		want := []string{"Alice", "Bob", "Chris", "Dave"}
+		if !noBatch {
+			want = append(want, "Eve")
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("noBatch=%v: names = %q, want %q", noBatch, got, want)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csv
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// A Decoder reads records from a CSV-encoded input into structs.
+//
+// The first record of the input is a header naming the columns.
+// Each following record is decoded into a struct by matching the
+// column names with the names of the struct's fields.
+//
+// A field's column name defaults to the field name, and may be
+// changed with a "csv" struct tag:
+//
+//	// Field appears in the column named "name".
+//	Field string `csv:"name"`
+//
+//	// Field is ignored.
+//	Field string `csv:"-"`
+//
+// Only exported fields are used. The fields of embedded structs are
+// treated as if they were fields of the outer struct, following the
+// rules used by [encoding/json].
+//
+// Fields may be strings, booleans, integers, floating-point numbers,
+// types implementing [encoding.TextUnmarshaler], or pointers to those.
+// A field of any other type causes Decode to return an [UnsupportedTypeError].
+// An empty column sets a pointer field to nil, and any other field
+// to its zero value, except for strings and types implementing
+// [encoding.TextUnmarshaler], which are given the empty text.
+//
+// Columns without a matching field are ignored, unless
+// [Decoder.DisallowUnknownFields] has been called.
+// Fields without a matching column are left unchanged.
+type Decoder struct {
+	r *Reader
+
+	header    []string
+	headerPos []position
+	headerErr error
+	disallow  bool
+
+	typ     reflect.Type
+	columns []*field // column index to field; nil for unused columns
+	unknown int      // index of the first unknown column, or -1
+}
+
+// NewDecoder returns a new Decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: NewReader(r)}
+}
+
+// Reader returns the [Reader] used by the Decoder.
+// Its exported fields may be changed to customize the details of the
+// input format before the first call to [Decoder.Header] or [Decoder.Decode].
+func (d *Decoder) Reader() *Reader {
+	return d.r
+}
+
+// DisallowUnknownFields causes the Decoder to return an error
+// when the header contains a column that does not match
+// any field of the destination struct.
+func (d *Decoder) DisallowUnknownFields() {
+	d.disallow = true
+}
+
+// Header returns the column names from the first record of the input,
+// reading it if necessary. The returned slice must not be modified.
+func (d *Decoder) Header() ([]string, error) {
+	if d.header == nil && d.headerErr == nil {
+		header, err := d.r.Read()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			d.headerErr = err
+			return nil, err
+		}
+		if d.r.ReuseRecord {
+			header = append([]string(nil), header...)
+		}
+		d.header = header
+		d.headerPos = append([]position(nil), d.r.fieldPositions...)
+	}
+	return d.header, d.headerErr
+}
+
+// Decode reads the next record from the input and stores it in the
+// struct pointed to by v.
+//
+// If there are no more records, Decode returns [io.EOF].
+// Errors in the input, and values which cannot be stored in the
+// corresponding field, are reported as a [*ParseError]. In the latter
+// case, the ParseError's Err field is an [*UnmarshalTypeError].
+func (d *Decoder) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("csv: Decode requires a non-nil pointer to a struct, not %T", v)
+	}
+	if _, err := d.Header(); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			// An empty input has no records.
+			return io.EOF
+		}
+		return err
+	}
+	if err := d.prepare(rv.Elem().Type()); err != nil {
+		return err
+	}
+	if d.disallow && d.unknown >= 0 {
+		p := d.headerPos[d.unknown]
+		return &ParseError{
+			StartLine: d.headerPos[0].line,
+			Line:      p.line,
+			Column:    p.col,
+			Err:       fmt.Errorf("unknown field %q", d.header[d.unknown]),
+		}
+	}
+
+	record, err := d.r.Read()
+	if err != nil {
+		return err
+	}
+	sv := rv.Elem()
+	for i, s := range record {
+		if i >= len(d.columns) || d.columns[i] == nil {
+			continue
+		}
+		f := d.columns[i]
+		fv, err := fieldByIndex(sv, f.index)
+		if err != nil {
+			return d.fieldError(i, err)
+		}
+		if err := unmarshalField(fv, s); err != nil {
+			return d.fieldError(i, &UnmarshalTypeError{
+				Value: s,
+				Type:  f.typ,
+				Field: f.name,
+				Err:   err,
+			})
+		}
+	}
+	return nil
+}
+
+// prepare maps the header columns to the fields of struct type t.
+func (d *Decoder) prepare(t reflect.Type) error {
+	if d.typ == t {
+		return nil
+	}
+	fields, err := cachedTypeFields(t)
+	if err != nil {
+		return err
+	}
+	d.typ = t
+	d.columns = make([]*field, len(d.header))
+	d.unknown = -1
+	for i, name := range d.header {
+		j, ok := fields.byName[name]
+		if !ok {
+			if d.unknown < 0 {
+				d.unknown = i
+			}
+			continue
+		}
+		d.columns[i] = &fields.list[j]
+	}
+	return nil
+}
+
+// fieldError returns a ParseError for the i'th field of the current record.
+func (d *Decoder) fieldError(i int, err error) error {
+	line, col := d.r.FieldPos(i)
+	startLine, _ := d.r.FieldPos(0)
+	return &ParseError{StartLine: startLine, Line: line, Column: col, Err: err}
+}
+
+// fieldByIndex returns the field of v with the given index sequence,
+// allocating embedded struct pointers as needed.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot set embedded pointer to unexported struct: %v", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// unmarshalField stores the text s in v.
+func unmarshalField(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Pointer {
+		if s == "" {
+			v.SetZero()
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+	if s == "" && v.Kind() != reflect.String {
+		v.SetZero()
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return numError(err)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return numError(err)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return numError(err)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return numError(err)
+		}
+		v.SetFloat(n)
+	default:
+		return &UnsupportedTypeError{Type: v.Type()}
+	}
+	return nil
+}
+
+// numError returns the underlying error of a strconv.NumError,
+// whose other details are reported by UnmarshalTypeError.
+func numError(err error) error {
+	if ne, ok := errors.AsType[*strconv.NumError](err); ok {
+		return ne.Err
+	}
+	return err
+}
+
+// An UnmarshalTypeError describes a CSV field that could not be
+// stored in the corresponding struct field.
+type UnmarshalTypeError struct {
+	Value string       // the CSV field
+	Type  reflect.Type // type of the struct field
+	Field string       // name of the column
+	Err   error        // the conversion error
+}
+
+func (e *UnmarshalTypeError) Error() string {
+	return fmt.Sprintf("cannot unmarshal %q into field %s of type %v: %v", e.Value, e.Field, e.Type, e.Err)
+}
+
+func (e *UnmarshalTypeError) Unwrap() error { return e.Err }
+
+// Unmarshal parses CSV-encoded data, with a header record, and appends
+// the decoded records to the slice pointed to by v. The elements of the
+// slice must be structs or pointers to structs. See [Decoder] for the
+// details of the decoding.
+func Unmarshal(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("csv: Unmarshal requires a non-nil pointer to a slice, not %T", v)
+	}
+	sv := rv.Elem()
+	et := sv.Type().Elem()
+	if _, ok := structType(et); !ok {
+		return fmt.Errorf("csv: Unmarshal requires a slice of structs, not %v", sv.Type())
+	}
+	d := NewDecoder(bytes.NewReader(data))
+	for {
+		ev := reflect.New(et)
+		target := ev
+		if et.Kind() == reflect.Pointer {
+			ev.Elem().Set(reflect.New(et.Elem()))
+			target = ev.Elem()
+		}
+		err := d.Decode(target.Interface())
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		sv.Set(reflect.Append(sv, ev.Elem()))
+	}
+}
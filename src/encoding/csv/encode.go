@@ -0,0 +1,218 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csv
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// An Encoder writes structs as CSV records.
+//
+// The first call to [Encoder.Encode] writes a header record naming the
+// columns, followed by one record per struct. The columns are the fields
+// of the struct, in declaration order, named as described for [Decoder].
+// The "omitempty" tag option causes a zero value to be written as an
+// empty field:
+//
+//	// Field appears in the column named "count",
+//	// which is empty if Field is 0.
+//	Field int `csv:"count,omitempty"`
+//
+// Fields implementing [encoding.TextMarshaler] are written as their text.
+// Nil pointers are written as empty fields.
+//
+// All values written by an Encoder must have the same struct type.
+type Encoder struct {
+	w *Writer
+
+	typ    reflect.Type
+	fields *structFields
+	record []string
+}
+
+// NewEncoder returns a new Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: NewWriter(w)}
+}
+
+// Writer returns the [Writer] used by the Encoder.
+// Its exported fields may be changed to customize the details of the
+// output format before the first call to [Encoder.Encode].
+func (e *Encoder) Writer() *Writer {
+	return e.w
+}
+
+// Encode writes v as one or more CSV records, preceded by a header
+// record if this is the first call to Encode.
+//
+// The value v must be a struct, a pointer to a struct, or a slice or
+// array whose elements are structs or pointers to structs. A slice or
+// array is written as one record per element; an empty slice writes
+// only the header, if it has not been written already.
+//
+// Records are buffered, so [Encoder.Flush] must eventually be called.
+func (e *Encoder) Encode(v any) error {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return fmt.Errorf("csv: Encode of nil value")
+	}
+	if k := rv.Kind(); k == reflect.Struct || k == reflect.Array {
+		// Make the value addressable, for TextMarshaler
+		// methods with pointer receivers.
+		p := reflect.New(rv.Type())
+		p.Elem().Set(rv)
+		rv = p.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if err := e.prepare(rv.Type().Elem()); err != nil {
+			return err
+		}
+		for i := range rv.Len() {
+			if err := e.encode(rv.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := e.prepare(rv.Type()); err != nil {
+		return err
+	}
+	return e.encode(rv)
+}
+
+// prepare checks that t is the struct type written by e, and writes
+// the header if this is the first record.
+func (e *Encoder) prepare(t reflect.Type) error {
+	st, ok := structType(t)
+	if !ok {
+		return fmt.Errorf("csv: Encode requires structs, not %v", t)
+	}
+	if e.typ != nil {
+		if st != e.typ {
+			return fmt.Errorf("csv: Encode of %v after %v", st, e.typ)
+		}
+		return nil
+	}
+	fields, err := cachedTypeFields(st)
+	if err != nil {
+		return err
+	}
+	header := make([]string, len(fields.list))
+	for i, f := range fields.list {
+		header[i] = f.name
+	}
+	if err := e.w.Write(header); err != nil {
+		return err
+	}
+	e.typ = st
+	e.fields = fields
+	e.record = header[:0]
+	return nil
+}
+
+// encode writes the struct or struct pointer v as a record.
+func (e *Encoder) encode(v reflect.Value) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return fmt.Errorf("csv: Encode of nil %v", v.Type())
+		}
+		v = v.Elem()
+	}
+	e.record = e.record[:0]
+	for i := range e.fields.list {
+		f := &e.fields.list[i]
+		fv, ok := fieldByIndexNoAlloc(v, f.index)
+		var s string
+		if ok && !(f.omitEmpty && fv.IsZero()) {
+			var err error
+			s, err = marshalField(fv)
+			if err != nil {
+				return fmt.Errorf("csv: encoding field %s: %w", f.name, err)
+			}
+		}
+		e.record = append(e.record, s)
+	}
+	return e.w.Write(e.record)
+}
+
+// Flush writes any buffered records to the underlying [io.Writer]
+// and returns any error that occurred during a previous Encode or Flush.
+func (e *Encoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// fieldByIndexNoAlloc returns the field of v with the given index
+// sequence. It reports false if the field is in a nil embedded struct.
+func fieldByIndexNoAlloc(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// marshalField returns the text for v.
+func marshalField(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		if !v.Type().Implements(textMarshalerType) {
+			v = v.Elem()
+		}
+	}
+	if v.Type().Implements(textMarshalerType) {
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+	if v.CanAddr() && reflect.PointerTo(v.Type()).Implements(textMarshalerType) {
+		b, err := v.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+	return "", &UnsupportedTypeError{Type: v.Type()}
+}
+
+// Marshal returns the CSV encoding of v, which must be a slice or array
+// of structs or pointers to structs. The output starts with a header
+// record. See [Encoder] for the details of the encoding.
+func Marshal(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if k := rv.Kind(); k != reflect.Slice && k != reflect.Array {
+		return nil, fmt.Errorf("csv: Marshal requires a slice or array, not %T", v)
+	}
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := e.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
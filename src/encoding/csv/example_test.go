@@ -129,3 +129,57 @@ func ExampleWriter_WriteAll() {
 	// Ken,Thompson,ken
 	// Robert,Griesemer,gri
 }
+
+func ExampleDecoder() {
+	in := `username,first_name,last_name,commits
+rob,"Rob","Pike",41
+ken,Ken,Thompson,27
+gri,"Robert","Griesemer",63
+`
+	type User struct {
+		Username  string `csv:"username"`
+		FirstName string `csv:"first_name"`
+		Commits   int    `csv:"commits"`
+	}
+
+	d := csv.NewDecoder(strings.NewReader(in))
+	for {
+		var u User
+		err := d.Decode(&u)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s (%s): %d\n", u.FirstName, u.Username, u.Commits)
+	}
+	// Output:
+	// Rob (rob): 41
+	// Ken (ken): 27
+	// Robert (gri): 63
+}
+
+func ExampleEncoder() {
+	type User struct {
+		Username string `csv:"username"`
+		Name     string `csv:"name"`
+		Admin    bool   `csv:"admin,omitempty"`
+	}
+	users := []User{
+		{"rob", "Rob Pike", true},
+		{"ken", "Ken Thompson", false},
+	}
+
+	e := csv.NewEncoder(os.Stdout)
+	if err := e.Encode(users); err != nil {
+		log.Fatal(err)
+	}
+	if err := e.Flush(); err != nil {
+		log.Fatal(err)
+	}
+	// Output:
+	// username,name,admin
+	// rob,Rob Pike,true
+	// ken,Ken Thompson,
+}
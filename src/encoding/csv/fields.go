@@ -0,0 +1,220 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csv
+
+import (
+	"encoding"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// A field is a struct field mapped to a CSV column.
+type field struct {
+	name      string
+	index     []int // index sequence for reflect.Value.FieldByIndex
+	typ       reflect.Type
+	omitEmpty bool
+	tagged    bool // whether name came from a tag
+}
+
+// structFields describes the CSV columns of a struct type.
+type structFields struct {
+	list   []field
+	byName map[string]int // name to index in list
+}
+
+var fieldCache sync.Map // map[reflect.Type]*structFields
+
+// cachedTypeFields is like typeFields but uses a cache to avoid repeated work.
+func cachedTypeFields(t reflect.Type) (*structFields, error) {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.(*structFields), nil
+	}
+	fields, err := typeFields(t)
+	if err != nil {
+		return nil, err
+	}
+	f, _ := fieldCache.LoadOrStore(t, fields)
+	return f.(*structFields), nil
+}
+
+// typeFields returns the fields of struct type t which map to CSV columns.
+//
+// The rules follow those of encoding/json: exported fields are used, and
+// the fields of embedded structs are promoted. A field at a shallower depth
+// hides fields of the same name at deeper depths. If there are several
+// fields of the same name at the shallowest depth, a single tagged field
+// is used, and otherwise the name is ignored.
+func typeFields(t reflect.Type) (*structFields, error) {
+	type candidate struct {
+		field
+		depth int
+	}
+	var candidates []candidate
+
+	type queued struct {
+		typ   reflect.Type
+		index []int
+	}
+	current := []queued{}
+	next := []queued{{typ: t}}
+	visited := map[reflect.Type]bool{}
+	for depth := 0; len(next) > 0; depth++ {
+		current, next = next, current[:0]
+		for _, q := range current {
+			if visited[q.typ] {
+				continue
+			}
+			visited[q.typ] = true
+			for i := range q.typ.NumField() {
+				sf := q.typ.Field(i)
+				if sf.Anonymous {
+					ft := sf.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+				tag := sf.Tag.Get("csv")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				index := append(append([]int(nil), q.index...), i)
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct && !isText(ft) {
+					next = append(next, queued{typ: ft, index: index})
+					continue
+				}
+				if !sf.IsExported() {
+					continue
+				}
+				if !supported(sf.Type) {
+					return nil, &UnsupportedTypeError{Type: sf.Type}
+				}
+				f := field{
+					name:   name,
+					index:  index,
+					typ:    sf.Type,
+					tagged: name != "",
+				}
+				if f.name == "" {
+					f.name = sf.Name
+				}
+				for opt := range strings.SplitSeq(opts, ",") {
+					if opt == "omitempty" {
+						f.omitEmpty = true
+					}
+				}
+				candidates = append(candidates, candidate{f, depth})
+			}
+		}
+	}
+
+	// Order the fields as they are declared, with the fields of an
+	// embedded struct at the position of the embedded field.
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		return slices.Compare(a.index, b.index)
+	})
+
+	// Resolve name conflicts. Of the fields with a given name, use the
+	// one at the shallowest depth. If there are several, use the single
+	// tagged one, or none.
+	byName := make(map[string][]int) // name to indexes in candidates
+	for i, c := range candidates {
+		byName[c.name] = append(byName[c.name], i)
+	}
+	dominant := func(name string) int {
+		minDepth := candidates[byName[name][0]].depth
+		for _, i := range byName[name] {
+			minDepth = min(minDepth, candidates[i].depth)
+		}
+		found, tagged := -1, -1
+		n, ntagged := 0, 0
+		for _, i := range byName[name] {
+			if candidates[i].depth != minDepth {
+				continue
+			}
+			found, n = i, n+1
+			if candidates[i].tagged {
+				tagged, ntagged = i, ntagged+1
+			}
+		}
+		switch {
+		case n == 1:
+			return found
+		case ntagged == 1:
+			return tagged
+		}
+		return -1
+	}
+	fields := &structFields{byName: make(map[string]int)}
+	for i, c := range candidates {
+		if dominant(c.name) != i {
+			continue
+		}
+		fields.byName[c.name] = len(fields.list)
+		fields.list = append(fields.list, c.field)
+	}
+	return fields, nil
+}
+
+var (
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// isText reports whether t is converted to and from a field
+// using its encoding.TextMarshaler and encoding.TextUnmarshaler methods.
+func isText(t reflect.Type) bool {
+	return t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// supported reports whether values of type t can be stored in a field.
+func supported(t reflect.Type) bool {
+	if isText(t) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Pointer:
+		return t.Elem().Kind() != reflect.Pointer && supported(t.Elem())
+	}
+	return false
+}
+
+// An UnsupportedTypeError is returned by [Encoder.Encode] and
+// [Decoder.Decode] when a struct field has a type which
+// cannot be represented as a CSV field.
+type UnsupportedTypeError struct {
+	Type reflect.Type
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return "csv: unsupported type: " + e.Type.String()
+}
+
+// structType returns the struct type described by t,
+// which must be a struct or a pointer to a struct.
+func structType(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t, t.Kind() == reflect.Struct
+}
@@ -0,0 +1,251 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csv
+
+import (
+	"errors"
+	"io"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testEmbedded struct {
+	Region string `csv:"region"`
+	Note   string `csv:"note"`
+}
+
+type testRecord struct {
+	testEmbedded
+	Name    string     `csv:"name"`
+	Age     int        `csv:"age"`
+	Score   float64    `csv:"score,omitempty"`
+	Active  bool       `csv:"active"`
+	Addr    netip.Addr `csv:"addr"`
+	Seen    *time.Time `csv:"seen"`
+	Count   *uint8     `csv:"count"`
+	Note    string     `csv:"comment"`
+	Ignored string     `csv:"-"`
+	private int
+}
+
+func TestDecode(t *testing.T) {
+	const in = `name,age,score,active,addr,seen,count,region,comment,extra
+Alice,30,1.5,true,192.0.2.1,2026-01-02T03:04:05Z,7,eu,hi,x
+Bob,,,false,::1,,,us,,y
+`
+	d := NewDecoder(strings.NewReader(in))
+	var got []testRecord
+	for {
+		var r testRecord
+		err := d.Decode(&r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, r)
+	}
+	seen := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	count := uint8(7)
+	want := []testRecord{{
+		testEmbedded: testEmbedded{Region: "eu"},
+		Name:         "Alice",
+		Age:          30,
+		Score:        1.5,
+		Active:       true,
+		Addr:         netip.MustParseAddr("192.0.2.1"),
+		Seen:         &seen,
+		Count:        &count,
+		Note:         "hi",
+	}, {
+		testEmbedded: testEmbedded{Region: "us"},
+		Name:         "Bob",
+		Addr:         netip.MustParseAddr("::1"),
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode:\ngot  %+v\nwant %+v", got, want)
+	}
+	if h, _ := d.Header(); len(h) != 10 || h[0] != "name" {
+		t.Errorf("Header() = %q", h)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	type rec struct {
+		A int     `csv:"a"`
+		B float32 `csv:"b"`
+	}
+	tests := []struct {
+		in       string
+		disallow bool
+		line     int
+		column   int
+		errIs    error
+		errText  string
+	}{{
+		in:      "a,b\n1,2\nx,3\n",
+		line:    3,
+		column:  1,
+		errIs:   strconv.ErrSyntax,
+		errText: `parse error on line 3, column 1: cannot unmarshal "x" into field a of type int: invalid syntax`,
+	}, {
+		in:     "a,b\n1,1e99\n",
+		line:   2,
+		column: 3,
+		errIs:  strconv.ErrRange,
+	}, {
+		in:     "a,b\n1,2,3\n",
+		line:   2,
+		column: 1,
+		errIs:  ErrFieldCount,
+	}, {
+		in:     "b,a\n\"1.5\",\"\nx\"\n",
+		line:   2,
+		column: 7,
+		errIs:  strconv.ErrSyntax,
+	}, {
+		in:       "a,c,b\n1,2,3\n",
+		disallow: true,
+		line:     1,
+		column:   3,
+		errText:  `parse error on line 1, column 3: unknown field "c"`,
+	}}
+	for _, tt := range tests {
+		d := NewDecoder(strings.NewReader(tt.in))
+		if tt.disallow {
+			d.DisallowUnknownFields()
+		}
+		var err error
+		for err == nil {
+			var r rec
+			err = d.Decode(&r)
+		}
+		pe, ok := errors.AsType[*ParseError](err)
+		if !ok {
+			t.Errorf("%q: Decode error = %v, want *ParseError", tt.in, err)
+			continue
+		}
+		if pe.Line != tt.line || pe.Column != tt.column {
+			t.Errorf("%q: error at line %d, column %d; want line %d, column %d", tt.in, pe.Line, pe.Column, tt.line, tt.column)
+		}
+		if tt.errIs != nil && !errors.Is(err, tt.errIs) {
+			t.Errorf("%q: error %v, want %v", tt.in, err, tt.errIs)
+		}
+		if tt.errText != "" && err.Error() != tt.errText {
+			t.Errorf("%q: error text:\ngot  %s\nwant %s", tt.in, err, tt.errText)
+		}
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	d := NewDecoder(strings.NewReader("a\n1\n"))
+	var s struct{ A int }
+	if err := d.Decode(s); err == nil {
+		t.Errorf("Decode(non-pointer) succeeded, want error")
+	}
+	var u struct {
+		A int
+		M map[string]int
+	}
+	if _, ok := errors.AsType[*UnsupportedTypeError](d.Decode(&u)); !ok {
+		t.Errorf("Decode of struct with map field did not return UnsupportedTypeError")
+	}
+	var empty struct{ A int }
+	if err := NewDecoder(strings.NewReader("")).Decode(&empty); err != io.EOF {
+		t.Errorf("Decode of empty input = %v, want io.EOF", err)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	seen := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := []testRecord{{
+		testEmbedded: testEmbedded{Region: "eu"},
+		Name:         "Alice, A.",
+		Age:          30,
+		Score:        1.5,
+		Active:       true,
+		Addr:         netip.MustParseAddr("192.0.2.1"),
+		Seen:         &seen,
+		Note:         `say "hi"`,
+	}, {
+		Name: "Bob",
+	}}
+	var b strings.Builder
+	e := NewEncoder(&b)
+	if err := e.Encode(rows[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Encode(rows[1:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Encode(&struct{ X int }{}); err == nil {
+		t.Errorf("Encode of a different struct type succeeded, want error")
+	}
+	if err := e.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	const want = `region,note,name,age,score,active,addr,seen,count,comment
+eu,,"Alice, A.",30,1.5,true,192.0.2.1,2026-01-02T03:04:05Z,,"say ""hi"""
+,,Bob,0,,false,,,,
+`
+	if got := b.String(); got != want {
+		t.Errorf("Encode:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	type rec struct {
+		ID   uint64  `csv:"id"`
+		Name *string `csv:"name"`
+		Rate float32 `csv:"rate"`
+	}
+	name := "multi\nline"
+	in := []*rec{{ID: 1, Name: &name, Rate: 0.25}, {ID: 2, Rate: -3}}
+	data, err := Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []*rec
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("round trip of %q:\ngot  %+v\nwant %+v", data, out, in)
+	}
+}
+
+func TestTypeFieldsConflict(t *testing.T) {
+	type A struct{ X, Y int }
+	type B struct {
+		X int
+		Y int `csv:"Y"`
+	}
+	type C struct {
+		A
+		B
+		Z int
+		X string // hides A.X and B.X
+	}
+	fields, err := typeFields(reflect.TypeFor[C]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range fields.list {
+		names = append(names, f.name)
+	}
+	// Y is taken from the tagged field of B.
+	if got, want := strings.Join(names, ","), "Y,Z,X"; got != want {
+		t.Errorf("fields = %s, want %s", got, want)
+	}
+	if got := fields.list[0].index; !reflect.DeepEqual(got, []int{1, 1}) {
+		t.Errorf("Y index = %v, want [1 1]", got)
+	}
+}
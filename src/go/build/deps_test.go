@@ -298,7 +298,13 @@ var depsRules = `
 	# compression
 	FMT, encoding/binary, hash/adler32, hash/crc32, sort
 	< compress/bzip2, compress/flate, compress/lzw, internal/zstd
-	< archive/zip, compress/gzip, compress/zlib;
+	< compress/gzip, compress/zlib, compress/zstd;
+
+	FMT
+	< archive/internal/extract;
+
+	compress/gzip, compress/zlib, compress/zstd, archive/internal/extract
+	< archive/zip;
 
 	# templates
 	FMT
@@ -411,7 +417,9 @@ var depsRules = `
 	< plugin;
 
 	CGO, FMT
-	< os/user
+	< os/user;
+
+	os/user, compress/bzip2, compress/gzip, compress/zstd, archive/internal/extract
 	< archive/tar;
 
 	sync
@@ -471,9 +479,6 @@ var depsRules = `
 	< log/slog
 	< log/slog/internal/slogtest, log/slog/internal/benchmarks;
 
-	NET, log
-	< net/mail;
-
 	# FIPS is the FIPS 140 module.
 	# It must not depend on external crypto packages.
 	# Package hash is ok as it's only the interface.
@@ -566,7 +571,9 @@ var depsRules = `
 	  crypto/hkdf,
 	  crypto/pbkdf2,
 	  crypto/ecdh,
+	  crypto/mldsa,
 	  crypto/mlkem
+	< crypto/internal/mldsaexpand
 	< CRYPTO;
 
 	CRYPTO
@@ -601,11 +608,22 @@ var depsRules = `
 	< golang.org/x/crypto/internal/subtle
 	< golang.org/x/crypto/chacha20
 	< golang.org/x/crypto/internal/poly1305
-	< golang.org/x/crypto/chacha20poly1305;
+	< golang.org/x/crypto/chacha20poly1305
+	< crypto/chacha20poly1305;
 
 	CRYPTO-MATH, golang.org/x/crypto/chacha20poly1305
 	< crypto/hpke;
 
+	CRYPTO-MATH, encoding/base64, encoding/binary, golang.org/x/sys/cpu
+	< golang.org/x/crypto/blake2b,
+	  golang.org/x/crypto/blowfish,
+	  golang.org/x/crypto/pbkdf2,
+	  golang.org/x/crypto/salsa20/salsa
+	< golang.org/x/crypto/argon2,
+	  golang.org/x/crypto/bcrypt,
+	  golang.org/x/crypto/scrypt
+	< crypto/password;
+
 	CRYPTO-MATH, NET, container/list, encoding/hex, encoding/pem, crypto/hpke,
 	golang.org/x/crypto/chacha20poly1305, crypto/tls/internal/fips140tls
 	< crypto/x509/internal/macos
@@ -613,6 +631,11 @@ var depsRules = `
 	< crypto/x509
 	< crypto/tls;
 
+	CRYPTO-MATH, encoding/binary < crypto/pkcs12/internal/rc2;
+
+	crypto/x509, crypto/pkcs12/internal/rc2, unicode/utf16
+	< crypto/pkcs12;
+
 	# crypto-aware packages
 
 	FMT, hash/maphash
@@ -629,7 +652,10 @@ var depsRules = `
 	NET, crypto/rand, mime/quotedprintable
 	< mime/multipart;
 
-	crypto/tls
+	log, mime/multipart
+	< net/mail;
+
+	crypto/tls, net/mail
 	< net/smtp;
 
 	# HTTP, King of Dependencies.
@@ -652,7 +678,16 @@ var depsRules = `
 	NET, crypto/tls
 	< net/http/httptrace;
 
+	# QUIC, for HTTP/3 in net/http. QUIC logs connection events with
+	# log/slog, so net/http depends on log/slog through it; the
+	# nethttpomithttp3 build tag removes both from a program.
+	crypto/tls, golang.org/x/crypto/hkdf, log/slog
+	< golang.org/x/net/internal/quic/quicwire
+	< golang.org/x/net/quic;
+
 	compress/gzip,
+	compress/zlib,
+	compress/zstd,
 	golang.org/x/net/http/httpguts,
 	golang.org/x/net/http/httpproxy,
 	golang.org/x/net/http2/hpack,
@@ -661,17 +696,12 @@ var depsRules = `
 	net/http/internal/testcert,
 	net/http/httptrace,
 	mime/multipart,
+	golang.org/x/net/quic,
 	log
 	< net/http/internal/httpcommon, net/http/internal/httpsfv
-	< net/http/internal/http2
+	< net/http/internal/http2, net/http/internal/http3
 	< net/http;
 
-	net/http, golang.org/x/crypto/hkdf, log/slog
-	< golang.org/x/net/internal/quic/quicwire
-	< golang.org/x/net/quic, golang.org/x/net/internal/httpcommon
-	< golang.org/x/net/internal/http3
-	< golang.org/x/net/http3;
-
 	# HTTP-aware packages
 
 	encoding/json, net/http
@@ -680,7 +710,7 @@ var depsRules = `
 	net/http, net/http/internal/ascii
 	< net/http/cookiejar, net/http/httputil;
 
-	net/http, flag
+	net/http, flag, regexp
 	< net/http/httptest;
 
 	net/http, regexp
@@ -715,7 +745,10 @@ var depsRules = `
 	FMT, flag, math/rand
 	< testing/quick;
 
-	FMT, DEBUG, flag, runtime/trace, internal/sysinfo, math/rand
+	FMT, sort
+	< internal/diff;
+
+	FMT, DEBUG, flag, runtime/trace, internal/sysinfo, internal/diff, math/rand
 	< testing;
 
 	testing, math
@@ -747,9 +780,6 @@ var depsRules = `
 	syscall
 	< os/exec/internal/fdtest;
 
-	FMT, sort
-	< internal/diff;
-
 	FMT
 	< internal/txtar;
 
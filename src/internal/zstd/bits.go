@@ -128,3 +128,36 @@ func (rbr *reverseBitReader) fetch(b uint8) bool {
 func (rbr *reverseBitReader) makeError(msg string) error {
 	return rbr.r.makeError(int(rbr.off), msg)
 }
+
+// bitWriter writes a bit stream going forward, to be read back by
+// a reverseBitReader. Values are packed starting at the least
+// significant bit of each byte.
+type bitWriter struct {
+	out  []byte // the completed bytes
+	bits uint64 // bits not yet written to out
+	cnt  uint32 // number of valid bits in the bits field
+}
+
+// add writes the low b bits of v.
+// The value of b must be at most 32.
+func (bw *bitWriter) add(v uint32, b uint8) {
+	bw.bits |= uint64(v&(1<<b-1)) << bw.cnt
+	bw.cnt += uint32(b)
+	for bw.cnt >= 8 {
+		bw.out = append(bw.out, byte(bw.bits))
+		bw.bits >>= 8
+		bw.cnt -= 8
+	}
+}
+
+// close terminates the stream with the 1 bit that marks its start
+// for the reader, and returns the completed bytes.
+func (bw *bitWriter) close() []byte {
+	bw.add(1, 1)
+	if bw.cnt > 0 {
+		bw.out = append(bw.out, byte(bw.bits))
+	}
+	bw.bits = 0
+	bw.cnt = 0
+	return bw.out
+}
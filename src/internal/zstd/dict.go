@@ -0,0 +1,122 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// dictMagic is the magic number at the start of a formatted dictionary.
+// RFC 5.
+const dictMagic = 0xec30a437
+
+// A Dict is a zstd dictionary, described in RFC 8878 section 5.
+// A Dict may be shared by any number of Readers and Writers.
+type Dict struct {
+	// The dictionary ID, or 0 for a raw content dictionary.
+	id uint32
+
+	// The content, used as history preceding the frame data.
+	content []byte
+
+	// Whether the entropy tables below are valid.
+	// This is false for a raw content dictionary.
+	hasTables bool
+
+	// The Huffman table used for literals.
+	huffmanTable     []uint16
+	huffmanTableBits int
+
+	// The sequence decode FSE tables.
+	seqTables    [3][]fseBaselineEntry
+	seqTableBits [3]uint8
+
+	// The initial repeated offsets.
+	repeatedOffsets [3]uint32
+}
+
+// ParseDict parses a zstd dictionary.
+// If data does not start with the dictionary magic number,
+// it is treated as a raw content dictionary with ID 0.
+// ParseDict retains a copy of the content.
+func ParseDict(data []byte) (*Dict, error) {
+	if len(data) < 8 || binary.LittleEndian.Uint32(data) != dictMagic {
+		return &Dict{
+			content:         append([]byte(nil), data...),
+			repeatedOffsets: [3]uint32{1, 4, 8},
+		}, nil
+	}
+
+	d := &Dict{
+		id:        binary.LittleEndian.Uint32(data[4:]),
+		hasTables: true,
+	}
+	if d.id == 0 {
+		return nil, errors.New("zstd: invalid dictionary ID 0")
+	}
+
+	// Read the entropy tables using a scratch Reader,
+	// which provides the table parsing and the error reporting.
+	// RFC 5, Entropy_Tables.
+	var r Reader
+	blk := block(data)
+	off := 8
+
+	d.huffmanTable = make([]uint16, 1<<maxHuffmanBits)
+	tableBits, off, err := r.readHuff(blk, off, d.huffmanTable)
+	if err != nil {
+		return nil, dictError(err)
+	}
+	d.huffmanTableBits = tableBits
+
+	// The FSE tables appear in the order offsets, match lengths,
+	// literal lengths.
+	for _, kind := range [...]seqCode{seqOffset, seqMatch, seqLiteral} {
+		info := &seqCodeInfo[kind]
+		fseTable := make([]fseEntry, 1<<info.maxBits)
+		tableBits, roff, err := r.readFSE(blk, off, info.maxSym, info.maxBits, fseTable)
+		if err != nil {
+			return nil, dictError(err)
+		}
+		fseTable = fseTable[:1<<tableBits]
+		baseline := make([]fseBaselineEntry, len(fseTable))
+		if err := info.toBaseline(&r, roff, fseTable, baseline); err != nil {
+			return nil, dictError(err)
+		}
+		d.seqTables[kind] = baseline
+		d.seqTableBits[kind] = uint8(tableBits)
+		off = roff
+	}
+
+	if off+12 > len(data) {
+		return nil, errors.New("zstd: dictionary truncated")
+	}
+	d.content = append([]byte(nil), data[off+12:]...)
+	for i := range d.repeatedOffsets {
+		ro := binary.LittleEndian.Uint32(data[off+4*i:])
+		if ro == 0 || uint64(ro) > uint64(len(d.content)) {
+			return nil, errors.New("zstd: invalid repeat offset in dictionary")
+		}
+		d.repeatedOffsets[i] = ro
+	}
+
+	return d, nil
+}
+
+// ID returns the dictionary ID, which is 0 for a raw content dictionary.
+func (d *Dict) ID() uint32 {
+	return d.id
+}
+
+// dictError converts an error from parsing the dictionary
+// entropy tables, dropping the offset information that is only
+// meaningful for a compressed stream.
+func dictError(err error) error {
+	if ze, ok := err.(*zstdError); ok {
+		err = ze.err
+	}
+	return errors.New("zstd: invalid dictionary: " + err.Error())
+}
@@ -0,0 +1,499 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"slices"
+	"sync"
+)
+
+// fseSymbolTransform is the encoding information for one symbol
+// in an FSE encoding table.
+type fseSymbolTransform struct {
+	deltaFindState int32  // offset of the symbol's states in stateTable
+	deltaNbBits    uint32 // used to compute the number of bits to write
+}
+
+// fseEncTable is an FSE encoding table. It is the inverse of the
+// decoding table built by buildFSE from the same distribution.
+type fseEncTable struct {
+	tableBits  uint8
+	stateTable []uint16
+	symbolTT   []fseSymbolTransform
+}
+
+// buildFSEEnc builds an FSE encoding table from a list of probabilities,
+// using the same symbol spread as buildFSE.
+func buildFSEEnc(norm []int16, tableBits int) *fseEncTable {
+	tableSize := 1 << tableBits
+	highThreshold := tableSize - 1
+	symbols := make([]uint8, tableSize)
+	cumul := make([]int, len(norm)+1)
+
+	for i, n := range norm {
+		if n == -1 {
+			cumul[i+1] = cumul[i] + 1
+			symbols[highThreshold] = uint8(i)
+			highThreshold--
+		} else {
+			cumul[i+1] = cumul[i] + int(n)
+		}
+	}
+
+	pos := 0
+	step := (tableSize >> 1) + (tableSize >> 3) + 3
+	mask := tableSize - 1
+	for i, n := range norm {
+		for j := 0; j < int(n); j++ {
+			symbols[pos] = uint8(i)
+			pos = (pos + step) & mask
+			for pos > highThreshold {
+				pos = (pos + step) & mask
+			}
+		}
+	}
+
+	t := &fseEncTable{
+		tableBits:  uint8(tableBits),
+		stateTable: make([]uint16, tableSize),
+		symbolTT:   make([]fseSymbolTransform, len(norm)),
+	}
+	for i, sym := range symbols {
+		t.stateTable[cumul[sym]] = uint16(tableSize + i)
+		cumul[sym]++
+	}
+
+	total := 0
+	for i, n := range norm {
+		tt := &t.symbolTT[i]
+		switch n {
+		case 0:
+			tt.deltaNbBits = uint32((tableBits+1)<<16 - tableSize)
+		case -1, 1:
+			tt.deltaNbBits = uint32(tableBits<<16 - tableSize)
+			tt.deltaFindState = int32(total - 1)
+			total++
+		default:
+			maxBitsOut := tableBits - (bits.Len16(uint16(n-1)) - 1)
+			minStatePlus := int(n) << maxBitsOut
+			tt.deltaNbBits = uint32(maxBitsOut<<16 - minStatePlus)
+			tt.deltaFindState = int32(total - int(n))
+			total += int(n)
+		}
+	}
+
+	return t
+}
+
+// fseEncState is the state of one FSE encoder.
+type fseEncState struct {
+	table *fseEncTable
+	state uint32
+}
+
+// init sets the initial state to encode sym.
+// The symbol sym itself is not written: it is the last symbol that
+// the decoder will see.
+func (s *fseEncState) init(table *fseEncTable, sym uint8) {
+	s.table = table
+	tt := table.symbolTT[sym]
+	nbBits := (tt.deltaNbBits + 1<<15) >> 16
+	v := nbBits<<16 - tt.deltaNbBits
+	s.state = uint32(table.stateTable[int32(v>>nbBits)+tt.deltaFindState])
+}
+
+// encode writes the bits needed to move to a state for sym.
+func (s *fseEncState) encode(bw *bitWriter, sym uint8) {
+	tt := s.table.symbolTT[sym]
+	nbBits := (s.state + tt.deltaNbBits) >> 16
+	bw.add(s.state, uint8(nbBits))
+	s.state = uint32(s.table.stateTable[int32(s.state>>nbBits)+tt.deltaFindState])
+}
+
+// flush writes the final state, which the decoder reads first.
+func (s *fseEncState) flush(bw *bitWriter) {
+	bw.add(s.state, s.table.tableBits)
+}
+
+// predefinedEncTables returns the encoding tables for the predefined
+// literal length, offset, and match length distributions.
+var predefinedEncTables = sync.OnceValue(func() *[3]*fseEncTable {
+	return &[3]*fseEncTable{
+		seqLiteral: buildFSEEnc(literalPredefinedDistribution, 6),
+		seqOffset:  buildFSEEnc(offsetPredefinedDistribution, 5),
+		seqMatch:   buildFSEEnc(matchPredefinedDistribution, 6),
+	}
+})
+
+// seq is a single sequence found by the match finder. RFC 3.1.1.3.2.
+type seq struct {
+	litLen   uint32 // number of literals preceding the match
+	offset   uint32 // distance back to the start of the match
+	matchLen uint32 // length of the match, at least 3
+}
+
+// literalLengthCode returns the code and extra bits for a literal length.
+// RFC 3.1.1.3.2.1.1.
+func literalLengthCode(ll uint32) (code uint8, extra uint32, nbits uint8) {
+	if ll < literalLengthOffset {
+		return uint8(ll), 0, 0
+	}
+	i := len(literalLengthBase) - 1
+	for literalLengthBase[i]&0xffffff > ll {
+		i--
+	}
+	base := literalLengthBase[i]
+	return uint8(i + literalLengthOffset), ll - base&0xffffff, uint8(base >> 24)
+}
+
+// matchLengthCode returns the code and extra bits for a match length.
+// RFC 3.1.1.3.2.1.1.
+func matchLengthCode(ml uint32) (code uint8, extra uint32, nbits uint8) {
+	if ml-3 < matchLengthOffset {
+		return uint8(ml - 3), 0, 0
+	}
+	i := len(matchLengthBase) - 1
+	for matchLengthBase[i]&0xffffff > ml {
+		i--
+	}
+	base := matchLengthBase[i]
+	return uint8(i + matchLengthOffset), ml - base&0xffffff, uint8(base >> 24)
+}
+
+// appendSequences appends the Sequences_Section for seqs to out,
+// using the predefined FSE tables. RFC 3.1.1.3.2.
+func appendSequences(out []byte, seqs []seq) []byte {
+	n := len(seqs)
+	switch {
+	case n < 128:
+		out = append(out, byte(n))
+	case n < 0x7f00:
+		out = append(out, byte(n>>8)+128, byte(n))
+	default:
+		out = append(out, 255, byte(n-0x7f00), byte((n-0x7f00)>>8))
+	}
+	if n == 0 {
+		return out
+	}
+
+	// All three codes use Predefined_Mode.
+	out = append(out, 0)
+
+	tables := predefinedEncTables()
+
+	type codes struct {
+		ll, of, ml             uint8
+		llExtra, ofExtra       uint32
+		mlExtra                uint32
+		llBits, ofBits, mlBits uint8
+	}
+	code := func(s seq) codes {
+		var c codes
+		c.ll, c.llExtra, c.llBits = literalLengthCode(s.litLen)
+		c.ml, c.mlExtra, c.mlBits = matchLengthCode(s.matchLen)
+		// We never use repeated offsets, so every offset
+		// value is the offset plus 3. RFC 3.1.1.5.
+		ov := s.offset + 3
+		c.of = uint8(bits.Len32(ov) - 1)
+		c.ofExtra = ov - 1<<c.of
+		c.ofBits = c.of
+		return c
+	}
+
+	// The decoder reads the bit stream backward,
+	// so we write the sequences from last to first.
+	bw := bitWriter{out: out}
+	var llState, ofState, mlState fseEncState
+	c := code(seqs[n-1])
+	mlState.init(tables[seqMatch], c.ml)
+	ofState.init(tables[seqOffset], c.of)
+	llState.init(tables[seqLiteral], c.ll)
+	bw.add(c.llExtra, c.llBits)
+	bw.add(c.mlExtra, c.mlBits)
+	bw.add(c.ofExtra, c.ofBits)
+	for i := n - 2; i >= 0; i-- {
+		c := code(seqs[i])
+		ofState.encode(&bw, c.of)
+		mlState.encode(&bw, c.ml)
+		llState.encode(&bw, c.ll)
+		bw.add(c.llExtra, c.llBits)
+		bw.add(c.mlExtra, c.mlBits)
+		bw.add(c.ofExtra, c.ofBits)
+	}
+	mlState.flush(&bw)
+	ofState.flush(&bw)
+	llState.flush(&bw)
+	return bw.close()
+}
+
+// appendRawLiterals appends a Raw_Literals_Block to out.
+// RFC 3.1.1.3.1.
+func appendRawLiterals(out, lits []byte) []byte {
+	n := len(lits)
+	switch {
+	case n < 32:
+		out = append(out, byte(n<<3))
+	case n < 4096:
+		out = append(out, byte(n<<4)|1<<2, byte(n>>4))
+	default:
+		out = append(out, byte(n<<4)|3<<2, byte(n>>4), byte(n>>12))
+	}
+	return append(out, lits...)
+}
+
+// appendRLELiterals appends an RLE_Literals_Block to out
+// for n copies of b. RFC 3.1.1.3.1.
+func appendRLELiterals(out []byte, b byte, n int) []byte {
+	switch {
+	case n < 32:
+		out = append(out, byte(n<<3)|1)
+	case n < 4096:
+		out = append(out, byte(n<<4)|1<<2|1, byte(n>>4))
+	default:
+		out = append(out, byte(n<<4)|3<<2|1, byte(n>>4), byte(n>>12))
+	}
+	return append(out, b)
+}
+
+// minHuffLiterals is the smallest number of literals for which we
+// try Huffman compression.
+const minHuffLiterals = 64
+
+// appendLiterals appends the Literals_Section for lits to out,
+// choosing the smallest of the representations we support.
+// RFC 3.1.1.3.1.
+func appendLiterals(out, lits []byte, scratch *huffScratch) []byte {
+	if len(lits) == 0 {
+		return append(out, 0)
+	}
+
+	var counts [256]int
+	maxSym := 0
+	for _, b := range lits {
+		counts[b]++
+		maxSym = max(maxSym, int(b))
+	}
+	if counts[lits[0]] == len(lits) {
+		return appendRLELiterals(out, lits[0], len(lits))
+	}
+
+	start := len(out)
+	out = appendRawLiterals(out, lits)
+	if len(lits) < minHuffLiterals || maxSym > 128 {
+		// The Huffman weights for symbols above 128
+		// would have to be FSE compressed, which we don't do.
+		return out
+	}
+
+	raw := len(out) - start
+	huff, ok := scratch.compress(lits, counts[:maxSym+1])
+	if !ok || len(huff) >= raw {
+		return out
+	}
+	return append(out[:start], huff...)
+}
+
+// huffScratch holds the buffers used for Huffman compression
+// of literals, to avoid repeated allocation.
+type huffScratch struct {
+	out   []byte
+	codes [256]uint16 // Huffman code for each symbol
+	nbits [256]uint8  // code length for each symbol
+	syms  []int
+}
+
+// compress returns a Compressed_Literals_Block for lits,
+// whose symbol counts are in counts. It reports false if
+// the literals can't be compressed that way.
+// RFC 3.1.1.3.1.
+func (h *huffScratch) compress(lits []byte, counts []int) ([]byte, bool) {
+	tableBits, ok := h.buildCodes(counts)
+	if !ok {
+		return nil, false
+	}
+
+	// Huffman tree description, with weights stored directly.
+	// The weight of the last symbol is implied. RFC 4.2.1.
+	maxSym := len(counts) - 1
+	tree := make([]byte, 0, 1+(maxSym+1)/2)
+	tree = append(tree, byte(127+maxSym))
+	weight := func(sym int) byte {
+		if sym >= maxSym || h.nbits[sym] == 0 {
+			return 0
+		}
+		return byte(tableBits + 1 - int(h.nbits[sym]))
+	}
+	for i := 0; i < maxSym; i += 2 {
+		tree = append(tree, weight(i)<<4|weight(i+1))
+	}
+
+	// Use a single stream for small inputs, as permitted by
+	// the 10 bit size fields. Otherwise use four streams.
+	// RFC 3.1.1.3.1.6.
+	streams := 4
+	if len(lits) < 256 {
+		streams = 1
+	}
+
+	body := append(h.out[:0], tree...)
+	if streams == 1 {
+		body = h.appendStream(body, lits)
+	} else {
+		jump := len(body)
+		body = append(body, 0, 0, 0, 0, 0, 0)
+		segment := (len(lits) + 3) / 4
+		for i := 0; i < 4; i++ {
+			start := len(body)
+			seg := lits[min(i*segment, len(lits)):min((i+1)*segment, len(lits))]
+			body = h.appendStream(body, seg)
+			if i < 3 {
+				size := len(body) - start
+				if size > 0xffff {
+					return nil, false
+				}
+				binary.LittleEndian.PutUint16(body[jump+2*i:], uint16(size))
+			}
+		}
+	}
+	h.out = body
+
+	regen, comp := len(lits), len(body)
+	var hdr []byte
+	switch {
+	case streams == 1 && regen < 1024 && comp < 1024:
+		hdr = []byte{byte(regen<<4) | 0<<2 | 2, byte(regen>>4)&0x3f | byte(comp<<6), byte(comp >> 2)}
+	case regen < 1024 && comp < 1024:
+		hdr = []byte{byte(regen<<4) | 1<<2 | 2, byte(regen>>4)&0x3f | byte(comp<<6), byte(comp >> 2)}
+	case streams == 1:
+		return nil, false
+	case regen < 16384 && comp < 16384:
+		hdr = []byte{byte(regen<<4) | 2<<2 | 2, byte(regen >> 4), byte(regen>>12)&3 | byte(comp<<2), byte(comp >> 6)}
+	case regen < 262144 && comp < 262144:
+		hdr = []byte{byte(regen<<4) | 3<<2 | 2, byte(regen >> 4), byte(regen>>12)&0x3f | byte(comp<<6), byte(comp >> 2), byte(comp >> 10)}
+	default:
+		return nil, false
+	}
+
+	return append(hdr, body...), true
+}
+
+// appendStream appends a single Huffman coded stream for lits to out.
+// The decoder reads the stream backward, so the literals are written
+// from last to first.
+func (h *huffScratch) appendStream(out, lits []byte) []byte {
+	bw := bitWriter{out: out}
+	for i := len(lits) - 1; i >= 0; i-- {
+		b := lits[i]
+		bw.add(uint32(h.codes[b]), h.nbits[b])
+	}
+	return bw.close()
+}
+
+// buildCodes computes length limited Huffman codes for counts,
+// storing them in h.codes and h.nbits. It returns the maximum
+// code length, which is the number of bits in the decoding table.
+// It reports false if fewer than two symbols are used.
+func (h *huffScratch) buildCodes(counts []int) (int, bool) {
+	syms := h.syms[:0]
+	for sym, c := range counts {
+		h.nbits[sym] = 0
+		if c > 0 {
+			syms = append(syms, sym)
+		}
+	}
+	h.syms = syms
+	if len(syms) < 2 {
+		return 0, false
+	}
+
+	// Sort by increasing count, then compute code lengths with
+	// the two queue method.
+	slices.SortStableFunc(syms, func(a, b int) int {
+		return counts[a] - counts[b]
+	})
+	type node struct {
+		weight int
+		parent int
+	}
+	nodes := make([]node, len(syms), 2*len(syms)-1)
+	for i, sym := range syms {
+		nodes[i].weight = counts[sym]
+	}
+	leaf, internal := 0, len(syms)
+	pick := func() int {
+		if leaf < len(syms) && (internal >= len(nodes) || nodes[leaf].weight <= nodes[internal].weight) {
+			leaf++
+			return leaf - 1
+		}
+		internal++
+		return internal - 1
+	}
+	for len(nodes) < cap(nodes) {
+		a, b := pick(), pick()
+		nodes = append(nodes, node{weight: nodes[a].weight + nodes[b].weight})
+		nodes[a].parent = len(nodes) - 1
+		nodes[b].parent = len(nodes) - 1
+	}
+	depth := make([]int, len(nodes))
+	for i := len(nodes) - 2; i >= 0; i-- {
+		depth[i] = depth[nodes[i].parent] + 1
+	}
+
+	// Limit the code lengths, and then adjust them so that they
+	// form a complete prefix code, as the decoder requires.
+	// Symbols are sorted by increasing count, so the lengths are
+	// non-increasing.
+	lengths := depth[:len(syms)]
+	kraft := 0 // in units of 2**-maxHuffmanBits
+	for i := range lengths {
+		lengths[i] = min(lengths[i], maxHuffmanBits)
+		kraft += 1 << (maxHuffmanBits - lengths[i])
+	}
+	const one = 1 << maxHuffmanBits
+	for kraft > one {
+		// Lengthen the least frequent code that is still short
+		// enough to be lengthened.
+		for i := range lengths {
+			if lengths[i] < maxHuffmanBits {
+				kraft -= 1 << (maxHuffmanBits - lengths[i] - 1)
+				lengths[i]++
+				break
+			}
+		}
+	}
+	for kraft < one {
+		// Shorten the most frequent code that fits in the space left.
+		for i := len(lengths) - 1; i >= 0; i-- {
+			if gain := 1 << (maxHuffmanBits - lengths[i]); lengths[i] > 1 && kraft+gain <= one {
+				kraft += gain
+				lengths[i]--
+				break
+			}
+		}
+	}
+
+	tableBits := 0
+	for i, sym := range syms {
+		h.nbits[sym] = uint8(lengths[i])
+		tableBits = max(tableBits, lengths[i])
+	}
+
+	// Assign codes the same way that readHuff builds its table:
+	// symbols with the longest codes come first, in increasing
+	// symbol order within each length.
+	next := 0
+	for n := tableBits; n > 0; n-- {
+		for sym := range counts {
+			if int(h.nbits[sym]) == n {
+				h.codes[sym] = uint16(next >> (tableBits - n))
+				next += 1 << (tableBits - n)
+			}
+		}
+	}
+
+	return tableBits, true
+}
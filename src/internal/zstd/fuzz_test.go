@@ -138,3 +138,32 @@ func FuzzReverse(f *testing.F) {
 		}
 	})
 }
+
+// Fuzz test to verify that we can decompress what we compress.
+func FuzzWriter(f *testing.F) {
+	for _, test := range tests {
+		f.Add([]byte(test.uncompressed), uint8(3))
+	}
+	f.Add(bytes.Repeat([]byte("abcdefghijklmnop"), 256), uint8(1))
+	f.Add(bytes.Repeat([]byte{0, 1, 2, 3, 4, 5, 250, 251}, 100), uint8(MaxLevel))
+
+	f.Fuzz(func(t *testing.T, b []byte, level uint8) {
+		var compressed bytes.Buffer
+		w := NewWriter(&compressed, int(level)%(MaxLevel+1), nil)
+		w.SetChecksum(true)
+		if _, err := w.Write(b); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := io.ReadAll(NewReader(&compressed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, b) {
+			showDiffs(t, got, b)
+		}
+	})
+}
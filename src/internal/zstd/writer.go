@@ -0,0 +1,545 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/bits"
+)
+
+// maxBlockSize is the largest amount of data in a single block.
+// RFC 3.1.1.2.4.
+const maxBlockSize = 128 << 10
+
+// minMatch is the shortest match that the encoder looks for.
+// The format permits matches of 3 bytes, but those rarely pay for
+// their encoding.
+const minMatch = 4
+
+// MaxLevel is the highest compression level supported by [NewWriter].
+const MaxLevel = 9
+
+// encoderParams are the parameters that the compression level selects.
+type encoderParams struct {
+	windowLog  uint8 // log2 of the window size, at least 18
+	hashLog    uint8 // log2 of the number of hash table entries
+	chainDepth int   // number of previous candidates to examine; 0 for none
+	lazy       bool  // whether to check for a better match at the next byte
+}
+
+// levelParams are the encoderParams for each level from 1 to MaxLevel.
+// Level 0 stores the data without compression.
+var levelParams = [MaxLevel + 1]encoderParams{
+	1: {windowLog: 19, hashLog: 15, chainDepth: 0},
+	2: {windowLog: 19, hashLog: 16, chainDepth: 2},
+	3: {windowLog: 20, hashLog: 16, chainDepth: 4},
+	4: {windowLog: 20, hashLog: 17, chainDepth: 8},
+	5: {windowLog: 20, hashLog: 17, chainDepth: 16, lazy: true},
+	6: {windowLog: 21, hashLog: 17, chainDepth: 32, lazy: true},
+	7: {windowLog: 21, hashLog: 18, chainDepth: 64, lazy: true},
+	8: {windowLog: 22, hashLog: 18, chainDepth: 128, lazy: true},
+	9: {windowLog: 22, hashLog: 18, chainDepth: 256, lazy: true},
+}
+
+// errWriterClosed is returned when writing to a closed Writer.
+var errWriterClosed = errors.New("zstd: write to closed Writer")
+
+// Writer implements [io.WriteCloser] to write a zstd compressed stream.
+// Each stream consists of a single frame.
+type Writer struct {
+	// The underlying Writer.
+	w io.Writer
+
+	// The compression level, from 0 to MaxLevel.
+	level  int
+	params encoderParams
+
+	// Whether to write a content checksum at the end of the frame.
+	checksum bool
+
+	// The dictionary, or nil.
+	dict *Dict
+
+	// Whether we have written the frame header.
+	wroteHeader bool
+
+	// Whether Close has been called.
+	closed bool
+
+	// The first error from the underlying Writer, if any.
+	err error
+
+	// The number of uncompressed bytes in the frame so far.
+	size uint64
+
+	// The history used to find matches, followed by pending data
+	// not yet compressed. The data at hist[cur:] is pending.
+	// The capacity of hist grows with the data written, up to
+	// twice the window size.
+	hist []byte
+	cur  int
+
+	// The hash table maps a hash of minMatch bytes to the most recent
+	// position in hist with that hash, or -1.
+	table []int32
+
+	// If chainDepth > 0, chain maps a position in hist to the previous
+	// position with the same hash, or -1. It is at least as long
+	// as the capacity of hist.
+	chain []int32
+
+	// Buffers reused from block to block.
+	seqs    []seq
+	lits    []byte
+	out     []byte
+	huffBuf huffScratch
+
+	// For checksum computation.
+	xxh xxhash64
+}
+
+// NewWriter returns a new Writer that compresses data written to w
+// at the given level, which must be between 0 and [MaxLevel].
+// Level 0 stores the data without compression.
+// If dict is not nil, the data is compressed using the dictionary;
+// it must be provided to the Reader to decompress the stream.
+func NewWriter(w io.Writer, level int, dict *Dict) *Writer {
+	if level < 0 || level > MaxLevel {
+		panic("zstd: invalid compression level")
+	}
+	zw := &Writer{
+		level:  level,
+		params: levelParams[level],
+		dict:   dict,
+	}
+	if level == 0 {
+		zw.params.windowLog = 18
+	}
+	zw.Reset(w)
+	return zw
+}
+
+// SetChecksum sets whether a checksum of the uncompressed data is written
+// at the end of the frame. It has no effect once the frame header
+// has been written, which happens when the first block is written.
+func (zw *Writer) SetChecksum(checksum bool) {
+	if !zw.wroteHeader {
+		zw.checksum = checksum
+	}
+}
+
+// Reset discards the Writer's state and makes it equivalent to the result
+// of NewWriter with the same level and dictionary, but writing to w.
+// The checksum setting is preserved.
+func (zw *Writer) Reset(w io.Writer) {
+	zw.w = w
+	zw.wroteHeader = false
+	zw.closed = false
+	zw.err = nil
+	zw.size = 0
+	zw.xxh.reset()
+
+	zw.hist = zw.hist[:0]
+	zw.cur = 0
+
+	if zw.level == 0 {
+		return
+	}
+
+	if zw.table == nil {
+		zw.table = make([]int32, 1<<zw.params.hashLog)
+	}
+	for i := range zw.table {
+		zw.table[i] = -1
+	}
+
+	// The dictionary content acts as history preceding the frame.
+	if zw.dict != nil && len(zw.dict.content) > 0 {
+		content := zw.dict.content
+		if windowSize := 1 << zw.params.windowLog; len(content) > windowSize {
+			content = content[len(content)-windowSize:]
+		}
+		zw.grow(len(content))
+		zw.hist = append(zw.hist, content...)
+		zw.cur = len(zw.hist)
+		for i := 0; i+minMatch <= zw.cur; i++ {
+			zw.insert(i)
+		}
+	}
+}
+
+// Write compresses p, implementing [io.Writer].
+func (zw *Writer) Write(p []byte) (int, error) {
+	if zw.err != nil {
+		return 0, zw.err
+	}
+	if zw.closed {
+		return 0, errWriterClosed
+	}
+	n := 0
+	for len(p) > 0 {
+		if len(zw.hist) == cap(zw.hist) {
+			if cap(zw.hist) < 2<<zw.params.windowLog {
+				zw.grow(1)
+			} else {
+				zw.slide()
+			}
+		}
+		room := min(cap(zw.hist)-len(zw.hist), maxBlockSize-(len(zw.hist)-zw.cur))
+		m := min(room, len(p))
+		zw.hist = append(zw.hist, p[:m]...)
+		zw.xxh.update(p[:m])
+		zw.size += uint64(m)
+		p = p[m:]
+		n += m
+		if len(zw.hist)-zw.cur == maxBlockSize {
+			if err := zw.writeBlock(false); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// Flush compresses any pending data and writes it to the underlying
+// writer. The data written so far can then be decompressed, although
+// the stream is not complete until Close is called.
+func (zw *Writer) Flush() error {
+	if zw.err != nil {
+		return zw.err
+	}
+	if zw.closed {
+		return nil
+	}
+	if zw.cur == len(zw.hist) && zw.wroteHeader {
+		return nil
+	}
+	return zw.writeBlock(false)
+}
+
+// Close compresses any pending data and writes the end of the frame,
+// including the checksum if requested. It does not close the
+// underlying writer.
+func (zw *Writer) Close() error {
+	if zw.err != nil {
+		return zw.err
+	}
+	if zw.closed {
+		return nil
+	}
+	zw.closed = true
+	if err := zw.writeBlock(true); err != nil {
+		return err
+	}
+	if zw.checksum {
+		var sum [4]byte
+		binary.LittleEndian.PutUint32(sum[:], uint32(zw.xxh.digest()))
+		return zw.write(sum[:])
+	}
+	return nil
+}
+
+// write writes b to the underlying writer, recording any error.
+func (zw *Writer) write(b []byte) error {
+	if zw.err != nil {
+		return zw.err
+	}
+	_, zw.err = zw.w.Write(b)
+	return zw.err
+}
+
+// appendFrameHeader appends the frame header to out.
+// If last is set, the frame is written in a single block,
+// so we know the content size. RFC 3.1.1.1.
+func (zw *Writer) appendFrameHeader(out []byte, last bool) []byte {
+	out = binary.LittleEndian.AppendUint32(out, 0xfd2fb528)
+
+	var descriptor byte
+	if zw.checksum {
+		descriptor |= 1 << 2
+	}
+	var dictID uint32
+	if zw.dict != nil {
+		dictID = zw.dict.id
+	}
+	if dictID != 0 {
+		descriptor |= 3
+	}
+
+	windowSize := uint64(1) << zw.params.windowLog
+	var fcs []byte
+	singleSegment := false
+	if last {
+		size := zw.size
+		singleSegment = size <= windowSize
+		switch {
+		case size < 256 && singleSegment:
+			fcs = []byte{byte(size)}
+		case size < 256+1<<16:
+			descriptor |= 1 << 6
+			fcs = binary.LittleEndian.AppendUint16(nil, uint16(size-256))
+		case size < 1<<32:
+			descriptor |= 2 << 6
+			fcs = binary.LittleEndian.AppendUint32(nil, uint32(size))
+		default:
+			descriptor |= 3 << 6
+			fcs = binary.LittleEndian.AppendUint64(nil, size)
+		}
+	}
+	if singleSegment {
+		descriptor |= 1 << 5
+	}
+
+	out = append(out, descriptor)
+	if !singleSegment {
+		// Window_Descriptor with a zero mantissa.
+		out = append(out, (zw.params.windowLog-10)<<3)
+	}
+	if dictID != 0 {
+		out = binary.LittleEndian.AppendUint32(out, dictID)
+	}
+	return append(out, fcs...)
+}
+
+// writeBlock compresses the pending data into a block and writes it,
+// preceded by the frame header if it has not been written yet.
+// If last is set this is the last block in the frame.
+func (zw *Writer) writeBlock(last bool) error {
+	out := zw.out[:0]
+	if !zw.wroteHeader {
+		out = zw.appendFrameHeader(out, last)
+		zw.wroteHeader = true
+	}
+
+	src := zw.hist[zw.cur:]
+	var lastBit uint32
+	if last {
+		lastBit = 1
+	}
+
+	hdrPos := len(out)
+	out = append(out, 0, 0, 0)
+	blockType, blockSize := uint32(0), len(src)
+	switch {
+	case len(src) == 0:
+		// An empty raw block.
+	case len(src) > 1 && allSame(src):
+		blockType = 1
+		out = append(out, src[0])
+	default:
+		if zw.level > 0 {
+			out = zw.compressBlock(out)
+			if n := len(out) - hdrPos - 3; n < len(src) {
+				blockType, blockSize = 2, n
+				break
+			}
+			out = out[:hdrPos+3]
+		}
+		out = append(out, src...)
+	}
+	hdr := lastBit | blockType<<1 | uint32(blockSize)<<3
+	out[hdrPos] = byte(hdr)
+	out[hdrPos+1] = byte(hdr >> 8)
+	out[hdrPos+2] = byte(hdr >> 16)
+	zw.out = out
+
+	zw.cur = len(zw.hist)
+	return zw.write(out)
+}
+
+// allSame reports whether all the bytes in b are the same.
+func allSame(b []byte) bool {
+	for _, c := range b[1:] {
+		if c != b[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// compressBlock finds matches in the pending data and appends the
+// compressed block contents to out. RFC 3.1.1.3.
+func (zw *Writer) compressBlock(out []byte) []byte {
+	hist := zw.hist
+	end := len(hist)
+	windowSize := 1 << zw.params.windowLog
+	seqs := zw.seqs[:0]
+	lits := zw.lits[:0]
+
+	lit := zw.cur // start of pending literals
+	s := zw.cur
+	for s+minMatch <= end {
+		offset, length := zw.findMatch(s, end, windowSize)
+		zw.insert(s)
+		if length > 0 && zw.params.lazy && s+1+minMatch <= end {
+			// If the next position has a longer match,
+			// emit this byte as a literal instead.
+			if offset2, length2 := zw.findMatch(s+1, end, windowSize); length2 > length+1 {
+				s++
+				offset, length = offset2, length2
+				zw.insert(s)
+			}
+		}
+		if length == 0 {
+			// Skip ahead faster when we aren't finding matches.
+			step := 1
+			if zw.params.chainDepth == 0 {
+				step += (s - lit) >> 6
+			}
+			s += step
+			continue
+		}
+		inserted := s
+
+		// Extend the match backward into the literals.
+		for s > lit && s-offset > 0 && hist[s-1] == hist[s-offset-1] {
+			s--
+			length++
+		}
+
+		lits = append(lits, hist[lit:s]...)
+		seqs = append(seqs, seq{
+			litLen:   uint32(s - lit),
+			offset:   uint32(offset),
+			matchLen: uint32(length),
+		})
+
+		// Insert the positions covered by the match. At the
+		// fastest level only insert a few to save time.
+		next := s + length
+		stride := 1
+		if zw.params.chainDepth == 0 {
+			stride = max(1, length/4)
+		}
+		for i := inserted + stride; i < next && i+minMatch <= end; i += stride {
+			zw.insert(i)
+		}
+		s = next
+		lit = s
+	}
+	lits = append(lits, hist[lit:end]...)
+
+	out = appendLiterals(out, lits, &zw.huffBuf)
+	out = appendSequences(out, seqs)
+	zw.seqs = seqs
+	zw.lits = lits
+	return out
+}
+
+// hash returns the hash table index for the minMatch bytes at hist[i].
+func (zw *Writer) hash(i int) uint32 {
+	v := binary.LittleEndian.Uint32(zw.hist[i:])
+	return (v * 2654435761) >> (32 - zw.params.hashLog)
+}
+
+// insert records position i in the hash table.
+func (zw *Writer) insert(i int) {
+	h := zw.hash(i)
+	if zw.chain != nil {
+		zw.chain[i] = zw.table[h]
+	}
+	zw.table[h] = int32(i)
+}
+
+// findMatch returns the offset and length of the longest match for
+// the data at hist[s:end] within windowSize bytes before s.
+// It returns a length of 0 if there is no usable match.
+func (zw *Writer) findMatch(s, end, windowSize int) (offset, length int) {
+	hist := zw.hist
+	cand := int(zw.table[zw.hash(s)])
+	for depth := 0; cand >= 0 && cand < s && s-cand <= windowSize; depth++ {
+		if hist[cand+length] == hist[s+length] {
+			n := matchLen(hist[cand:], hist[s:end])
+			if n > length {
+				offset, length = s-cand, n
+				if s+n == end {
+					break
+				}
+			}
+		}
+		if depth >= zw.params.chainDepth {
+			break
+		}
+		next := int(zw.chain[cand])
+		if next >= cand {
+			break
+		}
+		cand = next
+	}
+	if length < minMatch {
+		return 0, 0
+	}
+	return offset, length
+}
+
+// matchLen returns the length of the common prefix of a and b.
+// The length of b must be no more than the length of a.
+func matchLen(a, b []byte) int {
+	n := 0
+	for len(b)-n >= 8 {
+		if x := binary.LittleEndian.Uint64(a[n:]) ^ binary.LittleEndian.Uint64(b[n:]); x != 0 {
+			return n + bits.TrailingZeros64(x)/8
+		}
+		n += 8
+	}
+	for n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// grow increases the capacity of hist to hold at least n more bytes,
+// doubling it, but to no more than twice the window size, so that
+// short streams don't allocate buffers for the whole window.
+func (zw *Writer) grow(n int) {
+	c := max(cap(zw.hist), maxBlockSize)
+	for c < len(zw.hist)+n {
+		c *= 2
+	}
+	c = min(c, 2<<zw.params.windowLog)
+	if c > cap(zw.hist) {
+		hist := make([]byte, len(zw.hist), c)
+		copy(hist, zw.hist)
+		zw.hist = hist
+	}
+	if zw.level > 0 && zw.params.chainDepth > 0 && len(zw.chain) < c {
+		chain := make([]int32, c)
+		copy(chain, zw.chain)
+		zw.chain = chain
+	}
+}
+
+// slide discards history older than the window from the start of hist,
+// to make room for more data.
+func (zw *Writer) slide() {
+	windowSize := 1 << zw.params.windowLog
+	delta := zw.cur - windowSize
+	if delta <= 0 {
+		panic("zstd: internal error: no history to discard")
+	}
+	copy(zw.hist, zw.hist[delta:])
+	zw.hist = zw.hist[:len(zw.hist)-delta]
+	zw.cur -= delta
+
+	if zw.level == 0 {
+		return
+	}
+	shift := func(p []int32) {
+		for i, v := range p {
+			if int(v) >= delta {
+				p[i] = v - int32(delta)
+			} else {
+				p[i] = -1
+			}
+		}
+	}
+	shift(zw.table)
+	if zw.chain != nil {
+		copy(zw.chain, zw.chain[delta:])
+		shift(zw.chain[:len(zw.hist)])
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// writerInputs returns a set of inputs for compression tests.
+func writerInputs(t testing.TB) map[string][]byte {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	random := make([]byte, 300<<10)
+	for i := range random {
+		random[i] = byte(rnd.Uint32())
+	}
+	text := bigData(t)
+	return map[string][]byte{
+		"empty":  nil,
+		"byte":   {'a'},
+		"hello":  []byte("hello, world\n"),
+		"rle":    bytes.Repeat([]byte{'x'}, 200<<10),
+		"random": random,
+		"short":  text[:1000],
+		"block":  text[:maxBlockSize],
+		"text":   text[:3<<20],
+		"binary": bytes.Repeat(random[:5000], 100),
+	}
+}
+
+// compress compresses data at level using dict.
+func compress(t testing.TB, data []byte, level int, dict *Dict, checksum bool) []byte {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, level, dict)
+	w.SetChecksum(checksum)
+	// Write in uneven pieces to exercise buffering.
+	for len(data) > 0 {
+		n := min(len(data), 70000)
+		if _, err := w.Write(data[:n]); err != nil {
+			t.Fatal(err)
+		}
+		data = data[n:]
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestWriter(t *testing.T) {
+	for name, data := range writerInputs(t) {
+		for level := 0; level <= MaxLevel; level++ {
+			t.Run(fmt.Sprintf("%s/%d", name, level), func(t *testing.T) {
+				compressed := compress(t, data, level, nil, level%2 == 0)
+				got, err := io.ReadAll(NewReader(bytes.NewReader(compressed)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(got, data) {
+					showDiffs(t, got, data)
+				}
+				if level > 0 && len(data) > 1000 && name != "random" && len(compressed) > len(data)/2 {
+					t.Errorf("compressed %d bytes to %d", len(data), len(compressed))
+				}
+			})
+		}
+	}
+}
+
+func TestWriterFlush(t *testing.T) {
+	data := bigData(t)[:400<<10]
+	var buf bytes.Buffer
+	w := NewWriter(&buf, 3, nil)
+	r := NewReader(&buf)
+	for len(data) > 0 {
+		n := min(len(data), 10000)
+		if _, err := w.Write(data[:n]); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		got := make([]byte, n)
+		if _, err := io.ReadFull(r, got); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, data[:n]) {
+			showDiffs(t, got, data[:n])
+		}
+		data = data[n:]
+	}
+}
+
+func TestWriterReset(t *testing.T) {
+	data := bigData(t)[:200<<10]
+	var buf1, buf2 bytes.Buffer
+	w := NewWriter(&buf1, 5, nil)
+	w.Write(data)
+	w.Close()
+	w.Reset(&buf2)
+	w.Write(data)
+	w.Close()
+	if !bytes.Equal(buf1.Bytes(), buf2.Bytes()) {
+		t.Error("output after Reset differs")
+	}
+}
+
+func TestWriterBuffers(t *testing.T) {
+	// The buffers grow with the data written, up to twice the window size.
+	text := bigData(t)
+	w := NewWriter(io.Discard, MaxLevel, nil)
+	if cap(w.hist) != 0 || len(w.chain) != 0 {
+		t.Errorf("NewWriter allocated %d bytes of history and %d chain entries", cap(w.hist), len(w.chain))
+	}
+	w.Write(text[:1000])
+	w.Close()
+	if cap(w.hist) > maxBlockSize || len(w.chain) > maxBlockSize {
+		t.Errorf("after writing 1000 bytes: %d bytes of history and %d chain entries, want at most %d", cap(w.hist), len(w.chain), maxBlockSize)
+	}
+	w.Reset(io.Discard)
+	w.Write(text)
+	w.Close()
+	if limit := 2 << levelParams[MaxLevel].windowLog; cap(w.hist) > limit || len(w.chain) > limit {
+		t.Errorf("after writing %d bytes: %d bytes of history and %d chain entries, want at most %d", len(text), cap(w.hist), len(w.chain), limit)
+	}
+}
+
+func TestWriterDict(t *testing.T) {
+	text := bigData(t)
+	content := text[:20000]
+	dict, err := ParseDict(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sample := text[5000:8000]
+
+	withDict := compress(t, sample, 5, dict, true)
+	withoutDict := compress(t, sample, 5, nil, true)
+	if len(withDict) >= len(withoutDict)/4 {
+		t.Errorf("with dictionary compressed to %d bytes, without to %d", len(withDict), len(withoutDict))
+	}
+
+	r := NewReader(bytes.NewReader(withDict))
+	r.SetDict(dict)
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, sample) {
+		showDiffs(t, got, sample)
+	}
+}
+
+// TestWriterZstd checks that the zstd program can decompress our output.
+func TestWriterZstd(t *testing.T) {
+	zstd := findZstd(t)
+	for name, data := range writerInputs(t) {
+		for _, level := range []int{0, 1, 5, MaxLevel} {
+			t.Run(fmt.Sprintf("%s/%d", name, level), func(t *testing.T) {
+				compressed := compress(t, data, level, nil, true)
+				cmd := exec.Command(zstd, "-d", "-c")
+				cmd.Stdin = bytes.NewReader(compressed)
+				var out bytes.Buffer
+				cmd.Stdout = &out
+				cmd.Stderr = os.Stderr
+				if err := cmd.Run(); err != nil {
+					t.Fatalf("zstd failed: %v", err)
+				}
+				if !bytes.Equal(out.Bytes(), data) {
+					showDiffs(t, out.Bytes(), data)
+				}
+			})
+		}
+	}
+}
+
+// TestDictZstd checks formatted dictionaries trained by the zstd program.
+func TestDictZstd(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping dictionary training in short mode")
+	}
+	zstd := findZstd(t)
+
+	// Train a dictionary on lines of text.
+	dir := t.TempDir()
+	lines := bytes.SplitAfter(bigData(t)[:1<<20], []byte("\n"))
+	var samples []string
+	for i := 0; i+20 <= len(lines) && len(samples) < 500; i += 20 {
+		name := filepath.Join(dir, fmt.Sprintf("sample%d", len(samples)))
+		if err := os.WriteFile(name, bytes.Join(lines[i:i+20], nil), 0o666); err != nil {
+			t.Fatal(err)
+		}
+		samples = append(samples, name)
+	}
+	dictFile := filepath.Join(dir, "dict")
+	args := append([]string{"--train", "-q", "--maxdict=16384", "-o", dictFile}, samples...)
+	if out, err := exec.Command(zstd, args...).CombinedOutput(); err != nil {
+		t.Fatalf("zstd --train failed: %v\n%s", err, out)
+	}
+	dictData, err := os.ReadFile(dictFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dict, err := ParseDict(dictData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dict.ID() == 0 {
+		t.Fatal("trained dictionary has ID 0")
+	}
+
+	for _, sample := range samples[:20] {
+		data, err := os.ReadFile(sample)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Decompress zstd output.
+		compressed, err := exec.Command(zstd, "-q", "-c", "-D", dictFile, sample).Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := NewReader(bytes.NewReader(compressed))
+		r.SetDict(dict)
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, data) {
+			showDiffs(t, got, data)
+		}
+
+		// Compress for zstd.
+		cmd := exec.Command(zstd, "-q", "-d", "-c", "-D", dictFile)
+		cmd.Stdin = bytes.NewReader(compress(t, data, 5, dict, true))
+		got, err = cmd.Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, data) {
+			showDiffs(t, got, data)
+		}
+	}
+
+	// A frame compressed with the dictionary can't be read without it.
+	compressed := compress(t, []byte("some data"), 5, dict, false)
+	if _, err := io.ReadAll(NewReader(bytes.NewReader(compressed))); err == nil {
+		t.Error("reading without dictionary succeeded")
+	}
+}
+
+func BenchmarkWriter(b *testing.B) {
+	data := bigData(b)
+	for _, level := range []int{1, 3, MaxLevel} {
+		b.Run(fmt.Sprint(level), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			w := NewWriter(io.Discard, level, nil)
+			for b.Loop() {
+				w.Reset(io.Discard)
+				w.Write(data)
+				w.Close()
+			}
+		})
+	}
+}
@@ -2,8 +2,8 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package zstd provides a decompressor for zstd streams,
-// described in RFC 8878. It does not support dictionaries.
+// Package zstd provides a decompressor and a compressor for zstd streams,
+// described in RFC 8878.
 package zstd
 
 import (
@@ -82,6 +82,9 @@ type Reader struct {
 
 	// For checksum computation.
 	checksum xxhash64
+
+	// The dictionary to use for frames that request one, or nil.
+	dict *Dict
 }
 
 // NewReader creates a new Reader that decompresses data from the given reader.
@@ -119,6 +122,15 @@ func (r *Reader) Reset(input io.Reader) {
 	// seqTableBuffers
 	// scratch
 	// fseScratch
+	// dict
+}
+
+// SetDict sets the dictionary used to decompress frames.
+// It is used for frames that name its ID, and for frames that name no
+// dictionary at all. A nil dict means that no dictionary is available.
+// The dictionary is preserved by Reset.
+func (r *Reader) SetDict(dict *Dict) {
+	r.dict = dict
 }
 
 // Read implements [io.Reader].
@@ -255,13 +267,19 @@ retry:
 	}
 
 	// Dictionary_ID. RFC 3.1.1.1.3.
+	var dictionaryId uint32
 	if dictionaryIdSize != 0 {
-		dictionaryId := r.scratch[windowDescriptorSize : windowDescriptorSize+dictionaryIdSize]
-		// Allow only zero Dictionary ID.
-		for _, b := range dictionaryId {
-			if b != 0 {
-				return r.makeError(relativeOffset, "dictionaries are not supported")
-			}
+		for i, b := range r.scratch[windowDescriptorSize : windowDescriptorSize+dictionaryIdSize] {
+			dictionaryId |= uint32(b) << (8 * i)
+		}
+	}
+	dict := r.dict
+	if dictionaryId != 0 {
+		if dict == nil {
+			return r.makeError(relativeOffset, "dictionaries are not supported")
+		}
+		if dictionaryId != dict.id {
+			return r.wrapError(relativeOffset, fmt.Errorf("frame requires dictionary %#x, have %#x", dictionaryId, dict.id))
 		}
 	}
 
@@ -304,14 +322,43 @@ retry:
 	r.blockOffset += int64(relativeOffset)
 
 	// Prepare to read blocks from the frame.
-	r.repeatedOffset1 = 1
-	r.repeatedOffset2 = 4
-	r.repeatedOffset3 = 8
-	r.huffmanTableBits = 0
-	r.window.reset(int(windowSize))
-	r.seqTables[0] = nil
-	r.seqTables[1] = nil
-	r.seqTables[2] = nil
+	if dict == nil {
+		r.repeatedOffset1 = 1
+		r.repeatedOffset2 = 4
+		r.repeatedOffset3 = 8
+		r.huffmanTableBits = 0
+		r.window.reset(int(windowSize))
+		r.seqTables[0] = nil
+		r.seqTables[1] = nil
+		r.seqTables[2] = nil
+		return nil
+	}
+
+	// The dictionary provides the initial state. RFC 5.
+	// Its content precedes the frame data, so the window is
+	// extended to hold it.
+	r.repeatedOffset1 = dict.repeatedOffsets[0]
+	r.repeatedOffset2 = dict.repeatedOffsets[1]
+	r.repeatedOffset3 = dict.repeatedOffsets[2]
+	r.window.reset(int(windowSize) + len(dict.content))
+	r.window.save(dict.content)
+	if dict.hasTables {
+		// readHuffLiterals overwrites r.huffmanTable in place,
+		// so copy the dictionary table. The sequence tables are
+		// never modified, so they can be shared.
+		if len(r.huffmanTable) < 1<<maxHuffmanBits {
+			r.huffmanTable = make([]uint16, 1<<maxHuffmanBits)
+		}
+		copy(r.huffmanTable, dict.huffmanTable)
+		r.huffmanTableBits = dict.huffmanTableBits
+		r.seqTables = dict.seqTables
+		r.seqTableBits = dict.seqTableBits
+	} else {
+		r.huffmanTableBits = 0
+		r.seqTables[0] = nil
+		r.seqTables[1] = nil
+		r.seqTables[2] = nil
+	}
 
 	return nil
 }
@@ -0,0 +1,136 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fs
+
+import (
+	"io"
+	"path"
+)
+
+// CreateFS is the interface implemented by a file system
+// that supports creating files.
+type CreateFS interface {
+	FS
+
+	// Create creates or truncates the named file.
+	// If the file already exists, it is truncated.
+	// If the file does not exist, it is created with mode 0o666
+	// (before umask or the file system's equivalent).
+	// The returned File must implement [io.Writer].
+	// If there is an error, it should be of type [*PathError].
+	Create(name string) (File, error)
+}
+
+// MkdirFS is the interface implemented by a file system
+// that supports creating directories.
+type MkdirFS interface {
+	FS
+
+	// Mkdir creates a new directory with the specified name and
+	// permission bits (before umask or the file system's equivalent).
+	// If the directory already exists, Mkdir returns an error
+	// for which errors.Is(err, ErrExist) is true.
+	// If there is an error, it should be of type [*PathError].
+	Mkdir(name string, perm FileMode) error
+}
+
+// RemoveFS is the interface implemented by a file system
+// that supports removing files.
+type RemoveFS interface {
+	FS
+
+	// Remove removes the named file or empty directory.
+	// If the named file is a symbolic link, Remove removes the link.
+	// If there is an error, it should be of type [*PathError].
+	Remove(name string) error
+}
+
+// RenameFS is the interface implemented by a file system
+// that supports renaming files.
+type RenameFS interface {
+	FS
+
+	// Rename renames (moves) oldname to newname.
+	// If newname already exists and is not a directory,
+	// Rename replaces it.
+	Rename(oldname, newname string) error
+}
+
+// WriteFileFS is the interface implemented by a file system
+// that provides an optimized implementation of [WriteFile].
+type WriteFileFS interface {
+	FS
+
+	// WriteFile writes data to the named file, creating it if necessary.
+	// If the file does not exist, WriteFile creates it with permissions
+	// perm (before umask or the file system's equivalent); otherwise
+	// WriteFile truncates it before writing, without changing permissions.
+	WriteFile(name string, data []byte, perm FileMode) error
+}
+
+// WriteFile writes data to the named file in the file system fsys,
+// creating it if necessary.
+//
+// If fsys implements [WriteFileFS], WriteFile calls fsys.WriteFile.
+// Otherwise, if fsys implements [CreateFS], WriteFile calls fsys.Create
+// and uses Write and Close on the returned [File]; the file is created
+// with the mode used by Create, and perm is not used.
+// Otherwise WriteFile returns an error.
+func WriteFile(fsys FS, name string, data []byte, perm FileMode) error {
+	if fsys, ok := fsys.(WriteFileFS); ok {
+		return fsys.WriteFile(name, data, perm)
+	}
+	cfs, ok := fsys.(CreateFS)
+	if !ok {
+		return &PathError{Op: "writefile", Path: name, Err: ErrInvalid}
+	}
+	f, err := cfs.Create(name)
+	if err != nil {
+		return err
+	}
+	w, ok := f.(io.Writer)
+	if !ok {
+		f.Close()
+		return &PathError{Op: "writefile", Path: name, Err: ErrInvalid}
+	}
+	_, err = w.Write(data)
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	return err
+}
+
+// MkdirAll creates a directory named name in the file system fsys,
+// along with any necessary parents. The permission bits perm
+// (before umask or the file system's equivalent) are used for all
+// directories that MkdirAll creates. If name is already a directory,
+// MkdirAll does nothing and returns nil.
+//
+// If fsys does not implement [MkdirFS], then MkdirAll returns an error.
+func MkdirAll(fsys FS, name string, perm FileMode) error {
+	mfs, ok := fsys.(MkdirFS)
+	if !ok || !ValidPath(name) {
+		return &PathError{Op: "mkdir", Path: name, Err: ErrInvalid}
+	}
+	if info, err := Stat(fsys, name); err == nil {
+		if info.IsDir() {
+			return nil
+		}
+		return &PathError{Op: "mkdir", Path: name, Err: ErrExist}
+	}
+	if dir := path.Dir(name); dir != "." {
+		if err := MkdirAll(fsys, dir, perm); err != nil {
+			return err
+		}
+	}
+	if err := mfs.Mkdir(name, perm); err != nil {
+		// The directory may have been created concurrently.
+		if info, err1 := Stat(fsys, name); err1 == nil && info.IsDir() {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
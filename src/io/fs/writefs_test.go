@@ -0,0 +1,81 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fs_test
+
+import (
+	"errors"
+	. "io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+// createOnlyFS hides all methods of a MemFS except Open, Create, and Mkdir.
+type createOnlyFS struct {
+	m *fstest.MemFS
+}
+
+func (c createOnlyFS) Open(name string) (File, error)         { return c.m.Open(name) }
+func (c createOnlyFS) Create(name string) (File, error)       { return c.m.Create(name) }
+func (c createOnlyFS) Mkdir(name string, perm FileMode) error { return c.m.Mkdir(name, perm) }
+
+func TestWriteFile(t *testing.T) {
+	m := new(fstest.MemFS)
+	for _, fsys := range []FS{m, createOnlyFS{m}} {
+		if err := WriteFile(fsys, "a", []byte("hello, world\n"), 0o600); err != nil {
+			t.Fatalf("WriteFile(%T): %v", fsys, err)
+		}
+		if data, err := ReadFile(fsys, "a"); err != nil || string(data) != "hello, world\n" {
+			t.Errorf("ReadFile(%T) = %q, %v; want %q, nil", fsys, data, err, "hello, world\n")
+		}
+		if err := WriteFile(fsys, "a", []byte("hi\n"), 0o600); err != nil {
+			t.Fatalf("WriteFile(%T): %v", fsys, err)
+		}
+		if data, err := ReadFile(fsys, "a"); err != nil || string(data) != "hi\n" {
+			t.Errorf("ReadFile(%T) after rewrite = %q, %v; want %q, nil", fsys, data, err, "hi\n")
+		}
+		if err := WriteFile(fsys, "missing/a", nil, 0o600); !errors.Is(err, ErrNotExist) {
+			t.Errorf("WriteFile(%T) in missing directory = %v, want ErrNotExist", fsys, err)
+		}
+	}
+	if info, err := m.Stat("a"); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("Stat(a) = %v, %v; want mode 0600", info, err)
+	}
+
+	var pe *PathError
+	if err := WriteFile(fstest.MapFS{}, "a", nil, 0o666); !errors.As(err, &pe) || !errors.Is(err, ErrInvalid) {
+		t.Errorf("WriteFile(MapFS) = %v, want PathError with ErrInvalid", err)
+	}
+}
+
+func TestMkdirAll(t *testing.T) {
+	m := new(fstest.MemFS)
+	for _, fsys := range []FS{m, createOnlyFS{m}} {
+		if err := MkdirAll(fsys, "a/b/c", 0o755); err != nil {
+			t.Fatalf("MkdirAll(%T): %v", fsys, err)
+		}
+		if info, err := Stat(fsys, "a/b/c"); err != nil || !info.IsDir() {
+			t.Errorf("Stat(%T) after MkdirAll = %v, %v; want directory", fsys, info, err)
+		}
+		if err := MkdirAll(fsys, "a/b", 0o755); err != nil {
+			t.Errorf("MkdirAll(%T) of existing directory = %v", fsys, err)
+		}
+	}
+	if err := WriteFile(m, "f", nil, 0o666); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"f", "f/d"} {
+		if err := MkdirAll(m, name, 0o755); err == nil {
+			t.Errorf("MkdirAll(%q) through file succeeded, want error", name)
+		}
+	}
+	for _, name := range []string{"/a", "../a", ""} {
+		if err := MkdirAll(m, name, 0o755); !errors.Is(err, ErrInvalid) {
+			t.Errorf("MkdirAll(%q) = %v, want ErrInvalid", name, err)
+		}
+	}
+	if err := MkdirAll(fstest.MapFS{}, "a", 0o755); !errors.Is(err, ErrInvalid) {
+		t.Errorf("MkdirAll(MapFS) = %v, want ErrInvalid", err)
+	}
+}
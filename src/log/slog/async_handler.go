@@ -0,0 +1,194 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// AsyncOptions are options for an [AsyncHandler].
+// A zero AsyncOptions consists entirely of default values.
+type AsyncOptions struct {
+	// QueueSize is the maximum number of records waiting to be handled.
+	// If QueueSize is zero, a default of 1024 is used.
+	QueueSize int
+
+	// Block causes Handle to wait for room in the queue when it is full,
+	// until the context passed to Handle is done or the handler is closed.
+	// By default, a record which does not fit in the queue is dropped,
+	// and counted by [AsyncHandler.Dropped].
+	Block bool
+
+	// OnError is called with any error returned by the wrapped
+	// handler's Handle method. If OnError is nil, errors are ignored.
+	// OnError is called from the handler's background goroutine.
+	OnError func(error)
+}
+
+// ErrAsyncHandlerClosed is returned by [AsyncHandler.Handle]
+// after the handler has been closed.
+var ErrAsyncHandlerClosed = errors.New("slog: AsyncHandler is closed")
+
+// An AsyncHandler is a [Handler] that passes records to another Handler
+// on a background goroutine, so that logging does not wait for output.
+//
+// Records are held in a bounded queue until they are handled. When the
+// queue is full, new records are dropped, unless [AsyncOptions.Block]
+// is set. Because records are handled after the call to Handle returns,
+// errors from the wrapped handler are reported to [AsyncOptions.OnError]
+// rather than to the caller.
+//
+// [AsyncHandler.Close] must be called to handle the records still
+// in the queue when the program is finished logging.
+type AsyncHandler struct {
+	h Handler
+	q *asyncQueue
+}
+
+// asyncQueue is shared by an AsyncHandler and the handlers
+// derived from it by WithAttrs and WithGroup.
+type asyncQueue struct {
+	opts     AsyncOptions
+	mu       sync.RWMutex // held for reading while sending to c
+	closed   bool
+	c        chan asyncRecord
+	stop     chan struct{} // closed by Close, to wake blocked senders
+	stopOnce sync.Once
+	done     chan struct{} // closed when the queue has been drained
+	dropped  atomic.Uint64
+}
+
+type asyncRecord struct {
+	h   Handler
+	ctx context.Context
+	r   Record
+}
+
+// NewAsyncHandler creates an [AsyncHandler] that handles records with h,
+// using the given options. If opts is nil, the default options are used.
+//
+// NewAsyncHandler starts a goroutine which runs until the
+// AsyncHandler is closed.
+func NewAsyncHandler(h Handler, opts *AsyncOptions) *AsyncHandler {
+	q := &asyncQueue{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	if opts != nil {
+		q.opts = *opts
+	}
+	size := q.opts.QueueSize
+	if size <= 0 {
+		size = 1024
+	}
+	q.c = make(chan asyncRecord, size)
+	go q.run()
+	return &AsyncHandler{h: h, q: q}
+}
+
+func (q *asyncQueue) run() {
+	defer close(q.done)
+	for ar := range q.c {
+		if err := ar.h.Handle(ar.ctx, ar.r); err != nil && q.opts.OnError != nil {
+			q.opts.OnError(err)
+		}
+	}
+}
+
+// Enabled reports whether the wrapped handler is enabled at the given level.
+func (h *AsyncHandler) Enabled(ctx context.Context, l Level) bool {
+	return h.h.Enabled(ctx, l)
+}
+
+// Handle queues a copy of r to be handled by the wrapped handler.
+// Values in ctx remain available to the wrapped handler, but its
+// cancellation is not propagated.
+//
+// Handle returns [ErrAsyncHandlerClosed] if the handler has been closed.
+// It does not report whether the record was dropped because the queue
+// was full. If [AsyncOptions.Block] is set, Handle waits for room in the
+// queue: if the handler is closed while it waits, Handle returns
+// ErrAsyncHandlerClosed, and if ctx is done first, Handle drops the
+// record and returns ctx.Err().
+func (h *AsyncHandler) Handle(ctx context.Context, r Record) error {
+	ar := asyncRecord{h: h.h, ctx: context.WithoutCancel(ctx), r: r.Clone()}
+	q := h.q
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrAsyncHandlerClosed
+	}
+	select {
+	case q.c <- ar:
+		return nil
+	default:
+	}
+	if !q.opts.Block {
+		q.dropped.Add(1)
+		return nil
+	}
+	// Close waits for the read lock to be released before closing q.c,
+	// so it first closes q.stop to wake Handle.
+	select {
+	case q.c <- ar:
+		return nil
+	case <-q.stop:
+		return ErrAsyncHandlerClosed
+	case <-ctx.Done():
+		q.dropped.Add(1)
+		return ctx.Err()
+	}
+}
+
+// WithAttrs returns an AsyncHandler which wraps h.WithAttrs(attrs)
+// and shares the queue of h.
+func (h *AsyncHandler) WithAttrs(attrs []Attr) Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &AsyncHandler{h: h.h.WithAttrs(attrs), q: h.q}
+}
+
+// WithGroup returns an AsyncHandler which wraps h.WithGroup(name)
+// and shares the queue of h.
+func (h *AsyncHandler) WithGroup(name string) Handler {
+	if name == "" {
+		return h
+	}
+	return &AsyncHandler{h: h.h.WithGroup(name), q: h.q}
+}
+
+// Dropped returns the number of records dropped because the queue was full.
+// It includes records dropped by handlers derived from h by WithAttrs
+// and WithGroup.
+func (h *AsyncHandler) Dropped() uint64 {
+	return h.q.dropped.Load()
+}
+
+// Close stops the handler from accepting records, and waits until
+// the records already in the queue have been handled. Calls to Handle
+// waiting for room in the queue return [ErrAsyncHandlerClosed].
+// Closing h also closes the handlers derived from it by WithAttrs
+// and WithGroup, and vice versa.
+//
+// Close does not close the wrapped handler's output.
+// It returns [ErrAsyncHandlerClosed] if the handler was already closed.
+func (h *AsyncHandler) Close() error {
+	q := h.q
+	q.stopOnce.Do(func() { close(q.stop) })
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return ErrAsyncHandlerClosed
+	}
+	q.closed = true
+	close(q.c)
+	q.mu.Unlock()
+	<-q.done
+	return nil
+}
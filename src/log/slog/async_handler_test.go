@@ -0,0 +1,166 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// blockingHandler is a handler whose Handle method waits
+// until its release channel is closed.
+type blockingHandler struct {
+	Handler
+	release chan struct{}
+}
+
+func (h *blockingHandler) Handle(ctx context.Context, r Record) error {
+	<-h.release
+	return h.Handler.Handle(ctx, r)
+}
+
+func TestAsyncHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewAsyncHandler(NewTextHandler(&buf, &HandlerOptions{ReplaceAttr: removeKeys(TimeKey)}), nil)
+	logger := New(h)
+	logger.Info("one", "a", 1)
+	logger.With("b", 2).WithGroup("g").Warn("two", "c", 3)
+	logger.Debug("disabled")
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := "level=INFO msg=one a=1\nlevel=WARN msg=two b=2 g.c=3\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if err := h.Handle(context.Background(), NewRecord(testTime, LevelInfo, "late", 0)); err != ErrAsyncHandlerClosed {
+		t.Errorf("Handle after Close = %v, want ErrAsyncHandlerClosed", err)
+	}
+	if err := h.Close(); err != ErrAsyncHandlerClosed {
+		t.Errorf("second Close = %v, want ErrAsyncHandlerClosed", err)
+	}
+}
+
+func TestAsyncHandlerDrop(t *testing.T) {
+	var buf bytes.Buffer
+	bh := &blockingHandler{
+		Handler: NewTextHandler(&buf, &HandlerOptions{ReplaceAttr: removeKeys(TimeKey, LevelKey)}),
+		release: make(chan struct{}),
+	}
+	h := NewAsyncHandler(bh, &AsyncOptions{QueueSize: 2})
+	logger := New(h)
+	// The first record is taken from the queue by the handler's
+	// goroutine, which may or may not have happened by the time
+	// the later records are queued; so the queue holds the next
+	// one or two, and the rest are dropped.
+	for i := range 10 {
+		logger.Info("m", "i", i)
+	}
+	close(bh.release)
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Count(buf.String(), "\n")
+	if lines < 2 || lines > 3 {
+		t.Errorf("handled %d records, want 2 or 3:\n%s", lines, buf.String())
+	}
+	if got := h.Dropped(); got != uint64(10-lines) {
+		t.Errorf("Dropped() = %d, want %d", got, 10-lines)
+	}
+}
+
+func TestAsyncHandlerBlock(t *testing.T) {
+	var buf bytes.Buffer
+	bh := &blockingHandler{
+		Handler: NewTextHandler(&buf, &HandlerOptions{ReplaceAttr: removeKeys(TimeKey, LevelKey)}),
+		release: make(chan struct{}),
+	}
+	h := NewAsyncHandler(bh, &AsyncOptions{QueueSize: 1, Block: true})
+	logger := New(h)
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for i := range 5 {
+			logger.Info("m", "i", i)
+		}
+	})
+	close(bh.release)
+	wg.Wait()
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := "msg=m i=0\nmsg=m i=1\nmsg=m i=2\nmsg=m i=3\nmsg=m i=4\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if got := h.Dropped(); got != 0 {
+		t.Errorf("Dropped() = %d, want 0", got)
+	}
+}
+
+func TestAsyncHandlerBlockCancel(t *testing.T) {
+	var buf bytes.Buffer
+	bh := &blockingHandler{
+		Handler: NewTextHandler(&buf, &HandlerOptions{ReplaceAttr: removeKeys(TimeKey, LevelKey)}),
+		release: make(chan struct{}),
+	}
+	h := NewAsyncHandler(bh, &AsyncOptions{QueueSize: 1, Block: true})
+	// Fill the queue: one record is being handled, and one is queued.
+	h.Handle(context.Background(), NewRecord(testTime, LevelInfo, "m", 0))
+	for len(h.q.c) != 0 {
+		runtime.Gosched()
+	}
+	h.Handle(context.Background(), NewRecord(testTime, LevelInfo, "m", 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := h.Handle(ctx, NewRecord(testTime, LevelInfo, "canceled", 0)); err != context.Canceled {
+		t.Errorf("Handle with canceled context = %v, want context.Canceled", err)
+	}
+	if got := h.Dropped(); got != 1 {
+		t.Errorf("Dropped() = %d, want 1", got)
+	}
+
+	// Close wakes a blocked Handle before waiting for the queue to drain.
+	errc := make(chan error)
+	go func() {
+		errc <- h.Handle(context.Background(), NewRecord(testTime, LevelInfo, "closed", 0))
+	}()
+	closec := make(chan error)
+	go func() {
+		closec <- h.Close()
+	}()
+	if err := <-errc; err != ErrAsyncHandlerClosed {
+		t.Errorf("blocked Handle during Close = %v, want ErrAsyncHandlerClosed", err)
+	}
+	close(bh.release)
+	if err := <-closec; err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "msg=m\nmsg=m\n"; got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestAsyncHandlerError(t *testing.T) {
+	errFail := errors.New("fail")
+	var got []error
+	h := NewAsyncHandler(&mockFailingHandler{Handler: DiscardHandler, err: errFail}, &AsyncOptions{
+		OnError: func(err error) { got = append(got, err) },
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := h.Handle(ctx, NewRecord(testTime, LevelInfo, "m", 0)); err != nil {
+		t.Fatalf("Handle = %v", err)
+	}
+	h.Close()
+	if len(got) != 1 || got[0] != errFail {
+		t.Errorf("OnError called with %v, want [%v]", got, errFail)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+func ExampleAsyncHandler() {
+	removeTime := func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey && len(groups) == 0 {
+			return slog.Attr{}
+		}
+		return a
+	}
+
+	dir, err := os.MkdirTemp("", "example")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Write JSON to a file which is rotated when it reaches 10 MB,
+	// keeping 5 old files, without waiting for the file writes.
+	file, err := slog.OpenRotatingFile(filepath.Join(dir, "app.log"), &slog.RotateOptions{
+		MaxSize:    10 << 20,
+		MaxBackups: 5,
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+	fileHandler := slog.NewAsyncHandler(slog.NewJSONHandler(file, &slog.HandlerOptions{ReplaceAttr: removeTime}), nil)
+
+	// Also write text to standard output.
+	textHandler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{ReplaceAttr: removeTime})
+
+	logger := slog.New(slog.NewMultiHandler(textHandler, fileHandler))
+	logger.Info("login", "name", "whoami", "id", 42)
+
+	// Wait for queued records to be written before closing the file.
+	fileHandler.Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	if err != nil {
+		panic(err)
+	}
+	os.Stdout.Write(data)
+
+	// Output:
+	// level=INFO msg=login name=whoami id=42
+	// {"level":"INFO","msg":"login","name":"whoami","id":42}
+}
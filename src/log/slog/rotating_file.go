@@ -0,0 +1,197 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RotateOptions are options for a [RotatingFile].
+// A zero RotateOptions never rotates the file.
+type RotateOptions struct {
+	// MaxSize is the size in bytes beyond which the file is rotated.
+	// A write which would make the file larger than MaxSize is
+	// made to a new file; a single write larger than MaxSize is
+	// still written whole. If MaxSize is zero, the file is not
+	// rotated because of its size.
+	MaxSize int64
+
+	// MaxAge is how long a file is written to before it is rotated.
+	// The age of an existing file is measured from its modification
+	// time when it is opened. If MaxAge is zero, the file is not
+	// rotated because of its age.
+	MaxAge time.Duration
+
+	// MaxBackups is the number of rotated files to keep.
+	// If MaxBackups is zero, all rotated files are kept.
+	MaxBackups int
+
+	// Perm is the permission bits used to create the file.
+	// If Perm is zero, 0644 is used.
+	Perm fs.FileMode
+}
+
+// A RotatingFile is an [io.Writer] that appends to a file, and
+// replaces the file with a new, empty one when it grows too large or
+// too old, as described by its [RotateOptions]. It may be used as the
+// output of a [TextHandler] or [JSONHandler], and is safe for
+// concurrent use.
+//
+// When the file named name is rotated, it is renamed to name.1,
+// after renaming name.1 to name.2, and so on. Rotated files beyond
+// [RotateOptions.MaxBackups] are removed.
+//
+// Each log line should be written with a single call to Write,
+// as the built-in handlers do, so that no line is split between files.
+type RotatingFile struct {
+	name string
+	opts RotateOptions
+
+	mu     sync.Mutex
+	f      *os.File
+	size   int64
+	opened time.Time // when the current file was created
+}
+
+// OpenRotatingFile opens the named file for appending, creating it if
+// it does not exist, and returns a [RotatingFile] which writes to it.
+// If opts is nil, the file is never rotated.
+func OpenRotatingFile(name string, opts *RotateOptions) (*RotatingFile, error) {
+	rf := &RotatingFile{name: name}
+	if opts != nil {
+		rf.opts = *opts
+	}
+	if rf.opts.Perm == 0 {
+		rf.opts.Perm = 0o644
+	}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// open opens the file for appending.
+func (rf *RotatingFile) open() error {
+	f, err := os.OpenFile(rf.name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, rf.opts.Perm)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	rf.f = f
+	rf.size = info.Size()
+	rf.opened = time.Now()
+	if rf.size > 0 {
+		rf.opened = info.ModTime()
+	}
+	return nil
+}
+
+// Write writes b to the file, first rotating it if necessary.
+func (rf *RotatingFile) Write(b []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if rf.f == nil {
+		return 0, os.ErrClosed
+	}
+	if rf.size > 0 && rf.needRotate(int64(len(b))) {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := rf.f.Write(b)
+	rf.size += int64(n)
+	return n, err
+}
+
+// needRotate reports whether the file must be rotated
+// before writing n bytes to it.
+func (rf *RotatingFile) needRotate(n int64) bool {
+	if rf.opts.MaxSize > 0 && rf.size+n > rf.opts.MaxSize {
+		return true
+	}
+	return rf.opts.MaxAge > 0 && time.Since(rf.opened) >= rf.opts.MaxAge
+}
+
+// Rotate closes the current file, renames it as described for
+// [RotatingFile], and opens a new, empty file.
+func (rf *RotatingFile) Rotate() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if rf.f == nil {
+		return os.ErrClosed
+	}
+	return rf.rotate()
+}
+
+func (rf *RotatingFile) rotate() error {
+	if err := rf.f.Close(); err != nil {
+		rf.f = nil
+		return err
+	}
+	rf.f = nil
+
+	// Find the oldest backup, removing those beyond MaxBackups,
+	// and shift the rest up by one.
+	n := 1
+	for ; ; n++ {
+		if _, err := os.Lstat(rf.backupName(n)); errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+	}
+	var errs []error
+	for i := n - 1; i >= 1; i-- {
+		if rf.opts.MaxBackups > 0 && i >= rf.opts.MaxBackups {
+			if err := os.Remove(rf.backupName(i)); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if err := os.Rename(rf.backupName(i), rf.backupName(i+1)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := os.Rename(rf.name, rf.backupName(1)); err != nil {
+		errs = append(errs, err)
+	}
+	if err := rf.open(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (rf *RotatingFile) backupName(n int) string {
+	return rf.name + "." + strconv.Itoa(n)
+}
+
+// Sync commits the current contents of the file to stable storage.
+func (rf *RotatingFile) Sync() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if rf.f == nil {
+		return os.ErrClosed
+	}
+	return rf.f.Sync()
+}
+
+// Close closes the file. Subsequent writes return an error.
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if rf.f == nil {
+		return os.ErrClosed
+	}
+	err := rf.f.Close()
+	rf.f = nil
+	return err
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// readFiles returns the contents of the files in dir, by name.
+func readFiles(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, e := range entries {
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[e.Name()] = string(b)
+	}
+	return files
+}
+
+func TestRotatingFileSize(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "log")
+	rf, err := OpenRotatingFile(name, &RotateOptions{MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"aaaa\n", "bbbb\n", "cccc\n", "dddddddddddd\n", "eeee\n", "ffff\n"} {
+		if _, err := rf.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rf.Write([]byte("x")); err == nil {
+		t.Errorf("Write after Close succeeded")
+	}
+	got := readFiles(t, dir)
+	want := map[string]string{
+		"log":   "eeee\nffff\n",
+		"log.1": "dddddddddddd\n",
+		"log.2": "cccc\n",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("files = %q, want %q", got, want)
+	}
+}
+
+func TestRotatingFileAppend(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "log")
+	if err := os.WriteFile(name, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rf, err := OpenRotatingFile(name, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rf.Write([]byte("new\n"))
+	if err := rf.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	rf.Write([]byte("newer\n"))
+	rf.Close()
+	got := readFiles(t, dir)
+	want := map[string]string{
+		"log":   "newer\n",
+		"log.1": "old\nnew\n",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("files = %q, want %q", got, want)
+	}
+}
+
+func TestRotatingFileAge(t *testing.T) {
+	dir := t.TempDir()
+	synctest.Test(t, func(t *testing.T) {
+		name := filepath.Join(dir, "log")
+		rf, err := OpenRotatingFile(name, &RotateOptions{MaxAge: time.Hour})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rf.Close()
+		h := NewTextHandler(rf, &HandlerOptions{ReplaceAttr: removeKeys(TimeKey)})
+		logger := New(h)
+		logger.Info("first")
+		time.Sleep(30 * time.Minute)
+		logger.Info("second")
+		time.Sleep(30 * time.Minute)
+		logger.Info("third")
+		got := readFiles(t, dir)
+		want := map[string]string{
+			"log":   "level=INFO msg=third\n",
+			"log.1": "level=INFO msg=first\nlevel=INFO msg=second\n",
+		}
+		if !maps.Equal(got, want) {
+			t.Errorf("files = %q, want %q", got, want)
+		}
+	})
+}
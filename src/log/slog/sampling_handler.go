@@ -0,0 +1,210 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SamplingOptions are options for a [SamplingHandler].
+type SamplingOptions struct {
+	// Interval is the period over which records are counted.
+	// If Interval is zero, one second is used.
+	Interval time.Duration
+
+	// First is the number of records with each key which are handled
+	// in each interval before sampling starts.
+	First int
+
+	// Thereafter causes every Thereafter'th record with each key after
+	// the First to be handled, and the others dropped. If Thereafter is
+	// zero, all records after the First are dropped until the interval ends.
+	Thereafter int
+}
+
+// DroppedKey is the key used by a [SamplingHandler] for the number
+// of dropped records in a summary record.
+const DroppedKey = "dropped"
+
+// A SamplingHandler is a [Handler] that limits the rate of records
+// passed to another Handler.
+//
+// Records are grouped by their key, which is the pair of their level
+// and message, and counted over intervals of time. In each interval,
+// the first [SamplingOptions.First] records with a key are handled,
+// followed by one of every [SamplingOptions.Thereafter] records.
+// The other records are dropped.
+//
+// At the end of an interval in which records with a key were dropped,
+// the wrapped handler is passed a summary record with the level and
+// message of the dropped records, the program counter of the first
+// dropped record, and a single attribute whose key is [DroppedKey]
+// and whose value is the number of records dropped. Summary records
+// do not include the attributes or groups added by WithAttrs
+// or WithGroup.
+//
+// The handlers derived from a SamplingHandler by WithAttrs and
+// WithGroup share its counts, so that records from a call site
+// are sampled together regardless of the attributes of the
+// logger which produced them.
+type SamplingHandler struct {
+	h Handler
+	s *sampler
+}
+
+type sampler struct {
+	h    Handler // the handler for summary records
+	opts SamplingOptions
+
+	mu     sync.Mutex
+	end    time.Time // end of the current interval
+	counts map[sampleKey]*sampleCount
+	timer  *time.Timer // ends the interval if records were dropped
+}
+
+type sampleKey struct {
+	level Level
+	msg   string
+}
+
+type sampleCount struct {
+	n       int     // records seen in the interval
+	dropped int64   // records dropped in the interval
+	pc      uintptr // PC of the first dropped record
+}
+
+// NewSamplingHandler creates a [SamplingHandler] that passes records
+// to h, using the given options. If opts is nil, the first 100 records
+// with each key in each second are handled, and every 100th after that.
+func NewSamplingHandler(h Handler, opts *SamplingOptions) *SamplingHandler {
+	s := &sampler{
+		h: h,
+		opts: SamplingOptions{
+			First:      100,
+			Thereafter: 100,
+		},
+		counts: make(map[sampleKey]*sampleCount),
+	}
+	if opts != nil {
+		s.opts = *opts
+	}
+	if s.opts.Interval <= 0 {
+		s.opts.Interval = time.Second
+	}
+	return &SamplingHandler{h: h, s: s}
+}
+
+// Enabled reports whether the wrapped handler is enabled at the given level.
+func (h *SamplingHandler) Enabled(ctx context.Context, l Level) bool {
+	return h.h.Enabled(ctx, l)
+}
+
+// Handle passes r to the wrapped handler, unless r is dropped.
+// If the interval has ended, it first passes the summary records
+// for the interval.
+func (h *SamplingHandler) Handle(ctx context.Context, r Record) error {
+	keep, summaries := h.s.count(time.Now(), r)
+	var errs []error
+	for _, sr := range summaries {
+		if err := h.s.h.Handle(ctx, sr); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if keep {
+		if err := h.h.Handle(ctx, r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs returns a SamplingHandler which wraps h.WithAttrs(attrs)
+// and shares the counts of h.
+func (h *SamplingHandler) WithAttrs(attrs []Attr) Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &SamplingHandler{h: h.h.WithAttrs(attrs), s: h.s}
+}
+
+// WithGroup returns a SamplingHandler which wraps h.WithGroup(name)
+// and shares the counts of h.
+func (h *SamplingHandler) WithGroup(name string) Handler {
+	if name == "" {
+		return h
+	}
+	return &SamplingHandler{h: h.h.WithGroup(name), s: h.s}
+}
+
+// count counts r, reporting whether it should be handled, and returns
+// the summary records for the previous interval if it has ended.
+func (s *sampler) count(now time.Time, r Record) (keep bool, summaries []Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !now.Before(s.end) {
+		summaries = s.endIntervalLocked(now)
+		s.end = now.Add(s.opts.Interval)
+	}
+	k := sampleKey{r.Level, r.Message}
+	c := s.counts[k]
+	if c == nil {
+		c = new(sampleCount)
+		s.counts[k] = c
+	}
+	c.n++
+	if c.n <= s.opts.First || (s.opts.Thereafter > 0 && (c.n-s.opts.First)%s.opts.Thereafter == 0) {
+		return true, summaries
+	}
+	if c.dropped == 0 {
+		c.pc = r.PC
+	}
+	c.dropped++
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.end.Sub(now), s.timerExpired)
+	}
+	return false, summaries
+}
+
+// timerExpired passes the summary records to the wrapped handler
+// when no record has arrived since the end of the interval.
+func (s *sampler) timerExpired() {
+	s.mu.Lock()
+	var summaries []Record
+	now := time.Now()
+	if s.timer != nil && !now.Before(s.end) {
+		summaries = s.endIntervalLocked(now)
+	}
+	s.mu.Unlock()
+	for _, sr := range summaries {
+		s.h.Handle(context.Background(), sr)
+	}
+}
+
+// endIntervalLocked resets the counts and returns the summary records
+// for the interval which has ended. s.mu must be held.
+func (s *sampler) endIntervalLocked(now time.Time) []Record {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	var summaries []Record
+	for k, c := range s.counts {
+		if c.dropped > 0 {
+			sr := NewRecord(now, k.level, k.msg, c.pc)
+			sr.AddAttrs(Int64(DroppedKey, c.dropped))
+			summaries = append(summaries, sr)
+		}
+	}
+	clear(s.counts)
+	slices.SortFunc(summaries, func(a, b Record) int {
+		return cmp.Or(cmp.Compare(a.Level, b.Level), cmp.Compare(a.Message, b.Message))
+	})
+	return summaries
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var buf lockedBuffer
+		h := NewSamplingHandler(NewTextHandler(&buf, &HandlerOptions{ReplaceAttr: removeKeys(TimeKey)}),
+			&SamplingOptions{Interval: time.Minute, First: 2, Thereafter: 3})
+		logger := New(h)
+		for i := range 9 {
+			logger.With("i", i).Info("hot")
+			if i < 3 {
+				logger.Warn("hot", "i", i)
+			}
+		}
+		logger.Info("cold")
+		want := []string{
+			"level=INFO msg=hot i=0",
+			"level=WARN msg=hot i=0",
+			"level=INFO msg=hot i=1",
+			"level=WARN msg=hot i=1",
+			"level=INFO msg=hot i=4",
+			"level=INFO msg=hot i=7",
+			"level=INFO msg=cold",
+		}
+		checkLines(t, buf.String(), want)
+
+		// The summary is written by a timer when the interval ends.
+		buf.Reset()
+		time.Sleep(time.Minute)
+		synctest.Wait()
+		checkLines(t, buf.String(), []string{
+			"level=INFO msg=hot dropped=5",
+			"level=WARN msg=hot dropped=1",
+		})
+
+		// A new interval starts with new counts.
+		buf.Reset()
+		for i := range 3 {
+			logger.Info("hot", "i", i)
+		}
+		time.Sleep(2 * time.Minute)
+		logger.Info("hot", "i", 3)
+		checkLines(t, buf.String(), []string{
+			"level=INFO msg=hot i=0",
+			"level=INFO msg=hot i=1",
+			"level=INFO msg=hot dropped=1",
+			"level=INFO msg=hot i=3",
+		})
+	})
+}
+
+func TestSamplingHandlerSource(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var c captureHandler
+		h := NewSamplingHandler(&c, &SamplingOptions{First: 1})
+		logger := New(h)
+		for range 3 {
+			logger.Info("m")
+		}
+		time.Sleep(time.Second)
+		synctest.Wait()
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.r.Message != "m" || c.r.NumAttrs() != 1 {
+			t.Fatalf("summary record = %v", c.r)
+		}
+		c.r.Attrs(func(a Attr) bool {
+			if !a.Equal(Int64(DroppedKey, 2)) {
+				t.Errorf("summary attr = %v, want %s=2", a, DroppedKey)
+			}
+			return true
+		})
+		if got := c.r.Source(); got == nil || !strings.HasSuffix(got.File, "sampling_handler_test.go") {
+			t.Errorf("summary source = %+v, want this file", got)
+		}
+	})
+}
+
+// lockedBuffer is a bytes.Buffer which may be written
+// by a SamplingHandler's timer.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *lockedBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Reset()
+}
+
+func checkLines(t *testing.T, got string, want []string) {
+	t.Helper()
+	if g, w := got, strings.Join(want, "\n")+"\n"; g != w {
+		t.Errorf("got:\n%s\nwant:\n%s", g, w)
+	}
+}
+
+func TestSamplingHandlerEnabled(t *testing.T) {
+	h := NewSamplingHandler(DiscardHandler, nil)
+	if h.Enabled(context.Background(), LevelInfo) {
+		t.Errorf("Enabled = true for DiscardHandler")
+	}
+}
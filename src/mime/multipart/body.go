@@ -0,0 +1,232 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package multipart
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/textproto"
+	"slices"
+	"strings"
+)
+
+// A Body is a multipart message composed of parts with known sizes,
+// which can be read as a stream without being buffered in memory.
+// Its total length is known before it is read, and it can be read
+// again from the start, so it can be used as the body of an HTTP
+// request with a Content-Length: [net/http.NewRequest] sets the
+// request's ContentLength and GetBody for a Body.
+//
+// The message written is the same as that written by a [Writer]
+// with the same boundary and parts.
+//
+// A Body is not safe for concurrent use.
+type Body struct {
+	boundary string
+	parts    []*bodyPart
+	r        io.Reader // reader used by Read, once reading has started
+}
+
+// A bodyPart is a part of a Body.
+type bodyPart struct {
+	header textproto.MIMEHeader
+	size   int64
+
+	r      io.Reader
+	start  int64 // offset of the part in r, if r is an io.Seeker
+	seeker bool  // r is an io.Seeker
+	read   bool  // r has been read, and can't be read again
+}
+
+// NewBody returns a new, empty, [Body] with a random boundary.
+func NewBody() *Body {
+	return &Body{boundary: randomBoundary()}
+}
+
+// Boundary returns the Body's boundary.
+func (b *Body) Boundary() string {
+	return b.boundary
+}
+
+// SetBoundary overrides the Body's default randomly-generated boundary
+// separator with an explicit value, as [Writer.SetBoundary] does.
+// SetBoundary must be called before any parts are added.
+func (b *Body) SetBoundary(boundary string) error {
+	if len(b.parts) > 0 {
+		return errors.New("mime: SetBoundary called after AddPart")
+	}
+	w := &Writer{}
+	if err := w.SetBoundary(boundary); err != nil {
+		return err
+	}
+	b.boundary = boundary
+	return nil
+}
+
+// FormDataContentType returns the Content-Type for an HTTP
+// multipart/form-data with the Body's boundary.
+func (b *Body) FormDataContentType() string {
+	return (&Writer{boundary: b.boundary}).FormDataContentType()
+}
+
+// AddPart adds a part with the given header, whose body is the next
+// size bytes read from r.
+//
+// If r implements [io.ReaderAt] and [io.Seeker], such as an [*os.File]
+// or a [*bytes.Reader], the part is read from r's offset when AddPart
+// is called using ReadAt, and r's offset is not changed. Otherwise, if
+// r implements io.Seeker, r is sought back to that offset to read the
+// body again. A Body with a part which implements neither, or whose
+// Seek method fails, can only be read once.
+//
+// AddPart must not be called after the Body is read.
+func (b *Body) AddPart(header textproto.MIMEHeader, r io.Reader, size int64) error {
+	if b.r != nil {
+		return errors.New("multipart: AddPart called after Read")
+	}
+	if size < 0 {
+		return errors.New("multipart: negative part size")
+	}
+	p := &bodyPart{header: header, size: size, r: r}
+	if s, ok := r.(io.Seeker); ok {
+		// Some Seekers, such as an *os.File for a pipe, can't seek.
+		if start, err := s.Seek(0, io.SeekCurrent); err == nil {
+			p.start, p.seeker = start, true
+		}
+	}
+	b.parts = append(b.parts, p)
+	return nil
+}
+
+// AddFormFile adds a form-data part for a file, with the given field
+// name and file name, whose content is the next size bytes read from r.
+// See [Body.AddPart].
+func (b *Body) AddFormFile(fieldname, filename string, r io.Reader, size int64) error {
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", FileContentDisposition(fieldname, filename))
+	h.Set("Content-Type", "application/octet-stream")
+	return b.AddPart(h, r, size)
+}
+
+// AddFormField adds a form-data part with the given field name and value.
+func (b *Body) AddFormField(fieldname, value string) error {
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(fieldname)))
+	return b.AddPart(h, strings.NewReader(value), int64(len(value)))
+}
+
+// partHeader returns the delimiter and header which begin part i.
+func (b *Body) partHeader(i int) []byte {
+	var buf bytes.Buffer
+	if i > 0 {
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s\r\n", b.boundary)
+	h := b.parts[i].header
+	for _, k := range slices.Sorted(maps.Keys(h)) {
+		for _, v := range h[k] {
+			fmt.Fprintf(&buf, "%s: %s\r\n", k, v)
+		}
+	}
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
+
+// trailer returns the delimiter which ends the Body.
+func (b *Body) trailer() string {
+	return "\r\n--" + b.boundary + "--\r\n"
+}
+
+// Len returns the length of the Body in bytes.
+func (b *Body) Len() int64 {
+	n := int64(len(b.trailer()))
+	for i, p := range b.parts {
+		n += int64(len(b.partHeader(i))) + p.size
+	}
+	return n
+}
+
+// Read reads from the Body. The first call to Read starts reading
+// from a reader returned by [Body.Open].
+//
+// If a part's reader ends before the size given for it, Read returns
+// [io.ErrUnexpectedEOF].
+func (b *Body) Read(p []byte) (int, error) {
+	if b.r == nil {
+		r, err := b.Open()
+		if err != nil {
+			return 0, err
+		}
+		b.r = r
+	}
+	return b.r.Read(p)
+}
+
+// Open returns a new reader of the Body from its start, independent
+// of Read, for use as [net/http.Request.GetBody]. Readers of parts
+// which implement io.Seeker but not io.ReaderAt are sought back to
+// their start; any reader previously returned by Open must not be
+// used after Open is called again.
+//
+// Open returns an error if a part's reader implements neither
+// io.ReaderAt nor io.Seeker, and has already been read.
+func (b *Body) Open() (io.Reader, error) {
+	readers := make([]io.Reader, 0, 2*len(b.parts)+1)
+	for i, p := range b.parts {
+		readers = append(readers, bytes.NewReader(b.partHeader(i)))
+		r, err := p.open()
+		if err != nil {
+			return nil, err
+		}
+		readers = append(readers, r)
+	}
+	readers = append(readers, strings.NewReader(b.trailer()))
+	return io.MultiReader(readers...), nil
+}
+
+// open returns a reader of the part's body.
+func (p *bodyPart) open() (io.Reader, error) {
+	if ra, ok := p.r.(io.ReaderAt); ok && p.seeker {
+		return &sizedReader{r: io.NewSectionReader(ra, p.start, p.size), n: p.size}, nil
+	}
+	if p.seeker {
+		if p.read {
+			if _, err := p.r.(io.Seeker).Seek(p.start, io.SeekStart); err != nil {
+				return nil, err
+			}
+		}
+	} else if p.read {
+		return nil, errors.New("multipart: part cannot be read again")
+	}
+	p.read = true
+	return &sizedReader{r: p.r, n: p.size}, nil
+}
+
+// A sizedReader reads exactly n bytes from r.
+type sizedReader struct {
+	r io.Reader
+	n int64 // bytes remaining
+}
+
+func (s *sizedReader) Read(p []byte) (int, error) {
+	if s.n <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > s.n {
+		p = p[:s.n]
+	}
+	n, err := s.r.Read(p)
+	s.n -= int64(n)
+	if err == io.EOF {
+		if s.n > 0 {
+			return n, io.ErrUnexpectedEOF
+		}
+		err = nil
+	}
+	return n, err
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package multipart
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBody(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, []byte("xxfile contents"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.Seek(2, io.SeekStart)
+
+	// A Seeker which is not a ReaderAt.
+	seeker := struct{ io.ReadSeeker }{strings.NewReader("seekable")}
+
+	b := NewBody()
+	if err := b.SetBoundary("MyBoundary"); err != nil {
+		t.Fatal(err)
+	}
+	b.AddFormField("field", "value")
+	b.AddFormFile("file", "file.txt", f, 13)
+	b.AddPart(textproto.MIMEHeader{"X-Part": {"seeker"}}, seeker, 8)
+
+	// The same message written by a Writer.
+	var want bytes.Buffer
+	w := NewWriter(&want)
+	w.SetBoundary("MyBoundary")
+	w.WriteField("field", "value")
+	fw, _ := w.CreateFormFile("file", "file.txt")
+	io.WriteString(fw, "file contents")
+	pw, _ := w.CreatePart(textproto.MIMEHeader{"X-Part": {"seeker"}})
+	io.WriteString(pw, "seekable")
+	w.Close()
+
+	if got := b.Len(); got != int64(want.Len()) {
+		t.Errorf("Len() = %d, want %d", got, want.Len())
+	}
+	got, err := io.ReadAll(b)
+	if err != nil || string(got) != want.String() {
+		t.Fatalf("Read = %q, %v; want %q", got, err, want.String())
+	}
+	if err := b.AddFormField("late", ""); err == nil {
+		t.Errorf("AddFormField after Read succeeded")
+	}
+	for range 2 {
+		r, err := b.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil || string(got) != want.String() {
+			t.Fatalf("reading Open() = %q, %v; want %q", got, err, want.String())
+		}
+	}
+	if off, _ := f.Seek(0, io.SeekCurrent); off != 2 {
+		t.Errorf("file offset after reading body = %d, want 2", off)
+	}
+}
+
+func TestBodyEmpty(t *testing.T) {
+	b := NewBody()
+	var want bytes.Buffer
+	w := NewWriter(&want)
+	w.SetBoundary(b.Boundary())
+	w.Close()
+	got, err := io.ReadAll(b)
+	if err != nil || string(got) != want.String() || b.Len() != int64(want.Len()) {
+		t.Errorf("empty Body = %q (Len %d), %v; want %q", got, b.Len(), err, want.String())
+	}
+}
+
+func TestBodyPipe(t *testing.T) {
+	// An *os.File for a pipe is an io.Seeker which can't seek.
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.Close()
+	go func() {
+		io.WriteString(pw, "piped")
+		pw.Close()
+	}()
+
+	b := NewBody()
+	if err := b.AddFormFile("file", "file.txt", pr, 5); err != nil {
+		t.Fatalf("AddFormFile with pipe: %v", err)
+	}
+	var want bytes.Buffer
+	w := NewWriter(&want)
+	w.SetBoundary(b.Boundary())
+	fw, _ := w.CreateFormFile("file", "file.txt")
+	io.WriteString(fw, "piped")
+	w.Close()
+
+	got, err := io.ReadAll(b)
+	if err != nil || string(got) != want.String() {
+		t.Fatalf("Read = %q, %v; want %q", got, err, want.String())
+	}
+	if _, err := b.Open(); err == nil {
+		t.Errorf("Open after reading pipe succeeded")
+	}
+}
+
+func TestBodyErrors(t *testing.T) {
+	// A part which ends before its size.
+	b := NewBody()
+	b.AddPart(nil, strings.NewReader("short"), 10)
+	if _, err := io.ReadAll(b); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("reading short part: err = %v, want io.ErrUnexpectedEOF", err)
+	}
+
+	// A part which can only be read once.
+	b = NewBody()
+	b.AddPart(nil, struct{ io.Reader }{strings.NewReader("once")}, 4)
+	if _, err := io.ReadAll(b); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Open(); err == nil {
+		t.Errorf("Open after reading part which cannot be reread succeeded")
+	}
+
+	b = NewBody()
+	if err := b.AddPart(nil, strings.NewReader(""), -1); err == nil {
+		t.Errorf("AddPart with negative size succeeded")
+	}
+	b.AddFormField("field", "value")
+	if err := b.SetBoundary("other"); err == nil {
+		t.Errorf("SetBoundary after AddPart succeeded")
+	}
+}
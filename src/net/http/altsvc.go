@@ -0,0 +1,179 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// setAltSvc advertises the server's HTTP/3 endpoint on responses
+// sent over HTTP/1 and HTTP/2 TLS connections.
+func (s *Server) setAltSvc(w ResponseWriter, req *Request) {
+	if req.ProtoMajor >= 3 || req.TLS == nil {
+		return
+	}
+	v := s.h3AltSvc.Load()
+	if v == nil {
+		return
+	}
+	if h := w.Header(); h != nil && h["Alt-Svc"] == nil {
+		h["Alt-Svc"] = []string{*v}
+	}
+}
+
+// Alt-Svc (RFC 7838) support.
+//
+// A Transport which supports both HTTP/3 and an earlier protocol records
+// the HTTP/3 alternative services advertised by origins in an altSvcCache.
+// Later requests to an origin with a usable alternative are sent over
+// HTTP/3. If connecting to the alternative fails, it is marked broken for
+// a while and requests fall back to TCP.
+
+const (
+	// altSvcDefaultMaxAge is the default freshness lifetime
+	// of an alternative service (RFC 7838, Section 3.1).
+	altSvcDefaultMaxAge = 24 * time.Hour
+
+	// altSvcBrokenDuration is how long an alternative which
+	// failed to connect is not used.
+	altSvcBrokenDuration = 5 * time.Minute
+
+	// altSvcMaxEntries bounds the number of origins an altSvcCache tracks.
+	altSvcMaxEntries = 1000
+)
+
+// altSvcCache records HTTP/3 alternatives, keyed by origin "host:port".
+type altSvcCache struct {
+	mu sync.Mutex
+	m  map[string]altSvcEntry
+}
+
+type altSvcEntry struct {
+	addr        string    // UDP "host:port" to dial, empty if none
+	expires     time.Time // when addr becomes stale
+	brokenUntil time.Time // addr must not be used before this time
+}
+
+// lookup returns the UDP address of the HTTP/3 alternative for origin,
+// or "" if there is none.
+func (c *altSvcCache) lookup(origin string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.m[origin]
+	if !ok {
+		return ""
+	}
+	now := time.Now()
+	if e.addr == "" || now.After(e.expires) || now.Before(e.brokenUntil) {
+		return ""
+	}
+	return e.addr
+}
+
+// markBroken records that the HTTP/3 alternative for origin
+// could not be reached.
+func (c *altSvcCache) markBroken(origin string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.m == nil {
+		return
+	}
+	c.m[origin] = altSvcEntry{brokenUntil: time.Now().Add(altSvcBrokenDuration)}
+}
+
+// update processes the Alt-Svc header values in a response from origin.
+func (c *altSvcCache) update(origin string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	host, _, err := net.SplitHostPort(origin)
+	if err != nil {
+		return
+	}
+	addr, maxAge, clear := parseAltSvcH3(host, values)
+	if !clear && addr == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	old, ok := c.m[origin]
+	if ok && time.Now().Before(old.brokenUntil) {
+		return
+	}
+	if clear || maxAge <= 0 {
+		delete(c.m, origin)
+		return
+	}
+	if c.m == nil {
+		c.m = make(map[string]altSvcEntry)
+	}
+	if !ok && len(c.m) >= altSvcMaxEntries {
+		for k := range c.m {
+			delete(c.m, k)
+			break
+		}
+	}
+	c.m[origin] = altSvcEntry{
+		addr:    addr,
+		expires: time.Now().Add(maxAge),
+	}
+}
+
+// parseAltSvcH3 parses Alt-Svc header values and returns the address
+// and freshness lifetime of the first "h3" alternative on host.
+// It reports clear if the values consist of the "clear" keyword.
+//
+// Alternatives on a different host are ignored: the Transport only uses
+// alternatives it can authenticate with the origin's own host name.
+func parseAltSvcH3(host string, values []string) (addr string, maxAge time.Duration, clear bool) {
+	for _, v := range values {
+		for alt := range strings.SplitSeq(v, ",") {
+			alt = textproto.TrimString(alt)
+			if alt == "clear" {
+				return "", 0, true
+			}
+			params := strings.Split(alt, ";")
+			proto, authority, ok := strings.Cut(params[0], "=")
+			if !ok || textproto.TrimString(proto) != "h3" {
+				continue
+			}
+			authority, err := strconv.Unquote(textproto.TrimString(authority))
+			if err != nil {
+				continue
+			}
+			ahost, port, err := net.SplitHostPort(authority)
+			if err != nil || (ahost != "" && ahost != host) {
+				continue
+			}
+			if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+				continue
+			}
+			maxAge = altSvcDefaultMaxAge
+			for _, p := range params[1:] {
+				k, v, _ := strings.Cut(textproto.TrimString(p), "=")
+				if k != "ma" {
+					continue
+				}
+				secs, err := strconv.ParseInt(strings.Trim(v, `"`), 10, 64)
+				if err != nil || secs < 0 {
+					continue
+				}
+				maxAge = time.Duration(min(secs, int64(maxAltSvcMaxAge/time.Second))) * time.Second
+			}
+			return net.JoinHostPort(host, port), maxAge, false
+		}
+	}
+	return "", 0, false
+}
+
+// maxAltSvcMaxAge caps the lifetime of an alternative service,
+// regardless of the advertised ma parameter.
+const maxAltSvcMaxAge = 30 * 24 * time.Hour
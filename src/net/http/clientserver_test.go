@@ -19,7 +19,6 @@ import (
 	"log"
 	"maps"
 	"net"
-	"net/http"
 	. "net/http"
 	"net/http/httptest"
 	"net/http/httptrace"
@@ -36,17 +35,8 @@ import (
 	"testing"
 	"testing/synctest"
 	"time"
-	_ "unsafe" // for linkname
-
-	_ "golang.org/x/net/http3"
 )
 
-//go:linkname registerHTTP3Transport
-func registerHTTP3Transport(*http.Transport)
-
-//go:linkname registerHTTP3Server
-func registerHTTP3Server(*http.Server) <-chan string
-
 type testMode string
 
 const (
@@ -60,7 +50,7 @@ const (
 // http3SkippedMode is a convenient alias for []testMode{http1Mode, http2Mode},
 // which was the default test mode used by run and runSynctest prior to HTTP/3
 // development.
-// As we work on getting net/http tests to pass for our x/net HTTP/3
+// As we work on getting net/http tests to pass for our HTTP/3
 // implementation, tests that still use http3SkippedMode are essentially a list
 // of TODOs on what work needs to be done for our HTTP/3 implementation to
 // reach basic feature parity with our HTTP/1 and HTTP/2 implementations
@@ -207,6 +197,9 @@ func newClientServerTest(t testing.TB, mode testMode, h Handler, opts ...any) *c
 	if mode == http2Mode || mode == http2UnencryptedMode {
 		CondSkipHTTP2(t)
 	}
+	if mode == http3Mode {
+		CondSkipHTTP3(t)
+	}
 	cst := &clientServerTest{
 		t:  t,
 		h2: mode == http2Mode,
@@ -274,10 +267,10 @@ func newClientServerTest(t testing.TB, mode testMode, h Handler, opts ...any) *c
 		cst.ts.TLS = cst.ts.Config.TLSConfig
 		cst.ts.StartTLS()
 	case http3Mode:
-		http.ProtocolSetHTTP3(p)
+		p.SetHTTP3(true)
 		cst.ts.TLS = cst.ts.Config.TLSConfig
 		cst.ts.StartTLS()
-		listenAddrCh := registerHTTP3Server(cst.ts.Config)
+		listenAddrCh := HTTP3ListenAddrForTesting(cst.ts.Config)
 
 		cst.ts.Config.TLSConfig = cst.ts.TLS
 		cst.ts.Config.Addr = "localhost:0"
@@ -305,10 +298,6 @@ func newClientServerTest(t testing.TB, mode testMode, h Handler, opts ...any) *c
 	if cst.tr.Protocols == nil {
 		cst.tr.Protocols = p
 	}
-	if mode == http3Mode {
-		registerHTTP3Transport(cst.tr)
-	}
-
 	t.Cleanup(func() {
 		cst.close()
 	})
@@ -0,0 +1,317 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"compress/zstd"
+	"io"
+	"net/http/internal"
+	"net/http/internal/ascii"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CompressHandler returns a handler that compresses the responses
+// of the handler h, using a content coding negotiated from the
+// request's Accept-Encoding header. The supported codings are, in
+// order of preference, "zstd", "gzip" and "deflate".
+//
+// A response is not compressed if:
+//   - the request's method is HEAD, or it has a Range header;
+//   - the response has a status which does not permit a body,
+//     or is a 206 Partial Content response;
+//   - h sets a Content-Encoding or Content-Range header;
+//   - the response's Content-Type is a format which is already
+//     compressed, such as an image, video or archive format;
+//   - the response body is smaller than a few hundred bytes,
+//     unless h flushes the response before it finishes.
+//
+// If h does not set a Content-Type header, the handler sets one
+// using [DetectContentType] on the uncompressed content.
+//
+// When the handler compresses a response, it sets the Content-Encoding
+// header, deletes the Content-Length and Accept-Ranges headers, and
+// changes a strong ETag header to a weak one. It adds "Accept-Encoding"
+// to the Vary header of every response.
+//
+// Calling Flush on the ResponseWriter passed to h, or using a
+// [ResponseController] to flush it, flushes any data buffered by
+// the compressor before flushing the underlying ResponseWriter.
+//
+// If h panics, the compressed response body is left unfinished, so that
+// the client can tell that the response is incomplete.
+func CompressHandler(h Handler) Handler {
+	return HandlerFunc(func(w ResponseWriter, r *Request) {
+		coding := negotiateEncoding(r.Header["Accept-Encoding"])
+		if coding == "" || r.Method == "HEAD" || r.Header.Get("Range") != "" {
+			addVary(w.Header())
+			h.ServeHTTP(w, r)
+			return
+		}
+		cw := &compressWriter{rw: w, coding: coding}
+		defer cw.close()
+		h.ServeHTTP(cw, r)
+		cw.finish()
+	})
+}
+
+// addVary adds Accept-Encoding to the Vary header in h.
+func addVary(h Header) {
+	for _, v := range h["Vary"] {
+		if hasToken(v, "accept-encoding") {
+			return
+		}
+	}
+	h.Add("Vary", "Accept-Encoding")
+}
+
+// compressEncodings are the content codings supported
+// by CompressHandler, in order of preference.
+var compressEncodings = [...]string{"zstd", "gzip", "deflate"}
+
+// negotiateEncoding returns the content coding to use for a response
+// to a request with the Accept-Encoding header values accept,
+// or "" if the response should not be compressed.
+func negotiateEncoding(accept []string) string {
+	var (
+		qs       [len(compressEncodings)]float64
+		listed   [len(compressEncodings)]bool
+		wildcard = -1.0 // q value for unlisted codings, or -1 if none
+	)
+	for _, v := range accept {
+		for elem := range strings.SplitSeq(v, ",") {
+			coding, params, _ := strings.Cut(elem, ";")
+			coding, _ = ascii.ToLower(textproto.TrimString(coding))
+			q := 1.0
+			for param := range strings.SplitSeq(params, ";") {
+				k, v, _ := strings.Cut(param, "=")
+				if ascii.EqualFold(textproto.TrimString(k), "q") {
+					var err error
+					if q, err = strconv.ParseFloat(textproto.TrimString(v), 64); err != nil {
+						q = 0
+					}
+				}
+			}
+			if coding == "x-gzip" {
+				coding = "gzip"
+			}
+			if coding == "*" {
+				wildcard = q
+				continue
+			}
+			for i, enc := range compressEncodings {
+				if coding == enc {
+					qs[i], listed[i] = q, true
+				}
+			}
+		}
+	}
+	best, bestQ := "", 0.0
+	for i, enc := range compressEncodings {
+		q := qs[i]
+		if !listed[i] {
+			q = max(wildcard, 0)
+		}
+		if q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
+
+// compressMinLength is the length below which CompressHandler
+// does not compress a complete response body.
+const compressMinLength = 256
+
+// A compressor is a gzip.Writer, zlib.Writer or zstd.Writer.
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+var compressorPools = map[string]*sync.Pool{
+	// zstd's fastest level uses a small window and no hash chains,
+	// so its buffers are no larger than those of gzip's default level.
+	"zstd": {New: func() any {
+		zw, _ := zstd.NewWriterLevel(nil, zstd.BestSpeed)
+		return zw
+	}},
+	"gzip":    {New: func() any { return gzip.NewWriter(nil) }},
+	"deflate": {New: func() any { return zlib.NewWriter(nil) }},
+}
+
+// A compressWriter is the ResponseWriter passed to the handler
+// wrapped by CompressHandler.
+//
+// It buffers the start of the response body, and decides whether to
+// compress the response once the buffer is full, the handler flushes
+// the response, or the handler returns.
+type compressWriter struct {
+	rw      ResponseWriter
+	coding  string     // negotiated content coding
+	code    int        // status code, or 0 if not yet set
+	buf     []byte     // body written before the response is started
+	started bool       // whether the status has been written to rw
+	zw      compressor // compressor, or nil if not compressing
+}
+
+func (cw *compressWriter) Header() Header {
+	return cw.rw.Header()
+}
+
+// Unwrap returns the underlying ResponseWriter,
+// for use by ResponseController.
+func (cw *compressWriter) Unwrap() ResponseWriter {
+	return cw.rw
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.started {
+		// Let the underlying ResponseWriter report the superfluous call.
+		cw.rw.WriteHeader(code)
+		return
+	}
+	if cw.code != 0 {
+		return
+	}
+	if code >= 100 && code <= 199 && code != StatusSwitchingProtocols {
+		cw.rw.WriteHeader(code)
+		return
+	}
+	cw.code = code
+	if !bodyAllowedForStatus(code) {
+		cw.start(nil, false)
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if cw.code == 0 {
+		cw.code = StatusOK
+	}
+	if cw.started {
+		return cw.write(p)
+	}
+	if len(cw.buf)+len(p) < internal.SniffLen {
+		cw.buf = append(cw.buf, p...)
+		return len(p), nil
+	}
+	sniff := cw.buf
+	if n := internal.SniffLen - len(sniff); n > 0 {
+		sniff = append(sniff, p[:n]...)
+	}
+	if err := cw.start(sniff, true); err != nil {
+		return 0, err
+	}
+	return cw.write(p)
+}
+
+func (cw *compressWriter) write(p []byte) (int, error) {
+	if cw.zw != nil {
+		return cw.zw.Write(p)
+	}
+	return cw.rw.Write(p)
+}
+
+// start decides whether to compress the response, and writes the
+// status and any buffered body to the underlying ResponseWriter.
+// sniff is the start of the response body, and large reports
+// whether the body is long enough to be worth compressing.
+func (cw *compressWriter) start(sniff []byte, large bool) error {
+	cw.started = true
+	h := cw.rw.Header()
+	addVary(h)
+	_, haveType := h["Content-Type"]
+	if !haveType && len(sniff) > 0 && bodyAllowedForStatus(cw.code) {
+		h.Set("Content-Type", DetectContentType(sniff))
+		haveType = true
+	}
+	mediaType, _, _ := strings.Cut(h.Get("Content-Type"), ";")
+	mediaType, _ = ascii.ToLower(textproto.TrimString(mediaType))
+	if large &&
+		bodyAllowedForStatus(cw.code) &&
+		cw.code != StatusPartialContent &&
+		h.Get("Content-Encoding") == "" &&
+		h.Get("Content-Range") == "" &&
+		!internal.IsCompressedType(mediaType) {
+		if !haveType {
+			// Prevent the server from sniffing the compressed content.
+			h.Set("Content-Type", DetectContentType(nil))
+		}
+		h.Set("Content-Encoding", cw.coding)
+		h.Del("Content-Length")
+		h.Del("Accept-Ranges")
+		if etag := h.Get("Etag"); strings.HasPrefix(etag, `"`) {
+			h.Set("Etag", "W/"+etag)
+		}
+		cw.zw = compressorPools[cw.coding].Get().(compressor)
+		cw.zw.Reset(cw.rw)
+	}
+	cw.rw.WriteHeader(cw.code)
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) > 0 {
+		if _, err := cw.write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FlushError starts the response if necessary, flushes any data
+// buffered by the compressor, and flushes the underlying ResponseWriter.
+func (cw *compressWriter) FlushError() error {
+	if !cw.started {
+		if cw.code == 0 {
+			cw.code = StatusOK
+		}
+		if err := cw.start(cw.buf, true); err != nil {
+			return err
+		}
+	}
+	if cw.zw != nil {
+		if err := cw.zw.Flush(); err != nil {
+			return err
+		}
+	}
+	return NewResponseController(cw.rw).Flush()
+}
+
+func (cw *compressWriter) Flush() {
+	cw.FlushError()
+}
+
+// finish finishes the response after the handler returns.
+func (cw *compressWriter) finish() {
+	if !cw.started {
+		if cw.code == 0 && len(cw.buf) == 0 {
+			// Nothing was written; leave the response to the server.
+			addVary(cw.rw.Header())
+			return
+		}
+		if cw.code == 0 {
+			cw.code = StatusOK
+		}
+		cw.start(cw.buf, len(cw.buf) >= compressMinLength)
+	}
+	if cw.zw != nil {
+		cw.zw.Close()
+	}
+}
+
+// close returns the compressor, if any, to its pool. It is called even if
+// the handler panics, in which case the compressed stream is not finished,
+// so that the client doesn't mistake the response for a complete one.
+func (cw *compressWriter) close() {
+	if cw.zw != nil {
+		cw.zw.Reset(nil)
+		compressorPools[cw.coding].Put(cw.zw)
+		cw.zw = nil
+	}
+}
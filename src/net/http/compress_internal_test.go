@@ -0,0 +1,64 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// White-box tests for compress.go (in package http instead of http_test).
+
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+)
+
+// bufferResponseWriter is a ResponseWriter which records the body.
+type bufferResponseWriter struct {
+	header Header
+	body   bytes.Buffer
+}
+
+func (w *bufferResponseWriter) Header() Header {
+	if w.header == nil {
+		w.header = make(Header)
+	}
+	return w.header
+}
+
+func (w *bufferResponseWriter) Write(p []byte) (int, error) { return w.body.Write(p) }
+func (w *bufferResponseWriter) WriteHeader(code int)        {}
+
+func TestCompressHandlerPanic(t *testing.T) {
+	var cw *compressWriter
+	h := CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		cw = w.(*compressWriter)
+		io.WriteString(w, strings.Repeat("a", 1000))
+		panic(ErrAbortHandler)
+	}))
+	req, _ := NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := &bufferResponseWriter{}
+	func() {
+		defer func() {
+			if p := recover(); p != ErrAbortHandler {
+				t.Errorf("recovered %v, want ErrAbortHandler", p)
+			}
+		}()
+		h.ServeHTTP(rec, req)
+	}()
+	if cw.zw != nil {
+		t.Errorf("compressor not released after the handler panicked")
+	}
+	if ce := rec.Header().Get("Content-Encoding"); ce != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", ce)
+	}
+	zr, err := gzip.NewReader(&rec.body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(zr); err != io.ErrUnexpectedEOF {
+		t.Errorf("reading the body of the aborted response: %v, want ErrUnexpectedEOF", err)
+	}
+}
@@ -0,0 +1,301 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"compress/zstd"
+	"io"
+	. "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var compressTestBody = strings.Repeat("The quick brown fox jumps over the lazy dog. ", 100)
+
+// decodeBody returns the body of a response with the content coding ce.
+func decodeBody(t *testing.T, ce string, body []byte) string {
+	t.Helper()
+	var r io.Reader = bytes.NewReader(body)
+	var err error
+	switch ce {
+	case "":
+	case "gzip":
+		r, err = gzip.NewReader(r)
+	case "deflate":
+		r, err = zlib.NewReader(r)
+	case "zstd":
+		r = zstd.NewReader(r)
+	default:
+		t.Fatalf("unexpected Content-Encoding %q", ce)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("decoding %q body: %v", ce, err)
+	}
+	return string(b)
+}
+
+func TestCompressHandlerNegotiate(t *testing.T) {
+	h := CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		io.WriteString(w, compressTestBody)
+	}))
+	for _, tt := range []struct {
+		accept string
+		want   string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"x-gzip", "gzip"},
+		{"deflate", "deflate"},
+		{"zstd", "zstd"},
+		{"gzip, deflate, br, zstd", "zstd"},
+		{"gzip;q=1.0, zstd;q=0.5", "gzip"},
+		{"GZIP ; Q=0.8, deflate;q=0.9", "deflate"},
+		{"zstd;q=0, gzip;q=0", ""},
+		{"*", "zstd"},
+		{"zstd;q=0, *;q=0.5", "gzip"},
+		{"identity", ""},
+		{"br", ""},
+	} {
+		req := httptest.NewRequest("GET", "/", nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept-Encoding", tt.accept)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		res := w.Result()
+		ce := res.Header.Get("Content-Encoding")
+		if ce != tt.want {
+			t.Errorf("Accept-Encoding %q: Content-Encoding = %q, want %q", tt.accept, ce, tt.want)
+			continue
+		}
+		if got := decodeBody(t, ce, w.Body.Bytes()); got != compressTestBody {
+			t.Errorf("Accept-Encoding %q: decoded body differs from original", tt.accept)
+		}
+		if got := res.Header.Get("Vary"); got != "Accept-Encoding" {
+			t.Errorf("Accept-Encoding %q: Vary = %q, want Accept-Encoding", tt.accept, got)
+		}
+		if got, want := res.Header.Get("Content-Type"), "text/plain; charset=utf-8"; got != want {
+			t.Errorf("Accept-Encoding %q: Content-Type = %q, want %q", tt.accept, got, want)
+		}
+	}
+}
+
+func TestCompressHandlerHeaders(t *testing.T) {
+	h := CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		w.Header().Set("Vary", "Origin")
+		w.Header().Set("Content-Length", "4500")
+		w.Header().Set("Accept-Ranges", "bytes")
+		w.Header().Set("Etag", `"abc"`)
+		// Write in pieces, to be reassembled after the buffer fills.
+		for i := 0; i < len(compressTestBody); i += 100 {
+			io.WriteString(w, compressTestBody[i:i+100])
+		}
+	}))
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	for k, want := range map[string]string{
+		"Content-Encoding": "gzip",
+		"Content-Length":   "",
+		"Accept-Ranges":    "",
+		"Etag":             `W/"abc"`,
+	} {
+		if got := w.Header().Get(k); got != want {
+			t.Errorf("%s = %q, want %q", k, got, want)
+		}
+	}
+	if got, want := w.Header().Values("Vary"), []string{"Origin", "Accept-Encoding"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Vary = %q, want %q", got, want)
+	}
+	if got := decodeBody(t, "gzip", w.Body.Bytes()); got != compressTestBody {
+		t.Errorf("decoded body differs from original")
+	}
+}
+
+func TestCompressHandlerUncompressed(t *testing.T) {
+	png := "\x89PNG\x0D\x0A\x1A\x0A" + compressTestBody
+	for _, tt := range []struct {
+		name   string
+		method string
+		header []string
+		h      func(w ResponseWriter, r *Request)
+	}{{
+		name: "small body",
+		h: func(w ResponseWriter, r *Request) {
+			io.WriteString(w, "small")
+		},
+	}, {
+		name: "sniffed image",
+		h: func(w ResponseWriter, r *Request) {
+			io.WriteString(w, png)
+		},
+	}, {
+		name: "image type",
+		h: func(w ResponseWriter, r *Request) {
+			w.Header().Set("Content-Type", "image/JPEG")
+			io.WriteString(w, compressTestBody)
+		},
+	}, {
+		name: "video type",
+		h: func(w ResponseWriter, r *Request) {
+			w.Header().Set("Content-Type", "video/mp4; codecs=avc1")
+			io.WriteString(w, compressTestBody)
+		},
+	}, {
+		name: "already encoded",
+		h: func(w ResponseWriter, r *Request) {
+			w.Header().Set("Content-Encoding", "br")
+			io.WriteString(w, compressTestBody)
+		},
+	}, {
+		name: "partial content",
+		h: func(w ResponseWriter, r *Request) {
+			w.WriteHeader(StatusPartialContent)
+			io.WriteString(w, compressTestBody)
+		},
+	}, {
+		name:   "range request",
+		header: []string{"Range", "bytes=0-"},
+		h: func(w ResponseWriter, r *Request) {
+			io.WriteString(w, compressTestBody)
+		},
+	}, {
+		name:   "HEAD request",
+		method: "HEAD",
+		h: func(w ResponseWriter, r *Request) {
+			io.WriteString(w, compressTestBody)
+		},
+	}, {
+		name: "not modified",
+		h: func(w ResponseWriter, r *Request) {
+			w.WriteHeader(StatusNotModified)
+		},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = "GET"
+			}
+			req := httptest.NewRequest(method, "/", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			for i := 0; i+1 < len(tt.header); i += 2 {
+				req.Header.Set(tt.header[i], tt.header[i+1])
+			}
+			want := httptest.NewRecorder()
+			HandlerFunc(tt.h).ServeHTTP(want, req)
+			got := httptest.NewRecorder()
+			CompressHandler(HandlerFunc(tt.h)).ServeHTTP(got, req)
+			if ce := got.Header().Get("Content-Encoding"); ce != want.Header().Get("Content-Encoding") {
+				t.Errorf("Content-Encoding = %q, want %q", ce, want.Header().Get("Content-Encoding"))
+			}
+			if got.Code != want.Code {
+				t.Errorf("status = %d, want %d", got.Code, want.Code)
+			}
+			if got.Body.String() != want.Body.String() {
+				t.Errorf("body = %q, want %q", got.Body, want.Body)
+			}
+		})
+	}
+}
+
+func TestCompressHandlerFlush(t *testing.T) {
+	flushed := make(chan struct{})
+	proceed := make(chan struct{})
+	h := CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "data: 1\n\n")
+		if err := NewResponseController(w).Flush(); err != nil {
+			t.Errorf("Flush: %v", err)
+		}
+		close(flushed)
+		<-proceed
+		io.WriteString(w, "data: 2\n\n")
+	}))
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		h.ServeHTTP(w, req)
+		close(done)
+	}()
+	<-flushed
+	if !w.Flushed {
+		t.Errorf("ResponseRecorder not flushed")
+	}
+	if ce := w.Header().Get("Content-Encoding"); ce != "gzip" {
+		t.Errorf("Content-Encoding = %q, want gzip", ce)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(w.Body.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 9)
+	if _, err := io.ReadFull(zr, buf); err != nil || string(buf) != "data: 1\n\n" {
+		t.Errorf("flushed data = %q, %v; want %q", buf, err, "data: 1\n\n")
+	}
+	close(proceed)
+	<-done
+	if got := decodeBody(t, "gzip", w.Body.Bytes()); got != "data: 1\n\ndata: 2\n\n" {
+		t.Errorf("body = %q, want %q", got, "data: 1\n\ndata: 2\n\n")
+	}
+}
+
+func TestTransportZstd(t *testing.T) { run(t, testTransportZstd, http3SkippedMode) }
+func testTransportZstd(t *testing.T, mode testMode) {
+	cst := newClientServerTest(t, mode, CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		io.WriteString(w, r.Header.Get("Accept-Encoding")+"\n"+compressTestBody)
+	})), func(tr *Transport) {
+		tr.EnableZstd = true
+	})
+	res, err := cst.c.Get(cst.ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "zstd, gzip\n" + compressTestBody; string(b) != want {
+		t.Errorf("body = %.40q..., want %.40q...", b, want)
+	}
+	if !res.Uncompressed {
+		t.Errorf("Uncompressed = false, want true")
+	}
+	if ce := res.Header.Get("Content-Encoding"); ce != "" {
+		t.Errorf("Content-Encoding = %q, want none", ce)
+	}
+
+	// A zstd response to a request which did not ask for zstd
+	// is not decoded.
+	cst.tr.EnableZstd = false
+	req, _ := NewRequest("GET", cst.ts.URL, nil)
+	req.Header.Set("Accept-Encoding", "zstd")
+	res, err = cst.c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	b, err = io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Uncompressed || res.Header.Get("Content-Encoding") != "zstd" {
+		t.Errorf("Uncompressed = %v, Content-Encoding = %q; want false, zstd", res.Uncompressed, res.Header.Get("Content-Encoding"))
+	}
+	if got, want := decodeBody(t, "zstd", b), "zstd\n"+compressTestBody; got != want {
+		t.Errorf("decoded body = %.40q..., want %.40q...", got, want)
+	}
+}
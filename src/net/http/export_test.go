@@ -33,17 +33,37 @@ var (
 	Export_writeStatusLine            = writeStatusLine
 	Export_is408Message               = is408Message
 	MaxPostCloseReadTime              = maxPostCloseReadTime
-	ProtocolSetHTTP3                  = protocolSetHTTP3
+	ExportParseAltSvcH3               = parseAltSvcH3
 )
 
 var MaxWriteWaitBeforeConnReuse = &maxWriteWaitBeforeConnReuse
 
+var http3ListenAddrs sync.Map // *Server -> chan string
+
+// HTTP3ListenAddrForTesting returns a channel which receives the UDP
+// address of the HTTP/3 endpoint opened by s.ListenAndServeTLS.
+func HTTP3ListenAddrForTesting(s *Server) <-chan string {
+	ch := make(chan string, 1)
+	http3ListenAddrs.Store(s, ch)
+	return ch
+}
+
+func (t *Transport) AltSvcForTesting(origin string) string {
+	return t.altSvc.lookup(origin)
+}
+
 func init() {
 	// We only want to pay for this cost during testing.
 	// When not under test, these values are always nil
 	// and never assigned to.
 	testHookMu = new(sync.Mutex)
 
+	testHookHTTP3Listen = func(s *Server, addr net.Addr) {
+		if ch, ok := http3ListenAddrs.LoadAndDelete(s); ok {
+			ch.(chan string) <- addr.String()
+		}
+	}
+
 	testHookClientDoResult = func(res *Response, err error) {
 		if err != nil {
 			if _, ok := err.(*url.Error); !ok {
@@ -66,6 +86,12 @@ func CondSkipHTTP2(t testing.TB) {
 	}
 }
 
+func CondSkipHTTP3(t testing.TB) {
+	if omitHTTP3 {
+		t.Skip("skipping HTTP/3 test when nethttpomithttp3 build tag in use")
+	}
+}
+
 var (
 	SetEnterRoundTripHook = hookSetter(&testHookEnterRoundTrip)
 	SetRoundTripRetried   = hookSetter(&testHookRoundTripRetried)
@@ -151,7 +177,7 @@ func (t *Transport) IdleConnStrsForTesting() []string {
 func (t *Transport) IdleConnCountForTesting(scheme, addr string) int {
 	t.idleMu.Lock()
 	defer t.idleMu.Unlock()
-	key := connectMethodKey{"", scheme, addr, false}
+	key := connectMethodKey{"", scheme, addr, false, false}
 	cacheKey := key.String()
 	for k, conns := range t.idleConn {
 		if k.String() == cacheKey {
@@ -181,7 +207,7 @@ func (t *Transport) QueueForIdleConnForTesting() {
 // persistConn for scheme, addr into the idle connection pool.
 func (t *Transport) PutIdleTestConn(scheme, addr string) bool {
 	c, _ := net.Pipe()
-	key := connectMethodKey{"", scheme, addr, false}
+	key := connectMethodKey{"", scheme, addr, false, false}
 
 	if t.MaxConnsPerHost > 0 {
 		// Transport is tracking conns-per-host.
@@ -206,7 +232,7 @@ func (t *Transport) PutIdleTestConn(scheme, addr string) bool {
 // PutIdleTestConnH2 reports whether it was able to insert a fresh
 // HTTP/2 persistConn for scheme, addr into the idle connection pool.
 func (t *Transport) PutIdleTestConnH2(scheme, addr string, alt RoundTripper) bool {
-	key := connectMethodKey{"", scheme, addr, false}
+	key := connectMethodKey{"", scheme, addr, false, false}
 
 	if t.MaxConnsPerHost > 0 {
 		// Transport is tracking conns-per-host.
@@ -10,7 +10,6 @@ import (
 	"strings"
 	"time"
 	"unicode/utf8"
-	_ "unsafe"
 
 	"golang.org/x/net/http/httpguts"
 )
@@ -26,6 +25,16 @@ import (
 //   - HTTP2 is the HTTP/2 protcol over a TLS connection.
 //
 //   - UnencryptedHTTP2 is the HTTP/2 protocol over an unsecured TCP connection.
+//
+//   - HTTP3 is the HTTP/3 protocol over QUIC, which runs on UDP.
+//     HTTP3 is never enabled by default.
+//     A [Server] serves HTTP/3 only from [Server.ListenAndServeTLS].
+//     A [Transport] whose Protocols include only HTTP3 sends all requests
+//     using HTTP/3. A Transport whose Protocols include HTTP3 and other
+//     protocols uses HTTP/3 for an origin once the origin has advertised
+//     it in an Alt-Svc response header (RFC 7838).
+//     Programs built with the nethttpomithttp3 build tag do not support
+//     HTTP/3, and do not include the QUIC implementation it requires.
 type Protocols struct {
 	bits uint8
 }
@@ -55,14 +64,11 @@ func (p Protocols) UnencryptedHTTP2() bool { return p.bits&protoUnencryptedHTTP2
 // SetUnencryptedHTTP2 adds or removes unencrypted HTTP/2 from p.
 func (p *Protocols) SetUnencryptedHTTP2(ok bool) { p.setBit(protoUnencryptedHTTP2, ok) }
 
-// http3 reports whether p includes HTTP/3.
-func (p Protocols) http3() bool { return p.bits&protoHTTP3 != 0 }
-
-// setHTTP3 adds or removes HTTP/3 from p.
-func (p *Protocols) setHTTP3(ok bool) { p.setBit(protoHTTP3, ok) }
+// HTTP3 reports whether p includes HTTP/3.
+func (p Protocols) HTTP3() bool { return p.bits&protoHTTP3 != 0 }
 
-//go:linkname protocolSetHTTP3 golang.org/x/net/internal/http3_test.protocolSetHTTP3
-func protocolSetHTTP3(p *Protocols) { p.setHTTP3(true) }
+// SetHTTP3 adds or removes HTTP/3 from p.
+func (p *Protocols) SetHTTP3(ok bool) { p.setBit(protoHTTP3, ok) }
 
 func (p *Protocols) setBit(bit uint8, ok bool) {
 	if ok {
@@ -88,7 +94,7 @@ func (p Protocols) String() string {
 	if p.UnencryptedHTTP2() {
 		s = append(s, "UnencryptedHTTP2")
 	}
-	if p.http3() {
+	if p.HTTP3() {
 		s = append(s, "HTTP3")
 	}
 	return "{" + strings.Join(s, ",") + "}"
@@ -112,6 +118,10 @@ var aLongTimeAgo = time.Unix(1, 0)
 // shouldn't try to use it.
 var omitBundledHTTP2 bool
 
+// omitHTTP3 is set by omithttp3.go when the nethttpomithttp3 build tag
+// is set, and HTTP/3 is not supported.
+var omitHTTP3 bool
+
 // TODO(bradfitz): move common stuff here. The other files have accumulated
 // generic http stuff in random places.
 
@@ -321,6 +321,7 @@ type transportConfig struct {
 
 func (t transportConfig) MaxResponseHeaderBytes() int64        { return t.t.MaxResponseHeaderBytes }
 func (t transportConfig) DisableCompression() bool             { return t.t.DisableCompression }
+func (t transportConfig) EnableZstd() bool                     { return t.t.EnableZstd }
 func (t transportConfig) DisableKeepAlives() bool              { return t.t.DisableKeepAlives }
 func (t transportConfig) ExpectContinueTimeout() time.Duration { return t.t.ExpectContinueTimeout }
 func (t transportConfig) ResponseHeaderTimeout() time.Duration { return t.t.ResponseHeaderTimeout }
@@ -0,0 +1,203 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !nethttpomithttp3
+
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http/internal/http3"
+	"net/url"
+	"strconv"
+)
+
+// HTTP/3 support is provided by the net/http/internal/http3 package.
+//
+// This file (http3.go) connects net/http to the http3 package,
+// translating http package types (e.g., Request) into the equivalent
+// http3 package types (e.g., http3.ServerRequest), as http2.go does
+// for HTTP/2.
+//
+// HTTP/3 is never enabled by default. A Server serves HTTP/3 over UDP from
+// ListenAndServeTLS when its Protocols include HTTP3, and advertises it to
+// HTTP/1 and HTTP/2 clients with an Alt-Svc header. A Transport uses HTTP/3
+// when its Protocols include HTTP3: exclusively if HTTP3 is the only
+// protocol, and otherwise for origins which have advertised h3 in an
+// Alt-Svc response header.
+//
+// The nethttpomithttp3 build tag omits HTTP/3 support, along with the
+// QUIC implementation it is built on; see omithttp3.go.
+
+func init() {
+	// These values need to be the same in the http and http3 packages.
+	// See the comment in http2.go.
+	http3.NoBody = NoBody
+	http3.ErrContentLength = ErrContentLength
+	http3.LocalAddrContextKey = LocalAddrContextKey
+}
+
+type http3Server = http3.Server
+
+// listenHTTP3 opens the UDP endpoint for HTTP/3 on addr.
+// Connections are served on the endpoint by serveHTTP3.
+func (s *Server) listenHTTP3(addr string, config *tls.Config, advertise bool) (*http3Server, error) {
+	h3srv := &http3.Server{
+		Handler:        http3Handler{serverHandler{s}},
+		TLSConfig:      config,
+		BaseContext:    context.WithValue(context.Background(), ServerContextKey, s),
+		ErrorLog:       s.ErrorLog,
+		MaxHeaderBytes: s.MaxHeaderBytes,
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shuttingDown() {
+		return nil, ErrServerClosed
+	}
+	la, err := h3srv.Listen(addr)
+	if err != nil {
+		return nil, err
+	}
+	s.h3srv = h3srv
+	if advertise {
+		v := `h3=":` + strconv.Itoa(int(la.Port())) + `"`
+		s.h3AltSvc.Store(&v)
+	}
+	if fn := testHookHTTP3Listen; fn != nil {
+		fn(s, net.UDPAddrFromAddrPort(la))
+	}
+	return h3srv, nil
+}
+
+// serveHTTP3 serves connections on the endpoint of h3srv until it is
+// closed, and returns ErrServerClosed after Shutdown or Close.
+func (s *Server) serveHTTP3(h3srv *http3Server) error {
+	err := h3srv.Serve()
+	if s.shuttingDown() {
+		return ErrServerClosed
+	}
+	return err
+}
+
+type http3Handler struct {
+	h Handler
+}
+
+func (h http3Handler) ServeHTTP(w *http3.ResponseWriter, req *http3.ServerRequest) {
+	h.h.ServeHTTP(http3ResponseWriter{w}, &Request{
+		ctx:           req.Context,
+		Proto:         "HTTP/3.0",
+		ProtoMajor:    3,
+		ProtoMinor:    0,
+		Method:        req.Method,
+		URL:           req.URL,
+		Header:        Header(req.Header),
+		RequestURI:    req.RequestURI,
+		Trailer:       Header(req.Trailer),
+		Body:          req.Body,
+		Host:          req.Host,
+		ContentLength: req.ContentLength,
+		RemoteAddr:    req.RemoteAddr,
+		TLS:           req.TLS,
+	})
+}
+
+type http3ResponseWriter struct {
+	*http3.ResponseWriter
+}
+
+// Optional http.ResponseWriter interfaces implemented.
+var _ Flusher = http3ResponseWriter{}
+
+func (w http3ResponseWriter) Flush()            { w.ResponseWriter.FlushError() }
+func (w http3ResponseWriter) FlushError() error { return w.ResponseWriter.FlushError() }
+
+func (w http3ResponseWriter) Header() Header { return Header(w.ResponseWriter.Header()) }
+
+func (t *Transport) configureHTTP3() {
+	t.h3transport = http3Transport{http3.NewTransport(t.TLSClientConfig, http3TransportConfig{t})}
+}
+
+// http3TransportConfig implements the http3.TransportConfig interface.
+type http3TransportConfig struct {
+	t *Transport
+}
+
+func (t http3TransportConfig) MaxResponseHeaderBytes() int64 { return t.t.MaxResponseHeaderBytes }
+func (t http3TransportConfig) DisableCompression() bool      { return t.t.DisableCompression }
+func (t http3TransportConfig) EnableZstd() bool              { return t.t.EnableZstd }
+
+// http3Transport implements dialClientConner using the http3 package.
+type http3Transport struct {
+	t3 *http3.Transport
+}
+
+func (t http3Transport) RoundTrip(*Request) (*Response, error) {
+	panic("http3Transport.RoundTrip should never be called")
+}
+
+func (t http3Transport) DialClientConn(ctx context.Context, address string, proxy *url.URL, internalStateHook func()) (RoundTripper, error) {
+	if proxy != nil {
+		return nil, fmt.Errorf("http: HTTP/3 through a proxy: %w", errors.ErrUnsupported)
+	}
+	cc, err := t.t3.Dial(ctx, address, internalStateHook)
+	if err != nil {
+		return nil, err
+	}
+	return http3ClientConn{cc}, nil
+}
+
+func (t http3Transport) CloseIdleConnections() {
+	t.t3.CloseIdleConnections()
+}
+
+type http3ClientConn struct {
+	*http3.ClientConn
+}
+
+func (cc http3ClientConn) RoundTrip(req *Request) (*Response, error) {
+	cresp, err := cc.ClientConn.RoundTrip(&http3.ClientRequest{
+		Context:       req.Context(),
+		Method:        req.Method,
+		URL:           req.URL,
+		Header:        http3.Header(req.Header),
+		Trailer:       http3.Header(req.Trailer),
+		Body:          req.Body,
+		Host:          req.Host,
+		ContentLength: req.ContentLength,
+	})
+	if err != nil {
+		if err == http3.ErrNoCachedConn {
+			err = http3NoCachedConnError{}
+		}
+		return nil, err
+	}
+	return &Response{
+		Status:        strconv.Itoa(cresp.StatusCode) + " " + StatusText(cresp.StatusCode),
+		StatusCode:    cresp.StatusCode,
+		Proto:         "HTTP/3.0",
+		ProtoMajor:    3,
+		ProtoMinor:    0,
+		ContentLength: cresp.ContentLength,
+		Uncompressed:  cresp.Uncompressed,
+		Header:        Header(cresp.Header),
+		Trailer:       Header(cresp.Trailer),
+		Body:          cresp.Body,
+		TLS:           cresp.TLS,
+		Request:       req,
+	}, nil
+}
+
+// http3NoCachedConnError is returned when an HTTP/3 connection in the
+// Transport's pool can no longer be used and the request was not sent.
+// It is recognized by http2isNoCachedConnError, which causes the
+// Transport to discard the connection and retry the request.
+type http3NoCachedConnError struct{}
+
+func (http3NoCachedConnError) IsHTTP2NoCachedConnError() {}
+func (http3NoCachedConnError) Error() string             { return http3.ErrNoCachedConn.Error() }
@@ -0,0 +1,212 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"io"
+	"net"
+	. "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTP3RoundTrip(t *testing.T) {
+	cst := newClientServerTest(t, http3Mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		if r.ProtoMajor != 3 {
+			t.Errorf("server: request Proto = %q, want HTTP/3.0", r.Proto)
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("server: reading body: %v", err)
+		}
+		w.Header().Set("Trailer", "X-Trailer")
+		w.Header().Set("X-Method", r.Method)
+		io.WriteString(w, "got "+string(body))
+		w.Header().Set("X-Trailer", "done")
+	}))
+
+	res, err := cst.c.Post(cst.ts.URL, "text/plain", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := res.Proto, "HTTP/3.0"; got != want {
+		t.Errorf("response Proto = %q, want %q", got, want)
+	}
+	if got, want := string(body), "got hello"; got != want {
+		t.Errorf("response body = %q, want %q", got, want)
+	}
+	if got, want := res.Header.Get("X-Method"), "POST"; got != want {
+		t.Errorf("X-Method = %q, want %q", got, want)
+	}
+	if got, want := res.Trailer.Get("X-Trailer"), "done"; got != want {
+		t.Errorf("X-Trailer = %q, want %q", got, want)
+	}
+	if res.TLS == nil {
+		t.Errorf("response TLS = nil, want connection state")
+	}
+}
+
+func TestHTTP3AltSvc(t *testing.T) {
+	CondSkipHTTP3(t)
+	h := HandlerFunc(func(w ResponseWriter, r *Request) {
+		io.WriteString(w, r.Proto)
+	})
+	ts := httptest.NewUnstartedServer(h)
+	ts.StartTLS()
+	defer ts.Close()
+
+	srv := &Server{
+		Addr:      "localhost:0",
+		Handler:   h,
+		TLSConfig: ts.TLS,
+		Protocols: &Protocols{},
+	}
+	srv.Protocols.SetHTTP1(true)
+	srv.Protocols.SetHTTP2(true)
+	srv.Protocols.SetHTTP3(true)
+	listenAddrCh := HTTP3ListenAddrForTesting(srv)
+
+	// Serve TCP on the httptest listener, so the test knows its address,
+	// and HTTP/3 on the UDP endpoint opened by ListenAndServeTLS.
+	errc := make(chan error, 1)
+	srv.Addr = ts.Listener.Addr().String()
+	ts.Listener.Close()
+	go func() { errc <- srv.ListenAndServeTLS("", "") }()
+	select {
+	case <-listenAddrCh:
+	case err := <-errc:
+		t.Fatalf("ListenAndServeTLS: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	}()
+
+	tr := ts.Client().Transport.(*Transport).Clone()
+	defer tr.CloseIdleConnections()
+	tr.Protocols = &Protocols{}
+	tr.Protocols.SetHTTP1(true)
+	tr.Protocols.SetHTTP2(true)
+	tr.Protocols.SetHTTP3(true)
+	c := &Client{Transport: tr}
+
+	get := func() string {
+		t.Helper()
+		var res *Response
+		var err error
+		// The TCP listener may not be accepting connections yet.
+		for range 50 {
+			res, err = c.Get("https://" + srv.Addr)
+			if err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		if _, err := io.Copy(io.Discard, res.Body); err != nil {
+			t.Fatal(err)
+		}
+		return res.Proto
+	}
+
+	if got := get(); got == "HTTP/3.0" {
+		t.Errorf("first request Proto = %q, want a TCP-based protocol", got)
+	}
+	if tr.AltSvcForTesting(srv.Addr) == "" {
+		t.Fatalf("Transport did not record the Alt-Svc advertisement")
+	}
+	if got, want := get(), "HTTP/3.0"; got != want {
+		t.Errorf("second request Proto = %q, want %q", got, want)
+	}
+}
+
+func TestHTTP3ListenAndServeTLSAddrInUse(t *testing.T) {
+	CondSkipHTTP3(t)
+	ts := httptest.NewUnstartedServer(NotFoundHandler())
+	ts.StartTLS()
+	defer ts.Close()
+
+	// ListenAndServeTLS opens the HTTP/3 endpoint, and then fails to
+	// listen on the TCP port, which is in use by the httptest server.
+	srv := &Server{
+		Addr:      ts.Listener.Addr().String(),
+		TLSConfig: ts.TLS,
+		Protocols: &Protocols{},
+	}
+	srv.Protocols.SetHTTP1(true)
+	srv.Protocols.SetHTTP3(true)
+	listenAddrCh := HTTP3ListenAddrForTesting(srv)
+	if err := srv.ListenAndServeTLS("", ""); err == nil || err == ErrServerClosed {
+		t.Fatalf("ListenAndServeTLS = %v, want an error listening on the TCP port", err)
+	}
+
+	// The HTTP/3 endpoint has been closed.
+	udpAddr := <-listenAddrCh
+	pc, err := net.ListenPacket("udp", udpAddr)
+	if err != nil {
+		t.Fatalf("HTTP/3 endpoint still open: %v", err)
+	}
+	pc.Close()
+}
+
+func TestParseAltSvcH3(t *testing.T) {
+	for _, test := range []struct {
+		host       string
+		values     []string
+		wantAddr   string
+		wantMaxAge time.Duration
+		wantClear  bool
+	}{{
+		host:       "example.com",
+		values:     []string{`h3=":443"`},
+		wantAddr:   "example.com:443",
+		wantMaxAge: 24 * time.Hour,
+	}, {
+		host:       "example.com",
+		values:     []string{`h2=":443", h3=":8443"; ma=60`},
+		wantAddr:   "example.com:8443",
+		wantMaxAge: 60 * time.Second,
+	}, {
+		host:       "example.com",
+		values:     []string{`h3-29=":443"`, `h3="example.com:443"; persist=1; ma=3600`},
+		wantAddr:   "example.com:443",
+		wantMaxAge: time.Hour,
+	}, {
+		// Alternatives on other hosts are not used.
+		host:   "example.com",
+		values: []string{`h3="other.example.com:443"`},
+	}, {
+		host:   "example.com",
+		values: []string{`h3=":0"`, `h3=443`, `h3=":x"`},
+	}, {
+		host:      "example.com",
+		values:    []string{`clear`},
+		wantClear: true,
+	}, {
+		host:       "example.com",
+		values:     []string{`h3=":443"; ma=999999999999`},
+		wantAddr:   "example.com:443",
+		wantMaxAge: 30 * 24 * time.Hour,
+	}} {
+		addr, maxAge, clear := ExportParseAltSvcH3(test.host, test.values)
+		if addr != test.wantAddr || maxAge != test.wantMaxAge || clear != test.wantClear {
+			t.Errorf("parseAltSvcH3(%q, %q) = %q, %v, %v; want %q, %v, %v",
+				test.host, test.values, addr, maxAge, clear,
+				test.wantAddr, test.wantMaxAge, test.wantClear)
+		}
+	}
+}
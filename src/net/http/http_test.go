@@ -106,6 +106,30 @@ func TestOmitHTTP2Vet(t *testing.T) {
 	}
 }
 
+// Tests that the nethttpomithttp3 build tag doesn't rot too much.
+func TestOmitHTTP3(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+	t.Parallel()
+	goTool := testenv.GoToolPath(t)
+	out, err := testenv.Command(t, goTool, "test", "-short", "-tags=nethttpomithttp3", "net/http").CombinedOutput()
+	if err != nil {
+		t.Fatalf("go test -short failed: %v, %s", err, out)
+	}
+}
+
+// Tests that the nethttpomithttp3 build tag at least type checks
+// in short mode.
+func TestOmitHTTP3Vet(t *testing.T) {
+	t.Parallel()
+	goTool := testenv.GoToolPath(t)
+	out, err := testenv.Command(t, goTool, "vet", "-tags=nethttpomithttp3", "net/http").CombinedOutput()
+	if err != nil {
+		t.Fatalf("go vet failed: %v, %s", err, out)
+	}
+}
+
 var valuesCount int
 
 func BenchmarkCopyValues(b *testing.B) {
@@ -0,0 +1,153 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httptest
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// A Fault describes failures for a [Server] to inject into its
+// response to a request. See [Server.RequestFault].
+//
+// The zero Fault injects no failures.
+//
+// Faults wait using the time package's timers, so a Server started
+// within a [testing/synctest] bubble, with a Listener which does not
+// use the network, waits using the bubble's fake clock.
+type Fault struct {
+	// Delay is how long the server waits before passing the
+	// request to its handler. The server stops waiting
+	// if the request's context is done.
+	Delay time.Duration
+
+	// DripInterval, if non-zero, makes the server write the response
+	// body slowly: in pieces of at most DripSize bytes, waiting
+	// DripInterval before writing each piece and flushing it after.
+	// If DripSize is zero, the pieces are a single byte.
+	DripInterval time.Duration
+	DripSize     int
+
+	// Truncate, if true, makes the server abandon the response after
+	// writing TruncateAfter bytes of the response body, so that the
+	// client receives a truncated response. If the handler writes
+	// fewer bytes, the response is abandoned when the handler returns.
+	//
+	// An abandoned HTTP/1 response is ended by closing its connection,
+	// and an abandoned HTTP/2 response by resetting its stream.
+	Truncate      bool
+	TruncateAfter int64
+
+	// Reset, if true, makes the server end an HTTP/1 response
+	// abandoned by Truncate by resetting the TCP connection,
+	// rather than closing it.
+	Reset bool
+}
+
+// errInjectedFault is the error with which a Server fails
+// a TLS handshake for its FailTLSHandshake function.
+var errInjectedFault = errors.New("httptest: injected fault")
+
+// faultHandler returns a handler which serves requests using h,
+// injecting the faults returned by s.RequestFault.
+func (s *Server) faultHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f := s.RequestFault(r)
+		if f == nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if f.Delay > 0 {
+			timer := time.NewTimer(f.Delay)
+			select {
+			case <-timer.C:
+			case <-r.Context().Done():
+				timer.Stop()
+				return
+			}
+		}
+		if f.DripInterval <= 0 && !f.Truncate {
+			h.ServeHTTP(w, r)
+			return
+		}
+		fw := &faultWriter{ResponseWriter: w, f: f}
+		h.ServeHTTP(fw, r)
+		if f.Truncate {
+			fw.abandon()
+		}
+	})
+}
+
+// A faultWriter is a ResponseWriter which
+// injects the body faults described by f.
+type faultWriter struct {
+	http.ResponseWriter
+	f       *Fault
+	written int64 // body bytes written
+}
+
+func (fw *faultWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		chunk := p
+		if fw.f.Truncate {
+			remain := fw.f.TruncateAfter - fw.written
+			if remain <= 0 {
+				fw.abandon()
+			}
+			if int64(len(chunk)) > remain {
+				chunk = chunk[:remain]
+			}
+		}
+		if fw.f.DripInterval > 0 {
+			chunk = chunk[:min(len(chunk), max(fw.f.DripSize, 1))]
+			time.Sleep(fw.f.DripInterval)
+		}
+		m, err := fw.ResponseWriter.Write(chunk)
+		n += m
+		fw.written += int64(m)
+		if err != nil {
+			return n, err
+		}
+		if fw.f.DripInterval > 0 {
+			if err := http.NewResponseController(fw.ResponseWriter).Flush(); err != nil {
+				return n, err
+			}
+		}
+		p = p[m:]
+	}
+	return n, nil
+}
+
+func (fw *faultWriter) Flush() {
+	http.NewResponseController(fw.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying ResponseWriter,
+// for use by ResponseController.
+func (fw *faultWriter) Unwrap() http.ResponseWriter {
+	return fw.ResponseWriter
+}
+
+// abandon sends what has been written of the response,
+// and aborts the handler.
+func (fw *faultWriter) abandon() {
+	rc := http.NewResponseController(fw.ResponseWriter)
+	rc.Flush()
+	if fw.f.Reset {
+		if conn, _, err := rc.Hijack(); err == nil {
+			if tc, ok := conn.(*tls.Conn); ok {
+				conn = tc.NetConn()
+			}
+			if tc, ok := conn.(*net.TCPConn); ok {
+				tc.SetLinger(0)
+			}
+			conn.Close()
+		}
+	}
+	panic(http.ErrAbortHandler)
+}
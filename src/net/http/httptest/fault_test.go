@@ -0,0 +1,217 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httptest
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// pipeListener is a net.Listener which accepts connections
+// made with net.Pipe, so that a Server using it can be
+// tested within a synctest bubble.
+type pipeListener struct {
+	mu     sync.Mutex
+	closed bool
+	ch     chan net.Conn
+	done   chan struct{}
+}
+
+func newPipeListener() *pipeListener {
+	return &pipeListener{ch: make(chan net.Conn), done: make(chan struct{})}
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.ch:
+		return c, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *pipeListener) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.closed {
+		l.closed = true
+		close(l.done)
+	}
+	return nil
+}
+
+func (l *pipeListener) Addr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 80}
+}
+
+func (l *pipeListener) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	c1, c2 := net.Pipe()
+	select {
+	case l.ch <- c1:
+		return c2, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// newFaultServer starts a server using a pipeListener,
+// which responds with body and injects the faults returned by fault.
+func newFaultServer(t *testing.T, body string, fault func(*http.Request) *Fault) *Server {
+	l := newPipeListener()
+	s := &Server{
+		Listener: l,
+		Config: &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, body)
+		})},
+		RequestFault: fault,
+	}
+	s.Start()
+	s.client.Transport.(*http.Transport).DialContext = l.DialContext
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestFaultDelay(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s := newFaultServer(t, "hello", func(r *http.Request) *Fault {
+			return &Fault{Delay: 5 * time.Second}
+		})
+		start := time.Now()
+		res, err := s.Client().Get(s.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil || string(b) != "hello" {
+			t.Errorf("body = %q, %v; want %q", b, err, "hello")
+		}
+		if d := time.Since(start); d != 5*time.Second {
+			t.Errorf("response took %v, want 5s", d)
+		}
+
+		// The client gives up before the delay ends.
+		s.Client().Timeout = time.Second
+		if _, err := s.Client().Get(s.URL); err == nil {
+			t.Errorf("Get with timeout shorter than delay succeeded")
+		}
+	})
+}
+
+func TestFaultDrip(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s := newFaultServer(t, "0123456789", func(r *http.Request) *Fault {
+			return &Fault{DripInterval: time.Second, DripSize: 3}
+		})
+		start := time.Now()
+		res, err := s.Client().Get(s.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		if d := time.Since(start); d != time.Second {
+			t.Errorf("response header took %v, want 1s", d)
+		}
+		var got []string
+		buf := make([]byte, 10)
+		for {
+			n, err := res.Body.Read(buf)
+			if n > 0 {
+				got = append(got, string(buf[:n])+"@"+time.Since(start).String())
+			}
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if want := "012@1s 345@2s 678@3s 9@4s"; strings.Join(got, " ") != want {
+			t.Errorf("read %q, want %q", strings.Join(got, " "), want)
+		}
+	})
+}
+
+func TestFaultTruncate(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s := newFaultServer(t, "0123456789", func(r *http.Request) *Fault {
+			if r.URL.Path == "/ok" {
+				return nil
+			}
+			return &Fault{Truncate: true, TruncateAfter: 4}
+		})
+		res, err := s.Client().Get(s.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if string(b) != "0123" || !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("truncated body = %q, %v; want %q, unexpected EOF", b, err, "0123")
+		}
+
+		res, err = s.Client().Get(s.URL + "/ok")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err = io.ReadAll(res.Body)
+		res.Body.Close()
+		if string(b) != "0123456789" || err != nil {
+			t.Errorf("body = %q, %v; want %q", b, err, "0123456789")
+		}
+	})
+}
+
+func TestFaultReset(t *testing.T) {
+	s := NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "10")
+		io.WriteString(w, "0123456789")
+	}))
+	s.RequestFault = func(r *http.Request) *Fault {
+		return &Fault{Truncate: true, TruncateAfter: 4, Reset: true}
+	}
+	s.Start()
+	defer s.Close()
+	res, err := s.Client().Get(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if b, err := io.ReadAll(res.Body); err == nil {
+		t.Errorf("reading reset body = %q, want error", b)
+	}
+}
+
+func TestFaultTLSHandshake(t *testing.T) {
+	s := NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	s.Config.ErrorLog = log.New(io.Discard, "", 0)
+	var fail atomic.Bool
+	fail.Store(true)
+	s.FailTLSHandshake = func(*tls.ClientHelloInfo) bool { return fail.Load() }
+	s.StartTLS()
+	defer s.Close()
+	if _, err := s.Client().Get(s.URL); err == nil {
+		t.Errorf("Get with failing handshake succeeded")
+	}
+	fail.Store(false)
+	res, err := s.Client().Get(s.URL)
+	if err != nil {
+		t.Fatalf("Get after handshakes stopped failing: %v", err)
+	}
+	res.Body.Close()
+}
@@ -0,0 +1,310 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httptest
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// When writing a test which uses a ReplayTransport,
+// this flag lets you run
+//
+//	go test -run='^TestAPI$' -httptest.record='api\.replay'
+//
+// to record the interactions in the files matching the regular
+// expression, rather than replaying them.
+var recordFlag string
+
+func init() {
+	if strSliceContainsPrefix(os.Args, "-httptest.record=") || strSliceContainsPrefix(os.Args, "--httptest.record=") {
+		flag.StringVar(&recordFlag, "httptest.record", "", "if non-empty, a `regexp` matching the ReplayTransport files to record")
+	}
+}
+
+// replayHeader is the first line of a ReplayTransport file.
+const replayHeader = "httptest replay v1\n"
+
+// A ReplayTransport is an [http.RoundTripper] for testing clients of
+// HTTP servers which are not available in tests, such as third-party
+// APIs. It either records requests and their responses in a file, or
+// replays the recorded responses without making any requests.
+//
+// A ReplayTransport records when the test binary is run with the
+// -httptest.record flag set to a regular expression matching the
+// name of its file, and replays otherwise:
+//
+//	go test -run='^TestAPI$' -httptest.record='testdata/api\.replay'
+//
+// The file holds each request and response in HTTP/1.1 wire format,
+// as produced by [net/http/httputil.DumpRequestOut] and
+// [net/http/httputil.DumpResponse], preceded by a line giving their
+// lengths. Recorded requests are written with absolute URLs.
+//
+// When replaying, each request is matched against the recorded
+// requests in order, and receives the response to the first recorded
+// request which matches it and has not already been replayed.
+// A request which matches no recorded request fails.
+type ReplayTransport struct {
+	// Match reports whether a request matches a recorded request.
+	// The Body of each is an unread copy of the request body.
+	// If Match is nil, requests match when they have the same
+	// method, URL and body.
+	//
+	// Match must be set before the ReplayTransport is used.
+	Match func(req, recorded *http.Request) bool
+
+	// RedactHeaders lists request and response headers whose values
+	// are replaced with "REDACTED" when a request is recorded. The
+	// request sent and the response returned are not changed.
+	// If RedactHeaders is nil, the Authorization, Proxy-Authorization,
+	// Cookie and Set-Cookie headers are redacted.
+	//
+	// RedactHeaders must be set before the ReplayTransport is used.
+	RedactHeaders []string
+
+	file string
+	rt   http.RoundTripper // nil when replaying
+
+	mu      sync.Mutex
+	w       *os.File      // file being recorded
+	entries []replayEntry // entries being replayed
+}
+
+// A replayEntry is a recorded request and response.
+type replayEntry struct {
+	req      []byte // request in wire format
+	resp     []byte // response in wire format
+	replayed bool
+}
+
+// NewReplayTransport returns a [ReplayTransport] which records
+// requests made using the transport rt, or [http.DefaultTransport]
+// if rt is nil, and their responses in file, or which replays the
+// responses recorded in file.
+//
+// When recording, NewReplayTransport creates or truncates the file.
+// The caller must call [ReplayTransport.Close] when finished.
+// When replaying, NewReplayTransport reads the file.
+func NewReplayTransport(file string, rt http.RoundTripper) (*ReplayTransport, error) {
+	t := &ReplayTransport{file: file}
+	if recordFlag != "" {
+		re, err := regexp.Compile(recordFlag)
+		if err != nil {
+			return nil, fmt.Errorf("httptest: invalid -httptest.record flag: %v", err)
+		}
+		if re.MatchString(file) {
+			if rt == nil {
+				rt = http.DefaultTransport
+			}
+			t.rt = rt
+			if t.w, err = os.Create(file); err != nil {
+				return nil, err
+			}
+			if _, err := io.WriteString(t.w, replayHeader); err != nil {
+				t.w.Close()
+				return nil, err
+			}
+			return t, nil
+		}
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if t.entries, err = parseReplay(data); err != nil {
+		return nil, fmt.Errorf("httptest: reading %s: %v", file, err)
+	}
+	return t, nil
+}
+
+// parseReplay parses the contents of a ReplayTransport file.
+func parseReplay(data []byte) ([]replayEntry, error) {
+	rest, ok := bytes.CutPrefix(data, []byte(replayHeader))
+	if !ok {
+		return nil, errors.New("not a replay file")
+	}
+	var entries []replayEntry
+	for len(rest) > 0 {
+		line, after, ok := bytes.Cut(rest, []byte("\n"))
+		if !ok {
+			return nil, errors.New("truncated file")
+		}
+		reqLen, respLen, ok := strings.Cut(string(line), " ")
+		n1, err1 := strconv.Atoi(reqLen)
+		n2, err2 := strconv.Atoi(respLen)
+		if !ok || err1 != nil || err2 != nil || n1 < 0 || n2 < 0 || n1+n2 > len(after) {
+			return nil, fmt.Errorf("invalid entry header %q", line)
+		}
+		entries = append(entries, replayEntry{req: after[:n1], resp: after[n1 : n1+n2]})
+		rest = after[n1+n2:]
+	}
+	return entries, nil
+}
+
+// Recording reports whether t is recording, rather than replaying.
+func (t *ReplayTransport) Recording() bool {
+	return t.rt != nil
+}
+
+// Close closes the file being recorded.
+// It does nothing when t is replaying.
+func (t *ReplayTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.w == nil {
+		return nil
+	}
+	err := t.w.Close()
+	t.w = nil
+	return err
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if t.Recording() {
+		return t.record(req, body)
+	}
+	return t.replay(req, body)
+}
+
+// withBody returns a shallow copy of req with the given body.
+func withBody(req *http.Request, body []byte) *http.Request {
+	r := req.Clone(req.Context())
+	r.Body = http.NoBody
+	if len(body) > 0 {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	r.ContentLength = int64(len(body))
+	return r
+}
+
+func (t *ReplayTransport) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := t.rt.RoundTrip(withBody(req, body))
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	rec := withBody(req, body)
+	rec.Header = t.redact(rec.Header)
+	rec.TransferEncoding = nil
+	rec.Close = false
+	var reqBuf bytes.Buffer
+	if err := rec.WriteProxy(&reqBuf); err != nil {
+		return nil, err
+	}
+
+	recResp := *resp
+	recResp.Header = t.redact(resp.Header)
+	recResp.Trailer = t.redact(resp.Trailer)
+	recResp.Proto, recResp.ProtoMajor, recResp.ProtoMinor = "HTTP/1.1", 1, 1
+	recResp.Body = io.NopCloser(bytes.NewReader(respBody))
+	recResp.ContentLength = int64(len(respBody))
+	recResp.TransferEncoding = nil
+	recResp.Close = false
+	var respBuf bytes.Buffer
+	if err := recResp.Write(&respBuf); err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.w == nil {
+		return nil, errors.New("httptest: ReplayTransport used after Close")
+	}
+	if _, err := fmt.Fprintf(t.w, "%d %d\n%s%s", reqBuf.Len(), respBuf.Len(), reqBuf.Bytes(), respBuf.Bytes()); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// redact returns a copy of h with the headers in t.RedactHeaders redacted.
+func (t *ReplayTransport) redact(h http.Header) http.Header {
+	h = h.Clone()
+	names := t.RedactHeaders
+	if names == nil {
+		names = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+	}
+	for _, name := range names {
+		key := http.CanonicalHeaderKey(name)
+		if _, ok := h[key]; ok {
+			h[key] = []string{"REDACTED"}
+		}
+	}
+	return h
+}
+
+func (t *ReplayTransport) replay(req *http.Request, body []byte) (*http.Response, error) {
+	match := t.Match
+	if match == nil {
+		match = defaultReplayMatch
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i := range t.entries {
+		e := &t.entries[i]
+		if e.replayed {
+			continue
+		}
+		recorded, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(e.req)))
+		if err != nil {
+			return nil, fmt.Errorf("httptest: reading %s: %v", t.file, err)
+		}
+		recordedBody, err := io.ReadAll(recorded.Body)
+		if err != nil {
+			return nil, fmt.Errorf("httptest: reading %s: %v", t.file, err)
+		}
+		if !match(withBody(req, body), withBody(recorded, recordedBody)) {
+			continue
+		}
+		resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(e.resp)), req)
+		if err != nil {
+			return nil, fmt.Errorf("httptest: reading %s: %v", t.file, err)
+		}
+		e.replayed = true
+		return resp, nil
+	}
+	return nil, fmt.Errorf("httptest: no recorded response in %s for %s %s", t.file, req.Method, req.URL)
+}
+
+// defaultReplayMatch reports whether req and recorded have
+// the same method, URL and body.
+func defaultReplayMatch(req, recorded *http.Request) bool {
+	if req.Method != recorded.Method || req.URL.String() != recorded.URL.String() {
+		return false
+	}
+	b1, err1 := io.ReadAll(req.Body)
+	b2, err2 := io.ReadAll(recorded.Body)
+	return err1 == nil && err2 == nil && bytes.Equal(b1, b2)
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httptest
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// replayDo makes a request using rt, and returns the response's
+// Set-Cookie header and body separated by "|".
+func replayDo(t *testing.T, rt http.RoundTripper, method, url, body string, header ...string) (string, error) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	res, err := (&http.Client{Transport: rt}).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.Header.Get("Set-Cookie") + "|" + string(b), nil
+}
+
+func TestReplayTransport(t *testing.T) {
+	n := 0
+	ts := NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret"})
+		b, _ := io.ReadAll(r.Body)
+		auth := "noauth"
+		if r.Header.Get("Authorization") == "token" {
+			auth = "auth"
+		}
+		io.WriteString(w, r.Method+" "+r.URL.Path+" "+string(b)+" "+auth+" "+strings.Repeat("x", n))
+	}))
+	defer ts.Close()
+	file := filepath.Join(t.TempDir(), "test.replay")
+
+	type request struct {
+		method, path, body string
+		want               string
+	}
+	requests := []request{
+		{"GET", "/a", "", "session=secret|GET /a  auth x"},
+		{"POST", "/b", "body", "session=secret|POST /b body auth xx"},
+		{"GET", "/a", "", "session=secret|GET /a  auth xxx"},
+	}
+
+	recordFlag = `test\.replay$`
+	rt, err := NewReplayTransport(file, ts.Client().Transport)
+	recordFlag = ""
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rt.Recording() {
+		t.Fatal("Recording() = false with matching -httptest.record flag, want true")
+	}
+	for _, r := range requests {
+		got, err := replayDo(t, rt, r.method, ts.URL+r.path, r.body, "Authorization", "token")
+		if err != nil || got != r.want {
+			t.Errorf("recording %s %s: got %q, %v; want %q", r.method, r.path, got, err, r.want)
+		}
+	}
+	if err := rt.Close(); err != nil {
+		t.Fatal(err)
+	}
+	ts.Close()
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "token") || strings.Contains(string(data), "secret") {
+		t.Errorf("recorded file contains redacted values:\n%s", data)
+	}
+
+	rt, err = NewReplayTransport(file, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rt.Recording() {
+		t.Fatal("Recording() = true without -httptest.record flag, want false")
+	}
+	// Requests are matched in order, and each response is replayed once.
+	for _, i := range []int{1, 0, 2} {
+		r := requests[i]
+		want := strings.ReplaceAll(r.want, "session=secret", "REDACTED")
+		got, err := replayDo(t, rt, r.method, ts.URL+r.path, r.body)
+		if err != nil || got != want {
+			t.Errorf("replaying %s %s: got %q, %v; want %q", r.method, r.path, got, err, want)
+		}
+	}
+	if _, err := replayDo(t, rt, "GET", ts.URL+"/a", ""); err == nil {
+		t.Errorf("replaying request a third time succeeded, want error")
+	}
+	if _, err := replayDo(t, rt, "POST", ts.URL+"/b", "other"); err == nil {
+		t.Errorf("replaying request with different body succeeded, want error")
+	}
+
+	// A custom Match function.
+	rt, err = NewReplayTransport(file, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt.Match = func(req, recorded *http.Request) bool {
+		return req.Method == recorded.Method
+	}
+	want := "REDACTED|POST /b body auth xx"
+	if got, err := replayDo(t, rt, "POST", ts.URL+"/other", "other"); err != nil || got != want {
+		t.Errorf("replaying with custom Match: got %q, %v; want %q", got, err, want)
+	}
+}
+
+func TestReplayTransportBadFile(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewReplayTransport(filepath.Join(dir, "missing"), nil); err == nil {
+		t.Errorf("NewReplayTransport with missing file succeeded")
+	}
+	for _, data := range []string{
+		"not a replay file\n",
+		replayHeader + "1 2",
+		replayHeader + "100 100\nshort",
+		replayHeader + "-1 5\nhello",
+	} {
+		file := filepath.Join(dir, "bad")
+		if err := os.WriteFile(file, []byte(data), 0o666); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewReplayTransport(file, nil); err == nil {
+			t.Errorf("NewReplayTransport with contents %q succeeded", data)
+		}
+	}
+}
@@ -43,6 +43,20 @@ type Server struct {
 	// before Start or StartTLS.
 	Config *http.Server
 
+	// RequestFault, if non-nil, is called for each request the server
+	// receives, and returns the failures to inject into the response
+	// to the request, or nil to inject none. See [Fault].
+	// It must be set between calling NewUnstartedServer and
+	// calling Start or StartTLS.
+	RequestFault func(*http.Request) *Fault
+
+	// FailTLSHandshake, if non-nil, is called for each TLS handshake
+	// the server performs. If it returns true, the server fails the
+	// handshake by sending an alert to the client.
+	// It must be set between calling NewUnstartedServer and
+	// calling StartTLS.
+	FailTLSHandshake func(*tls.ClientHelloInfo) bool
+
 	// certificate is a parsed version of the TLS config certificate, if present.
 	certificate *x509.Certificate
 
@@ -190,6 +204,18 @@ func (s *Server) StartTLS() {
 	if len(s.TLS.Certificates) == 0 {
 		s.TLS.Certificates = []tls.Certificate{cert}
 	}
+	if fail := s.FailTLSHandshake; fail != nil {
+		getConfig := s.TLS.GetConfigForClient
+		s.TLS.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			if fail(hello) {
+				return nil, errInjectedFault
+			}
+			if getConfig != nil {
+				return getConfig(hello)
+			}
+			return nil, nil
+		}
+	}
 	s.certificate, err = x509.ParseCertificate(s.TLS.Certificates[0].Certificate[0])
 	if err != nil {
 		panic(fmt.Sprintf("httptest: NewTLSServer: %v", err))
@@ -354,8 +380,16 @@ func (s *Server) goServe() {
 }
 
 // wrap installs the connection state-tracking hook to know which
-// connections are idle.
+// connections are idle, and the fault-injecting handler.
 func (s *Server) wrap() {
+	if s.RequestFault != nil {
+		h := s.Config.Handler
+		if h == nil {
+			h = http.DefaultServeMux
+		}
+		s.Config.Handler = s.faultHandler(h)
+	}
+
 	oldHook := s.Config.ConnState
 	s.Config.ConnState = func(c net.Conn, cs http.ConnState) {
 		s.mu.Lock()
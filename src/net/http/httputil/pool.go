@@ -0,0 +1,487 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httputil
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNoBackend is returned by [Pool.RoundTrip] when no backend
+// in the pool is available to handle a request.
+var ErrNoBackend = errors.New("httputil: no available backend")
+
+// A BalancePolicy determines how a [Pool] chooses
+// a backend for each request.
+type BalancePolicy int
+
+const (
+	// RoundRobin chooses each available backend in turn.
+	RoundRobin BalancePolicy = iota
+
+	// LeastConnections chooses the available backend with the
+	// fewest requests in progress, breaking ties in turn.
+	LeastConnections
+
+	// ConsistentHash chooses a backend determined by the request's
+	// hash key, so that requests with the same key are sent to the
+	// same backend while it is available. The backends of a pool are
+	// fixed, but the keys of a backend which becomes unavailable, and
+	// only those, are sent to other backends until it is available
+	// again. Likewise, replacing a pool with one which has one more
+	// or one fewer backend changes the backend chosen for only a
+	// small fraction of keys.
+	ConsistentHash
+)
+
+// A Backend is a server in a [Pool].
+type Backend struct {
+	// URL is the base URL of the backend, as for [ProxyRequest.SetURL].
+	URL *url.URL
+
+	active    atomic.Int64
+	unhealthy atomic.Bool // the last health check failed
+
+	mu           sync.Mutex
+	fails        int       // consecutive failed requests
+	ejectedUntil time.Time // zero if not ejected
+}
+
+// ActiveRequests returns the number of requests
+// in progress to the backend.
+func (b *Backend) ActiveRequests() int {
+	return int(b.active.Load())
+}
+
+// Available reports whether the backend is receiving requests: that is,
+// whether it passed its last health check and is not ejected.
+func (b *Backend) Available() bool {
+	return b.available(time.Now())
+}
+
+func (b *Backend) available(now time.Time) bool {
+	if b.unhealthy.Load() {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !now.Before(b.ejectedUntil)
+}
+
+// A Pool is an [http.RoundTripper] which distributes requests
+// among a set of backends.
+//
+// To proxy requests to a pool, use the pool as the Transport of a
+// [ReverseProxy]. The pool sets the URL of each outbound request to
+// that of the chosen backend, as [ProxyRequest.SetURL] does, so the
+// proxy's Rewrite function need not set it. Unlike SetURL, the pool
+// does not change the outbound request's Host, which is that of the
+// inbound request unless Rewrite changes it; to send the backend's
+// host, Rewrite may set Out.Host to "":
+//
+//	proxy := &httputil.ReverseProxy{
+//		Rewrite: func(r *httputil.ProxyRequest) {
+//			r.SetXForwarded()
+//			r.Out.Host = ""
+//		},
+//		Transport: pool,
+//	}
+//
+// If no backend is
+// available, RoundTrip returns [ErrNoBackend], and the
+// proxy's ErrorHandler may check for it to respond with a status of
+// 503 Service Unavailable rather than 502 Bad Gateway.
+//
+// A backend is unavailable if it failed its last health check (see
+// [Pool.CheckHealth]), or if it has been ejected from the pool because
+// requests to it failed. A request fails if sending it returns an
+// error, or if the response has a status for which FailStatus reports
+// true, by default any 5xx status. When MaxFails consecutive requests
+// to a backend fail, it is ejected for EjectDuration.
+//
+// A failed response is nevertheless returned by RoundTrip, and the
+// proxy forwards it to the client. Only errors from RoundTrip, such as
+// ErrNoBackend or the error from the last backend tried, are passed to
+// the proxy's ErrorHandler, which has no effect on ejection.
+//
+// An idempotent request which fails with an error may be retried on
+// another backend. A request is idempotent if its method is GET, HEAD,
+// OPTIONS, or TRACE, or its Header contains an "Idempotency-Key" or
+// "X-Idempotency-Key" entry; a request with a body is retried only if
+// its GetBody field is set. Requests are not retried after their
+// context is done.
+//
+// The fields of a Pool must not be changed after it is first used.
+type Pool struct {
+	// Policy determines how backends are chosen.
+	Policy BalancePolicy
+
+	// HashKey returns the key of a request for the ConsistentHash policy.
+	// If nil, the host of the request's RemoteAddr is used.
+	HashKey func(*http.Request) string
+
+	// Transport is used to send requests to backends.
+	// If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	// MaxAttempts is the maximum number of backends tried for an
+	// idempotent request. If MaxAttempts is zero, 2 is used.
+	// A value of 1 disables retries.
+	MaxAttempts int
+
+	// MaxFails is the number of consecutive failed requests after
+	// which a backend is ejected from the pool.
+	// If MaxFails is zero, backends are not ejected.
+	MaxFails int
+
+	// FailStatus reports whether a response with the status code
+	// counts as a failed request to its backend.
+	// If FailStatus is nil, responses with a 5xx status code do.
+	FailStatus func(code int) bool
+
+	// EjectDuration is how long an ejected backend is unavailable.
+	// If EjectDuration is zero, 30 seconds is used.
+	EjectDuration time.Duration
+
+	// HealthCheckPath is the path, relative to each backend's URL,
+	// of the resource requested by CheckHealth. A backend passes a
+	// health check if the response has a 2xx or 3xx status code.
+	// If HealthCheckPath is empty, the backend's URL itself is used.
+	HealthCheckPath string
+
+	// HealthCheckTimeout is the time limit for each health check request.
+	// If HealthCheckTimeout is zero, 5 seconds is used.
+	HealthCheckTimeout time.Duration
+
+	backends []*Backend
+	next     atomic.Uint64 // round-robin counter
+
+	ringOnce sync.Once
+	ring     []ringEntry // sorted by hash
+}
+
+type ringEntry struct {
+	hash    uint64
+	backend *Backend
+}
+
+// ringReplicas is the number of points on the hash ring for each backend.
+const ringReplicas = 100
+
+// NewPool returns a new [Pool] which distributes requests
+// among backends with the given URLs.
+func NewPool(targets ...*url.URL) *Pool {
+	p := &Pool{}
+	for _, u := range targets {
+		p.backends = append(p.backends, &Backend{URL: u})
+	}
+	return p
+}
+
+// Backends returns the backends of the pool.
+func (p *Pool) Backends() []*Backend {
+	return slices.Clone(p.backends)
+}
+
+func (p *Pool) transport() http.RoundTripper {
+	if p.Transport != nil {
+		return p.Transport
+	}
+	return http.DefaultTransport
+}
+
+// RoundTrip sends req to a backend chosen according to the pool's
+// Policy, retrying an idempotent request on a different backend
+// if it fails.
+func (p *Pool) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempts := p.MaxAttempts
+	if attempts <= 0 {
+		attempts = 2
+	}
+	if !isIdempotent(req) {
+		attempts = 1
+	}
+	var tried []*Backend
+	var lastErr error
+	for len(tried) < attempts {
+		b := p.choose(req, tried)
+		if b == nil {
+			break
+		}
+		tried = append(tried, b)
+		out := req
+		if len(tried) > 1 && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			out = req.Clone(req.Context())
+			out.Body = body
+		}
+		res, err := p.send(b, out)
+		if err == nil {
+			return res, nil
+		}
+		lastErr = err
+		if req.Context().Err() != nil {
+			break
+		}
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, ErrNoBackend
+}
+
+// isIdempotent reports whether req may be retried after an error.
+func isIdempotent(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	if _, ok := req.Header["Idempotency-Key"]; ok {
+		return true
+	}
+	if _, ok := req.Header["X-Idempotency-Key"]; ok {
+		return true
+	}
+	return false
+}
+
+// send sends req to the backend b.
+func (p *Pool) send(b *Backend, req *http.Request) (*http.Response, error) {
+	out := new(http.Request)
+	*out = *req
+	u := *req.URL
+	out.URL = &u
+	rewriteRequestURL(out, b.URL)
+
+	b.active.Add(1)
+	res, err := p.transport().RoundTrip(out)
+	if err != nil {
+		b.active.Add(-1)
+		if req.Context().Err() == nil {
+			p.fail(b)
+		}
+		return nil, err
+	}
+	if p.failStatus(res.StatusCode) {
+		p.fail(b)
+	} else {
+		p.succeed(b)
+	}
+	if res.StatusCode == http.StatusSwitchingProtocols {
+		// The connection is no longer counted as a request
+		// in progress, and the body must remain writable.
+		b.active.Add(-1)
+		return res, nil
+	}
+	res.Body = &activeBody{ReadCloser: res.Body, b: b}
+	return res, nil
+}
+
+// failStatus reports whether a response with the status code
+// counts as a failed request.
+func (p *Pool) failStatus(code int) bool {
+	if p.FailStatus != nil {
+		return p.FailStatus(code)
+	}
+	return code >= 500 && code < 600
+}
+
+// fail records a failed request to b, ejecting it if necessary.
+func (p *Pool) fail(b *Backend) {
+	if p.MaxFails <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.fails++
+	if b.fails >= p.MaxFails {
+		d := p.EjectDuration
+		if d <= 0 {
+			d = 30 * time.Second
+		}
+		b.ejectedUntil = time.Now().Add(d)
+		b.fails = 0
+	}
+}
+
+// succeed records a successful request to b.
+func (p *Pool) succeed(b *Backend) {
+	if p.MaxFails <= 0 {
+		return
+	}
+	b.mu.Lock()
+	b.fails = 0
+	b.mu.Unlock()
+}
+
+// activeBody is a response body which counts its
+// request as in progress until it is closed.
+type activeBody struct {
+	io.ReadCloser
+	b    *Backend
+	once sync.Once
+}
+
+func (a *activeBody) Close() error {
+	a.once.Do(func() { a.b.active.Add(-1) })
+	return a.ReadCloser.Close()
+}
+
+// choose returns an available backend for req which is not in tried,
+// or nil if there is none.
+func (p *Pool) choose(req *http.Request, tried []*Backend) *Backend {
+	now := time.Now()
+	usable := func(b *Backend) bool {
+		return !slices.Contains(tried, b) && b.available(now)
+	}
+	switch p.Policy {
+	case ConsistentHash:
+		return p.chooseHash(req, usable)
+	case LeastConnections:
+		var best *Backend
+		n := len(p.backends)
+		start := int((p.next.Add(1) - 1) % uint64(max(n, 1)))
+		for i := range n {
+			b := p.backends[(start+i)%n]
+			if usable(b) && (best == nil || b.active.Load() < best.active.Load()) {
+				best = b
+			}
+		}
+		return best
+	default:
+		n := len(p.backends)
+		start := int((p.next.Add(1) - 1) % uint64(max(n, 1)))
+		for i := range n {
+			if b := p.backends[(start+i)%n]; usable(b) {
+				return b
+			}
+		}
+		return nil
+	}
+}
+
+func (p *Pool) chooseHash(req *http.Request, usable func(*Backend) bool) *Backend {
+	p.ringOnce.Do(p.buildRing)
+	if len(p.ring) == 0 {
+		return nil
+	}
+	var key string
+	if p.HashKey != nil {
+		key = p.HashKey(req)
+	} else if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		key = host
+	} else {
+		key = req.RemoteAddr
+	}
+	h := hashString(key)
+	i, _ := slices.BinarySearchFunc(p.ring, h, func(e ringEntry, h uint64) int {
+		return cmp.Compare(e.hash, h)
+	})
+	// Use the first usable backend at or after the key's position.
+	for j := range len(p.ring) {
+		if b := p.ring[(i+j)%len(p.ring)].backend; usable(b) {
+			return b
+		}
+	}
+	return nil
+}
+
+func (p *Pool) buildRing() {
+	for _, b := range p.backends {
+		for i := range ringReplicas {
+			p.ring = append(p.ring, ringEntry{hashString(b.URL.String() + "#" + strconv.Itoa(i)), b})
+		}
+	}
+	slices.SortFunc(p.ring, func(a, b ringEntry) int {
+		return cmp.Compare(a.hash, b.hash)
+	})
+}
+
+// hashString returns the 64-bit FNV-1a hash of s.
+func hashString(s string) uint64 {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= 1099511628211
+	}
+	return h
+}
+
+// CheckHealth sends a health check request to each backend in the pool,
+// and marks those which fail as unavailable until a later check passes.
+// It returns when all the checks are complete or ctx is done.
+func (p *Pool) CheckHealth(ctx context.Context) {
+	timeout := p.HealthCheckTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	var wg sync.WaitGroup
+	for _, b := range p.backends {
+		wg.Go(func() {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			b.unhealthy.Store(!p.check(ctx, b))
+		})
+	}
+	wg.Wait()
+}
+
+// check reports whether the backend b passes a health check.
+func (p *Pool) check(ctx context.Context, b *Backend) bool {
+	u := *b.URL
+	if p.HealthCheckPath != "" {
+		ref := &url.URL{Path: p.HealthCheckPath}
+		u.Path, u.RawPath = joinURLPath(&u, ref)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return false
+	}
+	res, err := p.transport().RoundTrip(req)
+	if err != nil {
+		return false
+	}
+	io.Copy(io.Discard, io.LimitReader(res.Body, 4<<10))
+	res.Body.Close()
+	return res.StatusCode >= 200 && res.StatusCode < 400
+}
+
+// RunHealthChecks calls CheckHealth immediately and then
+// every interval, until ctx is done.
+// If interval is not positive, 10 seconds is used.
+func (p *Pool) RunHealthChecks(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		p.CheckHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
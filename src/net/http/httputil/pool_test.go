@@ -0,0 +1,291 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httputil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// newPoolBackends starts n servers which respond with their index,
+// and returns their URLs.
+func newPoolBackends(t *testing.T, n int, h func(i int, w http.ResponseWriter, r *http.Request)) []*url.URL {
+	t.Helper()
+	var urls []*url.URL
+	for i := range n {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if h != nil {
+				h(i, w, r)
+			}
+			io.WriteString(w, string(rune('a'+i)))
+		}))
+		t.Cleanup(s.Close)
+		u, err := url.Parse(s.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		urls = append(urls, u)
+	}
+	return urls
+}
+
+// newPoolProxy returns a server which proxies to p.
+func newPoolProxy(t *testing.T, p *Pool) *httptest.Server {
+	t.Helper()
+	proxy := &ReverseProxy{
+		Rewrite:   func(r *ProxyRequest) {},
+		Transport: p,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			if errors.Is(err, ErrNoBackend) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+	s := httptest.NewServer(proxy)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func poolGet(t *testing.T, s *httptest.Server, header ...string) string {
+	t.Helper()
+	req, _ := http.NewRequest("GET", s.URL+"/path", nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	res, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != 200 {
+		return res.Status
+	}
+	return string(b)
+}
+
+func TestPoolRoundRobin(t *testing.T) {
+	p := NewPool(newPoolBackends(t, 3, func(i int, w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/path" {
+			t.Errorf("backend got path %q, want /path", r.URL.Path)
+		}
+	})...)
+	s := newPoolProxy(t, p)
+	var got strings.Builder
+	for range 6 {
+		got.WriteString(poolGet(t, s))
+	}
+	if got := got.String(); got != "abcabc" {
+		t.Errorf("responses = %q, want %q", got, "abcabc")
+	}
+}
+
+func TestPoolLeastConnections(t *testing.T) {
+	block := make(chan struct{})
+	started := make(chan struct{})
+	p := NewPool(newPoolBackends(t, 2, func(i int, w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Block") != "" {
+			close(started)
+			<-block
+		}
+	})...)
+	p.Policy = LeastConnections
+	s := newPoolProxy(t, p)
+
+	done := make(chan string)
+	go func() {
+		done <- poolGet(t, s, "Block", "1")
+	}()
+	<-started
+	busy := 0
+	for i, b := range p.Backends() {
+		if b.ActiveRequests() == 1 {
+			busy = i
+		}
+	}
+	want := string(rune('a' + 1 - busy))
+	for range 3 {
+		if got := poolGet(t, s); got != want {
+			t.Errorf("response = %q, want %q from the idle backend", got, want)
+		}
+	}
+	close(block)
+	<-done
+	for i, b := range p.Backends() {
+		if n := b.ActiveRequests(); n != 0 {
+			t.Errorf("backend %d has %d active requests after completion, want 0", i, n)
+		}
+	}
+}
+
+func TestPoolHost(t *testing.T) {
+	urls := newPoolBackends(t, 1, func(i int, w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Host+" ")
+	})
+	p := NewPool(urls...)
+	for _, tt := range []struct {
+		name    string
+		rewrite func(*ProxyRequest)
+		want    string
+	}{
+		{"inbound", func(r *ProxyRequest) { r.Out.Host = r.In.Host }, "example.com a"},
+		{"backend", func(r *ProxyRequest) { r.Out.Host = "" }, urls[0].Host + " a"},
+	} {
+		proxy := &ReverseProxy{Rewrite: tt.rewrite, Transport: p}
+		s := httptest.NewServer(proxy)
+		defer s.Close()
+		req, _ := http.NewRequest("GET", s.URL, nil)
+		req.Host = "example.com"
+		res, err := s.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		if string(b) != tt.want {
+			t.Errorf("%s: backend responded %q, want %q", tt.name, b, tt.want)
+		}
+	}
+}
+
+func TestPoolConsistentHash(t *testing.T) {
+	p := NewPool(newPoolBackends(t, 4, nil)...)
+	p.Policy = ConsistentHash
+	p.HashKey = func(r *http.Request) string { return r.Header.Get("User") }
+	s := newPoolProxy(t, p)
+
+	seen := make(map[string]string)
+	for range 3 {
+		for _, user := range []string{"alice", "bob", "carol", "dave", "eve", "frank"} {
+			got := poolGet(t, s, "User", user)
+			if prev, ok := seen[user]; ok && prev != got {
+				t.Errorf("user %q sent to backend %q, previously %q", user, got, prev)
+			}
+			seen[user] = got
+		}
+	}
+}
+
+func TestPoolHealthCheck(t *testing.T) {
+	healthy := []bool{true, false}
+	p := NewPool(newPoolBackends(t, 2, func(i int, w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" && !healthy[i] {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})...)
+	p.HealthCheckPath = "/healthz"
+	s := newPoolProxy(t, p)
+
+	p.CheckHealth(t.Context())
+	if !p.Backends()[0].Available() || p.Backends()[1].Available() {
+		t.Fatalf("after CheckHealth, Available = %v, %v; want true, false", p.Backends()[0].Available(), p.Backends()[1].Available())
+	}
+	for range 3 {
+		if got := poolGet(t, s); got != "a" {
+			t.Errorf("response = %q, want %q from the healthy backend", got, "a")
+		}
+	}
+
+	healthy[0] = false
+	p.CheckHealth(t.Context())
+	if got := poolGet(t, s); got != "503 Service Unavailable" {
+		t.Errorf("with no healthy backends, response = %q, want 503", got)
+	}
+}
+
+func TestPoolRetryAndEject(t *testing.T) {
+	urls := newPoolBackends(t, 2, nil)
+	// A backend which refuses connections.
+	dead := httptest.NewServer(http.NotFoundHandler())
+	deadURL, _ := url.Parse(dead.URL)
+	dead.Close()
+	p := NewPool(deadURL, urls[0])
+	p.MaxFails = 1
+	s := newPoolProxy(t, p)
+
+	// The first request is sent to the dead backend, and retried.
+	if got := poolGet(t, s); got != "a" {
+		t.Errorf("response = %q, want %q", got, "a")
+	}
+	if p.Backends()[0].Available() {
+		t.Errorf("dead backend is available after failing, want ejected")
+	}
+	for range 3 {
+		if got := poolGet(t, s); got != "a" {
+			t.Errorf("response = %q, want %q", got, "a")
+		}
+	}
+
+	// Non-idempotent requests are not retried.
+	p2 := NewPool(deadURL, urls[1])
+	p2.MaxAttempts = 2
+	s2 := newPoolProxy(t, p2)
+	res, err := s2.Client().Post(s2.URL, "text/plain", strings.NewReader("body"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusBadGateway {
+		t.Errorf("POST to dead backend: status %v, want 502", res.Status)
+	}
+}
+
+func TestPoolEjectFailStatus(t *testing.T) {
+	p := NewPool(newPoolBackends(t, 2, func(i int, w http.ResponseWriter, r *http.Request) {
+		if i == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})...)
+	p.MaxFails = 2
+	s := newPoolProxy(t, p)
+
+	// The failed responses are returned, and eject the backend.
+	got := []string{poolGet(t, s), poolGet(t, s), poolGet(t, s), poolGet(t, s)}
+	want := []string{"503 Service Unavailable", "b", "503 Service Unavailable", "b"}
+	if !slices.Equal(got, want) {
+		t.Errorf("responses = %q, want %q", got, want)
+	}
+	if p.Backends()[0].Available() {
+		t.Errorf("backend responding with 503 is available, want ejected")
+	}
+	for range 3 {
+		if got := poolGet(t, s); got != "b" {
+			t.Errorf("response = %q, want %q", got, "b")
+		}
+	}
+
+	// With a FailStatus which accepts 503, the backend is not ejected.
+	p2 := NewPool(p.Backends()[0].URL)
+	p2.MaxFails = 1
+	p2.FailStatus = func(code int) bool { return code == http.StatusBadGateway }
+	s2 := newPoolProxy(t, p2)
+	for range 2 {
+		if got := poolGet(t, s2); got != "503 Service Unavailable" {
+			t.Errorf("response = %q, want 503", got)
+		}
+	}
+	if !p2.Backends()[0].Available() {
+		t.Errorf("backend is ejected, want available")
+	}
+}
+
+func TestPoolRunHealthChecksZeroInterval(t *testing.T) {
+	p := NewPool(newPoolBackends(t, 1, nil)...)
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	p.RunHealthChecks(ctx, 0) // must not panic
+}
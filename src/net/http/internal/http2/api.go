@@ -96,6 +96,7 @@ type Header = textproto.MIMEHeader
 type TransportConfig interface {
 	MaxResponseHeaderBytes() int64
 	DisableCompression() bool
+	EnableZstd() bool
 	DisableKeepAlives() bool
 	ExpectContinueTimeout() time.Duration
 	ResponseHeaderTimeout() time.Duration
@@ -227,5 +227,5 @@ func NewNoDialClientConnPool() ClientConnPool {
 }
 
 func EncodeRequestHeaders(req *ClientRequest, addGzipHeader bool, peerMaxHeaderListSize uint64, headerf func(name, value string)) (httpcommon.EncodeHeadersResult, error) {
-	return encodeRequestHeaders(req, addGzipHeader, peerMaxHeaderListSize, headerf)
+	return encodeRequestHeaders(req, addGzipHeader, false, peerMaxHeaderListSize, headerf)
 }
@@ -11,6 +11,7 @@ import (
 	"bytes"
 	"compress/flate"
 	"compress/gzip"
+	"compress/zstd"
 	"context"
 	"crypto/rand"
 	"crypto/tls"
@@ -212,6 +213,10 @@ func (t *Transport) disableCompression() bool {
 	return t.DisableCompression || (t.t1 != nil && t.t1.DisableCompression())
 }
 
+func (t *Transport) enableZstd() bool {
+	return t.t1 != nil && t.t1.EnableZstd()
+}
+
 func NewTransport(t1 TransportConfig) *Transport {
 	connPool := new(clientConnPool)
 	t2 := &Transport{
@@ -367,6 +372,7 @@ type clientStream struct {
 	ID            uint32
 	bufPipe       pipe // buffered pipe with the flow-controlled response payload
 	requestedGzip bool
+	requestedZstd bool
 	isHead        bool
 
 	abortOnce sync.Once
@@ -1306,6 +1312,7 @@ func (cc *ClientConn) roundTrip(req *ClientRequest, streamf func(*clientStream))
 	cs := &req.stream
 
 	cs.requestedGzip = httpcommon.IsRequestGzip(req.Method, req.Header, cc.t.disableCompression())
+	cs.requestedZstd = cs.requestedGzip && cc.t.enableZstd()
 
 	go cs.doRequest(req, streamf)
 
@@ -1581,7 +1588,7 @@ func (cs *clientStream) encodeAndWriteHeaders(req *ClientRequest) error {
 	// sent by writeRequestBody below, along with any Trailers,
 	// again in form HEADERS{1}, CONTINUATION{0,})
 	cc.hbuf.Reset()
-	res, err := encodeRequestHeaders(req, cs.requestedGzip, cc.peerMaxHeaderListSize, func(name, value string) {
+	res, err := encodeRequestHeaders(req, cs.requestedGzip, cs.requestedZstd, cc.peerMaxHeaderListSize, func(name, value string) {
 		cc.writeHeader(name, value)
 	})
 	if err != nil {
@@ -1597,7 +1604,7 @@ func (cs *clientStream) encodeAndWriteHeaders(req *ClientRequest) error {
 	return err
 }
 
-func encodeRequestHeaders(req *ClientRequest, addGzipHeader bool, peerMaxHeaderListSize uint64, headerf func(name, value string)) (httpcommon.EncodeHeadersResult, error) {
+func encodeRequestHeaders(req *ClientRequest, addGzipHeader, addZstdHeader bool, peerMaxHeaderListSize uint64, headerf func(name, value string)) (httpcommon.EncodeHeadersResult, error) {
 	return httpcommon.EncodeHeaders(req.Context, httpcommon.EncodeHeadersParam{
 		Request: httpcommon.Request{
 			Header:              req.Header,
@@ -1608,6 +1615,7 @@ func encodeRequestHeaders(req *ClientRequest, addGzipHeader bool, peerMaxHeaderL
 			ActualContentLength: actualContentLength(req),
 		},
 		AddGzipHeader:         addGzipHeader,
+		AddZstdHeader:         addZstdHeader,
 		PeerMaxHeaderListSize: peerMaxHeaderListSize,
 		DefaultUserAgent:      defaultUserAgent,
 	}, headerf)
@@ -2477,13 +2485,18 @@ func (rl *clientConnReadLoop) handleResponse(cs *clientStream, f *MetaHeadersFra
 	cs.bytesRemain = res.ContentLength
 	res.Body = transportResponseBody{cs}
 
-	if cs.requestedGzip && asciiEqualFold(res.Header.Get("Content-Encoding"), "gzip") {
-		res.Header.Del("Content-Encoding")
-		res.Header.Del("Content-Length")
-		res.ContentLength = -1
+	switch ce := res.Header.Get("Content-Encoding"); {
+	case cs.requestedGzip && asciiEqualFold(ce, "gzip"):
 		res.Body = &gzipReader{body: res.Body}
-		res.Uncompressed = true
+	case cs.requestedZstd && asciiEqualFold(ce, "zstd"):
+		res.Body = &zstdReader{body: res.Body, zr: zstd.NewReader(res.Body)}
+	default:
+		return res, nil
 	}
+	res.Header.Del("Content-Encoding")
+	res.Header.Del("Content-Length")
+	res.ContentLength = -1
+	res.Uncompressed = true
 	return res, nil
 }
 
@@ -3092,6 +3105,20 @@ func (rt erringRoundTripper) RoundTrip(*ClientRequest) (*ClientResponse, error)
 
 var errConcurrentReadOnResBody = errors.New("http2: concurrent read on response body")
 
+// zstdReader wraps a response body to decode Zstandard content.
+type zstdReader struct {
+	body io.ReadCloser // underlying Response.Body
+	zr   *zstd.Reader  // reads from body
+}
+
+func (zr *zstdReader) Read(p []byte) (n int, err error) {
+	return zr.zr.Read(p)
+}
+
+func (zr *zstdReader) Close() error {
+	return zr.body.Close()
+}
+
 // gzipReader wraps a response body so it can lazily
 // get gzip.Reader from the pool on the first call to Read.
 // After Close is called it puts gzip.Reader to the pool immediately
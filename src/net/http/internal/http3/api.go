@@ -0,0 +1,80 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http3
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http/internal"
+	"net/textproto"
+	"net/url"
+)
+
+// Since net/http imports the http3 package, http3 cannot use any net/http types.
+// This file contains definitions which exist to avoid introducing a dependency cycle.
+
+// Variables defined in net/http and initialized by an init func in that package.
+//
+// NoBody, ErrContentLength and LocalAddrContextKey have concrete types or
+// identities in net/http, and therefore can't be moved into a common package
+// without introducing a dependency cycle.
+var (
+	NoBody              io.ReadCloser
+	ErrContentLength    error
+	LocalAddrContextKey any
+)
+
+var (
+	ErrAbortHandler   = internal.ErrAbortHandler
+	ErrBodyNotAllowed = internal.ErrBodyNotAllowed
+)
+
+type Header = textproto.MIMEHeader
+
+// A ClientRequest is a Request used by the HTTP/3 client (Transport).
+type ClientRequest struct {
+	Context       context.Context
+	Method        string
+	URL           *url.URL
+	Header        Header
+	Trailer       Header
+	Body          io.ReadCloser
+	Host          string
+	ContentLength int64
+}
+
+// A ClientResponse is a Response used by the HTTP/3 client (Transport).
+type ClientResponse struct {
+	StatusCode    int // e.g. 200
+	ContentLength int64
+	Uncompressed  bool
+	Header        Header
+	Trailer       Header
+	Body          io.ReadCloser
+	TLS           *tls.ConnectionState
+}
+
+// A ServerRequest is a Request used by the HTTP/3 server.
+type ServerRequest struct {
+	Context       context.Context
+	Method        string
+	URL           *url.URL
+	Header        Header
+	Trailer       Header
+	Body          io.ReadCloser
+	Host          string
+	ContentLength int64
+	RemoteAddr    string
+	RequestURI    string
+	TLS           *tls.ConnectionState
+}
+
+// A Handler responds to an HTTP/3 request.
+type Handler interface {
+	ServeHTTP(*ResponseWriter, *ServerRequest)
+}
+
+type ResponseWriter = responseWriter
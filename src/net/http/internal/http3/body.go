@@ -9,7 +9,6 @@ import (
 	"fmt"
 	"io"
 	"net"
-	"net/http"
 	"net/textproto"
 	"strings"
 	"sync"
@@ -20,7 +19,7 @@ import (
 // extractTrailerFromHeader extracts the "Trailer" header values from a header
 // map, and populates a trailer map with those values as keys. The extracted
 // header values will be canonicalized.
-func extractTrailerFromHeader(header, trailer http.Header) {
+func extractTrailerFromHeader(header, trailer Header) {
 	for _, names := range header["Trailer"] {
 		names = textproto.TrimString(names)
 		for name := range strings.SplitSeq(names, ",") {
@@ -40,7 +39,7 @@ type bodyWriter struct {
 	remain  int64         // -1 when content-length is not known
 	flush   bool          // flush the stream after every write
 	name    string        // "request" or "response"
-	trailer http.Header   // trailer headers that will be written once bodyWriter is closed.
+	trailer Header        // trailer headers that will be written once bodyWriter is closed.
 	enc     *qpackEncoder // QPACK encoder used by the connection.
 }
 
@@ -129,7 +128,7 @@ type bodyReader struct {
 	// there is a HEADERS frame after reading DATA frames to EOF, the value of
 	// the headers will be written here, provided that the name of the header
 	// exists in the map already.
-	trailer http.Header
+	trailer Header
 }
 
 func (r *bodyReader) Read(p []byte) (n int, err error) {
@@ -0,0 +1,15 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package http3 implements the HTTP/3 protocol (RFC 9114)
+// and QPACK header compression (RFC 9204) on top of QUIC.
+//
+// This package is low-level and is used by the net/http package,
+// which translates its Request and Response types to and from
+// the types defined here. Most users will use HTTP/3 through
+// net/http by setting HTTP3 in Server.Protocols or Transport.Protocols.
+//
+// The golang.org/x/net/internal/http3 package is the original source
+// of this implementation.
+package http3
@@ -0,0 +1,215 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http3
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zstd"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net/http/internal/testcert"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func init() {
+	// NoBody is normally set by net/http.
+	if NoBody == nil {
+		NoBody = io.NopCloser(strings.NewReader(""))
+	}
+}
+
+type handlerFunc func(*ResponseWriter, *ServerRequest)
+
+func (f handlerFunc) ServeHTTP(w *ResponseWriter, r *ServerRequest) { f(w, r) }
+
+type testTransportConfig struct {
+	compression bool // request compressed responses
+	zstd        bool // request zstd as well as gzip
+}
+
+func (c testTransportConfig) DisableCompression() bool    { return !c.compression }
+func (c testTransportConfig) EnableZstd() bool            { return c.zstd }
+func (testTransportConfig) MaxResponseHeaderBytes() int64 { return 0 }
+
+// newTestConn starts a Server on a loopback address with handler h,
+// and returns a ClientConn connected to it.
+func newTestConn(t *testing.T, srv *Server, h Handler) *ClientConn {
+	t.Helper()
+	return newTestConnConfig(t, srv, h, testTransportConfig{})
+}
+
+// newTestConnConfig is like newTestConn, but the client Transport
+// uses the configuration t1.
+func newTestConnConfig(t *testing.T, srv *Server, h Handler, t1 TransportConfig) *ClientConn {
+	t.Helper()
+	cert, err := tls.X509KeyPair(testcert.LocalhostCert, testcert.LocalhostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Handler = h
+	srv.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+	addr, err := srv.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve()
+	t.Cleanup(srv.Close)
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(testcert.LocalhostCert)
+	tr := NewTransport(&tls.Config{RootCAs: roots, ServerName: "example.com"}, t1)
+	t.Cleanup(tr.CloseIdleConnections)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cc, err := tr.Dial(ctx, addr.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cc
+}
+
+func TestRoundTripHeaders(t *testing.T) {
+	long := strings.Repeat("abcdefghijklmnopqrstuvwxyz", 20)
+	cc := newTestConn(t, &Server{}, handlerFunc(func(w *ResponseWriter, r *ServerRequest) {
+		if got, want := r.Header.Get("X-Long"), long; got != want {
+			t.Errorf("server: X-Long = %q, want %q", got, want)
+		}
+		if got, want := r.Header.Values("X-Multi"), []string{"a", "b"}; strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("server: X-Multi = %q, want %q", got, want)
+		}
+		if got, want := r.URL.Path, "/path"; got != want {
+			t.Errorf("server: path = %q, want %q", got, want)
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("X-Echo", r.Header.Get("X-Long"))
+		w.WriteHeader(202)
+		io.Copy(w, r.Body)
+	}))
+
+	req := &ClientRequest{
+		Context: context.Background(),
+		Method:  "PUT",
+		URL:     &url.URL{Scheme: "https", Host: "example.com", Path: "/path"},
+		Header: Header{
+			"X-Long":  {long},
+			"X-Multi": {"a", "b"},
+		},
+		Body:          io.NopCloser(strings.NewReader("body")),
+		ContentLength: 4,
+	}
+	res, err := cc.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != 202 {
+		t.Errorf("StatusCode = %v, want 202", res.StatusCode)
+	}
+	if got, want := string(body), "body"; got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+	if got := res.Header.Get("X-Echo"); got != long {
+		t.Errorf("X-Echo = %q, want %q", got, long)
+	}
+	if res.TLS == nil || res.TLS.NegotiatedProtocol != "h3" {
+		t.Errorf("TLS = %v, want negotiated protocol h3", res.TLS)
+	}
+}
+
+func TestRoundTripCompression(t *testing.T) {
+	const content = "compressed content"
+	var gzipped, zstded bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	io.WriteString(gw, content)
+	gw.Close()
+	zw := zstd.NewWriter(&zstded)
+	io.WriteString(zw, content)
+	zw.Close()
+
+	for _, tt := range []struct {
+		name   string
+		config testTransportConfig
+		accept string // Accept-Encoding sent by the client
+	}{
+		{"gzip", testTransportConfig{compression: true}, "gzip"},
+		{"zstd", testTransportConfig{compression: true, zstd: true}, "zstd, gzip"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := newTestConnConfig(t, &Server{}, handlerFunc(func(w *ResponseWriter, r *ServerRequest) {
+				if got := r.Header.Get("Accept-Encoding"); got != tt.accept {
+					t.Errorf("server: Accept-Encoding = %q, want %q", got, tt.accept)
+				}
+				if strings.HasPrefix(r.Header.Get("Accept-Encoding"), "zstd") {
+					w.Header().Set("Content-Encoding", "zstd")
+					w.Write(zstded.Bytes())
+				} else {
+					w.Header().Set("Content-Encoding", "gzip")
+					w.Write(gzipped.Bytes())
+				}
+			}), tt.config)
+			res, err := cc.RoundTrip(&ClientRequest{
+				Context: context.Background(),
+				Method:  "GET",
+				URL:     &url.URL{Scheme: "https", Host: "example.com", Path: "/"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(body) != content {
+				t.Errorf("body = %q, want %q", body, content)
+			}
+			if !res.Uncompressed || res.Header.Get("Content-Encoding") != "" {
+				t.Errorf("Uncompressed = %v, Content-Encoding = %q; want true, none", res.Uncompressed, res.Header.Get("Content-Encoding"))
+			}
+		})
+	}
+}
+
+func TestServerMaxHeaderBytes(t *testing.T) {
+	cc := newTestConn(t, &Server{MaxHeaderBytes: 1024}, handlerFunc(func(w *ResponseWriter, r *ServerRequest) {
+		t.Errorf("handler called for request with oversized header")
+	}))
+	req := &ClientRequest{
+		Context: context.Background(),
+		Method:  "GET",
+		URL:     &url.URL{Scheme: "https", Host: "example.com", Path: "/"},
+		Header:  Header{"X-Big": {strings.Repeat("x", 4096)}},
+	}
+	if res, err := cc.RoundTrip(req); err == nil {
+		res.Body.Close()
+		t.Fatalf("RoundTrip with oversized header succeeded, want error")
+	}
+}
+
+func TestServerHandlerPanic(t *testing.T) {
+	cc := newTestConn(t, &Server{}, handlerFunc(func(w *ResponseWriter, r *ServerRequest) {
+		panic(ErrAbortHandler)
+	}))
+	req := &ClientRequest{
+		Context: context.Background(),
+		Method:  "GET",
+		URL:     &url.URL{Scheme: "https", Host: "example.com", Path: "/"},
+		Header:  Header{},
+	}
+	if res, err := cc.RoundTrip(req); err == nil {
+		res.Body.Close()
+		t.Fatalf("RoundTrip to panicking handler succeeded, want error")
+	}
+}
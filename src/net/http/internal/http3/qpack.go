@@ -294,6 +294,11 @@ func (st *stream) readPrefixedStringWithByte(firstByte byte, prefixLen uint8) (s
 	hbit := byte(1) << prefixLen
 	isHuffman := firstByte&hbit != 0
 
+	// The string can't extend past the end of the field section.
+	if st.lim >= 0 && size > st.lim {
+		return "", errQPACKDecompressionFailed
+	}
+
 	// TODO: Avoid allocating here.
 	data := make([]byte, size)
 	if _, err := io.ReadFull(st, data); err != nil {
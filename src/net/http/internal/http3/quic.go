@@ -6,6 +6,7 @@ package http3
 
 import (
 	"crypto/tls"
+	"slices"
 
 	"golang.org/x/net/quic"
 )
@@ -33,7 +34,9 @@ func initConfig(config *quic.Config) *quic.Config {
 	if config.TLSConfig.MinVersion == 0 {
 		maybeCloneTLSConfig().MinVersion = tls.VersionTLS13
 	}
-	if config.TLSConfig.NextProtos == nil {
+	// The TLS config may come from an HTTP/1 or HTTP/2 configuration,
+	// which advertises other protocols. A QUIC endpoint only speaks h3.
+	if !slices.Equal(config.TLSConfig.NextProtos, []string{"h3"}) {
 		maybeCloneTLSConfig().NextProtos = []string{"h3"}
 	}
 	return config
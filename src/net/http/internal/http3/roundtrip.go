@@ -5,20 +5,22 @@
 package http3
 
 import (
+	"compress/gzip"
+	"compress/zstd"
 	"errors"
 	"io"
-	"net/http"
+	"maps"
 	"net/http/httptrace"
-	"net/textproto"
+	"net/http/internal/ascii"
+	"net/http/internal/httpcommon"
 	"strconv"
 	"sync"
 
 	"golang.org/x/net/http/httpguts"
-	"golang.org/x/net/internal/httpcommon"
 )
 
 type roundTripState struct {
-	cc *clientConn
+	cc *ClientConn
 	st *stream
 
 	// Request body, provided by the caller.
@@ -34,6 +36,9 @@ type roundTripState struct {
 
 	errOnce sync.Once
 	err     error
+
+	// endOnce reports the end of the request to the connection.
+	endOnce sync.Once
 }
 
 // abort terminates the RoundTrip.
@@ -55,6 +60,11 @@ func (rt *roundTripState) abort(err error) error {
 	return rt.err
 }
 
+// end records the completion of the request on the connection, at most once.
+func (rt *roundTripState) end() {
+	rt.endOnce.Do(rt.cc.endRequest)
+}
+
 // closeReqBody closes the Request.Body, at most once.
 func (rt *roundTripState) closeReqBody() {
 	if rt.reqBody != nil {
@@ -65,11 +75,11 @@ func (rt *roundTripState) closeReqBody() {
 }
 
 // TODO: Set up the rest of the hooks that might be in rt.trace.
-func (rt *roundTripState) maybeCallGot1xxResponse(status int, h http.Header) error {
+func (rt *roundTripState) maybeCallGot1xxResponse(status int, h Header) error {
 	if rt.trace == nil || rt.trace.Got1xxResponse == nil {
 		return nil
 	}
-	return rt.trace.Got1xxResponse(status, textproto.MIMEHeader(h))
+	return rt.trace.Got1xxResponse(status, h)
 }
 
 func (rt *roundTripState) maybeCallGot100Continue() {
@@ -87,33 +97,45 @@ func (rt *roundTripState) maybeCallWait100Continue() {
 }
 
 // RoundTrip sends a request on the connection.
-func (cc *clientConn) RoundTrip(req *http.Request) (_ *http.Response, err error) {
+func (cc *ClientConn) RoundTrip(req *ClientRequest) (_ *ClientResponse, err error) {
+	if err := cc.startRequest(); err != nil {
+		return nil, err
+	}
 	// Each request gets its own QUIC stream.
-	st, err := newConnStream(req.Context(), cc.qconn, streamTypeRequest)
+	st, err := newConnStream(req.Context, cc.qconn, streamTypeRequest)
 	if err != nil {
+		cc.endRequest()
+		if cc.Err() != nil {
+			// The connection closed before we sent anything.
+			return nil, ErrNoCachedConn
+		}
 		return nil, err
 	}
 	rt := &roundTripState{
 		cc:      cc,
 		st:      st,
-		trace:   httptrace.ContextClientTrace(req.Context()),
+		trace:   httptrace.ContextClientTrace(req.Context),
 		reqBody: req.Body,
 	}
 	if rt.reqBody == nil {
-		rt.reqBody = http.NoBody
+		rt.reqBody = NoBody
 	}
 	defer func() {
 		if err != nil {
 			err = rt.abort(err)
+			rt.closeReqBody()
+			rt.end()
 		}
 	}()
 
 	// Cancel reads/writes on the stream when the request expires.
-	st.stream.SetReadContext(req.Context())
-	st.stream.SetWriteContext(req.Context())
+	st.stream.SetReadContext(req.Context)
+	st.stream.SetWriteContext(req.Context)
 
+	requestedGzip := httpcommon.IsRequestGzip(req.Method, req.Header, cc.t1 != nil && cc.t1.DisableCompression())
+	requestedZstd := requestedGzip && cc.t1 != nil && cc.t1.EnableZstd()
 	headers := cc.enc.encode(func(yield func(itype indexType, name, value string)) {
-		_, err = httpcommon.EncodeHeaders(req.Context(), httpcommon.EncodeHeadersParam{
+		_, err = httpcommon.EncodeHeaders(req.Context, httpcommon.EncodeHeadersParam{
 			Request: httpcommon.Request{
 				URL:                 req.URL,
 				Method:              req.Method,
@@ -122,7 +144,8 @@ func (cc *clientConn) RoundTrip(req *http.Request) (_ *http.Response, err error)
 				Trailer:             req.Trailer,
 				ActualContentLength: actualContentLength(req),
 			},
-			AddGzipHeader:         false, // TODO: add when appropriate
+			AddGzipHeader:         requestedGzip,
+			AddZstdHeader:         requestedZstd,
 			PeerMaxHeaderListSize: 0,
 			DefaultUserAgent:      "Go-http-client/3",
 		}, func(name, value string) {
@@ -164,7 +187,6 @@ func (cc *clientConn) RoundTrip(req *http.Request) (_ *http.Response, err error)
 				return nil, err
 			}
 
-			// TODO: Handle 1xx responses.
 			if isInfoStatus(statusCode) {
 				if err := rt.maybeCallGot1xxResponse(statusCode, h); err != nil {
 					return nil, err
@@ -184,6 +206,13 @@ func (cc *clientConn) RoundTrip(req *http.Request) (_ *http.Response, err error)
 					continue
 				}
 			}
+			if is100ContinueReq && !bodyAndTrailerWritten {
+				// The server responded without asking for the body.
+				// Don't send it.
+				bodyAndTrailerWritten = true
+				rt.closeReqBody()
+				st.stream.CloseWrite()
+			}
 
 			// We have the response headers.
 			// Set up the response and return it to the caller.
@@ -192,31 +221,49 @@ func (cc *clientConn) RoundTrip(req *http.Request) (_ *http.Response, err error)
 				return nil, err
 			}
 
-			trailer := make(http.Header)
+			trailer := make(Header)
 			extractTrailerFromHeader(h, trailer)
 			delete(h, "Trailer")
 
-			if (contentLength != 0 && req.Method != http.MethodHead) || len(trailer) > 0 {
+			if (contentLength != 0 && req.Method != "HEAD") || len(trailer) > 0 {
 				rt.respBody = &bodyReader{
 					st:      st,
 					remain:  contentLength,
 					trailer: trailer,
 				}
 			} else {
-				rt.respBody = http.NoBody
+				rt.respBody = NoBody
 			}
-			resp := &http.Response{
-				Proto:         "HTTP/3.0",
-				ProtoMajor:    3,
-				Header:        h,
+			var body io.ReadCloser = (*transportResponseBody)(rt)
+			uncompressed := false
+			if rt.respBody != NoBody {
+				switch ce := h.Get("Content-Encoding"); {
+				case requestedGzip && ascii.EqualFold(ce, "gzip"):
+					body = &gzipReader{body: body}
+					uncompressed = true
+				case requestedZstd && ascii.EqualFold(ce, "zstd"):
+					body = &zstdReader{body: body, zr: zstd.NewReader(body)}
+					uncompressed = true
+				}
+			}
+			if uncompressed {
+				delete(h, "Content-Encoding")
+				delete(h, "Content-Length")
+				contentLength = -1
+			}
+			if h == nil {
+				h = make(Header)
+			}
+			tlsState := cc.qconn.ConnectionState()
+			return &ClientResponse{
 				StatusCode:    statusCode,
-				Status:        strconv.Itoa(statusCode) + " " + http.StatusText(statusCode),
 				ContentLength: contentLength,
+				Uncompressed:  uncompressed,
+				Header:        h,
 				Trailer:       trailer,
-				Body:          (*transportResponseBody)(rt),
-			}
-			// TODO: Automatic Content-Type: gzip decoding.
-			return resp, nil
+				Body:          body,
+				TLS:           &tlsState,
+			}, nil
 		case frameTypePushPromise:
 			if err := cc.handlePushPromise(st); err != nil {
 				return nil, err
@@ -231,8 +278,8 @@ func (cc *clientConn) RoundTrip(req *http.Request) (_ *http.Response, err error)
 
 // actualContentLength returns a sanitized version of req.ContentLength,
 // where 0 actually means zero (not unknown) and -1 means unknown.
-func actualContentLength(req *http.Request) int64 {
-	if req.Body == nil || req.Body == http.NoBody {
+func actualContentLength(req *ClientRequest) int64 {
+	if req.Body == nil || req.Body == NoBody {
 		return 0
 	}
 	if req.ContentLength != 0 {
@@ -243,10 +290,10 @@ func actualContentLength(req *http.Request) int64 {
 
 // writeBodyAndTrailer handles writing the body and trailer for a given
 // request, if any. This function will close the write direction of the stream.
-func (cc *clientConn) writeBodyAndTrailer(rt *roundTripState, req *http.Request) {
+func (cc *ClientConn) writeBodyAndTrailer(rt *roundTripState, req *ClientRequest) {
 	defer rt.closeReqBody()
 
-	declaredTrailer := req.Trailer.Clone()
+	declaredTrailer := maps.Clone(req.Trailer)
 
 	rt.reqBodyWriter.st = rt.st
 	rt.reqBodyWriter.remain = actualContentLength(req)
@@ -297,6 +344,7 @@ func (b *transportResponseBody) Close() error {
 		err = errRespBodyClosed
 	}
 	err = rt.abort(err)
+	rt.end()
 	if err == errRespBodyClosed {
 		// No other errors occurred before closing Response.Body,
 		// so consider this a successful request.
@@ -305,7 +353,7 @@ func (b *transportResponseBody) Close() error {
 	return err
 }
 
-func parseResponseContentLength(method string, statusCode int, h http.Header) (int64, error) {
+func parseResponseContentLength(method string, statusCode int, h Header) (int64, error) {
 	clens := h["Content-Length"]
 	if len(clens) == 0 {
 		return -1, nil
@@ -339,7 +387,10 @@ func parseResponseContentLength(method string, statusCode int, h http.Header) (i
 	return int64(contentLen), nil
 }
 
-func (cc *clientConn) handleHeaders(st *stream) (statusCode int, h http.Header, err error) {
+func (cc *ClientConn) handleHeaders(st *stream) (statusCode int, h Header, err error) {
+	if limit := cc.maxHeaderBytes(); st.lim > limit {
+		return 0, nil, &streamError{errH3ExcessiveLoad, "response header too large"}
+	}
 	haveStatus := false
 	cookie := ""
 	// Issue #71374: Consider tracking the never-indexed status of headers
@@ -373,7 +424,7 @@ func (cc *clientConn) handleHeaders(st *stream) (statusCode int, h http.Header,
 			}
 		default:
 			if h == nil {
-				h = make(http.Header)
+				h = make(Header)
 			}
 			// TODO: Use a per-connection canonicalization cache as we do in HTTP/2.
 			// Maybe we could put this in the QPACK decoder and have it deliver
@@ -396,7 +447,7 @@ func (cc *clientConn) handleHeaders(st *stream) (statusCode int, h http.Header,
 	}
 	if cookie != "" {
 		if h == nil {
-			h = make(http.Header)
+			h = make(Header)
 		}
 		h["Cookie"] = []string{cookie}
 	}
@@ -406,7 +457,7 @@ func (cc *clientConn) handleHeaders(st *stream) (statusCode int, h http.Header,
 	return statusCode, h, err
 }
 
-func (cc *clientConn) handlePushPromise(st *stream) error {
+func (cc *ClientConn) handlePushPromise(st *stream) error {
 	// "A client MUST treat receipt of a PUSH_PROMISE frame that contains a
 	// larger push ID than the client has advertised as a connection error of H3_ID_ERROR."
 	// https://www.rfc-editor.org/rfc/rfc9114.html#section-7.2.5-5
@@ -415,3 +466,43 @@ func (cc *clientConn) handlePushPromise(st *stream) error {
 		message: "PUSH_PROMISE received when no MAX_PUSH_ID has been sent",
 	}
 }
+
+// gzipReader wraps a response body so it can lazily
+// call gzip.NewReader on the first call to Read.
+type gzipReader struct {
+	body io.ReadCloser // underlying Response.Body
+	zr   *gzip.Reader  // lazily-initialized gzip reader
+	zerr error         // sticky error
+}
+
+func (gz *gzipReader) Read(p []byte) (n int, err error) {
+	if gz.zerr != nil {
+		return 0, gz.zerr
+	}
+	if gz.zr == nil {
+		gz.zr, err = gzip.NewReader(gz.body)
+		if err != nil {
+			gz.zerr = err
+			return 0, err
+		}
+	}
+	return gz.zr.Read(p)
+}
+
+func (gz *gzipReader) Close() error {
+	return gz.body.Close()
+}
+
+// zstdReader wraps a response body to decode Zstandard content.
+type zstdReader struct {
+	body io.ReadCloser // underlying Response.Body
+	zr   *zstd.Reader  // reads from body
+}
+
+func (zr *zstdReader) Read(p []byte) (n int, err error) {
+	return zr.zr.Read(p)
+}
+
+func (zr *zstdReader) Close() error {
+	return zr.body.Close()
+}
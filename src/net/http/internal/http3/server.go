@@ -7,10 +7,16 @@ package http3
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"maps"
-	"net/http"
+	"net"
+	"net/http/internal"
+	"net/http/internal/httpcommon"
+	"net/netip"
+	"runtime"
 	"slices"
 	"strconv"
 	"strings"
@@ -18,19 +24,35 @@ import (
 	"time"
 
 	"golang.org/x/net/http/httpguts"
-	"golang.org/x/net/internal/httpcommon"
 	"golang.org/x/net/quic"
 )
 
-// A server is an HTTP/3 server.
-// The zero value for server is a valid server.
-type server struct {
-	// handler to invoke for requests, http.DefaultServeMux if nil.
-	handler http.Handler
+// A Server is an HTTP/3 server.
+type Server struct {
+	// Handler handles requests.
+	Handler Handler
 
-	config *quic.Config
+	// TLSConfig is the TLS configuration used for QUIC connections.
+	// It is cloned and adjusted to negotiate "h3" with TLS 1.3.
+	TLSConfig *tls.Config
+
+	// BaseContext is the base context for requests.
+	// If nil, context.Background is used.
+	BaseContext context.Context
+
+	// MaxHeaderBytes is the maximum size of a request's encoded
+	// field section. If zero, DefaultMaxHeaderBytes is used.
+	MaxHeaderBytes int
+
+	// ErrorLog logs panics in handlers.
+	// If nil, logging is done via the log package's standard logger.
+	ErrorLog *log.Logger
+
+	// ListenQUIC determines how the server will open a QUIC endpoint.
+	// By default, quic.Listen("udp", addr, config) is used.
+	ListenQUIC func(addr string, config *quic.Config) (*quic.Endpoint, error)
 
-	listenQUIC func(addr string, config *quic.Config) (*quic.Endpoint, error)
+	config *quic.Config
 
 	initOnce sync.Once
 
@@ -42,84 +64,19 @@ type server struct {
 	// can avoid busy-waiting for activeConns to be empty.
 	connClosed  chan any
 	mu          sync.Mutex // Guards fields below.
+	endpoint    *quic.Endpoint
 	activeConns map[*serverConn]struct{}
 }
 
-// netHTTPHandler is an interface that is implemented by
-// net/http.http3ServerHandler in std.
-//
-// It provides a way for information to be passed between x/net and net/http
-// that would otherwise be inaccessible, such as the TLS configs that users
-// have supplied to net/http servers.
-//
-// This allows us to integrate our HTTP/3 server implementation with the
-// net/http server when RegisterServer is called.
-type netHTTPHandler interface {
-	http.Handler
-	TLSConfig() *tls.Config
-	BaseContext() context.Context
-	Addr() string
-	ListenErrHook(err error)
-	ShutdownContext() context.Context
-}
-
-type ServerOpts struct {
-	// ListenQUIC determines how the server will open a QUIC endpoint.
-	// By default, quic.Listen("udp", addr, config) is used.
-	ListenQUIC func(addr string, config *quic.Config) (*quic.Endpoint, error)
-
-	// QUICConfig is the QUIC configuration used by the server.
-	// QUICConfig may be nil and should not be modified after calling
-	// RegisterServer.
-	// If QUICConfig.TLSConfig is nil, the TLSConfig of the net/http Server
-	// given to RegisterServer will be used.
-	QUICConfig *quic.Config
-}
-
-// RegisterServer adds HTTP/3 support to a net/http Server.
-//
-// RegisterServer must be called before s begins serving, and only affects
-// s.ListenAndServeTLS.
-func RegisterServer(s *http.Server, opts ServerOpts) {
-	if s.TLSNextProto == nil {
-		s.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
-	}
-	s.TLSNextProto["http/3"] = func(s *http.Server, c *tls.Conn, h http.Handler) {
-		stdHandler, ok := h.(netHTTPHandler)
-		if !ok {
-			panic("RegisterServer was given a server that does not implement netHTTPHandler")
-		}
-		if opts.QUICConfig == nil {
-			opts.QUICConfig = &quic.Config{}
-		}
-		if opts.QUICConfig.TLSConfig == nil {
-			opts.QUICConfig.TLSConfig = stdHandler.TLSConfig()
-		}
-		s3 := &server{
-			config:     opts.QUICConfig,
-			listenQUIC: opts.ListenQUIC,
-			handler:    stdHandler,
-			serveCtx:   stdHandler.BaseContext(),
-		}
-		s3.init()
-		s.RegisterOnShutdown(func() {
-			s3.shutdown(stdHandler.ShutdownContext())
-		})
-		stdHandler.ListenErrHook(s3.listenAndServe(stdHandler.Addr()))
-	}
-}
-
-func (s *server) init() {
+func (s *Server) init() {
 	s.initOnce.Do(func() {
-		s.config = initConfig(s.config)
-		if s.handler == nil {
-			s.handler = http.DefaultServeMux
-		}
+		s.config = initConfig(&quic.Config{TLSConfig: s.TLSConfig})
+		s.serveCtx = s.BaseContext
 		if s.serveCtx == nil {
 			s.serveCtx = context.Background()
 		}
-		if s.listenQUIC == nil {
-			s.listenQUIC = func(addr string, config *quic.Config) (*quic.Endpoint, error) {
+		if s.ListenQUIC == nil {
+			s.ListenQUIC = func(addr string, config *quic.Config) (*quic.Endpoint, error) {
 				return quic.Listen("udp", addr, config)
 			}
 		}
@@ -129,40 +86,64 @@ func (s *server) init() {
 	})
 }
 
-// listenAndServe listens on the UDP network address addr
-// and then calls Serve to handle requests on incoming connections.
-func (s *server) listenAndServe(addr string) error {
+// DefaultMaxHeaderBytes is the default value of Server.MaxHeaderBytes.
+// It matches net/http.DefaultMaxHeaderBytes.
+const DefaultMaxHeaderBytes = 1 << 20 // 1 MB
+
+func (s *Server) maxHeaderBytes() int {
+	if s.MaxHeaderBytes > 0 {
+		return s.MaxHeaderBytes
+	}
+	return DefaultMaxHeaderBytes
+}
+
+// Listen opens a QUIC endpoint on the UDP network address addr,
+// and returns the endpoint's local address.
+// Connections are not accepted until Serve is called.
+func (s *Server) Listen(addr string) (netip.AddrPort, error) {
 	s.init()
-	e, err := s.listenQUIC(addr, s.config)
+	e, err := s.ListenQUIC(addr, s.config)
 	if err != nil {
-		return err
+		return netip.AddrPort{}, err
 	}
-	go s.serve(e)
-	return nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.endpoint != nil {
+		e.Close(canceledCtx)
+		return netip.AddrPort{}, errors.New("http3: Server is already listening")
+	}
+	s.endpoint = e
+	return e.LocalAddr(), nil
 }
 
-// serve accepts incoming connections on the QUIC endpoint e,
+// Serve accepts incoming connections on the endpoint opened by Listen,
 // and handles requests from those connections.
-func (s *server) serve(e *quic.Endpoint) error {
+// It returns after Shutdown or Close is called.
+func (s *Server) Serve() error {
 	s.init()
+	s.mu.Lock()
+	e := s.endpoint
+	s.mu.Unlock()
+	if e == nil {
+		return errors.New("http3: Serve called before Listen")
+	}
 	defer e.Close(canceledCtx)
 	for {
 		qconn, err := e.Accept(s.serveCtx)
 		if err != nil {
 			return err
 		}
-		go s.newServerConn(qconn, s.handler)
+		go s.newServerConn(qconn)
 	}
 }
 
-// shutdown attempts a graceful shutdown for the server.
-func (s *server) shutdown(ctx context.Context) {
-	// Set a reasonable default in case ctx is nil.
-	if ctx == nil {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
-		defer cancel()
-	}
+// Shutdown attempts a graceful shutdown of the server.
+// It sends a GOAWAY frame on every connection and waits for
+// the connections to close or for ctx to be done,
+// whichever comes first.
+// Remaining connections are then closed.
+func (s *Server) Shutdown(ctx context.Context) {
+	s.init()
 
 	// Send GOAWAY frames to all active connections to give a chance for them
 	// to gracefully terminate.
@@ -176,17 +157,7 @@ func (s *server) shutdown(ctx context.Context) {
 
 	// Complete shutdown as soon as there are no more active connections or ctx
 	// is done, whichever comes first.
-	defer func() {
-		s.mu.Lock()
-		defer s.mu.Unlock()
-		s.serveCtxCancel()
-		for sc := range s.activeConns {
-			sc.abort(&connectionError{
-				code:    errH3NoError,
-				message: "server is shutting down",
-			})
-		}
-	}()
+	defer s.Close()
 	noMoreConns := func() bool {
 		s.mu.Lock()
 		defer s.mu.Unlock()
@@ -204,13 +175,27 @@ func (s *server) shutdown(ctx context.Context) {
 	}
 }
 
-func (s *server) registerConn(sc *serverConn) {
+// Close immediately closes the server's endpoint and all its connections.
+func (s *Server) Close() {
+	s.init()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.serveCtxCancel()
+	for sc := range s.activeConns {
+		sc.abort(&connectionError{
+			code:    errH3NoError,
+			message: "server is shutting down",
+		})
+	}
+}
+
+func (s *Server) registerConn(sc *serverConn) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.activeConns[sc] = struct{}{}
 }
 
-func (s *server) unregisterConn(sc *serverConn) {
+func (s *Server) unregisterConn(sc *serverConn) {
 	s.mu.Lock()
 	delete(s.activeConns, sc)
 	s.mu.Unlock()
@@ -223,12 +208,14 @@ func (s *server) unregisterConn(sc *serverConn) {
 }
 
 type serverConn struct {
+	srv   *Server
 	qconn *quic.Conn
+	ctx   context.Context // base context for requests on this conn
+	tls   tls.ConnectionState
 
 	genericConn // for handleUnidirectionalStream
 	enc         qpackEncoder
 	dec         qpackDecoder
-	handler     http.Handler
 
 	// For handling shutdown.
 	controlStream      *stream
@@ -237,10 +224,16 @@ type serverConn struct {
 	goawaySent         bool
 }
 
-func (s *server) newServerConn(qconn *quic.Conn, handler http.Handler) {
+func (s *Server) newServerConn(qconn *quic.Conn) {
+	ctx := s.serveCtx
+	if LocalAddrContextKey != nil {
+		ctx = context.WithValue(ctx, LocalAddrContextKey, net.UDPAddrFromAddrPort(qconn.LocalAddr()))
+	}
 	sc := &serverConn{
-		qconn:   qconn,
-		handler: handler,
+		srv:   s,
+		qconn: qconn,
+		ctx:   ctx,
+		tls:   qconn.ConnectionState(),
 	}
 	s.registerConn(sc)
 	defer s.unregisterConn(sc)
@@ -253,7 +246,7 @@ func (s *server) newServerConn(qconn *quic.Conn, handler http.Handler) {
 	if err != nil {
 		return
 	}
-	sc.controlStream.writeSettings()
+	sc.controlStream.writeSettings(settingsMaxFieldSectionSize, int64(s.maxHeaderBytes()))
 	sc.controlStream.Flush()
 
 	sc.acceptStreams(sc.qconn, sc)
@@ -305,12 +298,14 @@ func (sc *serverConn) handleControlStream(st *stream) error {
 }
 
 func (sc *serverConn) handleEncoderStream(*stream) error {
-	// TODO
+	// We advertise a QPACK dynamic table capacity of zero,
+	// so the peer's encoder stream carries nothing we need.
 	return nil
 }
 
 func (sc *serverConn) handleDecoderStream(*stream) error {
-	// TODO
+	// We never use the dynamic table when encoding,
+	// so the peer's decoder stream carries nothing we need.
 	return nil
 }
 
@@ -331,15 +326,27 @@ type pseudoHeader struct {
 	authority string
 }
 
-func (sc *serverConn) parseHeader(st *stream) (http.Header, pseudoHeader, error) {
+func (sc *serverConn) parseHeader(st *stream) (Header, pseudoHeader, error) {
 	ftype, err := st.readFrameHeader()
 	if err != nil {
 		return nil, pseudoHeader{}, err
 	}
 	if ftype != frameTypeHeaders {
-		return nil, pseudoHeader{}, err
+		// "Receipt of an invalid sequence of frames MUST be treated
+		// as a connection error of type H3_FRAME_UNEXPECTED."
+		// https://www.rfc-editor.org/rfc/rfc9114.html#section-4.1-7
+		return nil, pseudoHeader{}, &connectionError{
+			code:    errH3FrameUnexpected,
+			message: "request stream did not start with HEADERS",
+		}
 	}
-	header := make(http.Header)
+	if st.lim > int64(sc.srv.maxHeaderBytes()) {
+		return nil, pseudoHeader{}, &streamError{
+			code:    errH3ExcessiveLoad,
+			message: "request header too large",
+		}
+	}
+	header := make(Header)
 	var pHeader pseudoHeader
 	var dec qpackDecoder
 	if err := dec.decode(st, func(_ indexType, name, value string) error {
@@ -423,8 +430,8 @@ func (sc *serverConn) handleRequestStream(st *stream) error {
 
 	var body io.ReadCloser
 	contentLength := int64(-1)
-	if n, err := strconv.Atoi(header.Get("Content-Length")); err == nil {
-		contentLength = int64(n)
+	if n, err := strconv.ParseInt(header.Get("Content-Length"), 10, 63); err == nil {
+		contentLength = n
 	}
 	if contentLength != 0 || len(reqInfo.Trailer) != 0 {
 		body = &bodyReader{
@@ -433,28 +440,34 @@ func (sc *serverConn) handleRequestStream(st *stream) error {
 			trailer: reqInfo.Trailer,
 		}
 	} else {
-		body = http.NoBody
+		body = NoBody
+	}
+	host := pHeader.authority
+	if host == "" {
+		host = header.Get("Host")
 	}
 
-	req := &http.Request{
-		Proto:         "HTTP/3.0",
+	ctx, cancel := context.WithCancel(sc.ctx)
+	defer cancel()
+	req := &ServerRequest{
+		Context:       ctx,
 		Method:        pHeader.method,
-		Host:          pHeader.authority,
+		Host:          host,
 		URL:           reqInfo.URL,
 		RequestURI:    reqInfo.RequestURI,
 		Trailer:       reqInfo.Trailer,
-		ProtoMajor:    3,
 		RemoteAddr:    sc.qconn.RemoteAddr().String(),
 		Body:          body,
 		Header:        header,
 		ContentLength: contentLength,
+		TLS:           &sc.tls,
 	}
 	defer req.Body.Close()
 
 	rw := &responseWriter{
 		st:             st,
-		headers:        make(http.Header),
-		trailer:        make(http.Header),
+		headers:        make(Header),
+		trailer:        make(Header),
 		bb:             make(bodyBuffer, 0, defaultBodyBufferCap),
 		cannotHaveBody: req.Method == "HEAD",
 		bw: &bodyWriter{
@@ -465,18 +478,50 @@ func (sc *serverConn) handleRequestStream(st *stream) error {
 			enc:    &sc.enc,
 		},
 	}
-	defer rw.close()
 	if reqInfo.NeedsContinue {
 		req.Body.(*bodyReader).send100Continue = func() {
 			rw.WriteHeader(100)
 		}
 	}
 
-	// TODO: handle panic coming from the HTTP handler.
-	sc.handler.ServeHTTP(rw, req)
+	if err := sc.runHandler(rw, req); err != nil {
+		return err
+	}
+	rw.close()
+	return nil
+}
+
+// runHandler calls the server's Handler,
+// recovering from any panic it raises.
+func (sc *serverConn) runHandler(rw *responseWriter, req *ServerRequest) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			if e != ErrAbortHandler {
+				const size = 64 << 10
+				buf := make([]byte, size)
+				buf = buf[:runtime.Stack(buf, false)]
+				sc.logf("http3: panic serving %v: %v\n%s", req.RemoteAddr, e, buf)
+			}
+			// Reset the stream so the client sees the response was
+			// not completed, rather than a truncated body.
+			err = &streamError{
+				code:    errH3InternalError,
+				message: "handler panicked",
+			}
+		}
+	}()
+	sc.srv.Handler.ServeHTTP(rw, req)
 	return nil
 }
 
+func (sc *serverConn) logf(format string, args ...any) {
+	if lg := sc.srv.ErrorLog; lg != nil {
+		lg.Printf(format, args...)
+	} else {
+		log.Printf(format, args...)
+	}
+}
+
 // abort closes the connection with an error.
 func (sc *serverConn) abort(err error) {
 	if e, ok := err.(*connectionError); ok {
@@ -507,8 +552,8 @@ type responseWriter struct {
 	st             *stream
 	bw             *bodyWriter
 	mu             sync.Mutex
-	headers        http.Header
-	trailer        http.Header
+	headers        Header
+	trailer        Header
 	bb             bodyBuffer
 	wroteHeader    bool // Non-1xx header has been (logically) written.
 	statusCode     int  // Status of the response that will be sent in HEADERS frame.
@@ -517,7 +562,8 @@ type responseWriter struct {
 	bodyLenLeft    int  // How much of the content body is left to be sent, set via "Content-Length" header. -1 if unknown.
 }
 
-func (rw *responseWriter) Header() http.Header {
+// Header returns the response header map.
+func (rw *responseWriter) Header() Header {
 	return rw.headers
 }
 
@@ -631,8 +677,8 @@ func checkWriteHeaderCode(code int) {
 	}
 }
 
+// WriteHeader sends a response header with the provided status code.
 func (rw *responseWriter) WriteHeader(statusCode int) {
-	// TODO: handle sending informational status headers (e.g. 103).
 	rw.mu.Lock()
 	defer rw.mu.Unlock()
 	if rw.statusCodeSet {
@@ -672,21 +718,22 @@ func (rw *responseWriter) trimWriteLocked(b []byte) ([]byte, bool) {
 	return b[:n], n != len(b)
 }
 
+// Write writes the data to the response body.
 func (rw *responseWriter) Write(b []byte) (n int, err error) {
 	// Calling Write implicitly calls WriteHeader(200) if WriteHeader has not
 	// been called before.
-	rw.WriteHeader(http.StatusOK)
+	rw.WriteHeader(200)
 	rw.mu.Lock()
 	defer rw.mu.Unlock()
 
-	if rw.statusCode == http.StatusNotModified {
-		return 0, http.ErrBodyNotAllowed
+	if rw.statusCode == 304 {
+		return 0, ErrBodyNotAllowed
 	}
 
 	b, trimmed := rw.trimWriteLocked(b)
 	if trimmed {
 		defer func() {
-			err = http.ErrContentLength
+			err = ErrContentLength
 		}()
 	}
 
@@ -721,18 +768,26 @@ func (rw *responseWriter) Write(b []byte) (n int, err error) {
 	return initialBLen, nil
 }
 
+// Flush sends any buffered data to the client.
 func (rw *responseWriter) Flush() {
+	rw.FlushError()
+}
+
+// FlushError is like Flush, but returns any error encountered.
+func (rw *responseWriter) FlushError() error {
 	// Calling Flush implicitly calls WriteHeader(200) if WriteHeader has not
 	// been called before.
-	rw.WriteHeader(http.StatusOK)
+	rw.WriteHeader(200)
 	rw.mu.Lock()
 	defer rw.mu.Unlock()
 	rw.writeHeaderLockedOnce()
 	if !rw.cannotHaveBody {
-		rw.bw.Write(rw.bb)
+		if _, err := rw.bw.Write(rw.bb); err != nil {
+			return err
+		}
 		rw.bb.discard()
 	}
-	rw.st.Flush()
+	return rw.st.Flush()
 }
 
 func (rw *responseWriter) close() error {
@@ -746,6 +801,9 @@ func (rw *responseWriter) close() error {
 	return rw.st.stream.Close()
 }
 
+// timeFormat is net/http.TimeFormat.
+const timeFormat = "Mon, 02 Jan 2006 15:04:05 GMT"
+
 // defaultBodyBufferCap is the default number of bytes of body that we are
 // willing to save in a buffer for the sake of inferring headers and coalescing
 // small writes. 512 was chosen to be consistent with how much
@@ -773,16 +831,16 @@ func (bb *bodyBuffer) discard() {
 // called only once with as much body content as possible in the buffer, before
 // a HEADERS frame is sent, and before discard has been called. Doing so
 // properly is the responsibility of the caller.
-func (bb *bodyBuffer) inferHeader(h http.Header, status int) {
+func (bb *bodyBuffer) inferHeader(h Header, status int) {
 	if _, ok := h["Date"]; !ok {
-		h.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+		h.Set("Date", time.Now().UTC().Format(timeFormat))
 	}
 	// If the Content-Encoding is non-blank, we shouldn't
 	// sniff the body. See Issue golang.org/issue/31753.
 	_, hasCE := h["Content-Encoding"]
 	_, hasCT := h["Content-Type"]
 	if !hasCE && !hasCT && responseCanHaveBody(status) && len(*bb) > 0 {
-		h.Set("Content-Type", http.DetectContentType(*bb))
+		h.Set("Content-Type", internal.DetectContentType(*bb))
 	}
 	// We can technically infer Content-Length too here, as long as the entire
 	// response body fits within hi.buf and does not require flushing. However,
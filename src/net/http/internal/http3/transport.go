@@ -0,0 +1,403 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http3
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/net/quic"
+)
+
+// A Transport is an HTTP/3 transport.
+//
+// It does not manage a pool of connections.
+// The net/http Transport pools the connections created by Dial.
+type Transport struct {
+	// config is the QUIC configuration used for client connections.
+	config *quic.Config
+
+	t1 TransportConfig
+
+	// listenQUIC opens the endpoint used by Dial.
+	// It is quic.Listen unless overridden by a test.
+	listenQUIC func(network, address string, config *quic.Config) (*quic.Endpoint, error)
+
+	mu sync.Mutex // Guards fields below.
+	// endpoint is the QUIC endpoint used by connections created by the
+	// transport. If CloseIdleConnections is called when activeConns is empty,
+	// endpoint will be unset. If unset, endpoint will be initialized by any
+	// call to dial.
+	endpoint      *quic.Endpoint
+	activeConns   map[*ClientConn]struct{}
+	inFlightDials int
+}
+
+// TransportConfig is configuration from an http.Transport.
+type TransportConfig interface {
+	DisableCompression() bool
+	EnableZstd() bool
+	MaxResponseHeaderBytes() int64
+}
+
+// NewTransport returns a Transport that dials connections
+// using tlsConfig, which may be nil.
+// The tlsConfig is cloned and adjusted to negotiate "h3".
+func NewTransport(tlsConfig *tls.Config, t1 TransportConfig) *Transport {
+	return &Transport{
+		// initConfig will clone the tlsConfig.
+		config: initConfig(&quic.Config{
+			TLSConfig: tlsConfig,
+		}),
+		t1:          t1,
+		listenQUIC:  quic.Listen,
+		activeConns: make(map[*ClientConn]struct{}),
+	}
+}
+
+func (tr *Transport) incInFlightDials() {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.inFlightDials++
+}
+
+func (tr *Transport) decInFlightDials() {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.inFlightDials--
+}
+
+func (tr *Transport) initEndpoint() (err error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if tr.endpoint == nil {
+		tr.endpoint, err = tr.listenQUIC("udp", ":0", nil)
+	}
+	return err
+}
+
+// Dial creates a new HTTP/3 client connection to the UDP address addr.
+//
+// The internalStateHook, if not nil, is called when the connection closes
+// and when a request on the connection completes.
+func (tr *Transport) Dial(ctx context.Context, addr string, internalStateHook func()) (*ClientConn, error) {
+	tr.incInFlightDials()
+	defer tr.decInFlightDials()
+
+	if err := tr.initEndpoint(); err != nil {
+		return nil, err
+	}
+	qconn, err := tr.endpoint.Dial(ctx, "udp", addr, tr.config)
+	if err != nil {
+		return nil, err
+	}
+	return tr.newClientConn(ctx, qconn, internalStateHook)
+}
+
+// CloseIdleConnections is called by net/http.Transport.CloseIdleConnections
+// after all existing idle connections are closed using ClientConn.Close.
+//
+// When the transport has no active connections anymore, calling this method
+// will make the transport clean up any shared resources that are no longer
+// required, such as its QUIC endpoint.
+func (tr *Transport) CloseIdleConnections() {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if tr.endpoint == nil || len(tr.activeConns) > 0 || tr.inFlightDials > 0 {
+		return
+	}
+	tr.endpoint.Close(canceledCtx)
+	tr.endpoint = nil
+}
+
+// A ClientConn is a client HTTP/3 connection.
+//
+// Multiple goroutines may invoke methods on a ClientConn simultaneously.
+type ClientConn struct {
+	qconn *quic.Conn
+	t1    TransportConfig
+	genericConn
+
+	enc qpackEncoder
+	dec qpackDecoder
+
+	internalStateHook func()
+
+	mu              sync.Mutex // guards fields below
+	err             error      // non-nil once the conn is unusable
+	streamsReserved int
+	streamsInFlight int
+}
+
+// maxConcurrentStreams is the number of requests a ClientConn reports
+// as available.
+//
+// The QUIC layer enforces the peer's actual stream limit by blocking the
+// creation of new streams; this value is only used to decide when the
+// net/http connection pool should prefer another connection.
+// It matches the default limit of our QUIC implementation.
+const maxConcurrentStreams = 100
+
+// ErrNoCachedConn is returned by ClientConn.RoundTrip when the connection
+// can no longer be used and no part of the request has been sent.
+// The request may be retried on another connection.
+var ErrNoCachedConn = errors.New("http3: no cached connection was available")
+
+// errClientConnClosed is the error reported by Err after the
+// connection has closed.
+var errClientConnClosed = errors.New("http3: client connection closed")
+
+func (tr *Transport) registerConn(cc *ClientConn) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.activeConns[cc] = struct{}{}
+}
+
+func (tr *Transport) unregisterConn(cc *ClientConn) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	delete(tr.activeConns, cc)
+}
+
+func (tr *Transport) newClientConn(ctx context.Context, qconn *quic.Conn, internalStateHook func()) (*ClientConn, error) {
+	cc := &ClientConn{
+		qconn:             qconn,
+		t1:                tr.t1,
+		internalStateHook: internalStateHook,
+	}
+	tr.registerConn(cc)
+	cc.enc.init()
+
+	// Create control stream and send SETTINGS frame.
+	controlStream, err := newConnStream(ctx, cc.qconn, streamTypeControl)
+	if err != nil {
+		tr.unregisterConn(cc)
+		qconn.Abort(nil)
+		return nil, fmt.Errorf("http3: cannot create control stream: %v", err)
+	}
+	controlStream.writeSettings()
+	controlStream.Flush()
+
+	go func() {
+		cc.acceptStreams(qconn, cc)
+		cc.setErr(errClientConnClosed)
+		tr.unregisterConn(cc)
+	}()
+	return cc, nil
+}
+
+// setErr marks the connection as unusable for new requests.
+func (cc *ClientConn) setErr(err error) {
+	cc.mu.Lock()
+	if cc.err == nil {
+		cc.err = err
+	}
+	cc.mu.Unlock()
+	cc.maybeCallStateHook()
+}
+
+func (cc *ClientConn) maybeCallStateHook() {
+	if cc.internalStateHook != nil {
+		cc.internalStateHook()
+	}
+}
+
+// Close closes the connection.
+// Outstanding requests are interrupted.
+func (cc *ClientConn) Close() error {
+	cc.mu.Lock()
+	if cc.err == nil {
+		cc.err = errClientConnClosed
+	}
+	cc.mu.Unlock()
+	// We need to use Close rather than Abort on the QUIC connection.
+	// Otherwise, when a net/http.Transport.CloseIdleConnections is called, it
+	// might call the http3.Transport.CloseIdleConnections prior to all idle
+	// connections being fully closed; this would make it unable to close its
+	// QUIC endpoint, making http3.Transport.CloseIdleConnections a no-op
+	// unintentionally.
+	return cc.qconn.Close()
+}
+
+// Err reports any fatal connection errors.
+// It returns nil if the connection is usable.
+func (cc *ClientConn) Err() error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	return cc.err
+}
+
+// Reserve reserves a request slot on the connection.
+func (cc *ClientConn) Reserve() error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.err != nil || cc.streamsReserved+cc.streamsInFlight >= maxConcurrentStreams {
+		return errors.New("connection is unavailable")
+	}
+	cc.streamsReserved++
+	return nil
+}
+
+// Release releases a slot reserved by Reserve.
+func (cc *ClientConn) Release() {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	// We don't complain if streamsReserved is 0.
+	//
+	// This is consistent with RoundTrip: both Release and RoundTrip will
+	// consume a reservation iff one exists.
+	if cc.streamsReserved > 0 {
+		cc.streamsReserved--
+	}
+}
+
+// Available returns the number of requests which may be
+// sent on the connection without blocking.
+func (cc *ClientConn) Available() int {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.err != nil {
+		return 0
+	}
+	return max(0, maxConcurrentStreams-cc.streamsReserved-cc.streamsInFlight)
+}
+
+// InFlight returns the number of requests in flight on the connection.
+func (cc *ClientConn) InFlight() int {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	return cc.streamsInFlight
+}
+
+// maxHeaderBytes is the maximum size of a response's encoded field section.
+func (cc *ClientConn) maxHeaderBytes() int64 {
+	if cc.t1 != nil {
+		if n := cc.t1.MaxResponseHeaderBytes(); n > 0 {
+			return n
+		}
+	}
+	return 10 << 20 // matches the net/http Transport default
+}
+
+// startRequest records the start of a request,
+// consuming a reservation if one exists.
+func (cc *ClientConn) startRequest() error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.err != nil {
+		return ErrNoCachedConn
+	}
+	if cc.streamsReserved > 0 {
+		cc.streamsReserved--
+	}
+	cc.streamsInFlight++
+	return nil
+}
+
+// endRequest records the completion of a request.
+func (cc *ClientConn) endRequest() {
+	cc.mu.Lock()
+	cc.streamsInFlight--
+	cc.mu.Unlock()
+	cc.maybeCallStateHook()
+}
+
+func (cc *ClientConn) handleControlStream(st *stream) error {
+	// "A SETTINGS frame MUST be sent as the first frame of each control stream [...]"
+	// https://www.rfc-editor.org/rfc/rfc9114.html#section-7.2.4-2
+	if err := st.readSettings(func(settingsType, settingsValue int64) error {
+		switch settingsType {
+		case settingsMaxFieldSectionSize:
+			_ = settingsValue // TODO
+		case settingsQPACKMaxTableCapacity:
+			_ = settingsValue // TODO
+		case settingsQPACKBlockedStreams:
+			_ = settingsValue // TODO
+		default:
+			// Unknown settings types are ignored.
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for {
+		ftype, err := st.readFrameHeader()
+		if err != nil {
+			return err
+		}
+		switch ftype {
+		case frameTypeCancelPush:
+			// "If a CANCEL_PUSH frame is received that references a push ID
+			// greater than currently allowed on the connection,
+			// this MUST be treated as a connection error of type H3_ID_ERROR."
+			// https://www.rfc-editor.org/rfc/rfc9114.html#section-7.2.3-7
+			return &connectionError{
+				code:    errH3IDError,
+				message: "CANCEL_PUSH received when no MAX_PUSH_ID has been sent",
+			}
+		case frameTypeGoaway:
+			// The server will not process new requests on this connection.
+			// Requests already in flight continue until the server closes
+			// the connection.
+			if err := st.discardFrame(); err != nil {
+				return err
+			}
+			cc.setErr(errors.New("http3: server sent GOAWAY"))
+		default:
+			// Unknown frames are ignored.
+			if err := st.discardUnknownFrame(ftype); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (cc *ClientConn) handleEncoderStream(*stream) error {
+	// We advertise a QPACK dynamic table capacity of zero,
+	// so the peer's encoder stream carries nothing we need.
+	return nil
+}
+
+func (cc *ClientConn) handleDecoderStream(*stream) error {
+	// We never use the dynamic table when encoding,
+	// so the peer's decoder stream carries nothing we need.
+	return nil
+}
+
+func (cc *ClientConn) handlePushStream(*stream) error {
+	// "A client MUST treat receipt of a push stream as a connection error
+	// of type H3_ID_ERROR when no MAX_PUSH_ID frame has been sent [...]"
+	// https://www.rfc-editor.org/rfc/rfc9114.html#section-4.6-3
+	return &connectionError{
+		code:    errH3IDError,
+		message: "push stream created when no MAX_PUSH_ID has been sent",
+	}
+}
+
+func (cc *ClientConn) handleRequestStream(st *stream) error {
+	// "Clients MUST treat receipt of a server-initiated bidirectional
+	// stream as a connection error of type H3_STREAM_CREATION_ERROR [...]"
+	// https://www.rfc-editor.org/rfc/rfc9114.html#section-6.1-3
+	return &connectionError{
+		code:    errH3StreamCreationError,
+		message: "server created bidirectional stream",
+	}
+}
+
+// abort closes the connection with an error.
+func (cc *ClientConn) abort(err error) {
+	if e, ok := err.(*connectionError); ok {
+		cc.qconn.Abort(&quic.ApplicationError{
+			Code:   uint64(e.code),
+			Reason: e.message,
+		})
+	} else {
+		cc.qconn.Abort(err)
+	}
+}
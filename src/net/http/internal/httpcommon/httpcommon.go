@@ -194,6 +194,10 @@ type EncodeHeadersParam struct {
 	// added to the request.
 	AddGzipHeader bool
 
+	// AddZstdHeader indicates that zstd should be included in the
+	// "accept-encoding" header added when AddGzipHeader is set.
+	AddZstdHeader bool
+
 	// PeerMaxHeaderListSize, when non-zero, is the peer's MAX_HEADER_LIST_SIZE setting.
 	PeerMaxHeaderListSize uint64
 
@@ -367,7 +371,9 @@ func EncodeHeaders(ctx context.Context, param EncodeHeadersParam, headerf func(n
 		if shouldSendReqContentLength(req.Method, req.ActualContentLength) {
 			f("content-length", strconv.FormatInt(req.ActualContentLength, 10))
 		}
-		if param.AddGzipHeader {
+		if param.AddGzipHeader && param.AddZstdHeader {
+			f("accept-encoding", "zstd, gzip")
+		} else if param.AddGzipHeader {
 			f("accept-encoding", "gzip")
 		}
 		if !didUA {
@@ -7,6 +7,7 @@ package internal
 import (
 	"bytes"
 	"encoding/binary"
+	"strings"
 )
 
 // The algorithm uses at most SniffLen bytes to make its decision.
@@ -194,6 +195,44 @@ var sniffSignatures = []sniffSig{
 	textSig{}, // should be last
 }
 
+// compressedTypes is the set of media types, mostly from
+// sniffSignatures, whose content is already compressed.
+var compressedTypes = map[string]bool{
+	"image/gif":  true,
+	"image/webp": true,
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/avif": true,
+
+	"audio/mpeg":      true,
+	"audio/aac":       true,
+	"audio/flac":      true,
+	"audio/mp4":       true,
+	"audio/ogg":       true,
+	"audio/webm":      true,
+	"application/ogg": true,
+
+	"font/woff":  true,
+	"font/woff2": true,
+
+	"application/gzip":             true,
+	"application/x-gzip":           true,
+	"application/zip":              true,
+	"application/zstd":             true,
+	"application/x-bzip2":          true,
+	"application/x-xz":             true,
+	"application/x-7z-compressed":  true,
+	"application/x-rar-compressed": true,
+}
+
+// IsCompressedType reports whether content of the media type mt,
+// such as "image/png", is already compressed, so that compressing it
+// again is unlikely to reduce its size. mt must be lower case, and
+// must not include parameters. All video types are considered compressed.
+func IsCompressedType(mt string) bool {
+	return compressedTypes[mt] || strings.HasPrefix(mt, "video/")
+}
+
 type exactSig struct {
 	sig []byte
 	ct  string
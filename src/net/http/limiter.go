@@ -0,0 +1,358 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RequestLimiter limits the number of requests handled concurrently,
+// overall and for each client, and the rate at which each client may
+// make requests.
+//
+// Clients are identified by a key, which is by default the IP address
+// of the request's RemoteAddr. See [RequestLimiter.SetKeyFunc].
+//
+// A request which would exceed the rate limit for its key is rejected
+// immediately. Only requests which are admitted count towards the rate
+// limit. A request which would exceed a concurrency limit waits
+// until another request finishes, or until the queue timeout set by
+// [RequestLimiter.SetQueueTimeout] expires, in which case it is rejected.
+// A request waiting for a concurrency limit is also rejected if its
+// context is done.
+//
+// Requests rejected because of a per-key limit should be responded to
+// with a 429 Too Many Requests status, and requests rejected because
+// of the overall concurrency limit with a 503 Service Unavailable status.
+//
+// The zero value of RequestLimiter is valid and imposes no limits.
+type RequestLimiter struct {
+	key  atomic.Pointer[func(*Request) string]
+	deny atomic.Pointer[Handler]
+
+	mu            sync.Mutex
+	maxConcurrent int
+	maxPerKey     int
+	rate          float64 // requests per second for each key
+	burst         int
+	queueTimeout  time.Duration
+	inflight      int
+	keys          map[string]*limiterKey
+	waiters       int
+	changed       chan struct{} // closed when a request finishes, if waiters > 0
+	lastSweep     time.Time
+}
+
+// limiterKey is the state of a key in a RequestLimiter.
+type limiterKey struct {
+	inflight int
+	waiters  int
+	tokens   float64   // rate limit tokens available at last
+	last     time.Time // time tokens was last updated
+}
+
+// limiterSweepInterval is how often a RequestLimiter
+// discards the state of idle keys.
+const limiterSweepInterval = time.Minute
+
+// NewRequestLimiter returns a new [RequestLimiter] value,
+// which imposes no limits until they are set.
+func NewRequestLimiter() *RequestLimiter {
+	return &RequestLimiter{}
+}
+
+// SetMaxConcurrent sets the maximum number of requests handled concurrently.
+// If n is zero, which is the default, the number is not limited.
+//
+// SetMaxConcurrent can be called concurrently with other methods
+// or request handling, and applies to future requests.
+func (l *RequestLimiter) SetMaxConcurrent(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxConcurrent = max(n, 0)
+}
+
+// SetMaxConcurrentPerKey sets the maximum number of requests with
+// the same key handled concurrently. If n is zero, which is the
+// default, the number is not limited.
+//
+// SetMaxConcurrentPerKey can be called concurrently with other methods
+// or request handling, and applies to future requests.
+func (l *RequestLimiter) SetMaxConcurrentPerKey(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxPerKey = max(n, 0)
+}
+
+// SetRate limits the rate of requests with each key to perSecond requests
+// per second, with bursts of up to burst requests. If perSecond is zero,
+// which is the default, the rate is not limited. If burst is less than 1,
+// 1 is used.
+//
+// SetRate can be called concurrently with other methods
+// or request handling, and applies to future requests.
+// Each key may then make up to burst requests immediately.
+func (l *RequestLimiter) SetRate(perSecond float64, burst int) {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = max(perSecond, 0)
+	l.burst = max(burst, 1)
+	// Keep the keys, which count their requests in progress.
+	for _, k := range l.keys {
+		k.tokens = float64(l.burst)
+		k.last = now
+	}
+}
+
+// SetQueueTimeout sets how long a request may wait for a concurrency limit.
+// If d is zero, which is the default, requests which would exceed a
+// concurrency limit are rejected without waiting.
+//
+// Time that a request spends waiting does not count against the
+// [Server.WriteTimeout] of the server handling it: when a request
+// handler returned by [RequestLimiter.Handler] admits a request which
+// has waited, it uses a [ResponseController] to extend the request's
+// write deadline.
+//
+// SetQueueTimeout can be called concurrently with other methods
+// or request handling, and applies to future requests.
+func (l *RequestLimiter) SetQueueTimeout(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.queueTimeout = max(d, 0)
+}
+
+// SetKeyFunc sets the function which returns the key identifying
+// the client making a request. If f is nil, which is the default,
+// the key is the IP address of the request's RemoteAddr.
+//
+// SetKeyFunc can be called concurrently with other methods
+// or request handling, and applies to future requests.
+func (l *RequestLimiter) SetKeyFunc(f func(*Request) string) {
+	if f == nil {
+		l.key.Store(nil)
+		return
+	}
+	l.key.Store(&f)
+}
+
+// SetDenyHandler sets a handler to invoke when a request is rejected.
+// The Retry-After header of the response has been set when the handler
+// is invoked. The default handler responds with a 429 Too Many Requests
+// or 503 Service Unavailable status, as described for [RequestLimiter].
+//
+// SetDenyHandler can be called concurrently with other methods
+// or request handling, and applies to future requests.
+//
+// Acquire does not call the deny handler.
+func (l *RequestLimiter) SetDenyHandler(h Handler) {
+	if h == nil {
+		l.deny.Store(nil)
+		return
+	}
+	l.deny.Store(&h)
+}
+
+// A LimitError is returned by [RequestLimiter.Acquire]
+// when a request is rejected.
+type LimitError struct {
+	// StatusCode is the status with which the request should be
+	// rejected: StatusTooManyRequests or StatusServiceUnavailable.
+	StatusCode int
+
+	// RetryAfter is how long the client should wait
+	// before making another request.
+	RetryAfter time.Duration
+}
+
+func (e *LimitError) Error() string {
+	return "http: request limit exceeded: " + StatusText(e.StatusCode)
+}
+
+// Acquire applies the limits to a request, waiting if necessary.
+// If the request is admitted, Acquire returns a function which the
+// caller must call when it has finished handling the request.
+// Otherwise, it returns a [*LimitError], or the error from the
+// request's context if the context is done while the request waits.
+func (l *RequestLimiter) Acquire(req *Request) (release func(), err error) {
+	release, _, err = l.acquire(req)
+	return release, err
+}
+
+// acquire is Acquire, and also reports whether the request waited.
+func (l *RequestLimiter) acquire(req *Request) (release func(), waited bool, err error) {
+	key := l.keyOf(req)
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweepLocked(now)
+	k := l.keys[key]
+	if k == nil {
+		k = &limiterKey{tokens: float64(l.burst), last: now}
+		if l.keys == nil {
+			l.keys = make(map[string]*limiterKey)
+		}
+		l.keys[key] = k
+	}
+	if err := l.checkRateLocked(k, now); err != nil {
+		return nil, false, err
+	}
+
+	var timer *time.Timer
+	for {
+		keyFull := l.maxPerKey > 0 && k.inflight >= l.maxPerKey
+		full := l.maxConcurrent > 0 && l.inflight >= l.maxConcurrent
+		if !keyFull && !full {
+			break
+		}
+		if timer == nil {
+			if l.queueTimeout <= 0 {
+				return nil, false, l.busyError(keyFull)
+			}
+			timer = time.NewTimer(l.queueTimeout)
+			defer timer.Stop()
+		}
+		if l.changed == nil {
+			l.changed = make(chan struct{})
+		}
+		changed := l.changed
+		l.waiters++
+		k.waiters++
+		l.mu.Unlock()
+		var err error
+		select {
+		case <-changed:
+		case <-timer.C:
+			err = l.busyError(keyFull)
+		case <-req.Context().Done():
+			err = req.Context().Err()
+		}
+		l.mu.Lock()
+		l.waiters--
+		k.waiters--
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	// Other requests with the key may have been admitted while
+	// this one waited.
+	if timer != nil {
+		if err := l.checkRateLocked(k, time.Now()); err != nil {
+			return nil, false, err
+		}
+	}
+	if l.rate > 0 {
+		k.tokens--
+	}
+	l.inflight++
+	k.inflight++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.inflight--
+			k.inflight--
+			if l.waiters > 0 {
+				close(l.changed)
+				l.changed = nil
+			}
+		})
+	}, timer != nil, nil
+}
+
+// checkRateLocked updates the rate limit tokens of k at time now,
+// and returns an error if a request with the key would exceed the rate.
+func (l *RequestLimiter) checkRateLocked(k *limiterKey, now time.Time) error {
+	if l.rate <= 0 {
+		return nil
+	}
+	k.tokens = min(k.tokens+now.Sub(k.last).Seconds()*l.rate, float64(l.burst))
+	k.last = now
+	if k.tokens < 1 {
+		wait := time.Duration((1 - k.tokens) / l.rate * float64(time.Second))
+		return &LimitError{StatusCode: StatusTooManyRequests, RetryAfter: wait}
+	}
+	return nil
+}
+
+// busyError returns the error for a request which could not be
+// admitted within the queue timeout.
+func (l *RequestLimiter) busyError(keyFull bool) error {
+	if keyFull {
+		return &LimitError{StatusCode: StatusTooManyRequests, RetryAfter: time.Second}
+	}
+	return &LimitError{StatusCode: StatusServiceUnavailable, RetryAfter: time.Second}
+}
+
+// keyOf returns the key of req.
+func (l *RequestLimiter) keyOf(req *Request) string {
+	if f := l.key.Load(); f != nil {
+		return (*f)(req)
+	}
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		return host
+	}
+	return req.RemoteAddr
+}
+
+// sweepLocked periodically discards the state of keys which have
+// no requests in progress and a full rate limit bucket.
+func (l *RequestLimiter) sweepLocked(now time.Time) {
+	if now.Sub(l.lastSweep) < limiterSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, k := range l.keys {
+		if k.inflight > 0 || k.waiters > 0 {
+			continue
+		}
+		if l.rate > 0 && k.tokens+now.Sub(k.last).Seconds()*l.rate < float64(l.burst) {
+			continue
+		}
+		delete(l.keys, key)
+	}
+}
+
+// Handler returns a handler that applies the limits
+// before invoking the handler h.
+//
+// If a request is rejected, the response has a Retry-After header
+// and a 429 Too Many Requests or 503 Service Unavailable status,
+// or is handled by the handler passed to [RequestLimiter.SetDenyHandler].
+func (l *RequestLimiter) Handler(h Handler) Handler {
+	return HandlerFunc(func(w ResponseWriter, r *Request) {
+		release, waited, err := l.acquire(r)
+		if err != nil {
+			le, ok := errors.AsType[*LimitError](err)
+			if !ok {
+				le = &LimitError{StatusCode: StatusServiceUnavailable, RetryAfter: time.Second}
+			}
+			secs := max(int64((le.RetryAfter+time.Second-1)/time.Second), 1)
+			w.Header().Set("Retry-After", strconv.FormatInt(secs, 10))
+			if deny := l.deny.Load(); deny != nil {
+				(*deny).ServeHTTP(w, r)
+				return
+			}
+			Error(w, StatusText(le.StatusCode), le.StatusCode)
+			return
+		}
+		defer release()
+		if waited {
+			if srv, ok := r.Context().Value(ServerContextKey).(*Server); ok && srv.WriteTimeout > 0 {
+				NewResponseController(w).SetWriteDeadline(time.Now().Add(srv.WriteTimeout))
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
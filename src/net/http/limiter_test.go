@@ -0,0 +1,279 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func limiterRequest(remoteAddr string) *http.Request {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = remoteAddr
+	return req
+}
+
+// serveLimited serves a request from remoteAddr with h,
+// and returns the response status and Retry-After header.
+func serveLimited(h http.Handler, remoteAddr string) (int, string) {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, limiterRequest(remoteAddr))
+	return w.Code, w.Header().Get("Retry-After")
+}
+
+func TestRequestLimiterZero(t *testing.T) {
+	var l http.RequestLimiter
+	h := l.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for range 100 {
+		if code, _ := serveLimited(h, "192.0.2.1:1234"); code != http.StatusOK {
+			t.Fatalf("zero RequestLimiter: status %d, want 200", code)
+		}
+	}
+}
+
+func TestRequestLimiterRate(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		l := http.NewRequestLimiter()
+		l.SetRate(1, 2)
+		h := l.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		for i := range 2 {
+			if code, _ := serveLimited(h, "192.0.2.1:1234"); code != http.StatusOK {
+				t.Errorf("request %d: status %d, want 200", i, code)
+			}
+		}
+		if code, retry := serveLimited(h, "192.0.2.1:5678"); code != http.StatusTooManyRequests || retry != "1" {
+			t.Errorf("request over rate: status %d, Retry-After %q; want 429, 1", code, retry)
+		}
+		if code, _ := serveLimited(h, "192.0.2.2:1234"); code != http.StatusOK {
+			t.Errorf("request with other key: status %d, want 200", code)
+		}
+		time.Sleep(1 * time.Second)
+		if code, _ := serveLimited(h, "192.0.2.1:1234"); code != http.StatusOK {
+			t.Errorf("request after wait: status %d, want 200", code)
+		}
+		if code, _ := serveLimited(h, "192.0.2.1:1234"); code != http.StatusTooManyRequests {
+			t.Errorf("second request after wait: status %d, want 429", code)
+		}
+	})
+}
+
+// blockingHandler returns a handler which blocks until unblock is closed.
+func blockingHandler(unblock <-chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Block") != "" {
+			<-unblock
+		}
+	})
+}
+
+func TestRequestLimiterConcurrency(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		l := http.NewRequestLimiter()
+		l.SetMaxConcurrent(1)
+		unblock := make(chan struct{})
+		h := l.Handler(blockingHandler(unblock))
+		go func() {
+			req := limiterRequest("192.0.2.1:1234")
+			req.Header.Set("Block", "1")
+			h.ServeHTTP(httptest.NewRecorder(), req)
+		}()
+		synctest.Wait()
+		if code, retry := serveLimited(h, "192.0.2.2:1234"); code != http.StatusServiceUnavailable || retry != "1" {
+			t.Errorf("request over limit: status %d, Retry-After %q; want 503, 1", code, retry)
+		}
+		close(unblock)
+		synctest.Wait()
+		if code, _ := serveLimited(h, "192.0.2.2:1234"); code != http.StatusOK {
+			t.Errorf("request after release: status %d, want 200", code)
+		}
+	})
+}
+
+func TestRequestLimiterQueue(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		l := http.NewRequestLimiter()
+		l.SetMaxConcurrent(1)
+		l.SetQueueTimeout(5 * time.Second)
+		unblock := make(chan struct{})
+		h := l.Handler(blockingHandler(unblock))
+		go func() {
+			req := limiterRequest("192.0.2.1:1234")
+			req.Header.Set("Block", "1")
+			h.ServeHTTP(httptest.NewRecorder(), req)
+		}()
+		synctest.Wait()
+
+		// A queued request is admitted when the first finishes.
+		start := time.Now()
+		go func() {
+			time.Sleep(2 * time.Second)
+			close(unblock)
+		}()
+		if code, _ := serveLimited(h, "192.0.2.2:1234"); code != http.StatusOK {
+			t.Errorf("queued request: status %d, want 200", code)
+		}
+		if d := time.Since(start); d != 2*time.Second {
+			t.Errorf("queued request waited %v, want 2s", d)
+		}
+
+		// A queued request is rejected when the queue timeout expires.
+		block := make(chan struct{})
+		go func() {
+			req := limiterRequest("192.0.2.1:1234")
+			req.Header.Set("Block", "1")
+			l.Handler(blockingHandler(block)).ServeHTTP(httptest.NewRecorder(), req)
+		}()
+		synctest.Wait()
+		start = time.Now()
+		if code, _ := serveLimited(h, "192.0.2.2:1234"); code != http.StatusServiceUnavailable {
+			t.Errorf("timed out request: status %d, want 503", code)
+		}
+		if d := time.Since(start); d != 5*time.Second {
+			t.Errorf("timed out request waited %v, want 5s", d)
+		}
+		close(block)
+	})
+}
+
+func TestRequestLimiterPerKey(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		l := http.NewRequestLimiter()
+		l.SetMaxConcurrentPerKey(1)
+		l.SetKeyFunc(func(r *http.Request) string { return r.Header.Get("User") })
+		unblock := make(chan struct{})
+		h := l.Handler(blockingHandler(unblock))
+		go func() {
+			req := limiterRequest("192.0.2.1:1234")
+			req.Header.Set("Block", "1")
+			req.Header.Set("User", "alice")
+			h.ServeHTTP(httptest.NewRecorder(), req)
+		}()
+		synctest.Wait()
+		for _, tt := range []struct {
+			user string
+			want int
+		}{
+			{"alice", http.StatusTooManyRequests},
+			{"bob", http.StatusOK},
+		} {
+			req := limiterRequest("192.0.2.1:1234")
+			req.Header.Set("User", tt.user)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("request from %s: status %d, want %d", tt.user, w.Code, tt.want)
+			}
+		}
+		close(unblock)
+	})
+}
+
+func TestRequestLimiterSetRateInFlight(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		l := http.NewRequestLimiter()
+		l.SetMaxConcurrentPerKey(1)
+		unblock := make(chan struct{})
+		h := l.Handler(blockingHandler(unblock))
+		go func() {
+			req := limiterRequest("192.0.2.1:1234")
+			req.Header.Set("Block", "1")
+			h.ServeHTTP(httptest.NewRecorder(), req)
+		}()
+		synctest.Wait()
+
+		// Changing the rate does not forget the request in progress.
+		l.SetRate(10, 10)
+		if code, _ := serveLimited(h, "192.0.2.1:1234"); code != http.StatusTooManyRequests {
+			t.Errorf("request after SetRate: status %d, want 429", code)
+		}
+		close(unblock)
+		synctest.Wait()
+		if code, _ := serveLimited(h, "192.0.2.1:1234"); code != http.StatusOK {
+			t.Errorf("request after release: status %d, want 200", code)
+		}
+	})
+}
+
+func TestRequestLimiterRateRejected(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		l := http.NewRequestLimiter()
+		l.SetMaxConcurrent(1)
+		l.SetRate(1, 1)
+		unblock := make(chan struct{})
+		h := l.Handler(blockingHandler(unblock))
+		go func() {
+			req := limiterRequest("192.0.2.2:1234")
+			req.Header.Set("Block", "1")
+			h.ServeHTTP(httptest.NewRecorder(), req)
+		}()
+		synctest.Wait()
+
+		// A request rejected for concurrency does not use up the rate.
+		if code, _ := serveLimited(h, "192.0.2.1:1234"); code != http.StatusServiceUnavailable {
+			t.Errorf("request over concurrency limit: status %d, want 503", code)
+		}
+		close(unblock)
+		synctest.Wait()
+		if code, _ := serveLimited(h, "192.0.2.1:1234"); code != http.StatusOK {
+			t.Errorf("request after release: status %d, want 200", code)
+		}
+	})
+}
+
+func TestRequestLimiterDenyHandler(t *testing.T) {
+	l := http.NewRequestLimiter()
+	l.SetRate(1, 1)
+	l.SetDenyHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if w.Header().Get("Retry-After") == "" {
+			t.Errorf("deny handler called without Retry-After header")
+		}
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	h := l.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serveLimited(h, "192.0.2.1:1234")
+	if code, _ := serveLimited(h, "192.0.2.1:1234"); code != http.StatusTeapot {
+		t.Errorf("denied request: status %d, want %d", code, http.StatusTeapot)
+	}
+}
+
+func TestRequestLimiterAcquire(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		l := http.NewRequestLimiter()
+		l.SetMaxConcurrent(1)
+		l.SetQueueTimeout(time.Minute)
+		release, err := l.Acquire(limiterRequest("192.0.2.1:1234"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithCancel(t.Context())
+		req := limiterRequest("192.0.2.1:1234").WithContext(ctx)
+		go func() {
+			time.Sleep(time.Second)
+			cancel()
+		}()
+		if _, err := l.Acquire(req); !errors.Is(err, context.Canceled) {
+			t.Errorf("Acquire with canceled context = %v, want context.Canceled", err)
+		}
+
+		release()
+		release() // no effect
+		release2, err := l.Acquire(limiterRequest("192.0.2.1:1234"))
+		if err != nil {
+			t.Fatalf("Acquire after release = %v", err)
+		}
+		l.SetQueueTimeout(0)
+		_, err = l.Acquire(limiterRequest("192.0.2.1:1234"))
+		if le, ok := errors.AsType[*http.LimitError](err); !ok || le.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("Acquire over limit = %v, want LimitError with status 503", err)
+		}
+		release2()
+	})
+}
@@ -0,0 +1,32 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build nethttpomithttp3
+
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+)
+
+func init() {
+	omitHTTP3 = true
+}
+
+var errNoHTTP3 = errors.New("http: HTTP/3 support omitted by the nethttpomithttp3 build tag")
+
+type http3Server struct{}
+
+func (*http3Server) Close()                   {}
+func (*http3Server) Shutdown(context.Context) {}
+
+func (s *Server) listenHTTP3(addr string, config *tls.Config, advertise bool) (*http3Server, error) {
+	return nil, errNoHTTP3
+}
+
+func (s *Server) serveHTTP3(*http3Server) error { return errNoHTTP3 }
+
+func (t *Transport) configureHTTP3() {}
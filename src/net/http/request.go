@@ -886,8 +886,8 @@ func NewRequest(method, url string, body io.Reader) (*Request, error) {
 // response headers and body. See the [Request] type's documentation for
 // the difference between inbound and outbound request fields.
 //
-// If body is of type [*bytes.Buffer], [*bytes.Reader], or
-// [*strings.Reader], the returned request's ContentLength is set to its
+// If body is of type [*bytes.Buffer], [*bytes.Reader], [*strings.Reader]
+// or [*multipart.Body], the returned request's ContentLength is set to its
 // exact value (instead of -1), GetBody is populated (so 307 and 308
 // redirects can replay the body), and Body is set to [NoBody] if the
 // ContentLength is 0.
@@ -948,6 +948,15 @@ func NewRequestWithContext(ctx context.Context, method, url string, body io.Read
 				r := snapshot
 				return io.NopCloser(&r), nil
 			}
+		case *multipart.Body:
+			req.ContentLength = v.Len()
+			req.GetBody = func() (io.ReadCloser, error) {
+				r, err := v.Open()
+				if err != nil {
+					return nil, err
+				}
+				return io.NopCloser(r), nil
+			}
 		default:
 			// This is where we'd set it to -1 (at least
 			// if body != NoBody) to mean unknown, but
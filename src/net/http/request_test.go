@@ -631,6 +631,56 @@ func TestNewRequestContentLength(t *testing.T) {
 	}
 }
 
+func TestNewRequestMultipartBody(t *testing.T) {
+	body := multipart.NewBody()
+	body.AddFormField("field", "value")
+	body.AddFormFile("file", "file.txt", strings.NewReader("file contents"), 13)
+	req, err := NewRequest("POST", "http://localhost/", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", body.FormDataContentType())
+	if req.ContentLength != body.Len() {
+		t.Errorf("ContentLength = %d, want %d", req.ContentLength, body.Len())
+	}
+	if req.GetBody == nil {
+		t.Fatal("GetBody is nil")
+	}
+
+	// Send the request twice, the second time with the body from GetBody.
+	for i := range 2 {
+		if i > 0 {
+			if req.Body, err = req.GetBody(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var buf bytes.Buffer
+		if err := req.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		sreq, err := ReadRequest(bufio.NewReader(&buf))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sreq.ContentLength != body.Len() {
+			t.Errorf("request %d: sent ContentLength = %d, want %d", i, sreq.ContentLength, body.Len())
+		}
+		if err := sreq.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("request %d: ParseMultipartForm: %v", i, err)
+		}
+		if got := sreq.FormValue("field"); got != "value" {
+			t.Errorf("request %d: field = %q, want %q", i, got, "value")
+		}
+		f, _, err := sreq.FormFile("file")
+		if err != nil {
+			t.Fatalf("request %d: FormFile: %v", i, err)
+		}
+		if b, _ := io.ReadAll(f); string(b) != "file contents" {
+			t.Errorf("request %d: file = %q, want %q", i, b, "file contents")
+		}
+	}
+}
+
 var parseHTTPVersionTests = []struct {
 	vers         string
 	major, minor int
@@ -3123,6 +3123,12 @@ type Server struct {
 	onShutdown []func()
 	h2         *http2Server
 	h3         *http3ServerHandler
+	h3srv      *http3Server
+
+	// h3AltSvc is the Alt-Svc header value advertising the
+	// HTTP/3 endpoint, if ListenAndServeTLS is serving HTTP/3
+	// alongside HTTP/1 or HTTP/2.
+	h3AltSvc atomic.Pointer[string]
 
 	listenerGroup sync.WaitGroup
 }
@@ -3154,6 +3160,9 @@ func (s *Server) Close() error {
 		c.rwc.Close()
 		delete(s.activeConn, c)
 	}
+	if s.h3srv != nil {
+		s.h3srv.Close()
+	}
 	return err
 }
 
@@ -3197,6 +3206,14 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	for _, f := range s.onShutdown {
 		go f()
 	}
+	var h3done chan struct{}
+	if h3srv := s.h3srv; h3srv != nil {
+		h3done = make(chan struct{})
+		go func() {
+			h3srv.Shutdown(ctx)
+			close(h3done)
+		}()
+	}
 	s.mu.Unlock()
 	s.listenerGroup.Wait()
 
@@ -3216,7 +3233,7 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	defer timer.Stop()
 	for {
 		if s.closeIdleConns() {
-			return lnerr
+			break
 		}
 		select {
 		case <-ctx.Done():
@@ -3225,6 +3242,14 @@ func (s *Server) Shutdown(ctx context.Context) error {
 			timer.Reset(nextPollInterval())
 		}
 	}
+	if h3done != nil {
+		select {
+		case <-h3done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return lnerr
 }
 
 // RegisterOnShutdown registers a function to call on [Server.Shutdown].
@@ -3349,6 +3374,7 @@ func (sh serverHandler) ServeHTTP(rw ResponseWriter, req *Request) {
 	if !sh.srv.DisableGeneralOptionsHandler && req.RequestURI == "*" && req.Method == "OPTIONS" {
 		handler = globalOptionsHandler{}
 	}
+	sh.srv.setAltSvc(rw, req)
 
 	handler.ServeHTTP(rw, req)
 }
@@ -3404,6 +3430,10 @@ func (s *Server) ListenAndServe() error {
 
 var testHookServerServe func(*Server, net.Listener) // used if non-nil
 
+// testHookHTTP3Listen is called with the UDP address of the HTTP/3
+// endpoint opened by ListenAndServeTLS.
+var testHookHTTP3Listen func(*Server, net.Addr)
+
 // shouldConfigureHTTP2ForServe reports whether Server.Serve should configure
 // automatic HTTP/2. (which sets up the s.TLSNextProto map)
 func (s *Server) shouldConfigureHTTP2ForServe() bool {
@@ -3752,9 +3782,8 @@ func ListenAndServeTLS(addr, certFile, keyFile string, handler Handler) error {
 // supports HTTP/3, allowing an external implementation of HTTP/3 to be used
 // via net/http. See https://go.dev/issue/77440 for details.
 //
-// This is currently only used with golang.org/x/net/internal/http3, to allow
-// us to test our HTTP/3 implementation againts tests in net/http. HTTP/3 is
-// not yet accessible to end-users.
+// If no external implementation is registered in [Server.TLSNextProto],
+// the implementation in net/http/internal/http3 is used.
 type http3ServerHandler struct {
 	handler     serverHandler
 	tlsConfig   *tls.Config
@@ -3828,34 +3857,42 @@ func (s *Server) ListenAndServeTLS(certFile, keyFile string) error {
 	}
 
 	p := s.protocols()
-	if p.http3() {
-		fn, ok := s.TLSNextProto["http/3"]
-		if !ok {
-			return errors.New("http: Server.Protocols contains HTTP3, but Server does not support HTTP/3")
-		}
+	serveTCP := p.HTTP1() || p.HTTP2() || p.UnencryptedHTTP2()
+	if p.HTTP3() {
 		config, err := s.setupTLSConfig(certFile, keyFile, []string{"h3"})
 		if err != nil {
 			return err
 		}
-		errc := make(chan error, 1)
-		s.mu.Lock()
-		s.h3 = &http3ServerHandler{
-			handler:   serverHandler{s},
-			tlsConfig: config,
-			baseCtx:   context.WithValue(context.Background(), ServerContextKey, s),
-			errc:      errc,
-		}
-		s.mu.Unlock()
-		go fn(s, nil, s.h3)
-		if err := <-errc; err != nil {
-			return err
+		if fn, ok := s.TLSNextProto["http/3"]; ok {
+			// An external HTTP/3 implementation has been registered.
+			errc := make(chan error, 1)
+			s.mu.Lock()
+			s.h3 = &http3ServerHandler{
+				handler:   serverHandler{s},
+				tlsConfig: config,
+				baseCtx:   context.WithValue(context.Background(), ServerContextKey, s),
+				errc:      errc,
+			}
+			s.mu.Unlock()
+			go fn(s, nil, s.h3)
+			if err := <-errc; err != nil {
+				return err
+			}
+			if !serveTCP {
+				return nil
+			}
+		} else {
+			h3srv, err := s.listenHTTP3(addr, config, serveTCP)
+			if err != nil {
+				return err
+			}
+			if !serveTCP {
+				return s.serveHTTP3(h3srv)
+			}
+			return s.serveTLSAndHTTP3(h3srv, addr, certFile, keyFile)
 		}
 	}
 
-	// Only start a TCP listener if HTTP/1 or HTTP/2 is used.
-	if !p.HTTP1() && !p.HTTP2() && !p.UnencryptedHTTP2() {
-		return nil
-	}
 	ln, err := net.Listen("tcp", addr)
 	if err != nil {
 		return err
@@ -3864,6 +3901,38 @@ func (s *Server) ListenAndServeTLS(certFile, keyFile string) error {
 	return s.ServeTLS(ln, certFile, keyFile)
 }
 
+// serveTLSAndHTTP3 serves TLS connections on the TCP address addr,
+// alongside HTTP/3 on h3srv. When either stops with an error, the
+// other is closed, and the first error is returned.
+func (s *Server) serveTLSAndHTTP3(h3srv *http3Server, addr, certFile, keyFile string) error {
+	h3errc := make(chan error, 1)
+	go func() { h3errc <- s.serveHTTP3(h3srv) }()
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		h3srv.Close()
+		<-h3errc
+		return err
+	}
+	defer ln.Close()
+	errc := make(chan error, 1)
+	go func() { errc <- s.ServeTLS(ln, certFile, keyFile) }()
+
+	select {
+	case err = <-errc:
+		// After Shutdown or Close, the HTTP/3 server is
+		// already being shut down.
+		if err != ErrServerClosed {
+			h3srv.Close()
+			<-h3errc
+		}
+	case err = <-h3errc:
+		ln.Close()
+		<-errc
+	}
+	return err
+}
+
 // setupHTTP2_ServeTLS conditionally configures HTTP/2 on
 // s and reports whether there was an error setting it up. If it is
 // not configured for policy reasons, nil is returned.
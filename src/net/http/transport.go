@@ -13,6 +13,7 @@ import (
 	"bufio"
 	"compress/flate"
 	"compress/gzip"
+	"compress/zstd"
 	"container/list"
 	"context"
 	"crypto/tls"
@@ -203,6 +204,13 @@ type Transport struct {
 	// uncompressed.
 	DisableCompression bool
 
+	// EnableZstd, if true, makes the Transport request Zstandard
+	// as well as gzip compression when DisableCompression permits it
+	// to request compression, by sending an "Accept-Encoding: zstd, gzip"
+	// request header. A response compressed with either coding is
+	// transparently decoded in the Response.Body.
+	EnableZstd bool
+
 	// MaxIdleConns controls the maximum number of idle (keep-alive)
 	// connections across all hosts. Zero means no limit.
 	MaxIdleConns int
@@ -293,6 +301,9 @@ type Transport struct {
 	h3transport        dialClientConner // non-nil if http3 wired up
 	tlsNextProtoWasNil bool             // whether TLSNextProto was nil when the Once fired
 
+	// altSvc records HTTP/3 alternative services advertised by servers.
+	altSvc altSvcCache
+
 	// ForceAttemptHTTP2 controls whether HTTP/2 is enabled when a non-zero
 	// Dial, DialTLS, or DialContext func or TLSClientConfig is provided.
 	// By default, use of any those fields conservatively disables HTTP/2.
@@ -348,6 +359,7 @@ func (t *Transport) Clone() *Transport {
 		TLSHandshakeTimeout:    t.TLSHandshakeTimeout,
 		DisableKeepAlives:      t.DisableKeepAlives,
 		DisableCompression:     t.DisableCompression,
+		EnableZstd:             t.EnableZstd,
 		MaxIdleConns:           t.MaxIdleConns,
 		MaxIdleConnsPerHost:    t.MaxIdleConnsPerHost,
 		MaxConnsPerHost:        t.MaxConnsPerHost,
@@ -441,6 +453,9 @@ var http2client = godebug.New("http2client")
 // It must be called via t.nextProtoOnce.Do.
 func (t *Transport) onceSetNextProtoDefaults() {
 	t.tlsNextProtoWasNil = (t.TLSNextProto == nil)
+	if t.protocols().HTTP3() && t.h3transport == nil {
+		t.configureHTTP3()
+	}
 	if http2client.Value() == "0" {
 		http2client.IncNonDefault()
 		return
@@ -707,6 +722,12 @@ func (t *Transport) roundTrip(req *Request) (_ *Response, err error) {
 		// to send it requests.
 		pconn, err := t.getConn(treq, cm)
 		if err != nil {
+			if cm.h3 && cm.h3Addr != "" && ctx.Err() == nil {
+				// The advertised HTTP/3 alternative is unreachable.
+				// Stop using it for a while and fall back to TCP.
+				t.altSvc.markBroken(cm.targetAddr)
+				continue
+			}
 			req.closeBody()
 			return nil, err
 		}
@@ -727,6 +748,11 @@ func (t *Transport) roundTrip(req *Request) (_ *Response, err error) {
 				// canceling the context after the response body is read.
 				cancel(errRequestDone)
 			}
+			if resp.ProtoMajor < 3 && cm.targetScheme == "https" && cm.proxyURL == nil {
+				if p := t.protocols(); p.HTTP3() && t.h3transport != nil {
+					t.altSvc.update(cm.targetAddr, resp.Header["Alt-Svc"])
+				}
+			}
 			resp.Request = origReq
 			return resp, nil
 		}
@@ -1026,6 +1052,15 @@ func (t *Transport) connectMethodForRequest(treq *transportRequest) (cm connectM
 		cm.proxyURL, err = t.Proxy(treq.Request)
 	}
 	cm.onlyH1 = treq.requiresHTTP1()
+	if p := t.protocols(); p.HTTP3() {
+		switch {
+		case !p.HTTP1() && !p.HTTP2() && !p.UnencryptedHTTP2():
+			cm.h3 = true
+		case cm.targetScheme == "https" && cm.proxyURL == nil && !cm.onlyH1 && t.h3transport != nil:
+			cm.h3Addr = t.altSvc.lookup(cm.targetAddr)
+			cm.h3 = cm.h3Addr != ""
+		}
+	}
 	return cm, err
 }
 
@@ -1805,18 +1840,19 @@ type erringRoundTripper interface {
 var testHookProxyConnectTimeout = context.WithTimeout
 
 func (t *Transport) dialConn(ctx context.Context, cm connectMethod, isClientConn bool, internalStateHook func()) (pconn *persistConn, err error) {
-	// TODO: actually support HTTP/3. Among other things:
+	// TODO: improve HTTP/3 support. Among other things:
 	// - make HTTP/3 play well with proxy.
 	// - implement happy eyeball between HTTP/3 and HTTP/1 & HTTP/2.
 	// - clean up the connection pooling logic.
-	if p := t.protocols(); p.http3() {
-		if p.HTTP1() || p.HTTP2() || p.UnencryptedHTTP2() {
-			return nil, errors.New("http: when using HTTP3, Transport.Protocols must contain only HTTP3")
-		}
+	if cm.h3 {
 		if t.h3transport == nil {
 			return nil, errors.New("http: Transport.Protocols contains HTTP3, but Transport does not support HTTP/3")
 		}
-		rt, err := t.h3transport.DialClientConn(ctx, cm.addr(), cm.proxyURL, internalStateHook)
+		addr := cm.h3Addr
+		if addr == "" {
+			addr = cm.addr()
+		}
+		rt, err := t.h3transport.DialClientConn(ctx, addr, cm.proxyURL, internalStateHook)
 		if err != nil {
 			return nil, err
 		}
@@ -2107,7 +2143,9 @@ type connectMethod struct {
 	// then targetAddr is not included in the connect method key, because the socket can
 	// be reused for different targetAddr values.
 	targetAddr string
-	onlyH1     bool // whether to disable HTTP/2 and force HTTP/1
+	onlyH1     bool   // whether to disable HTTP/2 and force HTTP/1
+	h3         bool   // whether to use HTTP/3
+	h3Addr     string // UDP "host:port" of an HTTP/3 alternative service, if any
 }
 
 func (cm *connectMethod) key() connectMethodKey {
@@ -2124,6 +2162,7 @@ func (cm *connectMethod) key() connectMethodKey {
 		scheme: cm.targetScheme,
 		addr:   targetAddr,
 		onlyH1: cm.onlyH1,
+		h3:     cm.h3,
 	}
 }
 
@@ -2156,6 +2195,7 @@ func (cm *connectMethod) tlsHost() string {
 type connectMethodKey struct {
 	proxy, scheme, addr string
 	onlyH1              bool
+	h3                  bool
 }
 
 func (k connectMethodKey) String() string {
@@ -2164,6 +2204,9 @@ func (k connectMethodKey) String() string {
 	if k.onlyH1 {
 		h1 = ",h1"
 	}
+	if k.h3 {
+		h1 += ",h3"
+	}
 	return fmt.Sprintf("%s|%s%s|%s", k.proxy, k.scheme, h1, k.addr)
 }
 
@@ -2514,8 +2557,13 @@ func (pc *persistConn) readLoop() {
 		}
 
 		resp.Body = body
-		if rc.addedGzip && ascii.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		switch ce := resp.Header.Get("Content-Encoding"); {
+		case rc.addedGzip && ascii.EqualFold(ce, "gzip"):
 			resp.Body = &gzipReader{body: body}
+		case rc.addedZstd && ascii.EqualFold(ce, "zstd"):
+			resp.Body = &zstdReader{body: body, zr: zstd.NewReader(body)}
+		}
+		if resp.Body != body {
 			resp.Header.Del("Content-Encoding")
 			resp.Header.Del("Content-Length")
 			resp.ContentLength = -1
@@ -2829,6 +2877,9 @@ type requestAndChan struct {
 	// set it, only then do we transparently decode the gzip.
 	addedGzip bool
 
+	// whether the Transport added zstd to the Accept-Encoding header.
+	addedZstd bool
+
 	// Optional blocking chan for Expect: 100-continue (for send).
 	// If the request has an "Expect: 100-continue" header and
 	// the server responds 100 Continue, readLoop send a value
@@ -2944,7 +2995,11 @@ func (pc *persistConn) roundTrip(req *transportRequest) (resp *Response, err err
 		// auto-decoding a portion of a gzipped document will just fail
 		// anyway. See https://golang.org/issue/8923
 		requestedGzip = true
-		req.extraHeaders().Set("Accept-Encoding", "gzip")
+		if pc.t.EnableZstd {
+			req.extraHeaders().Set("Accept-Encoding", "zstd, gzip")
+		} else {
+			req.extraHeaders().Set("Accept-Encoding", "gzip")
+		}
 	}
 
 	var continueCh chan struct{}
@@ -2975,6 +3030,7 @@ func (pc *persistConn) roundTrip(req *transportRequest) (resp *Response, err err
 		treq:       req,
 		ch:         resc,
 		addedGzip:  requestedGzip,
+		addedZstd:  requestedGzip && pc.t.EnableZstd,
 		continueCh: continueCh,
 		callerGone: gone,
 	}
@@ -3298,6 +3354,20 @@ func (gz *gzipReader) Close() error {
 	return gz.body.Close()
 }
 
+// zstdReader wraps a response body to decode Zstandard content.
+type zstdReader struct {
+	body io.ReadCloser // underlying response body
+	zr   *zstd.Reader  // reads from body
+}
+
+func (zr *zstdReader) Read(p []byte) (n int, err error) {
+	return zr.zr.Read(p)
+}
+
+func (zr *zstdReader) Close() error {
+	return zr.body.Close()
+}
+
 type tlsHandshakeTimeoutError struct{}
 
 func (tlsHandshakeTimeoutError) Timeout() bool   { return true }
@@ -0,0 +1,428 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mail
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"slices"
+	"strings"
+	"time"
+)
+
+// A Builder composes a message in the Internet Message Format
+// (RFC 5322), with a MIME (RFC 2045) body made from a plain text
+// and an HTML version of the message and attachments.
+//
+// The zero Builder, with a From address set, writes an empty
+// text message.
+type Builder struct {
+	From    *Address
+	ReplyTo []*Address
+	To      []*Address
+	Cc      []*Address
+
+	// Bcc holds blind carbon copy recipients. They are returned
+	// by Recipients, but are not written in the message header.
+	Bcc []*Address
+
+	Subject string
+
+	// Date is the origination date of the message.
+	// If zero, the time when the message is written is used.
+	Date time.Time
+
+	// MessageID is the Message-ID of the message, without angle
+	// brackets. If empty, a random one is generated in the
+	// domain of the From address.
+	MessageID string
+
+	// Header holds additional header fields, such as In-Reply-To.
+	// Values holding non-ASCII characters are written as RFC 2047
+	// encoded-words. Header must not hold the fields written for
+	// the other fields of the Builder, nor MIME-Version and
+	// Content-* fields.
+	Header Header
+
+	// Text and HTML are the plain text and HTML versions of the
+	// message body. If both are set, the message holds them as
+	// alternatives.
+	Text string
+	HTML string
+
+	Attachments []*Attachment
+}
+
+// An Attachment is a file attached to a message built by a [Builder].
+type Attachment struct {
+	// Filename is the name of the file.
+	Filename string
+
+	// ContentType is the media type of the file. If empty, it is
+	// determined from the extension of Filename by [mime.TypeByExtension],
+	// and is application/octet-stream if that fails.
+	ContentType string
+
+	// Data is the content of the file.
+	Data []byte
+
+	// Inline marks the attachment to be displayed as part of the
+	// message. Inline attachments of a message with an HTML body are
+	// grouped with it, so that it can refer to them by ContentID.
+	Inline bool
+
+	// ContentID is the Content-ID of the attachment, without angle
+	// brackets. An HTML body may refer to it with a "cid:" URL.
+	ContentID string
+}
+
+// Recipients returns the addresses of the recipients of the message,
+// from the To, Cc and Bcc fields, in the form expected by
+// [net/smtp.Client.Send].
+func (b *Builder) Recipients() []string {
+	var addrs []string
+	for _, list := range [][]*Address{b.To, b.Cc, b.Bcc} {
+		for _, a := range list {
+			addrs = append(addrs, a.Address)
+		}
+	}
+	return addrs
+}
+
+// builderFields are the header fields written by a Builder,
+// which must not be set in its Header.
+var builderFields = []string{"Date", "From", "Reply-To", "To", "Cc", "Bcc", "Subject", "Message-Id", "Mime-Version"}
+
+// WriteTo writes the message to w, with CRLF line endings.
+// Header fields are folded to lines of at most 78 characters
+// where possible. Text parts are written as 7bit data when they
+// are ASCII with short lines, and as quoted-printable otherwise;
+// other attachments are written in base64.
+func (b *Builder) WriteTo(w io.Writer) (int64, error) {
+	if b.From == nil {
+		return 0, errors.New("mail: message has no From address")
+	}
+	for key := range b.Header {
+		ckey := textproto.CanonicalMIMEHeaderKey(key)
+		if slices.Contains(builderFields, ckey) || strings.HasPrefix(ckey, "Content-") {
+			return 0, fmt.Errorf("mail: Builder.Header holds %s field", key)
+		}
+	}
+
+	cw := &countWriter{w: w}
+	date := b.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	writeField(cw, "Date", date.Format(time.RFC1123Z))
+	writeField(cw, "From", b.From.String())
+	writeAddressList(cw, "Reply-To", b.ReplyTo)
+	writeAddressList(cw, "To", b.To)
+	writeAddressList(cw, "Cc", b.Cc)
+	if b.Subject != "" {
+		writeField(cw, "Subject", mime.QEncoding.Encode("utf-8", b.Subject))
+	}
+	id := b.MessageID
+	if id == "" {
+		domain := "localhost"
+		if at := strings.LastIndexByte(b.From.Address, '@'); at >= 0 {
+			domain = b.From.Address[at+1:]
+		}
+		id = rand.Text() + "@" + domain
+	}
+	writeField(cw, "Message-ID", "<"+id+">")
+	for _, key := range slices.Sorted(maps.Keys(b.Header)) {
+		for _, v := range b.Header[key] {
+			writeField(cw, textproto.CanonicalMIMEHeaderKey(key), mime.QEncoding.Encode("utf-8", v))
+		}
+	}
+	writeField(cw, "MIME-Version", "1.0")
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	err := b.body().write(cw)
+	if cw.err != nil {
+		err = cw.err
+	}
+	return cw.n, err
+}
+
+// body returns the MIME entity holding the message body.
+func (b *Builder) body() *mimeEntity {
+	var alts, inline, attached []*mimeEntity
+	if b.Text != "" || b.HTML == "" && len(b.Attachments) == 0 {
+		alts = append(alts, textEntity("text/plain", b.Text))
+	}
+	if b.HTML != "" {
+		alts = append(alts, textEntity("text/html", b.HTML))
+	}
+	for _, a := range b.Attachments {
+		if a.Inline && b.HTML != "" {
+			inline = append(inline, a.entity())
+		} else {
+			attached = append(attached, a.entity())
+		}
+	}
+
+	var parts []*mimeEntity
+	if len(alts) > 0 {
+		parts = append(parts, multipartEntity("alternative", alts))
+	}
+	if len(inline) > 0 {
+		parts = []*mimeEntity{multipartEntity("related", append(parts, inline...))}
+	}
+	return multipartEntity("mixed", append(parts, attached...))
+}
+
+// A mimeEntity is a MIME entity: a message body or a body part.
+type mimeEntity struct {
+	header textproto.MIMEHeader
+	data   []byte        // data of a discrete entity
+	parts  []*mimeEntity // parts of a multipart entity
+}
+
+// multipartEntity returns a multipart entity holding parts,
+// or the only part if there is only one.
+func multipartEntity(subtype string, parts []*mimeEntity) *mimeEntity {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return &mimeEntity{
+		header: textproto.MIMEHeader{"Content-Type": {"multipart/" + subtype}},
+		parts:  parts,
+	}
+}
+
+// textEntity returns an entity holding text of the given media type.
+func textEntity(mediaType, text string) *mimeEntity {
+	return &mimeEntity{
+		header: textproto.MIMEHeader{
+			"Content-Type": {mime.FormatMediaType(mediaType, map[string]string{"charset": "utf-8"})},
+		},
+		data: []byte(text),
+	}
+}
+
+func (a *Attachment) entity() *mimeEntity {
+	ctype := a.ContentType
+	if ctype == "" {
+		if i := strings.LastIndexByte(a.Filename, '.'); i >= 0 {
+			ctype = mime.TypeByExtension(a.Filename[i:])
+		}
+		if ctype == "" {
+			ctype = "application/octet-stream"
+		}
+	}
+	disposition := "attachment"
+	if a.Inline {
+		disposition = "inline"
+	}
+	var params map[string]string
+	if a.Filename != "" {
+		params = map[string]string{"filename": a.Filename}
+	}
+	e := &mimeEntity{
+		header: textproto.MIMEHeader{
+			"Content-Type":        {ctype},
+			"Content-Disposition": {mime.FormatMediaType(disposition, params)},
+		},
+		data: a.Data,
+	}
+	if a.ContentID != "" {
+		e.header.Set("Content-Id", "<"+a.ContentID+">")
+	}
+	return e
+}
+
+// write writes the entity's header fields, a blank line, and its body.
+func (e *mimeEntity) write(w io.Writer) error {
+	e.prepare()
+	writeHeader(w, e.header)
+	io.WriteString(w, "\r\n")
+	return e.writeBody(w)
+}
+
+// prepare adds the boundary parameter of a multipart entity,
+// or the Content-Transfer-Encoding field of a discrete entity.
+func (e *mimeEntity) prepare() {
+	if e.parts != nil {
+		boundary := multipart.NewWriter(io.Discard).Boundary()
+		e.header.Set("Content-Type", e.header.Get("Content-Type")+"; boundary="+boundary)
+		return
+	}
+	mediaType, _, _ := mime.ParseMediaType(e.header.Get("Content-Type"))
+	cte := "base64"
+	if strings.HasPrefix(mediaType, "text/") {
+		cte = "quoted-printable"
+		if is7bit(e.data) {
+			cte = "7bit"
+		}
+	}
+	e.header.Set("Content-Transfer-Encoding", cte)
+}
+
+// writeBody writes the body of the entity, which must have been prepared.
+func (e *mimeEntity) writeBody(w io.Writer) error {
+	if e.parts != nil {
+		_, params, _ := mime.ParseMediaType(e.header.Get("Content-Type"))
+		mw := multipart.NewWriter(w)
+		if err := mw.SetBoundary(params["boundary"]); err != nil {
+			return err
+		}
+		for _, p := range e.parts {
+			p.prepare()
+			// multipart.Writer writes header values as they are,
+			// so fold them here.
+			h := make(textproto.MIMEHeader, len(p.header))
+			for key, vv := range p.header {
+				for _, v := range vv {
+					var b strings.Builder
+					writeField(&b, key, v)
+					v = strings.TrimPrefix(b.String(), key+":")
+					h.Add(key, strings.TrimSuffix(strings.TrimPrefix(v, " "), "\r\n"))
+				}
+			}
+			pw, err := mw.CreatePart(h)
+			if err != nil {
+				return err
+			}
+			if err := p.writeBody(pw); err != nil {
+				return err
+			}
+		}
+		return mw.Close()
+	}
+
+	switch e.header.Get("Content-Transfer-Encoding") {
+	case "7bit":
+		_, err := io.WriteString(w, toCRLF(e.data))
+		return err
+	case "quoted-printable":
+		qw := quotedprintable.NewWriter(w)
+		if _, err := qw.Write(e.data); err != nil {
+			return err
+		}
+		return qw.Close()
+	}
+	// Write base64 in lines of 76 characters (RFC 2045, section 6.8).
+	const lineBytes = 76 / 4 * 3
+	buf := make([]byte, base64.StdEncoding.EncodedLen(lineBytes)+2)
+	for data := e.data; len(data) > 0; {
+		n := min(len(data), lineBytes)
+		base64.StdEncoding.Encode(buf, data[:n])
+		m := base64.StdEncoding.EncodedLen(n)
+		buf[m], buf[m+1] = '\r', '\n'
+		if _, err := w.Write(buf[:m+2]); err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+// is7bit reports whether data is text which can be sent without
+// encoding: ASCII without NUL or bare CR, in lines of at most 998 octets.
+func is7bit(data []byte) bool {
+	line := 0
+	for i, c := range data {
+		switch {
+		case c == '\n':
+			line = 0
+			continue
+		case c == '\r':
+			if i+1 >= len(data) || data[i+1] != '\n' {
+				return false
+			}
+			continue
+		case c == 0 || c >= 0x80:
+			return false
+		}
+		if line++; line > 998 {
+			return false
+		}
+	}
+	return true
+}
+
+// toCRLF returns data with its line endings converted to CRLF.
+func toCRLF(data []byte) string {
+	return strings.ReplaceAll(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n", "\r\n")
+}
+
+// writeHeader writes the fields of h in sorted order.
+func writeHeader(w io.Writer, h textproto.MIMEHeader) {
+	for _, key := range slices.Sorted(maps.Keys(h)) {
+		for _, v := range h[key] {
+			writeField(w, key, v)
+		}
+	}
+}
+
+// writeAddressList writes an address list field, if addrs is not empty.
+func writeAddressList(w io.Writer, name string, addrs []*Address) {
+	if len(addrs) == 0 {
+		return
+	}
+	s := make([]string, len(addrs))
+	for i, a := range addrs {
+		s[i] = a.String()
+	}
+	writeField(w, name, strings.Join(s, ", "))
+}
+
+// writeField writes a header field, folding it at spaces into
+// lines of at most 78 characters where possible (RFC 5322, section 2.2.3).
+// The first line may end after the colon, if the first word of value
+// doesn't fit on it.
+// Line breaks in value are replaced with spaces.
+func writeField(w io.Writer, name, value string) {
+	const maxLine = 78
+	value = strings.Map(func(r rune) rune {
+		if r == '\r' || r == '\n' {
+			return ' '
+		}
+		return r
+	}, value)
+	var b strings.Builder
+	b.WriteString(name + ":")
+	lineLen := b.Len()
+	for _, word := range strings.Split(value, " ") {
+		if word != "" && lineLen > 0 && lineLen+1+len(word) > maxLine {
+			b.WriteString("\r\n")
+			lineLen = 0
+		}
+		b.WriteByte(' ')
+		b.WriteString(word)
+		lineLen += 1 + len(word)
+	}
+	b.WriteString("\r\n")
+	io.WriteString(w, b.String())
+}
+
+// A countWriter counts the bytes written to w,
+// and records the first error.
+type countWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
@@ -0,0 +1,211 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readEntity returns a description of the MIME entity with the given
+// header and body: its media type and decoded content, or its parts.
+func readEntity(t *testing.T, contentType, cte string, body io.Reader) string {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("parsing Content-Type %q: %v", contentType, err)
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		if cte == "base64" {
+			body = base64.NewDecoder(base64.StdEncoding, body)
+		}
+		b, err := io.ReadAll(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return mediaType + "(" + string(b) + ")"
+	}
+	var parts []string
+	r := multipart.NewReader(body, params["boundary"])
+	for {
+		p, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		desc := readEntity(t, p.Header.Get("Content-Type"), p.Header.Get("Content-Transfer-Encoding"), p)
+		if name := p.FileName(); name != "" {
+			desc = name + ":" + desc
+		}
+		if id := p.Header.Get("Content-Id"); id != "" {
+			desc = id + ":" + desc
+		}
+		parts = append(parts, desc)
+	}
+	return mediaType + "[" + strings.Join(parts, " ") + "]"
+}
+
+func TestBuilder(t *testing.T) {
+	b := &Builder{
+		From:      &Address{Name: "Jöe Bloggs", Address: "joe@example.com"},
+		To:        []*Address{{Address: "a@example.com"}, {Name: "B", Address: "b@example.com"}},
+		Cc:        []*Address{{Address: "c@example.com"}},
+		Bcc:       []*Address{{Address: "d@example.com"}},
+		Subject:   "A long subject, with non-ASCII characters: ünïcödé, which needs folding",
+		Date:      time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		MessageID: "1234@example.com",
+		Header:    Header{"In-Reply-To": {"<1233@example.com>"}},
+		Text:      "Grüße\nfrom\n",
+		HTML:      `<p>Hello <img src="cid:logo"></p>`,
+		Attachments: []*Attachment{
+			{Filename: "logo.png", Data: []byte("\x89PNG"), Inline: true, ContentID: "logo"},
+			{Filename: "notes.txt", Data: []byte("some notes")},
+			{Filename: "data.bin", ContentType: "application/x-test", Data: bytes.Repeat([]byte{0, 1, 2}, 100)},
+		},
+	}
+	var buf bytes.Buffer
+	n, err := b.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo returned %d, wrote %d bytes", n, buf.Len())
+	}
+	if strings.Count(buf.String(), "\n") != strings.Count(buf.String(), "\r\n") {
+		t.Errorf("message has bare LF line endings:\n%s", buf.String())
+	}
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		if len(line) > 78 {
+			t.Errorf("line %q longer than 78 characters", line)
+		}
+	}
+
+	msg, err := ReadMessage(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec mime.WordDecoder
+	subject, err := dec.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != b.Subject {
+		t.Errorf("Subject = %q, %v; want %q", subject, err, b.Subject)
+	}
+	from, err := msg.Header.AddressList("From")
+	if err != nil || len(from) != 1 || *from[0] != *b.From {
+		t.Errorf("From = %v, %v; want %v", from, err, b.From)
+	}
+	to, err := msg.Header.AddressList("To")
+	if err != nil || len(to) != 2 || *to[1] != *b.To[1] {
+		t.Errorf("To = %v, %v; want %v", to, err, b.To)
+	}
+	if date, err := msg.Header.Date(); err != nil || !date.Equal(b.Date) {
+		t.Errorf("Date = %v, %v; want %v", date, err, b.Date)
+	}
+	for key, want := range map[string]string{
+		"Message-Id":   "<1234@example.com>",
+		"In-Reply-To":  "<1233@example.com>",
+		"Mime-Version": "1.0",
+		"Bcc":          "",
+	} {
+		if got := msg.Header.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+
+	got := readEntity(t, msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	want := "multipart/mixed[multipart/related[" +
+		"multipart/alternative[text/plain(Grüße\r\nfrom\r\n) text/html(" + b.HTML + ")] " +
+		"<logo>:logo.png:image/png(\x89PNG)] " +
+		"notes.txt:text/plain(some notes) " +
+		"data.bin:application/x-test(" + strings.Repeat("\x00\x01\x02", 100) + ")]"
+	if got != want {
+		t.Errorf("body:\n%q\nwant:\n%q", got, want)
+	}
+
+	if got, want := b.Recipients(), []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Recipients() = %q, want %q", got, want)
+	}
+}
+
+func TestBuilderSimple(t *testing.T) {
+	b := &Builder{
+		From: &Address{Address: "joe@example.com"},
+		Text: "hello\n",
+	}
+	var buf bytes.Buffer
+	if _, err := b.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	msg, err := ReadMessage(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := msg.Header.Get("Message-Id"); !strings.HasSuffix(id, "@example.com>") {
+		t.Errorf("generated Message-ID = %q, want one in the From domain", id)
+	}
+	if ct, cte := msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"); ct != "text/plain; charset=utf-8" || cte != "7bit" {
+		t.Errorf("Content-Type, Content-Transfer-Encoding = %q, %q; want text/plain, 7bit", ct, cte)
+	}
+	if body, _ := io.ReadAll(msg.Body); string(body) != "hello\r\n" {
+		t.Errorf("body = %q, want %q", body, "hello\r\n")
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	from := &Address{Address: "joe@example.com"}
+	for _, b := range []*Builder{
+		{},
+		{From: from, Header: Header{"Subject": {"x"}}},
+		{From: from, Header: Header{"content-type": {"text/html"}}},
+	} {
+		if _, err := b.WriteTo(io.Discard); err == nil {
+			t.Errorf("WriteTo with %+v succeeded, want error", b)
+		}
+	}
+}
+
+func TestIs7bit(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want bool
+	}{
+		{"", true},
+		{"hello\r\nworld\n", true},
+		{"héllo", false},
+		{"a\rb", false},
+		{"a\x00b", false},
+		{strings.Repeat("x", 998) + "\n" + strings.Repeat("x", 998), true},
+		{strings.Repeat("x", 999), false},
+	} {
+		if got := is7bit([]byte(tt.in)); got != tt.want {
+			t.Errorf("is7bit(%.20q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWriteFieldFolding(t *testing.T) {
+	var b strings.Builder
+	writeField(&b, "Subject", strings.Repeat("word ", 30)+strings.Repeat("x", 100))
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	for i, line := range lines {
+		if i > 0 && !strings.HasPrefix(line, " ") {
+			t.Errorf("continuation line %q does not begin with a space", line)
+		}
+		if len(line) > 78 && strings.Contains(strings.TrimSpace(line), " ") {
+			t.Errorf("line %q longer than 78 characters", line)
+		}
+	}
+	if got, want := strings.Join(lines, ""), "Subject: "+strings.Repeat("word ", 30)+strings.Repeat("x", 100); got != want {
+		t.Errorf("unfolded field = %q, want %q", got, want)
+	}
+}
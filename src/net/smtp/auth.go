@@ -107,3 +107,41 @@ func (a *cramMD5Auth) Next(fromServer []byte, more bool) ([]byte, error) {
 	}
 	return nil, nil
 }
+
+type xoauth2Auth struct {
+	username, token string
+	host            string
+}
+
+// XOAUTH2Auth returns an [Auth] that implements the XOAUTH2 authentication
+// mechanism used by some mail providers to authenticate with OAuth 2.0
+// bearer tokens. The returned Auth uses the given username and access token
+// to authenticate to host.
+//
+// Like [PlainAuth], XOAUTH2Auth will only send the token if the connection
+// is using TLS or is connected to localhost.
+func XOAUTH2Auth(username, token, host string) Auth {
+	return &xoauth2Auth{username, token, host}
+}
+
+func (a *xoauth2Auth) Start(server *ServerInfo) (string, []byte, error) {
+	// Must have TLS, or else localhost server. See plainAuth.Start.
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+	resp := []byte("user=" + a.username + "\x01auth=Bearer " + a.token + "\x01\x01")
+	return "XOAUTH2", resp, nil
+}
+
+func (a *xoauth2Auth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if more {
+		// The server sends a challenge holding an error
+		// description, to which the client sends an empty
+		// response before the server fails the authentication.
+		return []byte{}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package smtp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"time"
+)
+
+// A StartTLSPolicy is a policy for the use of the STARTTLS extension
+// by a [Dialer].
+type StartTLSPolicy int
+
+const (
+	// StartTLSOpportunistic uses STARTTLS if the server supports it,
+	// and continues without TLS otherwise.
+	StartTLSOpportunistic StartTLSPolicy = iota
+
+	// StartTLSRequired uses STARTTLS, and fails if the server does
+	// not support it.
+	StartTLSRequired
+
+	// StartTLSDisabled never uses STARTTLS.
+	StartTLSDisabled
+)
+
+// A Dialer contains options for connecting to an SMTP server.
+//
+// The zero value is a valid Dialer which introduces itself as "localhost",
+// uses STARTTLS if the server supports it, and does not authenticate.
+type Dialer struct {
+	// LocalName is the host name sent in the EHLO or HELO command.
+	// If empty, "localhost" is used.
+	LocalName string
+
+	// TLSConfig is the TLS configuration used for STARTTLS and
+	// implicit TLS. If nil, the default configuration is used.
+	// If TLSConfig.ServerName is empty, the host name from the
+	// address passed to Dial is used.
+	TLSConfig *tls.Config
+
+	// StartTLS is the policy for using STARTTLS.
+	// It is ignored when ImplicitTLS is set.
+	StartTLS StartTLSPolicy
+
+	// ImplicitTLS, if true, makes the Dialer use TLS from the start
+	// of the connection (RFC 8314), as is usual on port 465,
+	// rather than using STARTTLS.
+	ImplicitTLS bool
+
+	// Auth, if non-nil, is used to authenticate with the server
+	// after TLS is established.
+	Auth Auth
+
+	// NetDialer, if non-nil, is used to make the TCP connection.
+	NetDialer *net.Dialer
+}
+
+// Dial connects to the SMTP server at addr, which must include a port,
+// as in "mail.example.com:smtp". It sends the EHLO command, establishes
+// TLS according to the Dialer's options, and authenticates if d.Auth is
+// set.
+//
+// The context is used for connecting and for the initial exchange with
+// the server. Once Dial returns, the context does not affect the
+// returned [Client].
+func (d *Dialer) Dial(ctx context.Context, addr string) (*Client, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	nd := d.NetDialer
+	if nd == nil {
+		nd = &net.Dialer{}
+	}
+	conn, err := nd.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	config := d.tlsConfig(host)
+	if d.ImplicitTLS {
+		conn = tls.Client(conn, config)
+	}
+
+	// Interrupt the exchange with the server when ctx is done.
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Unix(1, 0))
+	})
+	c, err := d.setup(conn, host, config)
+	if !stop() {
+		if err == nil {
+			c.Close()
+		}
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// setup runs the initial exchange with the server on conn.
+func (d *Dialer) setup(conn net.Conn, host string, config *tls.Config) (*Client, error) {
+	c, err := NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if d.LocalName != "" {
+		err = c.Hello(d.LocalName)
+	} else {
+		err = c.hello()
+	}
+	if err == nil && !d.ImplicitTLS && d.StartTLS != StartTLSDisabled {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			err = c.StartTLS(config)
+		} else if d.StartTLS == StartTLSRequired {
+			err = errors.New("smtp: server doesn't support STARTTLS")
+		}
+	}
+	if err == nil && d.Auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			err = errors.New("smtp: server doesn't support AUTH")
+		} else {
+			err = c.Auth(d.Auth)
+		}
+	}
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// tlsConfig returns the TLS configuration for connecting to host.
+func (d *Dialer) tlsConfig(host string) *tls.Config {
+	var config *tls.Config
+	if d.TLSConfig != nil {
+		config = d.TLSConfig.Clone()
+	} else {
+		config = &tls.Config{}
+	}
+	if config.ServerName == "" {
+		config.ServerName = host
+	}
+	if testHookStartTLS != nil {
+		testHookStartTLS(config)
+	}
+	return config
+}
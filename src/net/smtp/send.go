@@ -0,0 +1,334 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package smtp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MailOptions holds optional parameters of a MAIL command.
+type MailOptions struct {
+	// Size is the size of the message in bytes, sent as the SIZE
+	// parameter (RFC 1870) if the server supports the SIZE extension.
+	// Zero means the size is unknown.
+	Size int64
+
+	// UTF8 requests SMTPUTF8 processing of the message (RFC 6531),
+	// which is required when the message headers contain non-ASCII
+	// characters. The server must support the SMTPUTF8 extension.
+	// SMTPUTF8 processing is always requested for non-ASCII addresses.
+	UTF8 bool
+
+	// Return is the RET parameter of a delivery status notification
+	// request (RFC 3461): "FULL" to return the full message with a
+	// notification, or "HDRS" to return only its headers.
+	// Return and EnvelopeID require the DSN extension.
+	Return string
+
+	// EnvelopeID is the ENVID parameter of a delivery status
+	// notification request, an identifier to be returned in
+	// notifications.
+	EnvelopeID string
+}
+
+// RcptOptions holds optional parameters of a RCPT command.
+type RcptOptions struct {
+	// Notify is the NOTIFY parameter of a delivery status notification
+	// request (RFC 3461): either "NEVER", or one or more of "SUCCESS",
+	// "FAILURE" and "DELAY". Notify and OriginalRecipient require the
+	// DSN extension.
+	Notify []string
+
+	// OriginalRecipient is the ORCPT parameter of a delivery status
+	// notification request, the address to which the message was
+	// originally addressed.
+	OriginalRecipient string
+}
+
+// MailWithOptions is like [Client.Mail], but sends the parameters in opts,
+// which may be nil. Unlike Mail, it adds the SMTPUTF8 parameter only when
+// it is requested by opts or the address is not ASCII.
+func (c *Client) MailWithOptions(from string, opts *MailOptions) error {
+	if err := c.hello(); err != nil {
+		return err
+	}
+	cmd, err := c.mailCmd(from, opts)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.cmd(250, "%s", cmd)
+	return err
+}
+
+// RcptWithOptions is like [Client.Rcpt], but sends the parameters in opts,
+// which may be nil.
+func (c *Client) RcptWithOptions(to string, opts *RcptOptions) error {
+	cmd, err := c.rcptCmd(to, opts)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.cmd(25, "%s", cmd)
+	return err
+}
+
+// mailCmd returns a MAIL command with the given parameters.
+func (c *Client) mailCmd(from string, opts *MailOptions) (string, error) {
+	if err := validateLine(from); err != nil {
+		return "", err
+	}
+	if opts == nil {
+		opts = &MailOptions{}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "MAIL FROM:<%s>", from)
+	if _, ok := c.ext["8BITMIME"]; ok {
+		b.WriteString(" BODY=8BITMIME")
+	}
+	if opts.UTF8 || !isASCII(from) {
+		if _, ok := c.ext["SMTPUTF8"]; !ok {
+			return "", errors.New("smtp: server doesn't support SMTPUTF8")
+		}
+		b.WriteString(" SMTPUTF8")
+	}
+	if max, ok := c.ext["SIZE"]; ok && opts.Size > 0 {
+		if max, err := strconv.ParseInt(max, 10, 64); err == nil && max > 0 && opts.Size > max {
+			return "", fmt.Errorf("smtp: message size %d exceeds server limit %d", opts.Size, max)
+		}
+		fmt.Fprintf(&b, " SIZE=%d", opts.Size)
+	}
+	if opts.Return != "" || opts.EnvelopeID != "" {
+		if _, ok := c.ext["DSN"]; !ok {
+			return "", errors.New("smtp: server doesn't support DSN")
+		}
+	}
+	switch opts.Return {
+	case "":
+	case "FULL", "HDRS":
+		b.WriteString(" RET=" + opts.Return)
+	default:
+		return "", fmt.Errorf("smtp: invalid DSN return %q", opts.Return)
+	}
+	if opts.EnvelopeID != "" {
+		b.WriteString(" ENVID=" + xtextEncode(opts.EnvelopeID))
+	}
+	return b.String(), nil
+}
+
+// rcptCmd returns a RCPT command with the given parameters.
+func (c *Client) rcptCmd(to string, opts *RcptOptions) (string, error) {
+	if err := validateLine(to); err != nil {
+		return "", err
+	}
+	cmd := "RCPT TO:<" + to + ">"
+	if opts == nil || len(opts.Notify) == 0 && opts.OriginalRecipient == "" {
+		return cmd, nil
+	}
+	if _, ok := c.ext["DSN"]; !ok {
+		return "", errors.New("smtp: server doesn't support DSN")
+	}
+	if len(opts.Notify) > 0 {
+		if err := validateNotify(opts.Notify); err != nil {
+			return "", err
+		}
+		cmd += " NOTIFY=" + strings.Join(opts.Notify, ",")
+	}
+	if opts.OriginalRecipient != "" {
+		cmd += " ORCPT=rfc822;" + xtextEncode(opts.OriginalRecipient)
+	}
+	return cmd, nil
+}
+
+// validateNotify checks the values of a NOTIFY parameter.
+func validateNotify(notify []string) error {
+	for _, v := range notify {
+		switch v {
+		case "NEVER":
+			if len(notify) > 1 {
+				return errors.New("smtp: DSN notify NEVER combined with other values")
+			}
+		case "SUCCESS", "FAILURE", "DELAY":
+		default:
+			return fmt.Errorf("smtp: invalid DSN notify %q", v)
+		}
+	}
+	return nil
+}
+
+// Send sends a message in a single mail transaction, from the address
+// from to the addresses in to, using the MAIL parameters in opts, which
+// may be nil. The message is read from msg, which has the same form as
+// the msg parameter of [SendMail].
+//
+// If the server supports the PIPELINING extension (RFC 2920), Send
+// sends the MAIL, RCPT and DATA commands together, without waiting
+// for the response to each.
+//
+// If the server rejects some but not all of the recipients, Send sends
+// the message to the others, and returns an error for each rejected
+// recipient. The errors wrap the [*textproto.Error] from the server.
+func (c *Client) Send(from string, to []string, msg io.Reader, opts *MailOptions) error {
+	if len(to) == 0 {
+		return errors.New("smtp: no recipients")
+	}
+	if err := c.hello(); err != nil {
+		return err
+	}
+	if !isASCII(from) || !allASCII(to) {
+		o := MailOptions{UTF8: true}
+		if opts != nil {
+			o = *opts
+			o.UTF8 = true
+		}
+		opts = &o
+	}
+	cmds := make([]string, 0, len(to)+2)
+	cmd, err := c.mailCmd(from, opts)
+	if err != nil {
+		return err
+	}
+	cmds = append(cmds, cmd)
+	for _, addr := range to {
+		cmd, err := c.rcptCmd(addr, nil)
+		if err != nil {
+			return err
+		}
+		cmds = append(cmds, cmd)
+	}
+	cmds = append(cmds, "DATA")
+
+	// With pipelining, send all the commands before reading any response.
+	// Otherwise, stop if MAIL fails, and don't send DATA if every
+	// recipient is rejected.
+	_, pipelining := c.ext["PIPELINING"]
+	errs := make([]error, len(cmds))
+	if pipelining {
+		ids := make([]uint, len(cmds))
+		for i, cmd := range cmds {
+			if ids[i], err = c.Text.Cmd("%s", cmd); err != nil {
+				return err
+			}
+		}
+		for i, id := range ids {
+			errs[i] = c.response(id, expectCode(i, len(cmds)))
+		}
+	} else {
+		for i, cmd := range cmds[:len(cmds)-1] {
+			_, _, errs[i] = c.cmd(expectCode(i, len(cmds)), "%s", cmd)
+			if errs[0] != nil {
+				return errs[0]
+			}
+		}
+	}
+	accepted := 0
+	for i, addr := range to {
+		if err := errs[i+1]; err != nil {
+			errs[i+1] = fmt.Errorf("smtp: recipient %s: %w", addr, err)
+		} else {
+			accepted++
+		}
+	}
+	if !pipelining && accepted > 0 {
+		_, _, errs[len(cmds)-1] = c.cmd(354, "DATA")
+	}
+	if dataErr := errs[len(cmds)-1]; errs[0] != nil || accepted == 0 || dataErr != nil {
+		if pipelining && dataErr == nil {
+			// The server accepted DATA for a failed transaction.
+			// Send an empty message to end it.
+			(&dataCloser{c, c.Text.DotWriter()}).Close()
+		}
+		c.Reset()
+		return errors.Join(errs...)
+	}
+
+	w := &dataCloser{c, c.Text.DotWriter()}
+	if _, err := io.Copy(w, msg); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return errors.Join(errs[1 : len(to)+1]...)
+}
+
+// expectCode returns the expected response code of command i of n
+// sent by Send: 250 for MAIL, 25x for RCPT and 354 for DATA.
+func expectCode(i, n int) int {
+	switch i {
+	case 0:
+		return 250
+	case n - 1:
+		return 354
+	}
+	return 25
+}
+
+// response reads the response to the pipelined command with the given id.
+func (c *Client) response(id uint, expectCode int) error {
+	c.Text.StartResponse(id)
+	defer c.Text.EndResponse(id)
+	_, _, err := c.Text.ReadResponse(expectCode)
+	return err
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+func allASCII(v []string) bool {
+	for _, s := range v {
+		if !isASCII(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// xtextEncode encodes s as xtext (RFC 3461, section 4).
+func xtextEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < '!' || c > '~' || c == '+' || c == '=' {
+			fmt.Fprintf(&b, "+%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// xtextDecode decodes the xtext s.
+func xtextDecode(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '+' {
+			if i+2 >= len(s) {
+				return "", errors.New("smtp: invalid xtext")
+			}
+			n, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			if err != nil {
+				return "", errors.New("smtp: invalid xtext")
+			}
+			b.WriteByte(byte(n))
+			i += 2
+			continue
+		}
+		if c < '!' || c > '~' || c == '=' {
+			return "", errors.New("smtp: invalid xtext")
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), nil
+}
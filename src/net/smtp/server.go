@@ -0,0 +1,690 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package smtp
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A Backend creates sessions for a [Server].
+type Backend interface {
+	// NewSession returns a new session for the connection c.
+	// It is called each time the client sends HELO or EHLO.
+	// If NewSession returns an error, the server rejects the
+	// greeting with that error, as described for [Session].
+	NewSession(c *ServerConn) (Session, error)
+}
+
+// A Session receives the mail transactions of an SMTP connection.
+//
+// If a method returns a [*textproto.Error], the server responds to
+// the command with its code and message. Other errors are sent to
+// the client with a permanent failure code.
+type Session interface {
+	// Mail starts a mail transaction from the address from.
+	// The address is empty for a null reverse-path, as used by
+	// delivery status notifications.
+	Mail(from string, opts *MailOptions) error
+
+	// Rcpt adds the recipient to to the mail transaction.
+	Rcpt(to string, opts *RcptOptions) error
+
+	// Data receives the message of the mail transaction.
+	// The message body must not be used after Data returns.
+	Data(msg *mail.Message) error
+
+	// Reset ends the current mail transaction, if any.
+	// It is called after each call to Data, and when the
+	// client resets the transaction.
+	Reset()
+
+	// Close is called when the session ends.
+	Close() error
+}
+
+// A PlainAuthSession is a [Session] which supports authentication
+// with the PLAIN mechanism (RFC 4616).
+type PlainAuthSession interface {
+	Session
+
+	// AuthPlain authenticates the client as username, with password,
+	// to act as identity. The identity is empty if the client
+	// does not give one.
+	AuthPlain(identity, username, password string) error
+}
+
+// A Server is a minimal SMTP server, suitable for receiving mail in tests
+// and local development. It supports the PIPELINING, 8BITMIME, SMTPUTF8,
+// DSN, SIZE, ENHANCEDSTATUSCODES, STARTTLS and AUTH extensions, and
+// passes the mail it receives to a [Backend].
+//
+// A Server neither relays nor queues mail.
+type Server struct {
+	// Addr is the TCP address to listen on, used by ListenAndServe.
+	// If empty, ":smtp" is used.
+	Addr string
+
+	// Backend creates the sessions which receive mail.
+	Backend Backend
+
+	// Domain is the server's host name, sent in its greeting.
+	// If empty, "localhost" is used.
+	Domain string
+
+	// TLSConfig, if non-nil, enables the STARTTLS extension.
+	TLSConfig *tls.Config
+
+	// AllowInsecureAuth allows clients to authenticate on
+	// connections which do not use TLS.
+	AllowInsecureAuth bool
+
+	// MaxMessageBytes is the maximum size of a message.
+	// Zero means no limit.
+	MaxMessageBytes int64
+
+	// MaxRecipients is the maximum number of recipients
+	// of a mail transaction. Zero means no limit.
+	MaxRecipients int
+
+	// ReadTimeout and WriteTimeout are the maximum durations
+	// for reading each command or message from the client,
+	// and for writing each response. Zero means no timeout.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// ErrorLog specifies an optional logger for errors in
+	// connections and sessions. If nil, logging is done via
+	// the log package's standard logger.
+	ErrorLog *log.Logger
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[*ServerConn]struct{}
+	closed    bool
+}
+
+// ErrServerClosed is returned by the [Server.Serve] and
+// [Server.ListenAndServe] methods after a call to [Server.Close].
+var ErrServerClosed = errors.New("smtp: Server closed")
+
+// ListenAndServe listens on the TCP network address s.Addr and then
+// calls [Server.Serve] to handle connections.
+func (s *Server) ListenAndServe() error {
+	addr := s.Addr
+	if addr == "" {
+		addr = ":smtp"
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts connections on the listener l, serving each in a new
+// goroutine. Serve always returns a non-nil error and closes l.
+// After [Server.Close], the returned error is [ErrServerClosed].
+func (s *Server) Serve(l net.Listener) error {
+	defer l.Close()
+	if !s.track(l, nil, true) {
+		return ErrServerClosed
+	}
+	defer s.track(l, nil, false)
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
+			return err
+		}
+		c := &ServerConn{server: s, rwc: conn, conn: conn, text: textproto.NewConn(conn)}
+		if !s.track(nil, c, true) {
+			conn.Close()
+			return ErrServerClosed
+		}
+		go func() {
+			defer s.track(nil, c, false)
+			c.serve()
+		}()
+	}
+}
+
+// Close immediately closes all listeners and connections.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	var err error
+	for l := range s.listeners {
+		if cerr := l.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	for c := range s.conns {
+		c.rwc.Close()
+	}
+	return err
+}
+
+// track adds or removes a listener or connection.
+// It reports false if the server is closed.
+func (s *Server) track(l net.Listener, c *ServerConn, add bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if add && s.closed {
+		return false
+	}
+	if s.listeners == nil {
+		s.listeners = make(map[net.Listener]struct{})
+		s.conns = make(map[*ServerConn]struct{})
+	}
+	switch {
+	case l != nil && add:
+		s.listeners[l] = struct{}{}
+	case l != nil:
+		delete(s.listeners, l)
+	case add:
+		s.conns[c] = struct{}{}
+	default:
+		delete(s.conns, c)
+	}
+	return true
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *Server) logf(format string, args ...any) {
+	if s.ErrorLog != nil {
+		s.ErrorLog.Printf(format, args...)
+	} else {
+		log.Printf(format, args...)
+	}
+}
+
+// A ServerConn is a connection to a [Server].
+type ServerConn struct {
+	server *Server
+	rwc    net.Conn // underlying connection
+	conn   net.Conn // rwc, or a TLS connection using it
+	text   *textproto.Conn
+
+	helloName string
+	session   Session
+	authed    bool
+	inMail    bool // in a mail transaction
+	rcpts     int  // recipients of the mail transaction
+}
+
+// RemoteAddr returns the client's network address.
+func (c *ServerConn) RemoteAddr() net.Addr {
+	return c.rwc.RemoteAddr()
+}
+
+// Hello returns the host name sent by the client in its HELO or EHLO command.
+func (c *ServerConn) Hello() string {
+	return c.helloName
+}
+
+// TLSConnectionState returns the connection's TLS state.
+// The return values are their zero values if the connection
+// does not use TLS.
+func (c *ServerConn) TLSConnectionState() (state tls.ConnectionState, ok bool) {
+	tc, ok := c.conn.(*tls.Conn)
+	if !ok {
+		return
+	}
+	return tc.ConnectionState(), true
+}
+
+func (c *ServerConn) serve() {
+	defer func() {
+		c.endSession()
+		c.text.Close()
+	}()
+	domain := c.server.Domain
+	if domain == "" {
+		domain = "localhost"
+	}
+	c.reply(220, "%s ESMTP Service ready", domain)
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				c.server.logf("smtp: reading from %v: %v", c.RemoteAddr(), err)
+			}
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		if !c.handle(strings.ToUpper(verb), strings.TrimSpace(arg)) {
+			c.flush()
+			return
+		}
+	}
+}
+
+// readLine reads a command line, first sending the buffered
+// responses if the client has sent no more commands.
+func (c *ServerConn) readLine() (string, error) {
+	if c.text.R.Buffered() == 0 {
+		if err := c.flush(); err != nil {
+			return "", err
+		}
+	}
+	if d := c.server.ReadTimeout; d > 0 {
+		c.conn.SetReadDeadline(time.Now().Add(d))
+	}
+	return c.text.ReadLine()
+}
+
+// reply buffers a response to the client.
+func (c *ServerConn) reply(code int, format string, args ...any) {
+	fmt.Fprintf(c.text.W, "%d %s\r\n", code, fmt.Sprintf(format, args...))
+}
+
+// replyError buffers a response for the error err,
+// using code if err is not a *textproto.Error.
+func (c *ServerConn) replyError(code int, err error) {
+	msg := err.Error()
+	if terr, ok := errors.AsType[*textproto.Error](err); ok {
+		code, msg = terr.Code, terr.Msg
+	}
+	c.reply(code, "%s", strings.ReplaceAll(msg, "\n", " "))
+}
+
+// flush sends the buffered responses.
+func (c *ServerConn) flush() error {
+	if c.text.W.Buffered() == 0 {
+		return nil
+	}
+	if d := c.server.WriteTimeout; d > 0 {
+		c.conn.SetWriteDeadline(time.Now().Add(d))
+	}
+	return c.text.W.Flush()
+}
+
+// handle handles a command, and reports whether to continue
+// reading commands.
+func (c *ServerConn) handle(verb, arg string) bool {
+	switch verb {
+	case "HELO", "EHLO":
+		c.hello(verb, arg)
+	case "MAIL":
+		c.mail(arg)
+	case "RCPT":
+		c.rcpt(arg)
+	case "DATA":
+		return c.data()
+	case "RSET":
+		c.reset()
+		c.reply(250, "2.0.0 OK")
+	case "NOOP":
+		c.reply(250, "2.0.0 OK")
+	case "VRFY":
+		c.reply(252, "2.5.0 Cannot VRFY user")
+	case "STARTTLS":
+		return c.startTLS()
+	case "AUTH":
+		return c.auth(arg)
+	case "QUIT":
+		c.reply(221, "2.0.0 Bye")
+		return false
+	default:
+		c.reply(500, "5.5.2 Command not recognized")
+	}
+	return true
+}
+
+func (c *ServerConn) hello(verb, name string) {
+	if name == "" {
+		c.reply(501, "5.5.4 Domain name required")
+		return
+	}
+	c.endSession()
+	c.helloName = name
+	session, err := c.server.Backend.NewSession(c)
+	if err != nil {
+		c.helloName = ""
+		c.replyError(554, err)
+		return
+	}
+	c.session = session
+	domain := c.server.Domain
+	if domain == "" {
+		domain = "localhost"
+	}
+	if verb == "HELO" {
+		c.reply(250, "%s", domain)
+		return
+	}
+	exts := []string{domain, "PIPELINING", "8BITMIME", "SMTPUTF8", "DSN", "ENHANCEDSTATUSCODES"}
+	if max := c.server.MaxMessageBytes; max > 0 {
+		exts = append(exts, fmt.Sprintf("SIZE %d", max))
+	} else {
+		exts = append(exts, "SIZE")
+	}
+	_, isTLS := c.conn.(*tls.Conn)
+	if c.server.TLSConfig != nil && !isTLS {
+		exts = append(exts, "STARTTLS")
+	}
+	if c.canAuth() {
+		exts = append(exts, "AUTH PLAIN")
+	}
+	for i, ext := range exts {
+		sep := "-"
+		if i == len(exts)-1 {
+			sep = " "
+		}
+		fmt.Fprintf(c.text.W, "250%s%s\r\n", sep, ext)
+	}
+}
+
+// canAuth reports whether the client may authenticate.
+func (c *ServerConn) canAuth() bool {
+	if _, ok := c.session.(PlainAuthSession); !ok {
+		return false
+	}
+	_, isTLS := c.conn.(*tls.Conn)
+	return isTLS || c.server.AllowInsecureAuth
+}
+
+// endSession closes the current session, if any.
+func (c *ServerConn) endSession() {
+	if c.session == nil {
+		return
+	}
+	if err := c.session.Close(); err != nil {
+		c.server.logf("smtp: closing session for %v: %v", c.RemoteAddr(), err)
+	}
+	c.session = nil
+	c.helloName = ""
+	c.authed = false
+	c.inMail = false
+	c.rcpts = 0
+}
+
+// reset ends the current mail transaction.
+func (c *ServerConn) reset() {
+	if c.session != nil {
+		c.session.Reset()
+	}
+	c.inMail = false
+	c.rcpts = 0
+}
+
+// parsePath parses a command argument of the form "PREFIX:<path> params".
+func parsePath(prefix, arg string) (path string, params []string, ok bool) {
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", nil, false
+	}
+	arg = strings.TrimLeft(arg[len(prefix):], " ")
+	if !strings.HasPrefix(arg, "<") {
+		return "", nil, false
+	}
+	path, rest, ok := strings.Cut(arg[1:], ">")
+	if !ok || rest != "" && rest[0] != ' ' {
+		return "", nil, false
+	}
+	return path, strings.Fields(rest), true
+}
+
+func (c *ServerConn) mail(arg string) {
+	switch {
+	case c.session == nil:
+		c.reply(503, "5.5.1 Send HELO first")
+		return
+	case c.inMail:
+		c.reply(503, "5.5.1 Nested MAIL command")
+		return
+	}
+	from, params, ok := parsePath("FROM:", arg)
+	if !ok {
+		c.reply(501, "5.5.4 Syntax: MAIL FROM:<address>")
+		return
+	}
+	opts := &MailOptions{}
+	for _, p := range params {
+		key, value, _ := strings.Cut(p, "=")
+		var err error
+		switch strings.ToUpper(key) {
+		case "BODY":
+			switch strings.ToUpper(value) {
+			case "7BIT", "8BITMIME":
+			default:
+				err = errors.New("unsupported BODY value")
+			}
+		case "SMTPUTF8":
+			opts.UTF8 = true
+		case "SIZE":
+			opts.Size, err = strconv.ParseInt(value, 10, 64)
+		case "RET":
+			opts.Return = strings.ToUpper(value)
+			if opts.Return != "FULL" && opts.Return != "HDRS" {
+				err = errors.New("invalid RET value")
+			}
+		case "ENVID":
+			opts.EnvelopeID, err = xtextDecode(value)
+		default:
+			c.reply(555, "5.5.4 Unsupported parameter %s", key)
+			return
+		}
+		if err != nil {
+			c.reply(501, "5.5.4 Invalid %s parameter", strings.ToUpper(key))
+			return
+		}
+	}
+	if !opts.UTF8 && !isASCII(from) {
+		c.reply(553, "5.6.7 Non-ASCII address requires SMTPUTF8")
+		return
+	}
+	if max := c.server.MaxMessageBytes; max > 0 && opts.Size > max {
+		c.reply(552, "5.3.4 Message size exceeds limit")
+		return
+	}
+	if err := c.session.Mail(from, opts); err != nil {
+		c.replyError(550, err)
+		return
+	}
+	c.inMail = true
+	c.reply(250, "2.1.0 OK")
+}
+
+func (c *ServerConn) rcpt(arg string) {
+	if !c.inMail {
+		c.reply(503, "5.5.1 Send MAIL first")
+		return
+	}
+	to, params, ok := parsePath("TO:", arg)
+	if !ok || to == "" {
+		c.reply(501, "5.5.4 Syntax: RCPT TO:<address>")
+		return
+	}
+	if max := c.server.MaxRecipients; max > 0 && c.rcpts >= max {
+		c.reply(452, "4.5.3 Too many recipients")
+		return
+	}
+	opts := &RcptOptions{}
+	for _, p := range params {
+		key, value, _ := strings.Cut(p, "=")
+		var err error
+		switch strings.ToUpper(key) {
+		case "NOTIFY":
+			opts.Notify = strings.Split(strings.ToUpper(value), ",")
+			err = validateNotify(opts.Notify)
+		case "ORCPT":
+			addrType, addr, ok := strings.Cut(value, ";")
+			if !ok || !strings.EqualFold(addrType, "rfc822") {
+				err = errors.New("unsupported address type")
+				break
+			}
+			opts.OriginalRecipient, err = xtextDecode(addr)
+		default:
+			c.reply(555, "5.5.4 Unsupported parameter %s", key)
+			return
+		}
+		if err != nil {
+			c.reply(501, "5.5.4 Invalid %s parameter", strings.ToUpper(key))
+			return
+		}
+	}
+	if err := c.session.Rcpt(to, opts); err != nil {
+		c.replyError(550, err)
+		return
+	}
+	c.rcpts++
+	c.reply(250, "2.1.5 OK")
+}
+
+// errMessageTooLarge is returned when reading a message
+// larger than the server's MaxMessageBytes.
+var errMessageTooLarge = &textproto.Error{Code: 552, Msg: "5.3.4 Message size exceeds limit"}
+
+// A dataReader reads a message, failing once it exceeds max bytes.
+type dataReader struct {
+	r    io.Reader
+	max  int64 // maximum size, or 0 for no limit
+	size int64
+}
+
+func (r *dataReader) Read(p []byte) (int, error) {
+	if r.max > 0 && r.size > r.max {
+		return 0, errMessageTooLarge
+	}
+	n, err := r.r.Read(p)
+	r.size += int64(n)
+	if r.max > 0 && r.size > r.max {
+		return 0, errMessageTooLarge
+	}
+	return n, err
+}
+
+func (c *ServerConn) data() bool {
+	switch {
+	case !c.inMail:
+		c.reply(503, "5.5.1 Send MAIL first")
+		return true
+	case c.rcpts == 0:
+		c.reply(554, "5.5.1 No valid recipients")
+		return true
+	}
+	c.reply(354, "Start mail input; end with <CRLF>.<CRLF>")
+	if err := c.flush(); err != nil {
+		return false
+	}
+	if d := c.server.ReadTimeout; d > 0 {
+		c.conn.SetReadDeadline(time.Now().Add(d))
+	}
+	dot := c.text.DotReader()
+	r := &dataReader{r: dot, max: c.server.MaxMessageBytes}
+	msg, err := mail.ReadMessage(r)
+	if err == nil {
+		err = c.session.Data(msg)
+	} else if !errors.Is(err, errMessageTooLarge) {
+		err = &textproto.Error{Code: 554, Msg: "5.6.0 Malformed message: " + err.Error()}
+	}
+	if _, derr := io.Copy(io.Discard, dot); derr != nil {
+		return false
+	}
+	c.reset()
+	switch {
+	case r.max > 0 && r.size > r.max:
+		c.replyError(552, errMessageTooLarge)
+	case err != nil:
+		c.replyError(554, err)
+	default:
+		c.reply(250, "2.0.0 OK: message accepted")
+	}
+	return true
+}
+
+func (c *ServerConn) startTLS() bool {
+	_, isTLS := c.conn.(*tls.Conn)
+	if c.server.TLSConfig == nil || isTLS {
+		c.reply(502, "5.5.1 STARTTLS not available")
+		return true
+	}
+	c.reply(220, "2.0.0 Ready to start TLS")
+	if err := c.flush(); err != nil {
+		return false
+	}
+	tc := tls.Server(c.conn, c.server.TLSConfig)
+	if d := c.server.ReadTimeout; d > 0 {
+		tc.SetDeadline(time.Now().Add(d))
+	}
+	if err := tc.Handshake(); err != nil {
+		c.server.logf("smtp: TLS handshake with %v: %v", c.RemoteAddr(), err)
+		return false
+	}
+	tc.SetDeadline(time.Time{})
+	// Discard any commands sent before the handshake,
+	// and start again from the greeting (RFC 3207, section 4.2).
+	c.endSession()
+	c.conn = tc
+	c.text = textproto.NewConn(tc)
+	return true
+}
+
+func (c *ServerConn) auth(arg string) bool {
+	switch {
+	case c.session == nil:
+		c.reply(503, "5.5.1 Send EHLO first")
+		return true
+	case c.authed:
+		c.reply(503, "5.5.1 Already authenticated")
+		return true
+	case c.inMail:
+		c.reply(503, "5.5.1 AUTH not permitted during a mail transaction")
+		return true
+	case !c.canAuth():
+		c.reply(502, "5.5.1 AUTH not available")
+		return true
+	}
+	mech, resp, _ := strings.Cut(arg, " ")
+	if !strings.EqualFold(mech, "PLAIN") {
+		c.reply(504, "5.5.4 Unsupported authentication mechanism")
+		return true
+	}
+	if resp == "" {
+		c.reply(334, "")
+		line, err := c.readLine()
+		if err != nil {
+			return false
+		}
+		resp = line
+	}
+	if resp == "*" {
+		c.reply(501, "5.0.0 Authentication cancelled")
+		return true
+	}
+	b, err := base64.StdEncoding.DecodeString(resp)
+	parts := strings.Split(string(b), "\x00")
+	if err != nil || len(parts) != 3 {
+		c.reply(501, "5.5.2 Invalid authentication response")
+		return true
+	}
+	if err := c.session.(PlainAuthSession).AuthPlain(parts[0], parts[1], parts[2]); err != nil {
+		c.replyError(535, err)
+		return true
+	}
+	c.authed = true
+	c.reply(235, "2.7.0 Authentication successful")
+	return true
+}
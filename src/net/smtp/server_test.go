@@ -0,0 +1,285 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package smtp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// A testMessage is a message received by a testBackend.
+type testMessage struct {
+	from     string
+	mailOpts MailOptions
+	to       []string
+	rcptOpts []RcptOptions
+	subject  string
+	body     string
+	tls      bool
+}
+
+// A testBackend records the messages it receives.
+// It rejects recipients whose addresses begin with "reject".
+type testBackend struct {
+	requireAuth bool
+
+	mu   sync.Mutex
+	msgs []testMessage
+}
+
+func (b *testBackend) NewSession(c *ServerConn) (Session, error) {
+	return &testSession{b: b, c: c}, nil
+}
+
+func (b *testBackend) messages() []testMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.msgs
+}
+
+type testSession struct {
+	b      *testBackend
+	c      *ServerConn
+	authed bool
+	msg    testMessage
+}
+
+func (s *testSession) AuthPlain(identity, username, password string) error {
+	if username != "user" || password != "pass" {
+		return errors.New("invalid credentials")
+	}
+	s.authed = true
+	return nil
+}
+
+func (s *testSession) Mail(from string, opts *MailOptions) error {
+	if s.b.requireAuth && !s.authed {
+		return &textproto.Error{Code: 530, Msg: "5.7.0 Authentication required"}
+	}
+	_, tls := s.c.TLSConnectionState()
+	s.msg = testMessage{from: from, mailOpts: *opts, tls: tls}
+	return nil
+}
+
+func (s *testSession) Rcpt(to string, opts *RcptOptions) error {
+	if strings.HasPrefix(to, "reject") {
+		return &textproto.Error{Code: 550, Msg: "5.1.1 No such user"}
+	}
+	s.msg.to = append(s.msg.to, to)
+	s.msg.rcptOpts = append(s.msg.rcptOpts, *opts)
+	return nil
+}
+
+func (s *testSession) Data(msg *mail.Message) error {
+	body, err := io.ReadAll(msg.Body)
+	if err != nil {
+		return err
+	}
+	s.msg.subject = msg.Header.Get("Subject")
+	s.msg.body = string(body)
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	s.b.msgs = append(s.b.msgs, s.msg)
+	return nil
+}
+
+func (s *testSession) Reset() {
+	s.msg = testMessage{}
+}
+
+func (s *testSession) Close() error {
+	return nil
+}
+
+// newTestServer starts s, and returns its address.
+func newTestServer(t *testing.T, s *Server) string {
+	ln := newLocalListener(t)
+	s.ErrorLog = log.New(io.Discard, "", 0)
+	go s.Serve(ln)
+	t.Cleanup(func() { s.Close() })
+	return ln.Addr().String()
+}
+
+func testServerTLSConfig(t *testing.T) *tls.Config {
+	cert, err := tls.X509KeyPair(localhostCert, localhostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}}
+}
+
+func TestServerSend(t *testing.T) {
+	b := &testBackend{requireAuth: true}
+	addr := newTestServer(t, &Server{Backend: b, TLSConfig: testServerTLSConfig(t)})
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &Dialer{
+		StartTLS: StartTLSRequired,
+		Auth:     PlainAuth("", "user", "pass", host),
+	}
+	c, err := d.Dial(context.Background(), addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, ok := c.TLSConnectionState(); !ok {
+		t.Errorf("connection does not use TLS")
+	}
+	if ok, _ := c.Extension("PIPELINING"); !ok {
+		t.Errorf("server does not advertise PIPELINING")
+	}
+
+	to := []string{"a@example.com", "reject@example.com", "b@example.com"}
+	msg := "Subject: test\r\n\r\nhello\r\n.dot\r\n"
+	err = c.Send("joe@example.com", to, strings.NewReader(msg), &MailOptions{Return: "HDRS", EnvelopeID: "id+1 x"})
+	if terr, ok := errors.AsType[*textproto.Error](err); !ok || terr.Code != 550 || !strings.Contains(err.Error(), "reject@example.com") {
+		t.Errorf("Send error = %v, want 550 error for reject@example.com", err)
+	}
+
+	// A second transaction, with a non-ASCII address, and DSN parameters.
+	if err := c.MailWithOptions("jöe@example.com", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RcptWithOptions("c@example.com", &RcptOptions{Notify: []string{"FAILURE", "DELAY"}, OriginalRecipient: "c=d@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "Subject: second\r\n\r\nbody\r\n")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Quit(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []testMessage{{
+		from:     "joe@example.com",
+		mailOpts: MailOptions{Return: "HDRS", EnvelopeID: "id+1 x"},
+		to:       []string{"a@example.com", "b@example.com"},
+		rcptOpts: []RcptOptions{{}, {}},
+		subject:  "test",
+		body:     "hello\n.dot\n",
+		tls:      true,
+	}, {
+		from:     "jöe@example.com",
+		mailOpts: MailOptions{UTF8: true},
+		to:       []string{"c@example.com"},
+		rcptOpts: []RcptOptions{{Notify: []string{"FAILURE", "DELAY"}, OriginalRecipient: "c=d@example.com"}},
+		subject:  "second",
+		body:     "body\n",
+		tls:      true,
+	}}
+	if got := b.messages(); !reflect.DeepEqual(got, want) {
+		t.Errorf("received messages:\n%+v\nwant:\n%+v", got, want)
+	}
+}
+
+func TestServerSendFailures(t *testing.T) {
+	b := &testBackend{}
+	addr := newTestServer(t, &Server{Backend: b, MaxMessageBytes: 100, MaxRecipients: 2})
+	c, err := (&Dialer{}).Dial(context.Background(), addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	tests := []struct {
+		to   []string
+		msg  string
+		opts *MailOptions
+		code int
+	}{
+		{to: []string{"reject1@example.com", "reject2@example.com"}, msg: "Subject: x\r\n\r\n", code: 550},
+		{to: []string{"a@example.com", "b@example.com", "c@example.com"}, msg: "Subject: x\r\n\r\n", code: 452},
+		{to: []string{"a@example.com"}, msg: "Subject: x\r\n\r\n" + strings.Repeat("x", 200), code: 552},
+		{to: []string{"a@example.com"}, msg: "not a header\r\n", code: 554},
+	}
+	for _, tt := range tests {
+		err := c.Send("joe@example.com", tt.to, strings.NewReader(tt.msg), tt.opts)
+		if terr, ok := errors.AsType[*textproto.Error](err); !ok || terr.Code != tt.code {
+			t.Errorf("Send to %v: error = %v, want code %d", tt.to, err, tt.code)
+		}
+		// The connection remains usable.
+		if err := c.Noop(); err != nil {
+			t.Fatalf("Noop after Send to %v: %v", tt.to, err)
+		}
+	}
+	if n := len(b.messages()); n != 1 {
+		t.Errorf("received %d messages, want 1 (to the first two recipients)", n)
+	}
+
+	// The message size is checked before sending if it is known.
+	if err := c.MailWithOptions("joe@example.com", &MailOptions{Size: 200}); err == nil {
+		t.Errorf("MailWithOptions with size over the server limit succeeded")
+	}
+	// The server doesn't support STARTTLS without a TLSConfig.
+	if _, err := (&Dialer{StartTLS: StartTLSRequired}).Dial(context.Background(), addr); err == nil {
+		t.Errorf("Dial requiring STARTTLS succeeded without server support")
+	}
+}
+
+func TestServerPlainAuthRequiresTLS(t *testing.T) {
+	addr := newTestServer(t, &Server{Backend: &testBackend{}})
+	c, err := (&Dialer{}).Dial(context.Background(), addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("AUTH"); ok {
+		t.Errorf("server advertises AUTH without TLS")
+	}
+}
+
+func TestDialerCanceled(t *testing.T) {
+	ln := newLocalListener(t)
+	defer ln.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		// Accept the connection, but never send a greeting.
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		cancel()
+		io.Copy(io.Discard, c)
+	}()
+	if _, err := (&Dialer{}).Dial(ctx, ln.Addr().String()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Dial error = %v, want context.Canceled", err)
+	}
+}
+
+func TestXtext(t *testing.T) {
+	for _, s := range []string{"", "abc", "a+b=c d", "\x00\xff"} {
+		enc := xtextEncode(s)
+		if strings.ContainsAny(enc, " =\x00\xff") {
+			t.Errorf("xtextEncode(%q) = %q contains invalid characters", s, enc)
+		}
+		if dec, err := xtextDecode(enc); err != nil || dec != s {
+			t.Errorf("xtextDecode(%q) = %q, %v; want %q", enc, dec, err, s)
+		}
+	}
+	for _, s := range []string{"a=b", "a+", "a+4", "a+zz", "a b"} {
+		if _, err := xtextDecode(s); err == nil {
+			t.Errorf("xtextDecode(%q) succeeded, want error", s)
+		}
+	}
+}
@@ -5,16 +5,21 @@
 // Package smtp implements the Simple Mail Transfer Protocol as defined in RFC 5321.
 // It also implements the following extensions:
 //
-//	8BITMIME  RFC 1652
-//	AUTH      RFC 2554
-//	STARTTLS  RFC 3207
+//	8BITMIME    RFC 1652
+//	AUTH        RFC 4954
+//	DSN         RFC 3461
+//	PIPELINING  RFC 2920
+//	SIZE        RFC 1870
+//	SMTPUTF8    RFC 6531
+//	STARTTLS    RFC 3207
 //
 // Additional extensions may be handled by clients.
 //
-// The smtp package is frozen and is not accepting new features.
-// Some external packages provide more functionality. See:
+// A [Dialer] connects to a server with a policy for the use of TLS, and
+// [Client.Send] sends a message in a single, pipelined, mail transaction.
 //
-//	https://godoc.org/?q=smtp
+// The package also provides a minimal [Server], which passes the mail it
+// receives to a [Backend], for use in tests and local development.
 package smtp
 
 import (
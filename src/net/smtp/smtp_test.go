@@ -31,6 +31,7 @@ var authTests = []authTest{
 	{PlainAuth("", "user", "pass", "testserver"), []string{}, "PLAIN", []string{"\x00user\x00pass"}},
 	{PlainAuth("foo", "bar", "baz", "testserver"), []string{}, "PLAIN", []string{"foo\x00bar\x00baz"}},
 	{CRAMMD5Auth("user", "pass"), []string{"<123456.1322876914@testserver>"}, "CRAM-MD5", []string{"", "user 287eb355114cf5c471c26a875f1ca4ae"}},
+	{XOAUTH2Auth("user", "token", "testserver"), []string{`{"status":"401"}`}, "XOAUTH2", []string{"user=user\x01auth=Bearer token\x01\x01", ""}},
 }
 
 func TestAuth(t *testing.T) {
@@ -739,7 +739,8 @@ func (f *File) Fd() uintptr {
 // The directory dir must not be "".
 //
 // The result implements [io/fs.StatFS], [io/fs.ReadFileFS], [io/fs.ReadDirFS], and
-// [io/fs.ReadLinkFS].
+// [io/fs.ReadLinkFS], as well as the write interfaces [io/fs.CreateFS],
+// [io/fs.MkdirFS], [io/fs.RemoveFS], [io/fs.RenameFS], and [io/fs.WriteFileFS].
 func DirFS(dir string) fs.FS {
 	return dirFS(dir)
 }
@@ -748,6 +749,11 @@ var _ fs.StatFS = dirFS("")
 var _ fs.ReadFileFS = dirFS("")
 var _ fs.ReadDirFS = dirFS("")
 var _ fs.ReadLinkFS = dirFS("")
+var _ fs.CreateFS = dirFS("")
+var _ fs.MkdirFS = dirFS("")
+var _ fs.RemoveFS = dirFS("")
+var _ fs.RenameFS = dirFS("")
+var _ fs.WriteFileFS = dirFS("")
 
 type dirFS string
 
@@ -842,6 +848,83 @@ func (dir dirFS) ReadLink(name string) (string, error) {
 	return Readlink(fullname)
 }
 
+func (dir dirFS) Create(name string) (fs.File, error) {
+	fullname, err := dir.join(name)
+	if err != nil {
+		return nil, &PathError{Op: "create", Path: name, Err: err}
+	}
+	f, err := Create(fullname)
+	if err != nil {
+		// See comment in dirFS.Open.
+		err.(*PathError).Path = name
+		return nil, err
+	}
+	return f, nil
+}
+
+func (dir dirFS) Mkdir(name string, perm fs.FileMode) error {
+	fullname, err := dir.join(name)
+	if err != nil {
+		return &PathError{Op: "mkdir", Path: name, Err: err}
+	}
+	if err := Mkdir(fullname, perm); err != nil {
+		// See comment in dirFS.Open.
+		err.(*PathError).Path = name
+		return err
+	}
+	return nil
+}
+
+func (dir dirFS) Remove(name string) error {
+	fullname, err := dir.join(name)
+	if err != nil {
+		return &PathError{Op: "remove", Path: name, Err: err}
+	}
+	if err := Remove(fullname); err != nil {
+		// See comment in dirFS.Open.
+		err.(*PathError).Path = name
+		return err
+	}
+	return nil
+}
+
+func (dir dirFS) Rename(oldname, newname string) error {
+	oldfull, err := dir.join(oldname)
+	if err != nil {
+		return &LinkError{Op: "rename", Old: oldname, New: newname, Err: err}
+	}
+	newfull, err := dir.join(newname)
+	if err != nil {
+		return &LinkError{Op: "rename", Old: oldname, New: newname, Err: err}
+	}
+	if err := Rename(oldfull, newfull); err != nil {
+		// See comment in dirFS.Open.
+		if e, ok := err.(*LinkError); ok {
+			e.Old, e.New = oldname, newname
+		}
+		return err
+	}
+	return nil
+}
+
+// The WriteFile method calls the [WriteFile] function for the file
+// with the given name in the directory.
+// Through this method, dirFS implements [io/fs.WriteFileFS].
+func (dir dirFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	fullname, err := dir.join(name)
+	if err != nil {
+		return &PathError{Op: "writefile", Path: name, Err: err}
+	}
+	if err := WriteFile(fullname, data, perm); err != nil {
+		if e, ok := err.(*PathError); ok {
+			// See comment in dirFS.Open.
+			e.Path = name
+		}
+		return err
+	}
+	return nil
+}
+
 // join returns the path for name in dir.
 func (dir dirFS) join(name string) (string, error) {
 	if dir == "" {
@@ -3303,6 +3303,25 @@ func TestRootDirFS(t *testing.T) {
 	testDirFS(t, r.FS())
 }
 
+func TestDirFSWrite(t *testing.T) {
+	t.Parallel()
+	if err := fstest.TestWriteFS(DirFS(t.TempDir())); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRootFSWrite(t *testing.T) {
+	t.Parallel()
+	r, err := OpenRoot(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if err := fstest.TestWriteFS(r.FS()); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func testDirFS(t *testing.T, fsys fs.FS) {
 	forceMFTUpdateOnWindows(t, "./testdata/dirfs")
 
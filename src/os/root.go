@@ -353,7 +353,9 @@ func splitPathInRoot(s string, prefix, suffix []string) (_ []string, suffixSep s
 // FS returns a file system (an fs.FS) for the tree of files in the root.
 //
 // The result implements [io/fs.StatFS], [io/fs.ReadFileFS],
-// [io/fs.ReadDirFS], and [io/fs.ReadLinkFS].
+// [io/fs.ReadDirFS], and [io/fs.ReadLinkFS], as well as the write
+// interfaces [io/fs.CreateFS], [io/fs.MkdirFS], [io/fs.RemoveFS],
+// [io/fs.RenameFS], and [io/fs.WriteFileFS].
 func (r *Root) FS() fs.FS {
 	return (*rootFS)(r)
 }
@@ -433,6 +435,50 @@ func (rfs *rootFS) Lstat(name string) (FileInfo, error) {
 	return r.Lstat(name)
 }
 
+func (rfs *rootFS) Create(name string) (fs.File, error) {
+	r := (*Root)(rfs)
+	if !isValidRootFSPath(name) {
+		return nil, &PathError{Op: "create", Path: name, Err: ErrInvalid}
+	}
+	f, err := r.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (rfs *rootFS) Mkdir(name string, perm FileMode) error {
+	r := (*Root)(rfs)
+	if !isValidRootFSPath(name) {
+		return &PathError{Op: "mkdir", Path: name, Err: ErrInvalid}
+	}
+	return r.Mkdir(name, perm)
+}
+
+func (rfs *rootFS) Remove(name string) error {
+	r := (*Root)(rfs)
+	if !isValidRootFSPath(name) {
+		return &PathError{Op: "remove", Path: name, Err: ErrInvalid}
+	}
+	return r.Remove(name)
+}
+
+func (rfs *rootFS) Rename(oldname, newname string) error {
+	r := (*Root)(rfs)
+	if !isValidRootFSPath(oldname) || !isValidRootFSPath(newname) {
+		return &LinkError{Op: "rename", Old: oldname, New: newname, Err: ErrInvalid}
+	}
+	return r.Rename(oldname, newname)
+}
+
+func (rfs *rootFS) WriteFile(name string, data []byte, perm FileMode) error {
+	r := (*Root)(rfs)
+	if !isValidRootFSPath(name) {
+		return &PathError{Op: "writefile", Path: name, Err: ErrInvalid}
+	}
+	return r.WriteFile(name, data, perm)
+}
+
 // isValidRootFSPath reports whether name is a valid filename to pass a Root.FS method.
 func isValidRootFSPath(name string) bool {
 	if !fs.ValidPath(name) {
@@ -0,0 +1,698 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fstest
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A MemFS is a writable in-memory file system for use in tests.
+// The zero value is an empty file system ready to use.
+//
+// In addition to the read-only [fs.FS] interfaces, a MemFS provides
+// methods to create, modify, and remove files, directories, and
+// symbolic links. Their semantics follow those of the corresponding
+// methods of [os.Root], with the MemFS as the root: names must be
+// valid according to [fs.ValidPath], symbolic links are followed
+// within the file system, and a symbolic link which refers to a file
+// outside of it, or to an absolute path, cannot be followed. As on
+// Unix systems, an open file continues to refer to the same file
+// after the file is renamed or removed. Permission bits are recorded
+// but not enforced.
+//
+// A MemFS is safe for concurrent use by multiple goroutines.
+type MemFS struct {
+	mu   sync.Mutex
+	root *memNode
+}
+
+// A memNode is a file, directory, or symbolic link in a MemFS.
+type memNode struct {
+	mode     fs.FileMode
+	modTime  time.Time
+	data     []byte              // file content or symlink destination
+	children map[string]*memNode // directory entries
+}
+
+func newMemNode(mode fs.FileMode) *memNode {
+	n := &memNode{mode: mode, modTime: time.Now()}
+	if mode.IsDir() {
+		n.children = make(map[string]*memNode)
+	}
+	return n
+}
+
+var (
+	_ fs.ReadDirFS  = (*MemFS)(nil)
+	_ fs.ReadFileFS = (*MemFS)(nil)
+	_ fs.StatFS     = (*MemFS)(nil)
+	_ fs.ReadLinkFS = (*MemFS)(nil)
+
+	_ fs.CreateFS    = (*MemFS)(nil)
+	_ fs.MkdirFS     = (*MemFS)(nil)
+	_ fs.RemoveFS    = (*MemFS)(nil)
+	_ fs.RenameFS    = (*MemFS)(nil)
+	_ fs.WriteFileFS = (*MemFS)(nil)
+)
+
+var (
+	errPathEscapes = errors.New("path escapes from parent")
+	errNotDir      = errors.New("not a directory")
+	errIsDir       = errors.New("is a directory")
+	errNotEmpty    = errors.New("directory not empty")
+	errTooManyLink = errors.New("too many levels of symbolic links")
+	errBadMode     = errors.New("bad file descriptor")
+)
+
+// maxSymlinks is the maximum number of symbolic links
+// followed while resolving a name.
+const maxSymlinks = 40
+
+func (fsys *MemFS) rootLocked() *memNode {
+	if fsys.root == nil {
+		fsys.root = newMemNode(fs.ModeDir | 0o777)
+	}
+	return fsys.root
+}
+
+// walkLocked resolves name, following symbolic links in all but the
+// last element, and in the last element too if follow is true.
+// It returns the directory containing the named file, the name of
+// the file within that directory, and the file itself, which is nil
+// if it does not exist. If name resolves to a directory other than
+// through an entry in its parent, such as the root, dir is nil.
+func (fsys *MemFS) walkLocked(op, name string, follow bool) (dir *memNode, elem string, n *memNode, err error) {
+	if !fs.ValidPath(name) {
+		return nil, "", nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	stack := []*memNode{fsys.rootLocked()}
+	if name == "." {
+		return nil, "", stack[0], nil
+	}
+	parts := strings.Split(name, "/")
+	links := 0
+	for i := 0; i < len(parts); i++ {
+		cur := stack[len(stack)-1]
+		switch parts[i] {
+		case "", ".":
+			continue
+		case "..":
+			if len(stack) == 1 {
+				return nil, "", nil, &fs.PathError{Op: op, Path: name, Err: errPathEscapes}
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		if !cur.mode.IsDir() {
+			return nil, "", nil, &fs.PathError{Op: op, Path: name, Err: errNotDir}
+		}
+		last := i == len(parts)-1
+		child := cur.children[parts[i]]
+		switch {
+		case child == nil && last:
+			return cur, parts[i], nil, nil
+		case child == nil:
+			return nil, "", nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		case child.mode&fs.ModeSymlink != 0 && (follow || !last):
+			links++
+			if links > maxSymlinks {
+				return nil, "", nil, &fs.PathError{Op: op, Path: name, Err: errTooManyLink}
+			}
+			target := string(child.data)
+			if strings.HasPrefix(target, "/") {
+				return nil, "", nil, &fs.PathError{Op: op, Path: name, Err: errPathEscapes}
+			}
+			parts = append(strings.Split(target, "/"), parts[i+1:]...)
+			i = -1
+		case last:
+			return cur, parts[i], child, nil
+		default:
+			stack = append(stack, child)
+		}
+	}
+	return nil, "", stack[len(stack)-1], nil
+}
+
+// Open opens the named file for reading, after following any
+// symbolic links.
+func (fsys *MemFS) Open(name string) (fs.File, error) {
+	return fsys.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// Create creates or truncates the named file, with mode 0666
+// (before umask) if it is created. The returned [fs.File] is
+// open for reading and writing, and implements [io.Writer],
+// [io.WriterAt], [io.ReaderAt], and [io.Seeker].
+func (fsys *MemFS) Create(name string) (fs.File, error) {
+	return fsys.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o666)
+}
+
+// OpenFile opens the named file with the given flag, one of
+// [os.O_RDONLY], [os.O_WRONLY], or [os.O_RDWR] combined with any of
+// [os.O_APPEND], [os.O_CREATE], [os.O_EXCL], and [os.O_TRUNC].
+// If the file is created, it is given the permission bits of perm.
+// The returned [fs.File] implements the same interfaces as that
+// returned by [MemFS.Create], and directories implement [fs.ReadDirFile].
+func (fsys *MemFS) OpenFile(name string, flag int, perm fs.FileMode) (fs.File, error) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	excl := flag&(os.O_CREATE|os.O_EXCL) == os.O_CREATE|os.O_EXCL
+	dir, elem, n, err := fsys.walkLocked("open", name, !excl)
+	if err != nil {
+		return nil, err
+	}
+	write := flag&(os.O_WRONLY|os.O_RDWR) != 0
+	switch {
+	case n == nil && flag&os.O_CREATE == 0:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	case n == nil:
+		n = newMemNode(perm & fs.ModePerm)
+		dir.children[elem] = n
+		dir.modTime = n.modTime
+	case excl:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+	case n.mode.IsDir() && (write || flag&os.O_TRUNC != 0):
+		return nil, &fs.PathError{Op: "open", Path: name, Err: errIsDir}
+	case flag&os.O_TRUNC != 0 && write:
+		n.data = nil
+		n.modTime = time.Now()
+	}
+	return &memFile{fsys: fsys, n: n, path: name, flag: flag}, nil
+}
+
+// Mkdir creates a new directory with the specified name
+// and permission bits.
+func (fsys *MemFS) Mkdir(name string, perm fs.FileMode) error {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	dir, elem, n, err := fsys.walkLocked("mkdir", name, false)
+	if err != nil {
+		return err
+	}
+	if n != nil {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	}
+	n = newMemNode(fs.ModeDir | perm&fs.ModePerm)
+	dir.children[elem] = n
+	dir.modTime = n.modTime
+	return nil
+}
+
+// MkdirAll creates a directory named name, along with any
+// necessary parents. See [os.MkdirAll].
+func (fsys *MemFS) MkdirAll(name string, perm fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return nil
+	}
+	for i := 0; i <= len(name); i++ {
+		if i < len(name) && name[i] != '/' {
+			continue
+		}
+		dir := name[:i]
+		info, err := fsys.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return &fs.PathError{Op: "mkdir", Path: dir, Err: errNotDir}
+			}
+			continue
+		}
+		if err := fsys.Mkdir(dir, perm); err != nil && !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Remove removes the named file or (empty) directory.
+// If the file is a symbolic link, Remove removes the link.
+func (fsys *MemFS) Remove(name string) error {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	dir, elem, n, err := fsys.walkLocked("remove", name, false)
+	switch {
+	case err != nil:
+		return err
+	case dir == nil:
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrInvalid}
+	case n == nil:
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	case n.mode.IsDir() && len(n.children) > 0:
+		return &fs.PathError{Op: "remove", Path: name, Err: errNotEmpty}
+	}
+	delete(dir.children, elem)
+	dir.modTime = time.Now()
+	return nil
+}
+
+// RemoveAll removes name and any children it contains.
+// It returns nil if name does not exist.
+func (fsys *MemFS) RemoveAll(name string) error {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	dir, elem, n, err := fsys.walkLocked("RemoveAll", name, false)
+	switch {
+	case err != nil:
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	case dir == nil:
+		return &fs.PathError{Op: "RemoveAll", Path: name, Err: fs.ErrInvalid}
+	case n == nil:
+		return nil
+	}
+	delete(dir.children, elem)
+	dir.modTime = time.Now()
+	return nil
+}
+
+// Rename renames (moves) oldname to newname. If newname already
+// exists and is not a directory, Rename replaces it. If newname is
+// an empty directory, and oldname is a directory, Rename replaces it.
+// Symbolic links in the last element of either name are not followed.
+func (fsys *MemFS) Rename(oldname, newname string) error {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	linkErr := func(err error) error {
+		if pe, ok := err.(*fs.PathError); ok {
+			err = pe.Err
+		}
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: err}
+	}
+	odir, oelem, on, err := fsys.walkLocked("rename", oldname, false)
+	if err != nil {
+		return linkErr(err)
+	}
+	ndir, nelem, nn, err := fsys.walkLocked("rename", newname, false)
+	if err != nil {
+		return linkErr(err)
+	}
+	switch {
+	case odir == nil || ndir == nil:
+		return linkErr(fs.ErrInvalid)
+	case on == nil:
+		return linkErr(fs.ErrNotExist)
+	case on == nn:
+		return nil
+	case on.mode.IsDir() && on.contains(ndir):
+		// Moving a directory into itself.
+		return linkErr(fs.ErrInvalid)
+	case nn == nil:
+	case on.mode.IsDir() && !nn.mode.IsDir():
+		return linkErr(errNotDir)
+	case !on.mode.IsDir() && nn.mode.IsDir():
+		return linkErr(errIsDir)
+	case nn.mode.IsDir() && len(nn.children) > 0:
+		return linkErr(errNotEmpty)
+	}
+	delete(odir.children, oelem)
+	ndir.children[nelem] = on
+	now := time.Now()
+	odir.modTime, ndir.modTime = now, now
+	return nil
+}
+
+// contains reports whether the directory n is d or contains d.
+func (n *memNode) contains(d *memNode) bool {
+	if n == d {
+		return true
+	}
+	for _, c := range n.children {
+		if c.mode.IsDir() && c.contains(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// Symlink creates newname as a symbolic link to oldname.
+// The link is not checked when it is created; following it
+// fails if it refers to a name outside of fsys.
+func (fsys *MemFS) Symlink(oldname, newname string) error {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	dir, elem, n, err := fsys.walkLocked("symlink", newname, false)
+	switch {
+	case err != nil:
+		return &os.LinkError{Op: "symlink", Old: oldname, New: newname, Err: err.(*fs.PathError).Err}
+	case dir == nil || n != nil:
+		return &os.LinkError{Op: "symlink", Old: oldname, New: newname, Err: fs.ErrExist}
+	}
+	n = newMemNode(fs.ModeSymlink | 0o777)
+	n.data = []byte(oldname)
+	dir.children[elem] = n
+	dir.modTime = n.modTime
+	return nil
+}
+
+// Chmod changes the permission bits of the named file to those of mode,
+// after following any symbolic links.
+func (fsys *MemFS) Chmod(name string, mode fs.FileMode) error {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	n, err := fsys.lookupLocked("chmod", name, true)
+	if err != nil {
+		return err
+	}
+	n.mode = n.mode&^fs.ModePerm | mode&fs.ModePerm
+	return nil
+}
+
+// Chtimes changes the modification time of the named file,
+// after following any symbolic links. The access time is ignored.
+// A zero mtime leaves the modification time unchanged.
+func (fsys *MemFS) Chtimes(name string, atime, mtime time.Time) error {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	n, err := fsys.lookupLocked("chtimes", name, true)
+	if err != nil {
+		return err
+	}
+	if !mtime.IsZero() {
+		n.modTime = mtime
+	}
+	return nil
+}
+
+// lookupLocked returns the named file, which must exist.
+func (fsys *MemFS) lookupLocked(op, name string, follow bool) (*memNode, error) {
+	_, _, n, err := fsys.walkLocked(op, name, follow)
+	if err != nil {
+		return nil, err
+	}
+	if n == nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return n, nil
+}
+
+// ReadFile reads the named file and returns its contents.
+func (fsys *MemFS) ReadFile(name string) ([]byte, error) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	n, err := fsys.lookupLocked("open", name, true)
+	if err != nil {
+		return nil, err
+	}
+	if n.mode.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: errIsDir}
+	}
+	return slices.Clone(n.data), nil
+}
+
+// WriteFile writes data to the named file, creating it with
+// the permission bits of perm if necessary, or truncating it.
+func (fsys *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	f, err := fsys.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	_, err = f.(io.Writer).Write(data)
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	return err
+}
+
+// Stat returns a [fs.FileInfo] describing the named file,
+// after following any symbolic links.
+func (fsys *MemFS) Stat(name string) (fs.FileInfo, error) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	n, err := fsys.lookupLocked("stat", name, true)
+	if err != nil {
+		return nil, err
+	}
+	return n.infoLocked(path.Base(name)), nil
+}
+
+// Lstat returns a [fs.FileInfo] describing the named file,
+// without following a symbolic link in its last element.
+func (fsys *MemFS) Lstat(name string) (fs.FileInfo, error) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	n, err := fsys.lookupLocked("lstat", name, false)
+	if err != nil {
+		return nil, err
+	}
+	return n.infoLocked(path.Base(name)), nil
+}
+
+// ReadLink returns the destination of the named symbolic link.
+func (fsys *MemFS) ReadLink(name string) (string, error) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	n, err := fsys.lookupLocked("readlink", name, false)
+	if err != nil {
+		return "", err
+	}
+	if n.mode&fs.ModeSymlink == 0 {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return string(n.data), nil
+}
+
+// ReadDir reads the named directory and returns
+// its entries sorted by filename.
+func (fsys *MemFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	n, err := fsys.lookupLocked("readdir", name, true)
+	if err != nil {
+		return nil, err
+	}
+	if !n.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errNotDir}
+	}
+	return n.entriesLocked(), nil
+}
+
+// entriesLocked returns the entries of directory n, sorted by name.
+func (n *memNode) entriesLocked() []fs.DirEntry {
+	list := make([]fs.DirEntry, 0, len(n.children))
+	for name, c := range n.children {
+		list = append(list, fs.FileInfoToDirEntry(c.infoLocked(name)))
+	}
+	slices.SortFunc(list, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return list
+}
+
+// infoLocked returns a snapshot of the information about n.
+func (n *memNode) infoLocked(name string) *memFileInfo {
+	return &memFileInfo{
+		name:    name,
+		size:    int64(len(n.data)),
+		mode:    n.mode,
+		modTime: n.modTime,
+	}
+}
+
+// A memFileInfo implements fs.FileInfo for a file in a MemFS.
+type memFileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i *memFileInfo) Name() string       { return i.name }
+func (i *memFileInfo) Size() int64        { return i.size }
+func (i *memFileInfo) Mode() fs.FileMode  { return i.mode }
+func (i *memFileInfo) ModTime() time.Time { return i.modTime }
+func (i *memFileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *memFileInfo) Sys() any           { return nil }
+
+func (i *memFileInfo) String() string {
+	return fs.FormatFileInfo(i)
+}
+
+// A memFile is an open file or directory in a MemFS.
+type memFile struct {
+	fsys    *MemFS
+	n       *memNode
+	path    string
+	flag    int
+	offset  int64
+	closed  bool
+	dir     []fs.DirEntry // remaining directory entries, once read
+	dirRead bool
+}
+
+var (
+	_ fs.ReadDirFile = (*memFile)(nil)
+	_ io.ReaderAt    = (*memFile)(nil)
+	_ io.WriterAt    = (*memFile)(nil)
+	_ io.Seeker      = (*memFile)(nil)
+	_ io.Writer      = (*memFile)(nil)
+)
+
+// checkLocked returns an error if f is closed, or does not permit the
+// operation. fsys.mu must be held.
+func (f *memFile) checkLocked(op string, write bool) error {
+	if f.closed {
+		return &fs.PathError{Op: op, Path: f.path, Err: fs.ErrClosed}
+	}
+	if f.n.mode.IsDir() {
+		return &fs.PathError{Op: op, Path: f.path, Err: errIsDir}
+	}
+	acc := f.flag & (os.O_RDONLY | os.O_WRONLY | os.O_RDWR)
+	if write && acc == os.O_RDONLY || !write && acc == os.O_WRONLY {
+		return &fs.PathError{Op: op, Path: f.path, Err: errBadMode}
+	}
+	return nil
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) {
+	f.fsys.mu.Lock()
+	defer f.fsys.mu.Unlock()
+	if f.closed {
+		return nil, &fs.PathError{Op: "stat", Path: f.path, Err: fs.ErrClosed}
+	}
+	return f.n.infoLocked(path.Base(f.path)), nil
+}
+
+func (f *memFile) Close() error {
+	f.fsys.mu.Lock()
+	defer f.fsys.mu.Unlock()
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.path, Err: fs.ErrClosed}
+	}
+	f.closed = true
+	return nil
+}
+
+func (f *memFile) Read(b []byte) (int, error) {
+	f.fsys.mu.Lock()
+	defer f.fsys.mu.Unlock()
+	if err := f.checkLocked("read", false); err != nil {
+		return 0, err
+	}
+	if f.offset >= int64(len(f.n.data)) {
+		return 0, io.EOF
+	}
+	n := copy(b, f.n.data[f.offset:])
+	f.offset += int64(n)
+	return n, nil
+}
+
+func (f *memFile) ReadAt(b []byte, offset int64) (int, error) {
+	f.fsys.mu.Lock()
+	defer f.fsys.mu.Unlock()
+	if err := f.checkLocked("read", false); err != nil {
+		return 0, err
+	}
+	if offset < 0 {
+		return 0, &fs.PathError{Op: "read", Path: f.path, Err: fs.ErrInvalid}
+	}
+	if offset >= int64(len(f.n.data)) {
+		return 0, io.EOF
+	}
+	n := copy(b, f.n.data[offset:])
+	if n < len(b) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *memFile) Write(b []byte) (int, error) {
+	f.fsys.mu.Lock()
+	defer f.fsys.mu.Unlock()
+	if err := f.checkLocked("write", true); err != nil {
+		return 0, err
+	}
+	if f.flag&os.O_APPEND != 0 {
+		f.offset = int64(len(f.n.data))
+	}
+	f.writeAtLocked(b, f.offset)
+	f.offset += int64(len(b))
+	return len(b), nil
+}
+
+func (f *memFile) WriteAt(b []byte, offset int64) (int, error) {
+	f.fsys.mu.Lock()
+	defer f.fsys.mu.Unlock()
+	if err := f.checkLocked("write", true); err != nil {
+		return 0, err
+	}
+	if f.flag&os.O_APPEND != 0 {
+		return 0, &fs.PathError{Op: "writeat", Path: f.path, Err: errors.New("invalid use of WriteAt on file opened with O_APPEND")}
+	}
+	if offset < 0 {
+		return 0, &fs.PathError{Op: "writeat", Path: f.path, Err: fs.ErrInvalid}
+	}
+	f.writeAtLocked(b, offset)
+	return len(b), nil
+}
+
+// writeAtLocked writes b at offset, extending the file as needed.
+func (f *memFile) writeAtLocked(b []byte, offset int64) {
+	if end := offset + int64(len(b)); end > int64(len(f.n.data)) {
+		f.n.data = slices.Grow(f.n.data, int(end)-len(f.n.data))[:end]
+	}
+	copy(f.n.data[offset:], b)
+	f.n.modTime = time.Now()
+}
+
+func (f *memFile) Seek(offset int64, whence int) (int64, error) {
+	f.fsys.mu.Lock()
+	defer f.fsys.mu.Unlock()
+	if f.closed {
+		return 0, &fs.PathError{Op: "seek", Path: f.path, Err: fs.ErrClosed}
+	}
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += int64(len(f.n.data))
+	default:
+		return 0, &fs.PathError{Op: "seek", Path: f.path, Err: fs.ErrInvalid}
+	}
+	if offset < 0 {
+		return 0, &fs.PathError{Op: "seek", Path: f.path, Err: fs.ErrInvalid}
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *memFile) ReadDir(count int) ([]fs.DirEntry, error) {
+	f.fsys.mu.Lock()
+	defer f.fsys.mu.Unlock()
+	if f.closed {
+		return nil, &fs.PathError{Op: "readdir", Path: f.path, Err: fs.ErrClosed}
+	}
+	if !f.n.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: f.path, Err: errNotDir}
+	}
+	if !f.dirRead {
+		f.dir = f.n.entriesLocked()
+		f.dirRead = true
+	}
+	if count <= 0 {
+		list := f.dir
+		f.dir = nil
+		return list, nil
+	}
+	if len(f.dir) == 0 {
+		return nil, io.EOF
+	}
+	count = min(count, len(f.dir))
+	list := f.dir[:count:count]
+	f.dir = f.dir[count:]
+	return list, nil
+}
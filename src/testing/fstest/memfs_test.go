@@ -0,0 +1,200 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fstest
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestMemFS(t *testing.T) {
+	var m MemFS
+	if err := m.MkdirAll("fortune/k", 0o777); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WriteFile("hello", []byte("hello, world\n"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WriteFile("fortune/k/ken.txt", []byte("If a program is too slow, it must have a loop.\n"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Symlink("fortune/k", "k"); err != nil {
+		t.Fatal(err)
+	}
+	if err := TestFS(&m, "hello", "fortune", "fortune/k", "fortune/k/ken.txt", "k"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemFSWriteFS(t *testing.T) {
+	var m MemFS
+	if err := TestWriteFS(&m); err != nil {
+		t.Fatal(err)
+	}
+	if list, err := m.ReadDir("."); err != nil || len(list) != 0 {
+		t.Fatalf("ReadDir after TestWriteFS = %v, %v; want no entries", list, err)
+	}
+}
+
+func TestWriteFSNotWritable(t *testing.T) {
+	if err := TestWriteFS(MapFS{}); err == nil {
+		t.Fatal("TestWriteFS(MapFS) succeeded, want error")
+	}
+}
+
+func TestMemFSSymlinkEscape(t *testing.T) {
+	var m MemFS
+	m.Mkdir("dir", 0o777)
+	m.WriteFile("dir/file", []byte("x"), 0o666)
+	m.Symlink("../dir/file", "dir/up")
+	m.Symlink("../../outside", "dir/escape")
+	m.Symlink("/etc/passwd", "abs")
+	m.Symlink("loop2", "loop1")
+	m.Symlink("loop1", "loop2")
+
+	if b, err := m.ReadFile("dir/up"); err != nil || string(b) != "x" {
+		t.Errorf("ReadFile(dir/up) = %q, %v; want %q, nil", b, err, "x")
+	}
+	for _, name := range []string{"dir/escape", "abs", "loop1", "../dir/file", "/dir/file", "dir/../dir/file"} {
+		if _, err := m.Open(name); err == nil {
+			t.Errorf("Open(%q) succeeded, want error", name)
+		}
+	}
+	if _, err := m.Create("dir/escape"); err == nil {
+		t.Errorf("Create through escaping symlink succeeded, want error")
+	}
+	// Lstat and Remove do not follow the final symbolic link.
+	if info, err := m.Lstat("dir/escape"); err != nil || info.Mode().Type() != fs.ModeSymlink {
+		t.Errorf("Lstat(dir/escape) = %v, %v; want symbolic link", info, err)
+	}
+	if err := m.Remove("dir/escape"); err != nil {
+		t.Errorf("Remove(dir/escape) = %v", err)
+	}
+}
+
+func TestMemFSOpenFile(t *testing.T) {
+	var m MemFS
+	if err := m.WriteFile("a", []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.OpenFile("a", os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o666); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("OpenFile(O_EXCL) of existing file = %v, want fs.ErrExist", err)
+	}
+	if _, err := m.OpenFile("b", os.O_RDONLY, 0); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("OpenFile of missing file = %v, want fs.ErrNotExist", err)
+	}
+
+	f, err := m.OpenFile("a", os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := f.(io.WriteSeeker)
+	w.Seek(0, io.SeekStart)
+	if _, err := io.WriteString(w, ", world"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Read(make([]byte, 1)); err == nil {
+		t.Errorf("Read of write-only file succeeded, want error")
+	}
+	f.Close()
+	if _, err := io.WriteString(w, "!"); !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("Write after Close = %v, want fs.ErrClosed", err)
+	}
+	if b, _ := m.ReadFile("a"); string(b) != "hello, world" {
+		t.Errorf("after append, ReadFile = %q, want %q", b, "hello, world")
+	}
+
+	f, err = m.Open("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.(io.Writer).Write([]byte("x")); err == nil {
+		t.Errorf("Write of read-only file succeeded, want error")
+	}
+	f.Close()
+}
+
+func TestMemFSOpenAfterRemove(t *testing.T) {
+	var m MemFS
+	m.WriteFile("a", []byte("data"), 0o666)
+	f, err := m.Open("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := m.Rename("a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Remove("b"); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := io.ReadAll(f); err != nil || string(b) != "data" {
+		t.Errorf("ReadAll of removed file = %q, %v; want %q, nil", b, err, "data")
+	}
+}
+
+func TestMemFSRename(t *testing.T) {
+	var m MemFS
+	m.MkdirAll("a/b", 0o777)
+	m.Mkdir("c", 0o777)
+	m.WriteFile("c/f", nil, 0o666)
+	m.WriteFile("f", nil, 0o666)
+
+	var le *os.LinkError
+	if err := m.Rename("a", "a/b/a"); !errors.As(err, &le) {
+		t.Errorf("Rename of directory into itself = %v, want *os.LinkError", err)
+	}
+	if err := m.Rename("f", "a"); err == nil {
+		t.Errorf("Rename of file onto directory succeeded, want error")
+	}
+	if err := m.Rename("a", "c"); err == nil {
+		t.Errorf("Rename onto non-empty directory succeeded, want error")
+	}
+	if err := m.Rename("a", "f"); err == nil {
+		t.Errorf("Rename of directory onto file succeeded, want error")
+	}
+	if err := m.Remove("c/f"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Rename("a", "c"); err != nil {
+		t.Errorf("Rename onto empty directory = %v", err)
+	}
+	if _, err := m.Stat("c/b"); err != nil {
+		t.Errorf("Stat after Rename = %v", err)
+	}
+}
+
+func TestMemFSReadDir(t *testing.T) {
+	var m MemFS
+	for _, name := range []string{"c", "a", "b"} {
+		m.WriteFile(name, nil, 0o666)
+	}
+	f, err := m.Open(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	d := f.(fs.ReadDirFile)
+	var names []string
+	for {
+		list, err := d.ReadDir(2)
+		for _, e := range list {
+			names = append(names, e.Name())
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []string{"a", "b", "c"}; !slices.Equal(names, want) {
+		t.Errorf("ReadDir = %q, want %q", names, want)
+	}
+}
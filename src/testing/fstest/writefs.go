@@ -0,0 +1,273 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fstest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+)
+
+// A writeFS is a file system which supports the write operations
+// tested by TestWriteFS.
+type writeFS interface {
+	fs.CreateFS
+	fs.MkdirFS
+	fs.RemoveFS
+	fs.RenameFS
+}
+
+// A symlinkFS is a file system which can create symbolic links.
+type symlinkFS interface {
+	Symlink(oldname, newname string) error
+}
+
+// A chmodFS is a file system which can change file modes.
+type chmodFS interface {
+	Chmod(name string, mode fs.FileMode) error
+}
+
+// testWriteDir is the directory in which TestWriteFS works.
+const testWriteDir = "fstest-writefs.tmp"
+
+// TestWriteFS tests the write operations of a file system implementation.
+// The file system must implement [fs.CreateFS], [fs.MkdirFS], [fs.RemoveFS],
+// and [fs.RenameFS], with the semantics of the corresponding methods of
+// [MemFS] and [os.Root]. If the file system also implements [fs.WriteFileFS],
+// or provides the methods
+//
+//	Symlink(oldname, newname string) error
+//	Chmod(name string, mode fs.FileMode) error
+//
+// those are tested too.
+//
+// TestWriteFS works in a new directory named "fstest-writefs.tmp",
+// which must not exist, and removes the directory when it finishes.
+// After making changes in the directory, it checks the resulting
+// files with [TestFS].
+//
+// If TestWriteFS finds any misbehaviors, it returns either the first
+// error or a list of errors. Use [errors.Is] or [errors.AsType] to inspect.
+func TestWriteFS(fsys fs.FS) error {
+	wfs, ok := fsys.(writeFS)
+	if !ok {
+		return fmt.Errorf("TestWriteFS: %T does not implement fs.CreateFS, fs.MkdirFS, fs.RemoveFS, and fs.RenameFS", fsys)
+	}
+	if _, err := fs.Stat(fsys, testWriteDir); !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("TestWriteFS: %s already exists or cannot be checked: %v", testWriteDir, err)
+	}
+	t := writeTester{fsys: wfs}
+	t.run()
+	if len(t.errors) == 0 {
+		return nil
+	}
+	return fmt.Errorf("TestWriteFS found errors:\n%w", errors.Join(t.errors...))
+}
+
+// A writeTester holds state for running TestWriteFS.
+type writeTester struct {
+	fsys   writeFS
+	errors []error
+}
+
+// errorf adds an error to the list of errors.
+func (t *writeTester) errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Errorf(format, args...))
+}
+
+// name returns the name of elem within the test directory.
+func (t *writeTester) name(elem string) string {
+	return path.Join(testWriteDir, elem)
+}
+
+// check reports an error if err is not nil.
+func (t *writeTester) check(op string, err error) bool {
+	if err != nil {
+		t.errorf("%s: %w", op, err)
+		return false
+	}
+	return true
+}
+
+// checkErr reports an error if err does not match want.
+func (t *writeTester) checkErr(op string, err, want error) {
+	switch {
+	case err == nil:
+		t.errorf("%s succeeded, want error", op)
+	case want != nil && !errors.Is(err, want):
+		t.errorf("%s: %w, want %w", op, err, want)
+	}
+}
+
+// create creates the named file with the given contents.
+func (t *writeTester) create(name, data string) bool {
+	f, err := t.fsys.Create(name)
+	if !t.check("Create("+name+")", err) {
+		return false
+	}
+	w, ok := f.(io.Writer)
+	if !ok {
+		f.Close()
+		t.errorf("Create(%s) returned %T, which does not implement io.Writer", name, f)
+		return false
+	}
+	_, err = io.WriteString(w, data)
+	t.check("Write("+name+")", err)
+	return t.check("Close("+name+")", f.Close()) && err == nil
+}
+
+// checkContent checks that the named file contains data.
+func (t *writeTester) checkContent(name, data string) {
+	b, err := fs.ReadFile(t.fsys, name)
+	if !t.check("ReadFile("+name+")", err) {
+		return
+	}
+	if string(b) != data {
+		t.errorf("ReadFile(%s) = %q, want %q", name, b, data)
+	}
+	info, err := fs.Stat(t.fsys, name)
+	if !t.check("Stat("+name+")", err) {
+		return
+	}
+	if !info.Mode().IsRegular() || info.Size() != int64(len(data)) {
+		t.errorf("Stat(%s) = %v, size %d; want regular file, size %d", name, info.Mode(), info.Size(), len(data))
+	}
+}
+
+// checkNotExist checks that the named file does not exist.
+func (t *writeTester) checkNotExist(name string) {
+	_, err := fs.Stat(t.fsys, name)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.errorf("Stat(%s) = %v, want fs.ErrNotExist", name, err)
+	}
+}
+
+func (t *writeTester) run() {
+	fsys := t.fsys
+	if !t.check("Mkdir", fsys.Mkdir(testWriteDir, 0o777)) {
+		return
+	}
+	defer t.removeAll(testWriteDir)
+
+	// Directories.
+	t.checkErr("Mkdir of existing directory", fsys.Mkdir(testWriteDir, 0o777), fs.ErrExist)
+	t.checkErr("Mkdir in missing directory", fsys.Mkdir(t.name("missing/dir"), 0o777), fs.ErrNotExist)
+	t.check("Mkdir", fsys.Mkdir(t.name("dir"), 0o777))
+	if info, err := fs.Stat(fsys, t.name("dir")); t.check("Stat(dir)", err) && !info.IsDir() {
+		t.errorf("Stat(dir).IsDir() = false, want true")
+	}
+
+	// Files.
+	if !t.create(t.name("a"), "hello, world\n") {
+		return
+	}
+	t.checkContent(t.name("a"), "hello, world\n")
+	if t.create(t.name("a"), "hi\n") {
+		// Create truncates an existing file.
+		t.checkContent(t.name("a"), "hi\n")
+	}
+	_, err := fsys.Create(t.name("missing/file"))
+	t.checkErr("Create in missing directory", err, fs.ErrNotExist)
+	_, err = fsys.Create(testWriteDir + "/../escape")
+	t.checkErr("Create of invalid path", err, nil)
+	t.checkErr("Mkdir of existing file", fsys.Mkdir(t.name("a"), 0o777), fs.ErrExist)
+	t.create(t.name("dir/b"), "b\n")
+	if t.check("WriteFile(e)", fs.WriteFile(fsys, t.name("e"), []byte("e\n"), 0o666)) {
+		t.checkContent(t.name("e"), "e\n")
+		t.check("Remove(e)", fsys.Remove(t.name("e")))
+	}
+
+	// Rename.
+	if t.check("Rename(a, c)", fsys.Rename(t.name("a"), t.name("c"))) {
+		t.checkNotExist(t.name("a"))
+		t.checkContent(t.name("c"), "hi\n")
+	}
+	t.create(t.name("d"), "d\n")
+	if t.check("Rename(d, c)", fsys.Rename(t.name("d"), t.name("c"))) {
+		// Rename replaces an existing file.
+		t.checkNotExist(t.name("d"))
+		t.checkContent(t.name("c"), "d\n")
+	}
+	t.checkErr("Rename of missing file", fsys.Rename(t.name("missing"), t.name("x")), fs.ErrNotExist)
+	if t.check("Rename(dir, dir2)", fsys.Rename(t.name("dir"), t.name("dir2"))) {
+		t.checkNotExist(t.name("dir/b"))
+		t.checkContent(t.name("dir2/b"), "b\n")
+	}
+
+	// Directory listing.
+	if list, err := fs.ReadDir(fsys, testWriteDir); t.check("ReadDir", err) {
+		var names []string
+		for _, e := range list {
+			names = append(names, e.Name())
+		}
+		if want := []string{"c", "dir2"}; !slices.Equal(names, want) {
+			t.errorf("ReadDir = %q, want %q", names, want)
+		}
+	}
+
+	// Remove.
+	t.checkErr("Remove of non-empty directory", fsys.Remove(t.name("dir2")), nil)
+	t.checkErr("Remove of missing file", fsys.Remove(t.name("missing")), fs.ErrNotExist)
+	if t.check("Remove(dir2/b)", fsys.Remove(t.name("dir2/b"))) {
+		t.checkNotExist(t.name("dir2/b"))
+	}
+	if t.check("Remove(dir2)", fsys.Remove(t.name("dir2"))) {
+		t.checkNotExist(t.name("dir2"))
+	}
+
+	// Symbolic links.
+	expected := []string{"c"}
+	if sfs, ok := fsys.(symlinkFS); ok {
+		if t.check("Symlink", sfs.Symlink("c", t.name("link"))) {
+			expected = append(expected, "link")
+			t.checkContent(t.name("link"), "d\n")
+			if rfs, ok := fsys.(fs.ReadLinkFS); ok {
+				if target, err := rfs.ReadLink(t.name("link")); t.check("ReadLink", err) && target != "c" {
+					t.errorf("ReadLink = %q, want %q", target, "c")
+				}
+				if info, err := rfs.Lstat(t.name("link")); t.check("Lstat", err) && info.Mode().Type() != fs.ModeSymlink {
+					t.errorf("Lstat(link).Mode() = %v, want symbolic link", info.Mode())
+				}
+			}
+			t.checkErr("Symlink over existing file", sfs.Symlink("c", t.name("link")), fs.ErrExist)
+		}
+	}
+
+	// Modes.
+	if cfs, ok := fsys.(chmodFS); ok {
+		if t.check("Chmod", cfs.Chmod(t.name("c"), 0o444)) {
+			if info, err := fs.Stat(fsys, t.name("c")); t.check("Stat", err) && info.Mode().Perm()&0o222 != 0 {
+				t.errorf("Stat after Chmod(0o444) = %v, want read-only", info.Mode())
+			}
+			t.check("Chmod", cfs.Chmod(t.name("c"), 0o666))
+		}
+		t.checkErr("Chmod of missing file", cfs.Chmod(t.name("missing"), 0o666), fs.ErrNotExist)
+	}
+
+	sub, err := fs.Sub(fsys, testWriteDir)
+	if t.check("Sub", err) {
+		t.check("TestFS", TestFS(sub, expected...))
+	}
+}
+
+// removeAll removes dir and its contents.
+func (t *writeTester) removeAll(dir string) {
+	list, err := fs.ReadDir(t.fsys, dir)
+	if !t.check("ReadDir("+dir+")", err) {
+		return
+	}
+	for _, e := range list {
+		name := path.Join(dir, e.Name())
+		if e.IsDir() {
+			t.removeAll(name)
+		} else {
+			t.check("Remove("+name+")", t.fsys.Remove(name))
+		}
+	}
+	t.check("Remove("+dir+")", t.fsys.Remove(dir))
+}
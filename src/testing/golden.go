@@ -0,0 +1,65 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package testing
+
+import (
+	"bytes"
+	"errors"
+	"internal/diff"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Golden compares got with the contents of the golden file
+// testdata/name.golden, relative to the current directory,
+// which is the package directory unless the test has changed it.
+// The name uses forward slashes as separators, and may name a file
+// in a subdirectory of testdata. If the name is not local, as reported
+// by [filepath.IsLocal], Golden marks the test as failed.
+//
+// If the contents differ, or the file does not exist, Golden marks
+// the test as failed and logs a unified diff of the golden file and got.
+// The test continues to run.
+//
+// If the -test.update-golden flag is set, which 'go test -update-golden'
+// does, Golden instead writes got to the golden file, creating it and its
+// directory if necessary, and logs that the file was updated.
+func (c *common) Golden(name string, got []byte) {
+	c.checkFuzzFn("Golden")
+	c.Helper()
+	local := filepath.FromSlash(name)
+	if !filepath.IsLocal(local) {
+		c.Errorf("Golden: invalid name %q: not a local path", name)
+		return
+	}
+	file := filepath.Join("testdata", local+".golden")
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(file), 0o777); err != nil {
+			c.Errorf("Golden: %v", err)
+			return
+		}
+		if err := os.WriteFile(file, got, 0o666); err != nil {
+			c.Errorf("Golden: %v", err)
+			return
+		}
+		c.Logf("updated golden file %s", file)
+		return
+	}
+
+	want, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			c.Errorf("golden file %s does not exist; run 'go test -update-golden' to create it", file)
+			return
+		}
+		c.Errorf("Golden: %v", err)
+		return
+	}
+	if !bytes.Equal(got, want) {
+		c.Errorf("output does not match golden file %s; run 'go test -update-golden' to update it:\n%s",
+			file, diff.Diff(file, want, "got", got))
+	}
+}
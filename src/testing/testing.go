@@ -297,6 +297,25 @@
 //	    })
 //	}
 //
+// # Golden files
+//
+// A test which checks a large output, such as a generated file or a
+// formatted report, may compare it with the expected output stored in
+// a golden file in the package's testdata directory:
+//
+//	func TestReport(t *testing.T) {
+//	    got := report(input)
+//	    t.Golden("report", got) // compares with testdata/report.golden
+//	}
+//
+// When the output changes intentionally, running
+//
+//	go test -update-golden
+//
+// rewrites the golden files with the new output, instead of comparing
+// against them. The changes should then be reviewed before they are
+// committed. See [T.Golden] for details.
+//
 // # Subtests and Sub-benchmarks
 //
 // The [T.Run] and [B.Run] methods allow defining subtests and sub-benchmarks,
@@ -479,6 +498,7 @@ func Init() {
 	testlog = flag.String("test.testlogfile", "", "write test action log to `file` (for use only by cmd/go)")
 	shuffle = flag.String("test.shuffle", "off", "randomize the execution order of tests and benchmarks")
 	fullPath = flag.Bool("test.fullpath", false, "show full file names in error messages")
+	updateGolden = flag.Bool("test.update-golden", false, "write golden files in testdata instead of comparing against them")
 
 	initBenchmarkFlags()
 	initFuzzFlags()
@@ -512,6 +532,7 @@ var (
 	shuffle              *string
 	testlog              *string
 	fullPath             *bool
+	updateGolden         *bool
 
 	haveExamples bool // are there examples?
 
@@ -981,6 +1002,7 @@ type TB interface {
 	Failed() bool
 	Fatal(args ...any)
 	Fatalf(format string, args ...any)
+	Golden(name string, got []byte)
 	Helper()
 	Log(args ...any)
 	Logf(format string, args ...any)
@@ -1309,3 +1309,72 @@ func BenchmarkBNPrint(b *testing.B) {
 func TestArtifactDir(t *testing.T) {
 	t.Log(t.ArtifactDir())
 }
+
+func TestGoldenExample(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		t.Skip("run by TestGolden")
+	}
+	t.Golden("sub/out", []byte("line 1\nline 2\n"))
+}
+
+func TestGoldenBadNameExample(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		t.Skip("run by TestGoldenBadName")
+	}
+	for _, name := range []string{"../out", "sub/../../out", "/out", ""} {
+		t.Golden(name, []byte("data\n"))
+	}
+}
+
+func TestGoldenBadName(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := os.Mkdir("testdata", 0o777); err != nil {
+		t.Fatal(err)
+	}
+	out := string(runTest(t, "TestGoldenBadNameExample", "-test.update-golden"))
+	for _, name := range []string{"../out", "sub/../../out", "/out", ""} {
+		if want := fmt.Sprintf("Golden: invalid name %q", name); !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+	if !strings.Contains(out, "--- FAIL") {
+		t.Errorf("non-local names did not fail the test:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "out.golden")); err == nil {
+		t.Errorf("Golden wrote a file outside of testdata")
+	}
+}
+
+func TestGolden(t *testing.T) {
+	t.Chdir(t.TempDir())
+	file := filepath.Join("testdata", "sub", "out.golden")
+
+	out := string(runTest(t, "TestGoldenExample"))
+	if !strings.Contains(out, "--- FAIL") || !strings.Contains(out, "golden file "+file+" does not exist") {
+		t.Errorf("missing golden file did not fail the test:\n%s", out)
+	}
+
+	out = string(runTest(t, "TestGoldenExample", "-test.update-golden"))
+	if !strings.Contains(out, "--- PASS") || !strings.Contains(out, "updated golden file "+file) {
+		t.Errorf("-test.update-golden did not report the update:\n%s", out)
+	}
+	if got, err := os.ReadFile(file); err != nil || string(got) != "line 1\nline 2\n" {
+		t.Fatalf("golden file after update = %q, %v", got, err)
+	}
+
+	out = string(runTest(t, "TestGoldenExample"))
+	if !strings.Contains(out, "--- PASS") {
+		t.Errorf("matching golden file failed the test:\n%s", out)
+	}
+
+	if err := os.WriteFile(file, []byte("line 1\nold line 2\n"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	out = string(runTest(t, "TestGoldenExample"))
+	for _, want := range []string{"--- FAIL", "does not match golden file", "-old line 2", "+line 2"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output for mismatched golden file does not contain %q:\n%s", want, out)
+		}
+	}
+}